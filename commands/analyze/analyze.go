@@ -3,17 +3,27 @@
 // This package serves as the parent command for various analysis operations.
 // Currently supports:
 //   - includes: Analyze include directive relationships in RST files
+//   - included-by: Find every file that transitively includes a target file
 //   - usage: Find all files that use a target file
 //   - procedures: Analyze procedure variations and statistics
 //   - composables: Analyze composables in snooty.toml files
+//   - snooty-health: Check that every snooty.toml file parses and has a unique name
+//   - product-mappings: Print the rstspec-derived product mappings used by report testable-code
+//   - driver-coverage: Check that every driver has local content and test infrastructure
+//   - missing-language: List code examples that have no language
 //
 // Future subcommands could include analyzing cross-references, broken links, or content metrics.
 package analyze
 
 import (
 	"github.com/grove-platform/audit-cli/commands/analyze/composables"
+	drivercoverage "github.com/grove-platform/audit-cli/commands/analyze/driver-coverage"
+	includedby "github.com/grove-platform/audit-cli/commands/analyze/included-by"
 	"github.com/grove-platform/audit-cli/commands/analyze/includes"
+	missinglanguage "github.com/grove-platform/audit-cli/commands/analyze/missing-language"
 	"github.com/grove-platform/audit-cli/commands/analyze/procedures"
+	productmappings "github.com/grove-platform/audit-cli/commands/analyze/product-mappings"
+	snootyhealth "github.com/grove-platform/audit-cli/commands/analyze/snooty-health"
 	"github.com/grove-platform/audit-cli/commands/analyze/usage"
 	"github.com/spf13/cobra"
 )
@@ -30,18 +40,28 @@ func NewAnalyzeCommand() *cobra.Command {
 
 Currently supports:
   - includes: Analyze include directive relationships (forward dependencies)
+  - included-by: Find every file that transitively includes a target file (reverse includes)
   - usage: Find all files that use a target file (reverse dependencies)
   - procedures: Analyze procedure variations and statistics
   - composables: Analyze composables in snooty.toml files
+  - snooty-health: Check that every snooty.toml file parses and has a unique name
+  - product-mappings: Print the rstspec-derived product mappings used by report testable-code
+  - driver-coverage: Check that every driver has local content and test infrastructure
+  - missing-language: List code examples that have no language
 
 Future subcommands may support analyzing cross-references, broken links, or content metrics.`,
 	}
 
 	// Add subcommands
 	cmd.AddCommand(includes.NewIncludesCommand())
+	cmd.AddCommand(includedby.NewIncludedByCommand())
 	cmd.AddCommand(usage.NewUsageCommand())
 	cmd.AddCommand(procedures.NewProceduresCommand())
 	cmd.AddCommand(composables.NewComposablesCommand())
+	cmd.AddCommand(snootyhealth.NewSnootyHealthCommand())
+	cmd.AddCommand(productmappings.NewProductMappingsCommand())
+	cmd.AddCommand(drivercoverage.NewDriverCoverageCommand())
+	cmd.AddCommand(missinglanguage.NewMissingLanguageCommand())
 
 	return cmd
 }
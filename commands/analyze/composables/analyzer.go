@@ -2,11 +2,17 @@
 package composables
 
 import (
+	"fmt"
 	"sort"
 
+	"github.com/grove-platform/audit-cli/internal/projectinfo"
 	"github.com/grove-platform/audit-cli/internal/snooty"
 )
 
+// DefaultSimilarityThreshold is the minimum option overlap (Jaccard similarity) for two
+// composables with different IDs to be grouped as similar.
+const DefaultSimilarityThreshold = 0.6
+
 // AnalyzeComposables analyzes composables and groups them by similarity.
 //
 // This function identifies:
@@ -15,10 +21,12 @@ import (
 //
 // Parameters:
 //   - locations: All composable locations found in the monorepo
+//   - similarityThreshold: Minimum option overlap (0.0-1.0) for similar composables;
+//     lower values produce more, noisier groups (see DefaultSimilarityThreshold)
 //
 // Returns:
 //   - *AnalysisResult: Analysis results with grouped composables
-func AnalyzeComposables(locations []ComposableLocation) *AnalysisResult {
+func AnalyzeComposables(locations []ComposableLocation, similarityThreshold float64) *AnalysisResult {
 	result := &AnalysisResult{
 		AllComposables:  locations,
 		IdenticalGroups: []ComposableGroup{},
@@ -49,7 +57,7 @@ func AnalyzeComposables(locations []ComposableLocation) *AnalysisResult {
 	}
 
 	// Find similar composables (different IDs but similar option sets)
-	result.SimilarGroups = findSimilarComposables(locations, groupsByID)
+	result.SimilarGroups = findSimilarComposables(groupsByID, similarityThreshold)
 
 	// Sort groups by ID for consistent output
 	sort.Slice(result.IdenticalGroups, func(i, j int) bool {
@@ -118,9 +126,8 @@ func optionsToSortedStrings(options []snooty.ComposableOption) []string {
 
 // findSimilarComposables finds composables with different IDs but similar option sets.
 // This helps identify potential consolidation opportunities across different composable IDs.
-func findSimilarComposables(locations []ComposableLocation, groupsByID map[string][]ComposableLocation) []ComposableGroup {
-	const similarityThreshold = 0.6 // At least 60% option overlap to be considered similar
-
+// Composables are similar when their option overlap is at least similarityThreshold.
+func findSimilarComposables(groupsByID map[string][]ComposableLocation, similarityThreshold float64) []ComposableGroup {
 	var similarGroups []ComposableGroup
 
 	// Get unique composables (one per ID, preferring the one with most options)
@@ -256,3 +263,172 @@ func calculateGroupSimilarity(locs []ComposableLocation) float64 {
 	return totalSimilarity / float64(comparisons)
 }
 
+// FindDuplicates returns the identical groups that should fail a --fail-on-duplicates check.
+//
+// Parameters:
+//   - result: Analysis results from AnalyzeComposables
+//   - crossProjectOnly: Only return groups defined in more than one project, ignoring
+//     composables repeated across versions of a single project
+//
+// Returns:
+//   - []ComposableGroup: The offending identical groups, sorted by ID
+func FindDuplicates(result *AnalysisResult, crossProjectOnly bool) []ComposableGroup {
+	var duplicates []ComposableGroup
+	for _, group := range result.IdenticalGroups {
+		if crossProjectOnly && countProjects(group.Locations) < 2 {
+			continue
+		}
+		duplicates = append(duplicates, group)
+	}
+	return duplicates
+}
+
+// countProjects returns the number of distinct projects among locations.
+func countProjects(locs []ComposableLocation) int {
+	projects := make(map[string]bool)
+	for _, loc := range locs {
+		projects[loc.Project] = true
+	}
+	return len(projects)
+}
+
+// BuildConsolidationPlan recommends how to consolidate the identical and similar groups.
+//
+// For each identical group, one definition is nominated as canonical:
+//  1. The rstspec.toml definition, if present (see --with-rstspec)
+//  2. Otherwise, the project with the most usages (if usages are available)
+//  3. Otherwise, the project that defines the composable in the most versions
+//
+// Ties go to the project that sorts first. Within the chosen project, the current version
+// is preferred, then the newest version (see projectinfo.CompareVersions). Every other
+// definition in the group is listed as redundant.
+//
+// For each similar group, the superset of all options is suggested as the merged composable.
+//
+// Parameters:
+//   - result: Analysis results from AnalyzeComposables
+//   - usages: Composable usages from FindComposableUsages (may be nil)
+//
+// Returns:
+//   - *ConsolidationPlan: Recommendations in the same order as the groups in result
+func BuildConsolidationPlan(result *AnalysisResult, usages map[string]*ComposableUsage) *ConsolidationPlan {
+	plan := &ConsolidationPlan{}
+
+	for _, group := range result.IdenticalGroups {
+		canonical, reason := chooseCanonical(group, usages)
+		rec := CanonicalRecommendation{
+			ID:        group.ID,
+			Canonical: group.Locations[canonical],
+			Reason:    reason,
+		}
+		for i, loc := range group.Locations {
+			if i != canonical {
+				rec.Redundant = append(rec.Redundant, loc)
+			}
+		}
+		sortLocations(rec.Redundant)
+		plan.Canonical = append(plan.Canonical, rec)
+	}
+
+	for _, group := range result.SimilarGroups {
+		plan.Supersets = append(plan.Supersets, buildSuperset(group))
+	}
+
+	return plan
+}
+
+// chooseCanonical returns the index of the canonical location in an identical group and
+// the reason it was chosen. See BuildConsolidationPlan for the rules.
+func chooseCanonical(group ComposableGroup, usages map[string]*ComposableUsage) (int, string) {
+	for i, loc := range group.Locations {
+		if loc.Source == "rstspec.toml" {
+			return i, "defined in rstspec.toml"
+		}
+	}
+
+	// Count usages and definitions per project
+	usageCount := make(map[string]int)
+	for _, usage := range usages {
+		if usage.ComposableID == group.ID {
+			usageCount[usage.Project] += usage.UsageCount
+		}
+	}
+	definitionCount := make(map[string]int)
+	for _, loc := range group.Locations {
+		definitionCount[loc.Project]++
+	}
+
+	projects := make([]string, 0, len(definitionCount))
+	for project := range definitionCount {
+		projects = append(projects, project)
+	}
+	sort.Strings(projects)
+
+	best := projects[0]
+	for _, project := range projects[1:] {
+		if usageCount[project] > usageCount[best] ||
+			(usageCount[project] == usageCount[best] && definitionCount[project] > definitionCount[best]) {
+			best = project
+		}
+	}
+
+	reason := fmt.Sprintf("defined in the most versions (%d)", definitionCount[best])
+	if usageCount[best] > 0 {
+		reason = fmt.Sprintf("most used project (%d usages)", usageCount[best])
+	}
+
+	// Prefer the current version within the chosen project, then the newest version
+	chosen := -1
+	for i, loc := range group.Locations {
+		if loc.Project != best {
+			continue
+		}
+		if chosen == -1 || projectinfo.IsCurrentVersion(loc.Version) ||
+			(!projectinfo.IsCurrentVersion(group.Locations[chosen].Version) &&
+				projectinfo.CompareVersions(loc.Version, group.Locations[chosen].Version) > 0) {
+			chosen = i
+		}
+	}
+	return chosen, reason
+}
+
+// buildSuperset merges the options of every composable in a similar group.
+func buildSuperset(group ComposableGroup) SupersetRecommendation {
+	rec := SupersetRecommendation{}
+
+	optionsByID := make(map[string]snooty.ComposableOption)
+	optionCount := make(map[string]int)
+	for _, loc := range group.Locations {
+		rec.IDs = append(rec.IDs, loc.Composable.ID)
+		for _, opt := range loc.Composable.Options {
+			if _, exists := optionsByID[opt.ID]; !exists {
+				optionsByID[opt.ID] = opt
+			}
+			optionCount[opt.ID]++
+		}
+	}
+	sort.Strings(rec.IDs)
+
+	for id, opt := range optionsByID {
+		rec.Options = append(rec.Options, opt)
+		if optionCount[id] < len(group.Locations) {
+			rec.AddedOptions = append(rec.AddedOptions, id)
+		}
+	}
+	sort.Slice(rec.Options, func(i, j int) bool {
+		return rec.Options[i].ID < rec.Options[j].ID
+	})
+	sort.Strings(rec.AddedOptions)
+
+	return rec
+}
+
+// sortLocations sorts locations by project, then version (see projectinfo.CompareVersions).
+func sortLocations(locs []ComposableLocation) {
+	sort.Slice(locs, func(i, j int) bool {
+		if locs[i].Project != locs[j].Project {
+			return locs[i].Project < locs[j].Project
+		}
+		return projectinfo.CompareVersions(locs[i].Version, locs[j].Version) < 0
+	})
+}
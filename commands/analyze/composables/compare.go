@@ -0,0 +1,211 @@
+package composables
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/grove-platform/audit-cli/internal/projectinfo"
+	"github.com/grove-platform/audit-cli/internal/snooty"
+)
+
+// ComposableDiff lists the composables that differ between two monorepo checkouts.
+type ComposableDiff struct {
+	// BasePath is the monorepo compared against, and OtherPath the one compared to it
+	// (e.g., the main branch and a PR branch)
+	BasePath  string
+	OtherPath string
+	// Composables defined only in OtherPath
+	Added []ComposableLocation
+	// Composables defined only in BasePath
+	Removed []ComposableLocation
+	// Composables defined in both with a different title, default, or options
+	Changed []ComposableChange
+}
+
+// ComposableChange is a composable that is defined differently in the two checkouts.
+type ComposableChange struct {
+	ID      string
+	Project string
+	Version string // Empty for non-versioned projects
+	Base    ComposableLocation
+	Other   ComposableLocation
+	// Human-readable differences, e.g. `default "python" -> "nodejs"` or "added option rust (Rust)"
+	Details []string
+}
+
+// HasChanges reports whether any composable was added, removed, or changed.
+func (d *ComposableDiff) HasChanges() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Changed) > 0
+}
+
+// compareKey identifies a composable across checkouts: its project, version, and ID.
+type compareKey struct {
+	project, version, id string
+}
+
+// keyOf returns the key that matches a composable location across checkouts.
+func keyOf(loc ComposableLocation) compareKey {
+	return compareKey{loc.Project, loc.Version, loc.Composable.ID}
+}
+
+// CompareComposables compares the composables found in two monorepo checkouts.
+//
+// Composables are matched by project, version, and ID. A composable in both is changed
+// when composablesEqual reports a difference. Each list is sorted by project, version,
+// then ID.
+func CompareComposables(base, other []ComposableLocation) *ComposableDiff {
+	diff := &ComposableDiff{}
+
+	baseByKey := make(map[compareKey]ComposableLocation, len(base))
+	for _, loc := range base {
+		baseByKey[keyOf(loc)] = loc
+	}
+	otherByKey := make(map[compareKey]ComposableLocation, len(other))
+	for _, loc := range other {
+		otherByKey[keyOf(loc)] = loc
+	}
+
+	for key, otherLoc := range otherByKey {
+		baseLoc, ok := baseByKey[key]
+		if !ok {
+			diff.Added = append(diff.Added, otherLoc)
+			continue
+		}
+		if !composablesEqual(baseLoc.Composable, otherLoc.Composable) {
+			diff.Changed = append(diff.Changed, ComposableChange{
+				ID:      key.id,
+				Project: key.project,
+				Version: key.version,
+				Base:    baseLoc,
+				Other:   otherLoc,
+				Details: describeChanges(baseLoc.Composable, otherLoc.Composable),
+			})
+		}
+	}
+	for key, baseLoc := range baseByKey {
+		if _, ok := otherByKey[key]; !ok {
+			diff.Removed = append(diff.Removed, baseLoc)
+		}
+	}
+
+	sortByKey(diff.Added)
+	sortByKey(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		return lessKey(keyOf(diff.Changed[i].Base), keyOf(diff.Changed[j].Base))
+	})
+	return diff
+}
+
+// sortByKey sorts locations by project, version, then composable ID.
+func sortByKey(locs []ComposableLocation) {
+	sort.Slice(locs, func(i, j int) bool {
+		return lessKey(keyOf(locs[i]), keyOf(locs[j]))
+	})
+}
+
+// lessKey reports whether a sorts before b: by project, then version (see
+// projectinfo.CompareVersions, so v9.0 sorts before v10.0), then composable ID.
+func lessKey(a, b compareKey) bool {
+	if a.project != b.project {
+		return a.project < b.project
+	}
+	if cmp := projectinfo.CompareVersions(a.version, b.version); cmp != 0 {
+		return cmp < 0
+	}
+	return a.id < b.id
+}
+
+// describeChanges lists what differs between two definitions of the same composable:
+// the title, the default, and options added, removed, or retitled (by option ID).
+func describeChanges(base, other snooty.Composable) []string {
+	var details []string
+	if base.Title != other.Title {
+		details = append(details, fmt.Sprintf("title %q -> %q", base.Title, other.Title))
+	}
+	if base.Default != other.Default {
+		details = append(details, fmt.Sprintf("default %q -> %q", base.Default, other.Default))
+	}
+
+	baseOptions := make(map[string]string, len(base.Options))
+	for _, opt := range base.Options {
+		baseOptions[opt.ID] = opt.Title
+	}
+	otherOptions := make(map[string]string, len(other.Options))
+	for _, opt := range other.Options {
+		otherOptions[opt.ID] = opt.Title
+	}
+
+	var optionDetails []string
+	for id, title := range otherOptions {
+		baseTitle, ok := baseOptions[id]
+		switch {
+		case !ok:
+			optionDetails = append(optionDetails, fmt.Sprintf("added option %s (%s)", id, title))
+		case baseTitle != title:
+			optionDetails = append(optionDetails, fmt.Sprintf("option %s title %q -> %q", id, baseTitle, title))
+		}
+	}
+	for id, title := range baseOptions {
+		if _, ok := otherOptions[id]; !ok {
+			optionDetails = append(optionDetails, fmt.Sprintf("removed option %s (%s)", id, title))
+		}
+	}
+	sort.Strings(optionDetails)
+	return append(details, optionDetails...)
+}
+
+// PrintComparison prints the composables added, removed, and changed between two checkouts.
+func PrintComparison(w io.Writer, diff *ComposableDiff) {
+	fmt.Fprintln(w, strings.Repeat("=", 80))
+	fmt.Fprintln(w, "COMPOSABLE CHANGES")
+	fmt.Fprintln(w, strings.Repeat("=", 80))
+	fmt.Fprintf(w, "Base:  %s\n", diff.BasePath)
+	fmt.Fprintf(w, "Other: %s\n", diff.OtherPath)
+	fmt.Fprintf(w, "Added: %d, Removed: %d, Changed: %d\n", len(diff.Added), len(diff.Removed), len(diff.Changed))
+
+	if !diff.HasChanges() {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "No composables were added, removed, or changed.")
+		return
+	}
+
+	if len(diff.Added) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "ADDED")
+		fmt.Fprintln(w, strings.Repeat("-", 80))
+		for _, loc := range diff.Added {
+			fmt.Fprintf(w, "  + %s in %s: %s\n", loc.Composable.ID, formatLocation(loc), formatOptions(loc.Composable.Options))
+		}
+	}
+
+	if len(diff.Removed) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "REMOVED")
+		fmt.Fprintln(w, strings.Repeat("-", 80))
+		for _, loc := range diff.Removed {
+			fmt.Fprintf(w, "  - %s in %s\n", loc.Composable.ID, formatLocation(loc))
+		}
+	}
+
+	if len(diff.Changed) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "CHANGED")
+		fmt.Fprintln(w, strings.Repeat("-", 80))
+		for _, change := range diff.Changed {
+			fmt.Fprintf(w, "  ~ %s in %s\n", change.ID, formatLocation(change.Other))
+			for _, detail := range change.Details {
+				fmt.Fprintf(w, "      %s\n", detail)
+			}
+		}
+	}
+}
+
+// PrintComparisonJSON prints the comparison as indented JSON.
+func PrintComparisonJSON(w io.Writer, diff *ComposableDiff) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(diff)
+}
@@ -8,6 +8,8 @@ package composables
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/grove-platform/audit-cli/internal/config"
 	"github.com/spf13/cobra"
@@ -20,6 +22,7 @@ import (
 //   - All composables and their locations
 //   - Identical composables that could be consolidated
 //   - Similar composables that should be reviewed
+//   - A consolidation plan for the identical and similar composables
 //
 // Usage:
 //
@@ -34,15 +37,14 @@ import (
 //   - --find-similar: Show identical and similar composables for consolidation
 //   - --find-usages: Show where each composable is used in RST files
 //   - --with-rstspec: Include composables from the canonical rstspec.toml file
+//   - --similarity-threshold: Minimum option overlap for similar composables (default 0.6)
+//   - --fail-on-duplicates: Exit non-zero if any identical composables are found
+//   - --cross-project-only: With --fail-on-duplicates, ignore duplicates within one project
+//   - --file: Analyze a single snooty.toml file instead of the monorepo
+//   - --compare: Report composables added, removed, or changed in another monorepo checkout
+//   - --format: With --compare, the output format (text or json)
 func NewComposablesCommand() *cobra.Command {
-	var (
-		forProject  string
-		currentOnly bool
-		verbose     bool
-		findSimilar bool
-		findUsages  bool
-		withRstspec bool
-	)
+	var opts composablesOptions
 
 	cmd := &cobra.Command{
 		Use:   "composables [monorepo-path]",
@@ -62,6 +64,9 @@ By default, the output includes:
 With --find-similar, the output also includes:
   - Identical composables (same ID, title, and options) across different projects/versions
   - Similar composables (different IDs but similar option sets) that may be consolidation candidates
+  - A consolidation plan: for each identical group, the canonical definition to keep
+    (rstspec.toml if present, else the most-used project) and the redundant definitions
+    to delete; for each similar group, the superset of options to merge into
 
 With --find-usages, the output also includes:
   - Usage count for each composable
@@ -71,6 +76,28 @@ With --with-rstspec, the analysis also includes:
   - Composables from the canonical rstspec.toml file in the snooty-parser repository
   - Helps identify duplication between local snooty.toml files and the canonical definitions
 
+Use --similarity-threshold (0.0-1.0, default 0.6) to set the minimum option overlap for
+composables with different IDs to be grouped as similar. Lower values surface more groups,
+with more noise; higher values only group composables with nearly the same options.
+
+Use --fail-on-duplicates in CI to exit with a non-zero status if any identical composables
+are found. The offending IDs and locations are printed. Add --cross-project-only to only
+fail on composables duplicated across projects, ignoring a project that repeats the same
+composable in each of its versions.
+
+Use --file to analyze a single snooty.toml file, such as a draft you are editing, without
+a monorepo. The project and version are derived from the path when it is under a content
+directory (content/<project>[/<version>]/snooty.toml); otherwise the project is the
+file's directory name. Use --file-project and --file-version to set them explicitly.
+Combine --file with --with-rstspec to check the file against the canonical composables.
+
+Use --compare <other-monorepo> to review what a docs PR changed: the composables in both
+checkouts are matched by project, version, and ID, and the ones added in the other
+checkout, removed from it, or changed (title, default, or options) are listed instead of
+the analysis. The monorepo path is the base (e.g., a main branch checkout) and
+--compare the checkout to compare to it (e.g., the PR branch). --for-project and
+--current-only apply to both. Use --format json for machine-readable output.
+
 Monorepo Path Configuration:
   The monorepo path can be specified in three ways (in order of priority):
     1. Command-line argument: analyze composables /path/to/monorepo
@@ -103,43 +130,134 @@ Examples:
   # Include canonical rstspec.toml composables
   analyze composables --with-rstspec --find-similar
 
+  # Only group composables that share at least 80% of their options
+  analyze composables --find-similar --similarity-threshold 0.8
+
+  # Fail a CI check if a composable is duplicated in another project
+  analyze composables --fail-on-duplicates --cross-project-only
+
+  # Review the composable changes in a PR checkout
+  analyze composables /path/to/docs-monorepo --compare /path/to/pr-checkout
+
+  # Lint a single snooty.toml against the canonical composables
+  analyze composables --file ./snooty.toml --with-rstspec --find-similar
+
   # Combine flags
   analyze composables --for-project atlas --find-similar --find-usages --verbose`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Resolve monorepo path from args, env, or config
-			var cmdLineArg string
-			if len(args) > 0 {
-				cmdLineArg = args[0]
+			if opts.format != "text" && opts.format != "json" {
+				return fmt.Errorf("invalid format: %s (must be 'text' or 'json')", opts.format)
+			}
+			if opts.format != "text" && opts.compare == "" {
+				return fmt.Errorf("--format %s requires --compare", opts.format)
+			}
+			if opts.similarityThreshold < 0 || opts.similarityThreshold > 1 {
+				return fmt.Errorf("--similarity-threshold must be between 0.0 and 1.0, got %g", opts.similarityThreshold)
 			}
-			monorepoPath, err := config.GetMonorepoPath(cmdLineArg)
-			if err != nil {
-				return err
+			if opts.crossProjectOnly && !opts.failOnDuplicates {
+				return fmt.Errorf("--cross-project-only requires --fail-on-duplicates")
 			}
-			return runComposables(monorepoPath, forProject, currentOnly, verbose, findSimilar, findUsages, withRstspec)
+
+			// Analyze a single file without a monorepo
+			var monorepoPath string
+			if opts.file != "" {
+				if len(args) > 0 {
+					return fmt.Errorf("--file cannot be combined with a monorepo path")
+				}
+				filePath, err := config.ResolveFilePath(opts.file)
+				if err != nil {
+					return err
+				}
+				opts.file = filePath
+			} else {
+				// Resolve monorepo path from args, env, or config
+				var cmdLineArg string
+				if len(args) > 0 {
+					cmdLineArg = args[0]
+				}
+				path, err := config.GetMonorepoPath(cmdLineArg)
+				if err != nil {
+					return err
+				}
+				monorepoPath = path
+			}
+
+			// The arguments are valid, so later failures (e.g., duplicates found) are not usage errors
+			cmd.SilenceUsage = true
+			return runComposables(monorepoPath, opts)
 		},
 	}
 
-	cmd.Flags().StringVar(&forProject, "for-project", "", "Only analyze composables for a specific project")
-	cmd.Flags().BoolVar(&currentOnly, "current-only", false, "Only analyze composables in current versions")
-	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show full option details with titles")
-	cmd.Flags().BoolVar(&findSimilar, "find-similar", false, "Show identical and similar composables for consolidation")
-	cmd.Flags().BoolVar(&findUsages, "find-usages", false, "Show where each composable is used in RST files")
-	cmd.Flags().BoolVar(&withRstspec, "with-rstspec", false, "Include composables from the canonical rstspec.toml file")
+	cmd.Flags().StringVar(&opts.forProject, "for-project", "", "Only analyze composables for a specific project")
+	cmd.Flags().BoolVar(&opts.currentOnly, "current-only", false, "Only analyze composables in current versions")
+	cmd.Flags().BoolVarP(&opts.verbose, "verbose", "v", false, "Show full option details with titles")
+	cmd.Flags().BoolVar(&opts.findSimilar, "find-similar", false, "Show identical and similar composables for consolidation")
+	cmd.Flags().BoolVar(&opts.findUsages, "find-usages", false, "Show where each composable is used in RST files")
+	cmd.Flags().BoolVar(&opts.withRstspec, "with-rstspec", false, "Include composables from the canonical rstspec.toml file")
+	cmd.Flags().BoolVar(&opts.failOnDuplicates, "fail-on-duplicates", false, "Exit with a non-zero status if any identical composables are found")
+	cmd.Flags().BoolVar(&opts.crossProjectOnly, "cross-project-only", false, "With --fail-on-duplicates, only fail on composables duplicated across projects")
+	cmd.Flags().StringVar(&opts.file, "file", "", "Analyze a single snooty.toml file instead of the monorepo")
+	cmd.Flags().StringVar(&opts.fileProject, "file-project", "", "With --file, the project name to report (default: derived from the path)")
+	cmd.Flags().StringVar(&opts.fileVersion, "file-version", "", "With --file, the version name to report (default: derived from the path)")
+	cmd.MarkFlagsMutuallyExclusive("file", "for-project")
+	cmd.MarkFlagsMutuallyExclusive("file", "current-only")
+	cmd.MarkFlagsMutuallyExclusive("file", "find-usages")
+	cmd.Flags().StringVar(&opts.compare, "compare", "", "Report composables added, removed, or changed in this other monorepo checkout")
+	cmd.Flags().StringVar(&opts.format, "format", "text", "With --compare, the output format (text or json)")
+	for _, flag := range []string{"file", "find-similar", "find-usages", "with-rstspec", "fail-on-duplicates"} {
+		cmd.MarkFlagsMutuallyExclusive("compare", flag)
+	}
+	cmd.Flags().Float64Var(&opts.similarityThreshold, "similarity-threshold", DefaultSimilarityThreshold, "Minimum option overlap (0.0-1.0) for composables to be grouped as similar")
 
 	return cmd
 }
 
+// composablesOptions holds the flag values for the composables command.
+type composablesOptions struct {
+	forProject  string
+	currentOnly bool
+	verbose     bool
+	findSimilar bool
+	findUsages  bool
+	withRstspec bool
+	// similarityThreshold is the minimum option overlap for similar composables.
+	similarityThreshold float64
+	failOnDuplicates    bool
+	crossProjectOnly    bool
+	// file is a single snooty.toml file to analyze instead of the monorepo.
+	file        string
+	fileProject string
+	fileVersion string
+	// compare is another monorepo checkout whose composables are compared to the monorepo's.
+	compare string
+	format  string
+}
+
 // runComposables executes the composables analysis operation.
-func runComposables(monorepoPath string, forProject string, currentOnly bool, verbose bool, findSimilar bool, findUsages bool, withRstspec bool) error {
-	// Find all snooty.toml files and extract composables
-	locations, err := FindSnootyTOMLFiles(monorepoPath, forProject, currentOnly)
-	if err != nil {
-		return fmt.Errorf("failed to find snooty.toml files: %w", err)
+// When opts.file is set, only that snooty.toml file is analyzed and monorepoPath is unused.
+func runComposables(monorepoPath string, opts composablesOptions) error {
+	if opts.compare != "" {
+		return runCompare(monorepoPath, opts)
+	}
+
+	var locations []ComposableLocation
+	var err error
+	if opts.file != "" {
+		locations, err = LoadSnootyTOMLFile(opts.file, opts.fileProject, opts.fileVersion)
+		if err != nil {
+			return err
+		}
+	} else {
+		// Find all snooty.toml files and extract composables
+		locations, err = FindSnootyTOMLFiles(monorepoPath, opts.forProject, opts.currentOnly)
+		if err != nil {
+			return fmt.Errorf("failed to find snooty.toml files: %w", err)
+		}
 	}
 
 	// Fetch rstspec.toml composables if requested
-	if withRstspec {
+	if opts.withRstspec {
 		fmt.Println("Fetching composables from rstspec.toml...")
 		rstspecLocations, err := FetchRstspecComposables()
 		if err != nil {
@@ -150,25 +268,65 @@ func runComposables(monorepoPath string, forProject string, currentOnly bool, ve
 	}
 
 	if len(locations) == 0 {
-		fmt.Println("No composables found in the monorepo.")
+		if opts.file != "" {
+			fmt.Printf("No composables found in %s.\n", opts.file)
+		} else {
+			fmt.Println("No composables found in the monorepo.")
+		}
 		return nil
 	}
 
 	// Analyze the composables
-	result := AnalyzeComposables(locations)
+	result := AnalyzeComposables(locations, opts.similarityThreshold)
 
 	// Find usages if requested
 	var usages map[string]*ComposableUsage
-	if findUsages {
-		usages, err = FindComposableUsages(monorepoPath, result.AllComposables, forProject, currentOnly)
+	if opts.findUsages {
+		usages, err = FindComposableUsages(monorepoPath, result.AllComposables, opts.forProject, opts.currentOnly)
 		if err != nil {
 			return fmt.Errorf("failed to find composable usages: %w", err)
 		}
 	}
 
 	// Print the results
-	PrintResults(result, verbose, findSimilar, findUsages, usages)
+	PrintResults(result, opts.verbose, opts.findSimilar, opts.findUsages, usages)
+
+	if opts.failOnDuplicates {
+		if duplicates := FindDuplicates(result, opts.crossProjectOnly); len(duplicates) > 0 {
+			PrintDuplicates(duplicates)
+			return fmt.Errorf("found %d duplicate composable(s)", len(duplicates))
+		}
+	}
 
 	return nil
 }
 
+// runCompare compares the composables in monorepoPath with those in opts.compare and
+// prints the differences.
+func runCompare(monorepoPath string, opts composablesOptions) error {
+	base, err := FindSnootyTOMLFiles(monorepoPath, opts.forProject, opts.currentOnly)
+	if err != nil {
+		return fmt.Errorf("failed to find snooty.toml files in %s: %w", monorepoPath, err)
+	}
+	other, err := FindSnootyTOMLFiles(opts.compare, opts.forProject, opts.currentOnly)
+	if err != nil {
+		return fmt.Errorf("failed to find snooty.toml files in %s: %w", opts.compare, err)
+	}
+
+	diff := CompareComposables(base, other)
+	diff.BasePath, diff.OtherPath = absOrSelf(monorepoPath), absOrSelf(opts.compare)
+
+	if opts.format == "json" {
+		return PrintComparisonJSON(os.Stdout, diff)
+	}
+	PrintComparison(os.Stdout, diff)
+	return nil
+}
+
+// absOrSelf returns the absolute form of path, or path itself if it can't be made absolute.
+func absOrSelf(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
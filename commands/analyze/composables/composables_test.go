@@ -2,9 +2,13 @@
 package composables
 
 import (
+	"bytes"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/grove-platform/audit-cli/internal/projectinfo"
 	"github.com/grove-platform/audit-cli/internal/snooty"
 )
 
@@ -129,6 +133,106 @@ func TestParseSnootyTOML(t *testing.T) {
 	}
 }
 
+// TestLoadSnootyTOMLFile tests loading composables from a single snooty.toml file.
+func TestLoadSnootyTOMLFile(t *testing.T) {
+	monorepoFile := filepath.Join("..", "..", "..", "testdata", "composables-test", "content", "project2", "v1.0", "snooty.toml")
+
+	// A draft file outside the monorepo layout
+	draftDir := filepath.Join(t.TempDir(), "my-draft")
+	if err := os.MkdirAll(draftDir, 0755); err != nil {
+		t.Fatalf("Failed to create draft dir: %v", err)
+	}
+	content, err := os.ReadFile(monorepoFile)
+	if err != nil {
+		t.Fatalf("Failed to read snooty.toml: %v", err)
+	}
+	draftFile := filepath.Join(draftDir, "snooty.toml")
+	if err := os.WriteFile(draftFile, content, 0644); err != nil {
+		t.Fatalf("Failed to write draft: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		filePath        string
+		project         string
+		version         string
+		expectedProject string
+		expectedVersion string
+	}{
+		{"derived from monorepo layout", monorepoFile, "", "", "project2", "v1.0"},
+		{"directory name outside monorepo", draftFile, "", "", "my-draft", ""},
+		{"explicit project and version", draftFile, "manual", "upcoming", "manual", "upcoming"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locations, err := LoadSnootyTOMLFile(tt.filePath, tt.project, tt.version)
+			if err != nil {
+				t.Fatalf("LoadSnootyTOMLFile failed: %v", err)
+			}
+			if len(locations) != 2 {
+				t.Fatalf("Expected 2 composables, got %d", len(locations))
+			}
+			for _, loc := range locations {
+				if loc.Project != tt.expectedProject || loc.Version != tt.expectedVersion {
+					t.Errorf("Expected %s/%s, got %s/%s", tt.expectedProject, tt.expectedVersion, loc.Project, loc.Version)
+				}
+				if !filepath.IsAbs(loc.FilePath) || loc.Source != "snooty.toml" {
+					t.Errorf("Unexpected location: %+v", loc)
+				}
+			}
+		})
+	}
+
+	if _, err := LoadSnootyTOMLFile(filepath.Join(draftDir, "missing.toml"), "", ""); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
+
+// TestFindComposableUsagesSkipsExcludedDirs tests that usages in excluded directories are ignored.
+func TestFindComposableUsagesSkipsExcludedDirs(t *testing.T) {
+	defer projectinfo.SetExcludedDirs(nil)
+
+	monorepo := t.TempDir()
+	page := ".. composable-tutorial::\n   :options: language\n"
+	for _, dir := range []string{"source", "source/node_modules/pkg", "source/snapshots"} {
+		dirPath := filepath.Join(monorepo, "content", "atlas", dir)
+		if err := os.MkdirAll(dirPath, 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dirPath, err)
+		}
+		if err := os.WriteFile(filepath.Join(dirPath, "page.txt"), []byte(page), 0644); err != nil {
+			t.Fatalf("failed to write page: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		excludeDirs   []string
+		expectedCount int
+	}{
+		{"defaults skip node_modules", nil, 2},
+		{"exclude-dir skips snapshots", []string{"snapshots"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectinfo.SetExcludedDirs(tt.excludeDirs)
+
+			usages, err := FindComposableUsages(monorepo, nil, "", false)
+			if err != nil {
+				t.Fatalf("FindComposableUsages failed: %v", err)
+			}
+			usage, ok := usages["atlas::::language"]
+			if !ok {
+				t.Fatalf("Expected usage of language composable, got %v", usages)
+			}
+			if usage.UsageCount != tt.expectedCount {
+				t.Errorf("Expected %d usages, got %d: %v", tt.expectedCount, usage.UsageCount, usage.FilePaths)
+			}
+		})
+	}
+}
+
 // TestAnalyzeComposables tests the analysis functionality.
 func TestAnalyzeComposables(t *testing.T) {
 	testDataDir := filepath.Join("..", "..", "..", "testdata", "composables-test")
@@ -138,7 +242,7 @@ func TestAnalyzeComposables(t *testing.T) {
 		t.Fatalf("FindSnootyTOMLFiles failed: %v", err)
 	}
 
-	result := AnalyzeComposables(locations)
+	result := AnalyzeComposables(locations, DefaultSimilarityThreshold)
 
 	// Check total composables
 	if len(result.AllComposables) != 6 {
@@ -155,7 +259,7 @@ func TestIdenticalComposables(t *testing.T) {
 		t.Fatalf("FindSnootyTOMLFiles failed: %v", err)
 	}
 
-	result := AnalyzeComposables(locations)
+	result := AnalyzeComposables(locations, DefaultSimilarityThreshold)
 
 	// Expected: "interface" composable appears 3 times identically
 	// (project1, project2/current, project2/v1.0)
@@ -175,6 +279,56 @@ func TestIdenticalComposables(t *testing.T) {
 	}
 }
 
+// TestUnusedComposablesStableOrder tests that the Unused Composables section is
+// byte-stable regardless of the order composables were found in.
+func TestUnusedComposablesStableOrder(t *testing.T) {
+	location := func(project, version, id string) ComposableLocation {
+		return ComposableLocation{Project: project, Version: version, Composable: snooty.Composable{ID: id}}
+	}
+	composables := []ComposableLocation{
+		location("project2", "v1.0", "language"),
+		location("project1", "", "interface"),
+		location("project2", "current", "language"),
+		location("project1", "", "language"),
+		location("project3", "v2.0", "interface"),
+		location("project2", "current", "interface"),
+		location("project3", "current", "interface"),
+	}
+	// interface is used in project2/current only
+	usagesByID := map[string][]*ComposableUsage{
+		"interface": {{ComposableID: "interface", Project: "project2", Version: "current", UsageCount: 1}},
+	}
+
+	expected := `Unused Composables
+------------------
+
+  interface:
+    - project1
+    - project3/current
+    - project3/v2.0
+  language:
+    - project1
+    - project2/current
+    - project2/v1.0
+
+`
+	// Rotate the input, and reverse every other rotation, to check the output does not depend on input order
+	for i := 0; i < len(composables); i++ {
+		ordered := append(append([]ComposableLocation{}, composables[i:]...), composables[:i]...)
+		if i%2 == 1 {
+			for l, r := 0, len(ordered)-1; l < r; l, r = l+1, r-1 {
+				ordered[l], ordered[r] = ordered[r], ordered[l]
+			}
+		}
+
+		var buf bytes.Buffer
+		printUnusedComposables(&buf, findUnusedComposables(ordered, usagesByID))
+		if buf.String() != expected {
+			t.Fatalf("Input order %d: expected:\n%s\ngot:\n%s", i, expected, buf.String())
+		}
+	}
+}
+
 // TestSimilarComposables tests detection of similar composables with different IDs.
 // Note: The current test data doesn't have composables with different IDs but similar options,
 // so we don't expect any similar groups. This test verifies the analysis runs without error.
@@ -186,7 +340,7 @@ func TestSimilarComposables(t *testing.T) {
 		t.Fatalf("FindSnootyTOMLFiles failed: %v", err)
 	}
 
-	result := AnalyzeComposables(locations)
+	result := AnalyzeComposables(locations, DefaultSimilarityThreshold)
 
 	// With current test data, we don't expect similar groups
 	// (no composables with different IDs but similar option sets)
@@ -211,6 +365,214 @@ func TestSimilarComposables(t *testing.T) {
 	}
 }
 
+// TestBuildConsolidationPlan tests canonical nomination for identical groups and
+// option supersets for similar groups.
+func TestBuildConsolidationPlan(t *testing.T) {
+	testDataDir := filepath.Join("..", "..", "..", "testdata", "composables-test")
+
+	locations, err := FindSnootyTOMLFiles(testDataDir, "", false)
+	if err != nil {
+		t.Fatalf("FindSnootyTOMLFiles failed: %v", err)
+	}
+
+	t.Run("project defined in the most versions", func(t *testing.T) {
+		plan := BuildConsolidationPlan(AnalyzeComposables(locations, DefaultSimilarityThreshold), nil)
+
+		if len(plan.Canonical) != 1 {
+			t.Fatalf("Expected 1 canonical recommendation, got %d", len(plan.Canonical))
+		}
+		rec := plan.Canonical[0]
+		// project2 defines interface in two versions; the current one is kept
+		if rec.Canonical.Project != "project2" || rec.Canonical.Version != "current" {
+			t.Errorf("Expected project2/current to be canonical, got %s", formatLocation(rec.Canonical))
+		}
+		if rec.Reason != "defined in the most versions (2)" {
+			t.Errorf("Unexpected reason: %s", rec.Reason)
+		}
+		if len(rec.Redundant) != 2 {
+			t.Fatalf("Expected 2 redundant definitions, got %d", len(rec.Redundant))
+		}
+		if formatLocation(rec.Redundant[0]) != "project1" || formatLocation(rec.Redundant[1]) != "project2/v1.0" {
+			t.Errorf("Unexpected redundant definitions: %s, %s", formatLocation(rec.Redundant[0]), formatLocation(rec.Redundant[1]))
+		}
+	})
+
+	t.Run("most used project", func(t *testing.T) {
+		usages := map[string]*ComposableUsage{
+			"interface:project1:":        {ComposableID: "interface", Project: "project1", UsageCount: 5},
+			"interface:project2:current": {ComposableID: "interface", Project: "project2", Version: "current", UsageCount: 2},
+		}
+		plan := BuildConsolidationPlan(AnalyzeComposables(locations, DefaultSimilarityThreshold), usages)
+
+		rec := plan.Canonical[0]
+		if rec.Canonical.Project != "project1" {
+			t.Errorf("Expected project1 to be canonical, got %s", formatLocation(rec.Canonical))
+		}
+		if rec.Reason != "most used project (5 usages)" {
+			t.Errorf("Unexpected reason: %s", rec.Reason)
+		}
+	})
+
+	t.Run("rstspec definition wins", func(t *testing.T) {
+		withRstspec := append([]ComposableLocation{}, locations...)
+		for _, loc := range locations {
+			if loc.Composable.ID == "interface" {
+				withRstspec = append(withRstspec, ComposableLocation{
+					Project:    "rstspec",
+					Composable: loc.Composable,
+					Source:     "rstspec.toml",
+				})
+				break
+			}
+		}
+		plan := BuildConsolidationPlan(AnalyzeComposables(withRstspec, DefaultSimilarityThreshold), nil)
+
+		rec := plan.Canonical[0]
+		if rec.Canonical.Source != "rstspec.toml" || rec.Reason != "defined in rstspec.toml" {
+			t.Errorf("Expected rstspec.toml to be canonical, got %s (%s)", formatLocation(rec.Canonical), rec.Reason)
+		}
+		if len(rec.Redundant) != 3 {
+			t.Errorf("Expected 3 redundant definitions, got %d", len(rec.Redundant))
+		}
+	})
+
+	t.Run("versions compare by number", func(t *testing.T) {
+		composable := snooty.Composable{ID: "interface", Options: []snooty.ComposableOption{{ID: "driver", Title: "Driver"}}}
+		result := &AnalysisResult{
+			IdenticalGroups: []ComposableGroup{{
+				ID: "interface",
+				Locations: []ComposableLocation{
+					{Project: "manual", Version: "v10.0", Composable: composable},
+					{Project: "manual", Version: "v9.0", Composable: composable},
+					{Project: "manual", Version: "v11.0", Composable: composable},
+				},
+			}},
+		}
+		plan := BuildConsolidationPlan(result, nil)
+
+		rec := plan.Canonical[0]
+		if rec.Canonical.Version != "v11.0" {
+			t.Errorf("Expected the newest version, manual/v11.0, to be canonical, got %s", formatLocation(rec.Canonical))
+		}
+		if len(rec.Redundant) != 2 || rec.Redundant[0].Version != "v9.0" || rec.Redundant[1].Version != "v10.0" {
+			t.Errorf("Expected redundant v9.0 then v10.0, got %v", rec.Redundant)
+		}
+	})
+
+	t.Run("similar group superset", func(t *testing.T) {
+		result := &AnalysisResult{
+			SimilarGroups: []ComposableGroup{{
+				ID: "deployment",
+				Locations: []ComposableLocation{
+					{Project: "atlas", Composable: snooty.Composable{ID: "deployment", Options: []snooty.ComposableOption{
+						{ID: "atlas", Title: "Atlas"}, {ID: "local", Title: "Local"},
+					}}},
+					{Project: "manual", Composable: snooty.Composable{ID: "deployment-type", Options: []snooty.ComposableOption{
+						{ID: "atlas", Title: "Atlas"}, {ID: "local", Title: "Local"}, {ID: "self", Title: "Self-Managed"},
+					}}},
+				},
+			}},
+		}
+		plan := BuildConsolidationPlan(result, nil)
+
+		if len(plan.Supersets) != 1 {
+			t.Fatalf("Expected 1 superset recommendation, got %d", len(plan.Supersets))
+		}
+		rec := plan.Supersets[0]
+		if len(rec.IDs) != 2 || rec.IDs[0] != "deployment" || rec.IDs[1] != "deployment-type" {
+			t.Errorf("Unexpected IDs: %v", rec.IDs)
+		}
+		if got := formatOptions(rec.Options); got != "atlas, local, self" {
+			t.Errorf("Expected superset options 'atlas, local, self', got %q", got)
+		}
+		if len(rec.AddedOptions) != 1 || rec.AddedOptions[0] != "self" {
+			t.Errorf("Expected added option 'self', got %v", rec.AddedOptions)
+		}
+	})
+}
+
+// TestFindDuplicates tests which identical groups fail --fail-on-duplicates.
+func TestFindDuplicates(t *testing.T) {
+	testDataDir := filepath.Join("..", "..", "..", "testdata", "composables-test")
+
+	// interface is identical in project1, project2/current, and project2/v1.0
+	locations, err := FindSnootyTOMLFiles(testDataDir, "", false)
+	if err != nil {
+		t.Fatalf("FindSnootyTOMLFiles failed: %v", err)
+	}
+	result := AnalyzeComposables(locations, DefaultSimilarityThreshold)
+
+	duplicates := FindDuplicates(result, false)
+	if len(duplicates) != 1 || duplicates[0].ID != "interface" {
+		t.Errorf("Expected interface to be a duplicate, got %v", duplicates)
+	}
+	duplicates = FindDuplicates(result, true)
+	if len(duplicates) != 1 {
+		t.Errorf("Expected interface to be a cross-project duplicate, got %v", duplicates)
+	}
+
+	// project2 alone repeats interface across its versions
+	project2, err := FindSnootyTOMLFiles(testDataDir, "project2", false)
+	if err != nil {
+		t.Fatalf("FindSnootyTOMLFiles failed: %v", err)
+	}
+	result = AnalyzeComposables(project2, DefaultSimilarityThreshold)
+
+	if duplicates := FindDuplicates(result, false); len(duplicates) == 0 {
+		t.Error("Expected duplicates across project2 versions")
+	}
+	if duplicates := FindDuplicates(result, true); len(duplicates) != 0 {
+		t.Errorf("Expected no cross-project duplicates within project2, got %v", duplicates)
+	}
+}
+
+// TestSimilarityThreshold tests that the threshold decides which composables are grouped as similar.
+func TestSimilarityThreshold(t *testing.T) {
+	// a/b share 2 of 3 options (0.667); a/c share 1 of 4 options (0.25)
+	locations := []ComposableLocation{
+		{Project: "project1", Composable: snooty.Composable{ID: "a", Options: []snooty.ComposableOption{
+			{ID: "x"}, {ID: "y"}, {ID: "z"},
+		}}},
+		{Project: "project2", Composable: snooty.Composable{ID: "b", Options: []snooty.ComposableOption{
+			{ID: "x"}, {ID: "y"},
+		}}},
+		{Project: "project3", Composable: snooty.Composable{ID: "c", Options: []snooty.ComposableOption{
+			{ID: "x"}, {ID: "w"},
+		}}},
+	}
+
+	tests := []struct {
+		threshold     float64
+		expectedGroup []string // IDs in the similar group; nil for no group
+	}{
+		{threshold: 0.7, expectedGroup: nil},
+		{threshold: 0.6, expectedGroup: []string{"a", "b"}},
+		{threshold: 0.2, expectedGroup: []string{"a", "b", "c"}},
+	}
+
+	for _, tt := range tests {
+		result := AnalyzeComposables(locations, tt.threshold)
+
+		if tt.expectedGroup == nil {
+			if len(result.SimilarGroups) != 0 {
+				t.Errorf("threshold %.1f: expected no similar groups, got %d", tt.threshold, len(result.SimilarGroups))
+			}
+			continue
+		}
+
+		if len(result.SimilarGroups) != 1 {
+			t.Fatalf("threshold %.1f: expected 1 similar group, got %d", tt.threshold, len(result.SimilarGroups))
+		}
+		var ids []string
+		for _, loc := range result.SimilarGroups[0].Locations {
+			ids = append(ids, loc.Composable.ID)
+		}
+		if strings.Join(ids, ",") != strings.Join(tt.expectedGroup, ",") {
+			t.Errorf("threshold %.1f: expected group %v, got %v", tt.threshold, tt.expectedGroup, ids)
+		}
+	}
+}
+
 // TestCalculateOptionSimilarity tests the Jaccard similarity calculation.
 func TestCalculateOptionSimilarity(t *testing.T) {
 	// Test identical option sets
@@ -315,6 +677,120 @@ func TestComposablesEqual(t *testing.T) {
 	}
 }
 
+// TestCompareComposables tests reporting composables added, removed, and changed between two checkouts.
+func TestCompareComposables(t *testing.T) {
+	baseDir := filepath.Join("..", "..", "..", "testdata", "composables-test")
+
+	// The other checkout changes project1's language composable, adds project3, and drops project2
+	otherDir := t.TempDir()
+	project1 := `name = "project1"
+
+[[composables]]
+id = "interface"
+title = "Interface"
+default = "driver"
+options = [
+  {id = "atlas-ui", title = "Atlas UI"},
+  {id = "driver", title = "Driver"},
+  {id = "mongosh", title = "MongoDB Shell"},
+]
+
+[[composables]]
+id = "language"
+title = "Language"
+default = "nodejs"
+options = [
+  {id = "python", title = "Python 3"},
+  {id = "nodejs", title = "Node.js"},
+  {id = "rust", title = "Rust"},
+]
+`
+	project3 := `name = "project3"
+
+[[composables]]
+id = "deployment-type"
+title = "Deployment Type"
+options = [{id = "atlas", title = "Atlas"}]
+`
+	for path, content := range map[string]string{
+		filepath.Join("content", "project1", "snooty.toml"): project1,
+		filepath.Join("content", "project3", "snooty.toml"): project3,
+	} {
+		fullPath := filepath.Join(otherDir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	base, err := FindSnootyTOMLFiles(baseDir, "", false)
+	if err != nil {
+		t.Fatalf("FindSnootyTOMLFiles failed: %v", err)
+	}
+	other, err := FindSnootyTOMLFiles(otherDir, "", false)
+	if err != nil {
+		t.Fatalf("FindSnootyTOMLFiles failed: %v", err)
+	}
+
+	diff := CompareComposables(base, other)
+	if len(diff.Added) != 1 || diff.Added[0].Project != "project3" || diff.Added[0].Composable.ID != "deployment-type" {
+		t.Errorf("Unexpected added composables: %+v", diff.Added)
+	}
+	for _, loc := range diff.Removed {
+		if loc.Project != "project2" {
+			t.Errorf("Expected only project2's composables to be removed, got %s in %s", loc.Composable.ID, formatLocation(loc))
+		}
+	}
+	if len(diff.Removed) == 0 {
+		t.Error("Expected project2's composables to be removed")
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].ID != "language" || diff.Changed[0].Project != "project1" {
+		t.Fatalf("Unexpected changed composables: %+v", diff.Changed)
+	}
+	expectedDetails := []string{
+		`default "python" -> "nodejs"`,
+		"added option rust (Rust)",
+		`option python title "Python" -> "Python 3"`,
+		"removed option java (Java)",
+	}
+	if got := diff.Changed[0].Details; strings.Join(got, "\n") != strings.Join(expectedDetails, "\n") {
+		t.Errorf("Details = %q, want %q", got, expectedDetails)
+	}
+
+	var buf bytes.Buffer
+	PrintComparison(&buf, diff)
+	for _, want := range []string{"+ deployment-type in project3", "- interface in project2/current", "~ language in project1", `default "python" -> "nodejs"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected text output to contain %q, got:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := PrintComparisonJSON(&buf, diff); err != nil {
+		t.Fatalf("PrintComparisonJSON failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"Changed": [`) || !strings.Contains(buf.String(), `"added option rust (Rust)"`) {
+		t.Errorf("Unexpected JSON output: %s", buf.String())
+	}
+
+	// Identical checkouts have no changes
+	if same := CompareComposables(base, base); same.HasChanges() {
+		t.Errorf("Expected no changes comparing a checkout with itself, got %+v", same)
+	}
+
+	// Versions sort by number, so v9.0 comes before v10.0
+	interfaceComposable := snooty.Composable{ID: "interface"}
+	added := CompareComposables(nil, []ComposableLocation{
+		{Project: "manual", Version: "v10.0", Composable: interfaceComposable},
+		{Project: "manual", Version: "v9.0", Composable: interfaceComposable},
+	}).Added
+	if len(added) != 2 || added[0].Version != "v9.0" || added[1].Version != "v10.0" {
+		t.Errorf("Expected v9.0 then v10.0, got %v", added)
+	}
+}
+
 // TestExtractProjectAndVersion tests the project and version extraction.
 func TestExtractProjectAndVersion(t *testing.T) {
 	tests := []struct {
@@ -3,6 +3,8 @@ package composables
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"sort"
 	"strings"
 
@@ -47,6 +49,13 @@ func PrintResults(result *AnalysisResult, verbose bool, findSimilar bool, findUs
 				}
 			}
 		}
+
+		// Print what to do about them
+		if len(result.IdenticalGroups) > 0 || len(result.SimilarGroups) > 0 {
+			fmt.Printf("\nConsolidation Plan\n")
+			fmt.Printf("==================\n\n")
+			printConsolidationPlan(BuildConsolidationPlan(result, usages))
+		}
 	}
 
 	// Print usage information if requested
@@ -155,6 +164,50 @@ func printComposableGroup(group ComposableGroup, isIdentical bool, verbose bool)
 
 }
 
+// printConsolidationPlan prints the canonical definition to keep for each identical group
+// and the merged option set for each similar group.
+func printConsolidationPlan(plan *ConsolidationPlan) {
+	for _, rec := range plan.Canonical {
+		fmt.Printf("%s:\n", rec.ID)
+		fmt.Printf("  Keep:   %s (%s) - %s\n", formatLocation(rec.Canonical), rec.Canonical.Source, rec.Reason)
+		for _, loc := range rec.Redundant {
+			fmt.Printf("  Delete: %s (%s)\n", formatLocation(loc), loc.FilePath)
+		}
+		fmt.Printf("\n")
+	}
+
+	for _, rec := range plan.Supersets {
+		fmt.Printf("%s:\n", strings.Join(rec.IDs, ", "))
+		fmt.Printf("  Merge into one composable with options: %s\n", formatOptions(rec.Options))
+		if len(rec.AddedOptions) > 0 {
+			fmt.Printf("  Options not defined by every composable: %s\n", strings.Join(rec.AddedOptions, ", "))
+		}
+		fmt.Printf("\n")
+	}
+}
+
+// PrintDuplicates prints the identical composables that failed a --fail-on-duplicates check.
+func PrintDuplicates(duplicates []ComposableGroup) {
+	fmt.Printf("\nDuplicate Composables\n")
+	fmt.Printf("=====================\n\n")
+	for _, group := range duplicates {
+		fmt.Printf("%s:\n", group.ID)
+		locs := append([]ComposableLocation{}, group.Locations...)
+		sortLocations(locs)
+		for _, loc := range locs {
+			fmt.Printf("  - %s (%s)\n", formatLocation(loc), loc.FilePath)
+		}
+	}
+}
+
+// formatLocation formats a location as project or project/version.
+func formatLocation(loc ComposableLocation) string {
+	if loc.Version != "" {
+		return loc.Project + "/" + loc.Version
+	}
+	return loc.Project
+}
+
 // printAllComposablesTable prints all composables in a table format.
 func printAllComposablesTable(locations []ComposableLocation, verbose bool) {
 	// Sort by project, version, then ID
@@ -418,40 +471,34 @@ func printUsageInformation(composables []ComposableLocation, usages map[string]*
 	}
 
 	// Print composables with no usages
-	unusedComposables := findUnusedComposables(composables, usagesByID)
-	if len(unusedComposables) > 0 {
-		fmt.Printf("Unused Composables\n")
-		fmt.Printf("------------------\n\n")
-
-		// Group by ID
-		unusedByID := make(map[string][]ComposableLocation)
-		for _, loc := range unusedComposables {
-			unusedByID[loc.Composable.ID] = append(unusedByID[loc.Composable.ID], loc)
-		}
+	printUnusedComposables(os.Stdout, findUnusedComposables(composables, usagesByID))
+}
 
-		// Sort IDs
-		var unusedIDs []string
-		for id := range unusedByID {
-			unusedIDs = append(unusedIDs, id)
+// printUnusedComposables prints the "Unused Composables" section, grouped by composable ID.
+// The unused locations must be sorted as findUnusedComposables returns them, so the
+// section is byte-stable across runs.
+func printUnusedComposables(w io.Writer, unused []ComposableLocation) {
+	if len(unused) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "Unused Composables\n")
+	fmt.Fprintf(w, "------------------\n\n")
+
+	for i, loc := range unused {
+		if i == 0 || unused[i-1].Composable.ID != loc.Composable.ID {
+			fmt.Fprintf(w, "  %s:\n", loc.Composable.ID)
 		}
-		sort.Strings(unusedIDs)
-
-		for _, id := range unusedIDs {
-			locations := unusedByID[id]
-			fmt.Printf("  %s:\n", id)
-			for _, loc := range locations {
-				location := loc.Project
-				if loc.Version != "" {
-					location += "/" + loc.Version
-				}
-				fmt.Printf("    - %s\n", location)
-			}
+		location := loc.Project
+		if loc.Version != "" {
+			location += "/" + loc.Version
 		}
-		fmt.Printf("\n")
+		fmt.Fprintf(w, "    - %s\n", location)
 	}
+	fmt.Fprintf(w, "\n")
 }
 
-// findUnusedComposables finds composables that have no usages.
+// findUnusedComposables finds composables that have no usages, sorted by composable ID,
+// then project, then version.
 func findUnusedComposables(composables []ComposableLocation, usagesByID map[string][]*ComposableUsage) []ComposableLocation {
 	var unused []ComposableLocation
 
@@ -472,5 +519,11 @@ func findUnusedComposables(composables []ComposableLocation, usagesByID map[stri
 		}
 	}
 
+	// Group by ID, with each ID's locations in project/version order
+	sortLocations(unused)
+	sort.SliceStable(unused, func(i, j int) bool {
+		return unused[i].Composable.ID < unused[j].Composable.ID
+	})
+
 	return unused
 }
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/grove-platform/audit-cli/internal/projectinfo"
 	"github.com/grove-platform/audit-cli/internal/snooty"
 )
 
@@ -57,9 +58,9 @@ func FindSnootyTOMLFiles(monorepoPath string, forProject string, currentOnly boo
 			return err
 		}
 
-		// Skip directories
+		// Skip directories, and don't descend into excluded ones
 		if info.IsDir() {
-			return nil
+			return projectinfo.SkipExcludedDir(contentDir, path, info.Name())
 		}
 
 		// Only process snooty.toml files
@@ -118,6 +119,74 @@ func FindSnootyTOMLFiles(monorepoPath string, forProject string, currentOnly boo
 	return locations, nil
 }
 
+// LoadSnootyTOMLFile loads the composables from a single snooty.toml file.
+//
+// This lets a draft snooty.toml be analyzed without a monorepo. The project and version
+// are derived from the path when it is under a content directory (e.g.,
+// content/manual/v8.0/snooty.toml -> "manual", "v8.0"); otherwise the project is the
+// name of the file's directory and the version is empty. Non-empty project and version
+// arguments override the derived values.
+//
+// Parameters:
+//   - filePath: Path to the snooty.toml file
+//   - project: Project name to use instead of the derived one (may be empty)
+//   - version: Version name to use instead of the derived one (may be empty)
+//
+// Returns:
+//   - []ComposableLocation: The composables in the file with their location
+//   - error: Error if the file cannot be read or parsed
+func LoadSnootyTOMLFile(filePath string, project string, version string) ([]ComposableLocation, error) {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	composables, err := ParseSnootyTOML(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+
+	derivedProject, derivedVersion := projectAndVersionFromPath(absPath)
+	if project == "" {
+		project = derivedProject
+	}
+	if version == "" {
+		version = derivedVersion
+	}
+
+	locations := make([]ComposableLocation, 0, len(composables))
+	for _, comp := range composables {
+		locations = append(locations, ComposableLocation{
+			Project:    project,
+			Version:    version,
+			Composable: comp,
+			FilePath:   absPath,
+			Source:     "snooty.toml",
+		})
+	}
+	return locations, nil
+}
+
+// projectAndVersionFromPath derives the project and version for a snooty.toml file.
+// Paths under a content directory use the monorepo layout; any other path uses the
+// file's directory name as the project.
+func projectAndVersionFromPath(absPath string) (string, string) {
+	for dir := filepath.Dir(absPath); dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		if filepath.Base(dir) != "content" {
+			continue
+		}
+		relPath, err := filepath.Rel(dir, absPath)
+		if err != nil {
+			break
+		}
+		if project, version := snooty.ExtractProjectAndVersion(relPath); project != "" {
+			return project, version
+		}
+		break
+	}
+	return filepath.Base(filepath.Dir(absPath)), ""
+}
+
 // findContentDirectory finds the content directory from the given path.
 func findContentDirectory(dirPath string) (string, error) {
 	// Check if this is already a content directory
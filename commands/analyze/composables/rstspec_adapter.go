@@ -19,7 +19,7 @@ import (
 //   - An error if the fetch or parse fails
 func FetchRstspecComposables() ([]ComposableLocation, error) {
 	// Fetch the rstspec.toml file using the internal/rst package
-	config, err := rst.FetchRstspec()
+	config, err := rst.GetRstspec()
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch rstspec.toml: %w", err)
 	}
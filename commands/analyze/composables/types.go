@@ -32,6 +32,34 @@ type AnalysisResult struct {
 	SimilarGroups []ComposableGroup
 }
 
+// ConsolidationPlan recommends how to consolidate identical and similar composables.
+type ConsolidationPlan struct {
+	// One recommendation per identical group
+	Canonical []CanonicalRecommendation
+	// One recommendation per similar group
+	Supersets []SupersetRecommendation
+}
+
+// CanonicalRecommendation nominates one definition of an identical composable to keep.
+type CanonicalRecommendation struct {
+	ID        string
+	Canonical ComposableLocation
+	// Why Canonical was chosen (e.g., "defined in rstspec.toml")
+	Reason string
+	// Definitions that duplicate Canonical and can be deleted
+	Redundant []ComposableLocation
+}
+
+// SupersetRecommendation suggests merging similar composables into one with every option.
+type SupersetRecommendation struct {
+	// IDs of the similar composables, sorted
+	IDs []string
+	// Union of the options of every composable in the group, sorted by option ID
+	Options []snooty.ComposableOption
+	// Options not defined by every composable in the group, sorted by option ID
+	AddedOptions []string
+}
+
 // ComposableUsage tracks where a composable is used in RST files.
 type ComposableUsage struct {
 	ComposableID string
@@ -31,9 +31,9 @@ func FindComposableUsages(monorepoPath string, composables []ComposableLocation,
 			return err
 		}
 
-		// Skip directories
+		// Skip directories, and don't descend into excluded ones
 		if info.IsDir() {
-			return nil
+			return projectinfo.SkipExcludedDir(contentDir, path, info.Name())
 		}
 
 		// Only process .txt and .rst files
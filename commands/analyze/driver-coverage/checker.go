@@ -0,0 +1,46 @@
+// Package drivercoverage provides functionality for checking driver readiness for an audit.
+package drivercoverage
+
+import (
+	"sort"
+
+	"github.com/grove-platform/audit-cli/internal/config"
+)
+
+// CheckDrivers cross-references each driver slug with its project, its content
+// directory, and the drivers with test infrastructure.
+//
+// Parameters:
+//   - mapping: URL mapping with driver slugs and the monorepo's content directories
+//   - testableDrivers: Project names of drivers with test infrastructure
+//
+// Returns:
+//   - *CoverageReport: Every driver slug with its status
+func CheckDrivers(mapping *config.URLMapping, testableDrivers map[string]bool) *CoverageReport {
+	report := &CoverageReport{
+		MonorepoPath:       mapping.MonorepoPath,
+		UsedStaticFallback: mapping.UsedStaticFallback,
+	}
+
+	for _, slug := range mapping.GetDriverSlugs() {
+		driver := Driver{Slug: slug, Project: mapping.URLSlugToProject[slug]}
+		driver.ContentDir = mapping.ProjectToContentDir[driver.Project]
+		switch {
+		case driver.Project == "" || driver.ContentDir == "":
+			driver.Status = NoContent
+		case !testableDrivers[driver.Project]:
+			driver.Status = NoTestInfra
+		default:
+			driver.Status = Covered
+		}
+		report.Drivers = append(report.Drivers, driver)
+	}
+
+	sort.Slice(report.Drivers, func(i, j int) bool {
+		if report.Drivers[i].Project != report.Drivers[j].Project {
+			return report.Drivers[i].Project < report.Drivers[j].Project
+		}
+		return report.Drivers[i].Slug < report.Drivers[j].Slug
+	})
+	return report
+}
@@ -0,0 +1,92 @@
+// Package drivercoverage provides functionality for checking driver readiness for an audit.
+//
+// This package implements the "analyze driver-coverage" subcommand, which checks every
+// driver URL slug from the Snooty Data API against the monorepo before a driver-wide
+// audit (report testable-code --filter drivers). A driver whose project has no content
+// directory in the checkout fails on every page, and a driver without test
+// infrastructure can't have tested examples, so both are worth knowing up front.
+package drivercoverage
+
+import (
+	"os"
+
+	testablecode "github.com/grove-platform/audit-cli/commands/report/testable-code"
+	"github.com/grove-platform/audit-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// NewDriverCoverageCommand creates the driver-coverage subcommand for analysis.
+//
+// This command resolves each driver slug to its snooty project and content directory
+// and reports:
+//   - Drivers with no local content directory
+//   - Drivers with content but no test infrastructure (not in TestableDrivers)
+//   - Fully covered drivers
+//
+// Usage:
+//
+//	analyze driver-coverage /path/to/docs-monorepo
+//	analyze driver-coverage
+func NewDriverCoverageCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "driver-coverage [monorepo-path]",
+		Short: "Check that every driver has local content and test infrastructure",
+		Long: `Check which drivers are ready for a driver-wide audit.
+
+Each driver URL slug from the Snooty Data API (see report testable-code --list-drivers)
+is resolved to its snooty project, and the project to a content directory in the
+monorepo. Drivers are reported in three groups:
+  - No local content: the project has no content directory with a snooty.toml in the
+    monorepo, so every page of the driver fails to resolve
+  - Content but no test infrastructure: the driver isn't in TestableDrivers (including
+    testable_drivers in .audit-cli.yaml), so none of its examples count as testable
+  - Fully covered: local content and test infrastructure
+
+Monorepo Path Configuration:
+  The monorepo path can be specified in three ways (in order of priority):
+    1. Command-line argument: analyze driver-coverage /path/to/monorepo
+    2. Environment variable: export AUDIT_CLI_MONOREPO_PATH=/path/to/monorepo
+    3. Config file (.audit-cli.yaml):
+       monorepo_path: /path/to/monorepo
+
+Examples:
+  # Check drivers before running report testable-code --filter drivers
+  analyze driver-coverage /path/to/docs-monorepo
+
+  # Use configured monorepo path
+  analyze driver-coverage`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Resolve monorepo path from args, env, or config
+			var cmdLineArg string
+			if len(args) > 0 {
+				cmdLineArg = args[0]
+			}
+			monorepoPath, err := config.GetMonorepoPath(cmdLineArg)
+			if err != nil {
+				return err
+			}
+			return runDriverCoverage(monorepoPath)
+		},
+	}
+
+	return cmd
+}
+
+// runDriverCoverage loads the URL mapping and prints each driver's readiness.
+func runDriverCoverage(monorepoPath string) error {
+	// Match report testable-code's idea of which drivers are testable
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	testablecode.ApplyTestableOverrides(cfg)
+
+	mapping, err := config.GetURLMapping(monorepoPath)
+	if err != nil {
+		return err
+	}
+
+	PrintReport(os.Stdout, CheckDrivers(mapping, testablecode.TestableDrivers))
+	return nil
+}
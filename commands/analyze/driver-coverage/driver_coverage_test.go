@@ -0,0 +1,80 @@
+// Package drivercoverage provides tests for the driver-coverage subcommand.
+package drivercoverage
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/grove-platform/audit-cli/internal/config"
+)
+
+// TestCheckDrivers tests grouping driver slugs by local content and test infrastructure.
+func TestCheckDrivers(t *testing.T) {
+	mapping := &config.URLMapping{
+		URLSlugToProject: map[string]string{
+			"drivers/go":     "golang",
+			"drivers/rust":   "rust",
+			"drivers/kotlin": "kotlin",
+		},
+		ProjectToContentDir: map[string]string{
+			"golang": "golang",
+			"rust":   "rust",
+		},
+		DriverSlugs:  []string{"drivers/go", "drivers/kotlin", "drivers/rust", "drivers/unmapped"},
+		MonorepoPath: "/docs-monorepo",
+	}
+
+	report := CheckDrivers(mapping, map[string]bool{"golang": true, "kotlin": true})
+
+	expected := map[string]Status{
+		"drivers/go":       Covered,
+		"drivers/rust":     NoTestInfra,
+		"drivers/kotlin":   NoContent, // Testable, but not checked out
+		"drivers/unmapped": NoContent,
+	}
+	if len(report.Drivers) != len(expected) {
+		t.Fatalf("Expected %d drivers, got %d: %+v", len(expected), len(report.Drivers), report.Drivers)
+	}
+	for _, d := range report.Drivers {
+		if d.Status != expected[d.Slug] {
+			t.Errorf("%s: expected %s, got %s", d.Slug, expected[d.Slug], d.Status)
+		}
+	}
+
+	// Sorted by project, with unmapped slugs first
+	if report.Drivers[0].Slug != "drivers/unmapped" || report.Drivers[1].Project != "golang" {
+		t.Errorf("Expected drivers sorted by project, got %+v", report.Drivers)
+	}
+	if got := report.ByStatus(Covered); len(got) != 1 || got[0].ContentDir != "golang" {
+		t.Errorf("Expected golang to be the only covered driver, got %+v", got)
+	}
+}
+
+// TestPrintReport tests that problem groups are printed before covered drivers.
+func TestPrintReport(t *testing.T) {
+	report := &CoverageReport{
+		MonorepoPath: "/docs-monorepo",
+		Drivers: []Driver{
+			{Slug: "drivers/go", Project: "golang", ContentDir: "golang", Status: Covered},
+			{Slug: "drivers/kotlin", Project: "kotlin", Status: NoContent},
+		},
+	}
+
+	var buf bytes.Buffer
+	PrintReport(&buf, report)
+	output := buf.String()
+
+	noContent := strings.Index(output, "NO LOCAL CONTENT (pages will fail to resolve): 1")
+	noTestInfra := strings.Index(output, "CONTENT BUT NO TEST INFRASTRUCTURE: 0")
+	covered := strings.Index(output, "FULLY COVERED: 1")
+	if noContent == -1 || noTestInfra == -1 || covered == -1 {
+		t.Fatalf("Expected a heading with a count for each status, got:\n%s", output)
+	}
+	if !(noContent < noTestInfra && noTestInfra < covered) {
+		t.Errorf("Expected problems before covered drivers, got:\n%s", output)
+	}
+	if !strings.Contains(output, "kotlin") || !strings.Contains(output, "(none)") {
+		t.Errorf("Expected kotlin listed and an empty group marked (none), got:\n%s", output)
+	}
+}
@@ -0,0 +1,53 @@
+// Package drivercoverage provides functionality for checking driver readiness for an audit.
+package drivercoverage
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// statusHeadings are the section headings for each status.
+var statusHeadings = map[Status]string{
+	NoContent:   "NO LOCAL CONTENT (pages will fail to resolve)",
+	NoTestInfra: "CONTENT BUT NO TEST INFRASTRUCTURE",
+	Covered:     "FULLY COVERED",
+}
+
+// PrintReport prints the drivers grouped by status, problems first.
+//
+// Parameters:
+//   - w: Writer for the output
+//   - report: The driver coverage results
+func PrintReport(w io.Writer, report *CoverageReport) {
+	fmt.Fprintln(w, strings.Repeat("=", 80))
+	fmt.Fprintln(w, "DRIVER COVERAGE")
+	fmt.Fprintln(w, strings.Repeat("=", 80))
+	fmt.Fprintf(w, "Monorepo: %s\n", report.MonorepoPath)
+	fmt.Fprintf(w, "Driver slugs: %d\n", len(report.Drivers))
+	if report.UsedStaticFallback {
+		fmt.Fprintln(w, "Warning: the Snooty Data API was unavailable; driver slugs are from the built-in fallback")
+	}
+
+	for _, status := range statusOrder {
+		drivers := report.ByStatus(status)
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "%s: %d\n", statusHeadings[status], len(drivers))
+		fmt.Fprintln(w, strings.Repeat("-", 80))
+		if len(drivers) == 0 {
+			fmt.Fprintln(w, "  (none)")
+			continue
+		}
+		for _, d := range drivers {
+			project := d.Project
+			if project == "" {
+				project = "(no project)"
+			}
+			contentDir := d.ContentDir
+			if contentDir == "" {
+				contentDir = "-"
+			}
+			fmt.Fprintf(w, "  %-25s %-35s %s\n", project, d.Slug, contentDir)
+		}
+	}
+}
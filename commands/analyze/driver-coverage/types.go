@@ -0,0 +1,46 @@
+// Package drivercoverage provides functionality for checking driver readiness for an audit.
+package drivercoverage
+
+// Status is a driver's readiness for a driver-wide audit.
+type Status string
+
+const (
+	// NoContent means the driver's project has no content directory in the monorepo, so
+	// every page of the driver fails to resolve.
+	NoContent Status = "no-content"
+	// NoTestInfra means the driver has local content but isn't in TestableDrivers.
+	NoTestInfra Status = "no-test-infra"
+	// Covered means the driver has local content and test infrastructure.
+	Covered Status = "covered"
+)
+
+// statusOrder is the order the statuses are printed in: problems first.
+var statusOrder = []Status{NoContent, NoTestInfra, Covered}
+
+// Driver is one driver URL slug and what it resolves to.
+type Driver struct {
+	Slug       string // URL slug from the Snooty Data API (e.g., "drivers/go")
+	Project    string // Snooty project name; empty if the slug isn't mapped to a project
+	ContentDir string // Content directory in the monorepo; empty if there isn't one
+	Status     Status
+}
+
+// CoverageReport contains every driver slug and its readiness.
+type CoverageReport struct {
+	MonorepoPath string
+	Drivers      []Driver // Sorted by project name, then slug
+	// UsedStaticFallback is true if the driver slugs came from the built-in static
+	// mapping because the Snooty Data API was unavailable.
+	UsedStaticFallback bool
+}
+
+// ByStatus returns the drivers with the given status.
+func (r *CoverageReport) ByStatus(status Status) []Driver {
+	var drivers []Driver
+	for _, d := range r.Drivers {
+		if d.Status == status {
+			drivers = append(drivers, d)
+		}
+	}
+	return drivers
+}
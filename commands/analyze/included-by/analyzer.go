@@ -0,0 +1,309 @@
+package includedby
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/grove-platform/audit-cli/internal/projectinfo"
+	"github.com/grove-platform/audit-cli/internal/rst"
+)
+
+// FindTargets finds every file in the monorepo that matches an include path.
+//
+// The include path can be:
+//   - A path relative to the monorepo root (e.g., content/manual/manual/source/includes/fact.rst),
+//     which matches exactly one file
+//   - A source-relative include path as written in an include directive
+//     (e.g., /includes/fact.rst), which matches the file in every project and version
+//     source directory that has it
+//
+// Parameters:
+//   - includePath: The include path to look up
+//   - monorepoPath: Path to the documentation monorepo
+//
+// Returns:
+//   - []string: Absolute paths to the matching files
+//   - error: Error if the monorepo has no content directory or no file matches
+func FindTargets(includePath, monorepoPath string) ([]string, error) {
+	// A path relative to the monorepo root names a single file
+	monorepoRelative := filepath.Join(monorepoPath, includePath)
+	if info, err := os.Stat(monorepoRelative); err == nil && !info.IsDir() {
+		absPath, err := filepath.Abs(monorepoRelative)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get absolute path: %w", err)
+		}
+		return []string{absPath}, nil
+	}
+
+	sourceDirs, err := findSourceDirs(monorepoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []string
+	for _, sourceDir := range sourceDirs {
+		candidate, err := projectinfo.ResolveRelativeToSource(sourceDir, includePath)
+		if err != nil {
+			continue
+		}
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			targets = append(targets, candidate)
+		}
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no file matching %s found in any source directory under %s", includePath, filepath.Join(monorepoPath, "content"))
+	}
+	return targets, nil
+}
+
+// FilterCurrent keeps only targets in the current version of a versioned project,
+// or in a non-versioned project.
+//
+// Parameters:
+//   - targets: Absolute paths to target files
+//
+// Returns:
+//   - []string: The targets that are in a current or non-versioned source directory
+func FilterCurrent(targets []string) []string {
+	var filtered []string
+	for _, target := range targets {
+		sourceDir, err := projectinfo.FindSourceDirectory(target)
+		if err != nil {
+			continue
+		}
+		version := sourceVersion(sourceDir)
+		if version == "" || projectinfo.IsCurrentVersion(version) {
+			filtered = append(filtered, target)
+		}
+	}
+	return filtered
+}
+
+// sourceVersion returns the version directory name for a source directory, or an empty
+// string for a non-versioned project.
+//
+// In the monorepo, versioned projects use content/{project}/{version}/source and
+// non-versioned projects use content/{project}/source. Outside a content directory,
+// the parent directory is treated as a version if it looks like one.
+//
+// Parameters:
+//   - sourceDir: Absolute path to a source directory
+//
+// Returns:
+//   - string: The version directory name (e.g., "current", "v7.0"), or "" if not versioned
+func sourceVersion(sourceDir string) string {
+	parent := filepath.Dir(sourceDir)
+	grandparent := filepath.Dir(parent)
+
+	if filepath.Base(grandparent) == "content" {
+		return ""
+	}
+	if filepath.Base(filepath.Dir(grandparent)) == "content" {
+		return filepath.Base(parent)
+	}
+	if projectinfo.IsVersionDirectory(filepath.Base(parent)) {
+		return filepath.Base(parent)
+	}
+	return ""
+}
+
+// AnalyzeIncludedBy finds every file that transitively includes each target.
+//
+// For each target, this function scans all RST and YAML files in the target's source
+// directory, resolves their include directives, and walks the reverse include graph
+// from the target. Includes can only resolve within a single source directory, so
+// other projects and versions are not scanned.
+//
+// Parameters:
+//   - includePath: The include path as given on the command line (for display)
+//   - targets: Absolute paths to the target files
+//
+// Returns:
+//   - *IncludedByAnalysis: One result per target
+//   - error: Any error encountered scanning a source directory
+func AnalyzeIncludedBy(includePath string, targets []string) (*IncludedByAnalysis, error) {
+	analysis := &IncludedByAnalysis{IncludePath: includePath}
+
+	// Reverse include graphs are shared by targets in the same source directory
+	graphs := make(map[string]map[string][]string)
+
+	for _, target := range targets {
+		sourceDir, err := projectinfo.FindSourceDirectory(target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find source directory for %s: %w", target, err)
+		}
+
+		includedBy, ok := graphs[sourceDir]
+		if !ok {
+			includedBy, err = buildReverseIncludeGraph(sourceDir)
+			if err != nil {
+				return nil, err
+			}
+			graphs[sourceDir] = includedBy
+		}
+
+		analysis.Targets = append(analysis.Targets, TargetResult{
+			TargetFile: target,
+			SourceDir:  sourceDir,
+			Version:    sourceVersion(sourceDir),
+			Includers:  findIncluders(target, includedBy),
+		})
+	}
+
+	return analysis, nil
+}
+
+// buildReverseIncludeGraph maps each included file to the files that include it.
+//
+// Parameters:
+//   - sourceDir: The source directory to scan
+//
+// Returns:
+//   - map[string][]string: Included file path -> paths of files that include it
+//   - error: Any error encountered walking the directory
+func buildReverseIncludeGraph(sourceDir string) (map[string][]string, error) {
+	includedBy := make(map[string][]string)
+
+	err := filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return projectinfo.SkipExcludedDir(sourceDir, path, info.Name())
+		}
+		if !isIncludingFile(path) {
+			return nil
+		}
+
+		// Unresolved includes are reported by other commands; skip them quietly here
+		refs, err := rst.FindIncludeReferences(path)
+		if err != nil {
+			return nil
+		}
+		for _, ref := range refs {
+			if ref.Err != nil {
+				continue
+			}
+			includedBy[ref.ResolvedPath] = append(includedBy[ref.ResolvedPath], path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", sourceDir, err)
+	}
+
+	return includedBy, nil
+}
+
+// findIncluders walks the reverse include graph breadth-first from the target.
+//
+// Each includer is reported once, at the shortest include distance from the target.
+//
+// Parameters:
+//   - target: Absolute path to the target file
+//   - includedBy: Reverse include graph from buildReverseIncludeGraph
+//
+// Returns:
+//   - []Includer: All files that transitively include the target, sorted by depth then path
+func findIncluders(target string, includedBy map[string][]string) []Includer {
+	seen := map[string]bool{target: true}
+	var includers []Includer
+
+	frontier := []string{target}
+	for depth := 1; len(frontier) > 0; depth++ {
+		var next []string
+		for _, file := range frontier {
+			for _, includer := range includedBy[file] {
+				if seen[includer] {
+					continue
+				}
+				seen[includer] = true
+				includers = append(includers, Includer{
+					FilePath: includer,
+					Depth:    depth,
+					Via:      file,
+					IsPage:   strings.EqualFold(filepath.Ext(includer), ".txt"),
+				})
+				next = append(next, includer)
+			}
+		}
+		frontier = next
+	}
+
+	sort.SliceStable(includers, func(i, j int) bool {
+		if includers[i].Depth != includers[j].Depth {
+			return includers[i].Depth < includers[j].Depth
+		}
+		return includers[i].FilePath < includers[j].FilePath
+	})
+	return includers
+}
+
+// findSourceDirs lists every project source directory in the monorepo.
+//
+// Looks for content/{project}/source and content/{project}/{version}/source.
+//
+// Parameters:
+//   - monorepoPath: Path to the documentation monorepo
+//
+// Returns:
+//   - []string: Absolute paths to source directories, sorted
+//   - error: Error if the content directory cannot be read
+func findSourceDirs(monorepoPath string) ([]string, error) {
+	contentDir := filepath.Join(monorepoPath, "content")
+	projects, err := os.ReadDir(contentDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content directory: %w", err)
+	}
+
+	var sourceDirs []string
+	for _, project := range projects {
+		if !project.IsDir() {
+			continue
+		}
+		projectDir := filepath.Join(contentDir, project.Name())
+		if isDir(filepath.Join(projectDir, "source")) {
+			sourceDirs = append(sourceDirs, filepath.Join(projectDir, "source"))
+			continue
+		}
+
+		versions, err := os.ReadDir(projectDir)
+		if err != nil {
+			continue
+		}
+		for _, version := range versions {
+			versionSource := filepath.Join(projectDir, version.Name(), "source")
+			if version.IsDir() && isDir(versionSource) {
+				sourceDirs = append(sourceDirs, versionSource)
+			}
+		}
+	}
+
+	for i, dir := range sourceDirs {
+		if absDir, err := filepath.Abs(dir); err == nil {
+			sourceDirs[i] = absDir
+		}
+	}
+	sort.Strings(sourceDirs)
+	return sourceDirs, nil
+}
+
+// isIncludingFile reports whether a file can contain include directives.
+// YAML files are included because steps, extracts, and release files contain RST content blocks.
+func isIncludingFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".txt", ".rst", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// isDir reports whether path exists and is a directory.
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
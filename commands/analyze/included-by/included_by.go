@@ -0,0 +1,147 @@
+// Package includedby provides functionality for reverse include lookups.
+//
+// This package implements the "analyze included-by" subcommand, which finds every file
+// that includes a target file, directly or through a chain of other includes. It is the
+// inverse of "analyze includes": before editing a widely-shared include, it shows every
+// page the change will affect.
+package includedby
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/grove-platform/audit-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// NewIncludedByCommand creates the included-by subcommand.
+//
+// This command finds every file that transitively includes a target file through
+// .. include:: directives.
+//
+// Usage:
+//
+//	analyze included-by /path/to/source/includes/fact.rst
+//	analyze included-by /includes/fact.rst /path/to/docs-monorepo
+//
+// Flags:
+//   - --current-only: Only look up the target in current and non-versioned projects
+//   - --pages-only: Only list documentation pages (.txt files)
+//   - --format: Output format (text or json)
+func NewIncludedByCommand() *cobra.Command {
+	var (
+		currentOnly bool
+		pagesOnly   bool
+		format      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "included-by <include-path> [monorepo-path]",
+		Short: "Find every file that includes a target file, directly or transitively",
+		Long: `Find every file that includes a target file through .. include:: directives.
+
+This command scans every RST and YAML file in the target's source directory, resolves
+their include directives, and follows the include chain backwards from the target.
+Each file that includes the target, directly or through other includes, is listed
+with its include depth. Files ending in .txt are documentation pages.
+
+This is the inverse of "analyze includes". Use it before editing a shared include to
+see every page the change will affect. Unlike "analyze usage", it follows only include
+directives and always follows them transitively.
+
+Include Path Resolution:
+  The include path can be:
+    1. A file path (absolute, relative to the monorepo, or relative to the current directory)
+    2. A source-relative include path as written in a directive (e.g., /includes/fact.rst).
+       The file is looked up in every project and version source directory in the
+       monorepo, and each match is reported separately.
+
+Monorepo Path Configuration:
+  For source-relative include paths, the monorepo path can be specified in three ways
+  (in order of priority):
+    1. Command-line argument: analyze included-by /includes/fact.rst /path/to/monorepo
+    2. Environment variable: export AUDIT_CLI_MONOREPO_PATH=/path/to/monorepo
+    3. Config file (.audit-cli.yaml):
+       monorepo_path: /path/to/monorepo
+
+Examples:
+  # Find everything that includes a file
+  analyze included-by content/manual/manual/source/includes/fact-install.rst
+
+  # Find everything that includes /includes/fact-install.rst in every project and version
+  analyze included-by /includes/fact-install.rst /path/to/docs-monorepo
+
+  # Only the current version (and non-versioned projects)
+  analyze included-by /includes/fact-install.rst --current-only
+
+  # Only list affected pages
+  analyze included-by /includes/fact-install.rst --pages-only`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var cmdLineArg string
+			if len(args) > 1 {
+				cmdLineArg = args[1]
+			}
+			return runIncludedBy(args[0], cmdLineArg, currentOnly, pagesOnly, format)
+		},
+	}
+
+	cmd.Flags().BoolVar(&currentOnly, "current-only", false, "Only look up the target in current and non-versioned projects")
+	cmd.Flags().BoolVar(&pagesOnly, "pages-only", false, "Only list documentation pages (.txt files)")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format (text or json)")
+
+	return cmd
+}
+
+// runIncludedBy executes the reverse include lookup.
+//
+// Parameters:
+//   - includePath: The include path from the command line
+//   - monorepoArg: Monorepo path from the command line (may be empty)
+//   - currentOnly: If true, skip targets in non-current versions
+//   - pagesOnly: If true, only list documentation pages in text output
+//   - format: Output format (text or json)
+//
+// Returns:
+//   - error: Any error encountered during the lookup
+func runIncludedBy(includePath, monorepoArg string, currentOnly, pagesOnly bool, format string) error {
+	if format != "text" && format != "json" {
+		return fmt.Errorf("invalid format: %s (must be 'text' or 'json')", format)
+	}
+
+	var targets []string
+	if monorepoArg == "" {
+		// A file path names a single target; no monorepo needed
+		if filePath, err := config.ResolveFilePath(includePath); err == nil {
+			targets = []string{filePath}
+		}
+	}
+	if targets == nil {
+		monorepoPath, err := config.GetMonorepoPath(monorepoArg)
+		if err != nil {
+			return err
+		}
+		targets, err = FindTargets(includePath, monorepoPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	if currentOnly {
+		targets = FilterCurrent(targets)
+		if len(targets) == 0 {
+			return fmt.Errorf("no current-version file matching %s found", includePath)
+		}
+	}
+
+	analysis, err := AnalyzeIncludedBy(includePath, targets)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		return PrintJSON(os.Stdout, analysis)
+	}
+	PrintText(os.Stdout, analysis, pagesOnly)
+	return nil
+}
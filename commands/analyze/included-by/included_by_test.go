@@ -0,0 +1,139 @@
+// Package includedby provides tests for the included-by subcommand.
+package includedby
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestFindTargets tests finding a source-relative include path in every source directory.
+func TestFindTargets(t *testing.T) {
+	monorepo := filepath.Join("..", "..", "..", "testdata", "included-by-test")
+
+	targets, err := FindTargets("/includes/shared.rst", monorepo)
+	if err != nil {
+		t.Fatalf("FindTargets failed: %v", err)
+	}
+
+	// atlas, manual/current, manual/v7.0
+	if len(targets) != 3 {
+		t.Fatalf("Expected 3 targets, got %d: %v", len(targets), targets)
+	}
+
+	current := FilterCurrent(targets)
+	if len(current) != 2 {
+		t.Fatalf("Expected 2 current targets, got %d: %v", len(current), current)
+	}
+	for _, target := range current {
+		if strings.Contains(target, "v7.0") {
+			t.Errorf("Expected v7.0 to be filtered out, got %s", target)
+		}
+	}
+}
+
+// TestFindTargetsMonorepoRelative tests that a monorepo-relative path names a single file.
+func TestFindTargetsMonorepoRelative(t *testing.T) {
+	monorepo := filepath.Join("..", "..", "..", "testdata", "included-by-test")
+
+	targets, err := FindTargets("content/manual/v7.0/source/includes/shared.rst", monorepo)
+	if err != nil {
+		t.Fatalf("FindTargets failed: %v", err)
+	}
+	if len(targets) != 1 {
+		t.Fatalf("Expected 1 target, got %d: %v", len(targets), targets)
+	}
+}
+
+// TestFindTargetsNoMatch tests the error for an include path that matches no file.
+func TestFindTargetsNoMatch(t *testing.T) {
+	monorepo := filepath.Join("..", "..", "..", "testdata", "included-by-test")
+
+	if _, err := FindTargets("/includes/does-not-exist.rst", monorepo); err == nil {
+		t.Error("Expected error for include path with no matching file")
+	}
+}
+
+// TestAnalyzeIncludedBy tests finding direct and transitive includers.
+func TestAnalyzeIncludedBy(t *testing.T) {
+	target, err := filepath.Abs(filepath.Join("..", "..", "..", "testdata", "included-by-test",
+		"content", "manual", "current", "source", "includes", "shared.rst"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	analysis, err := AnalyzeIncludedBy("/includes/shared.rst", []string{target})
+	if err != nil {
+		t.Fatalf("AnalyzeIncludedBy failed: %v", err)
+	}
+	if len(analysis.Targets) != 1 {
+		t.Fatalf("Expected 1 target result, got %d", len(analysis.Targets))
+	}
+
+	result := analysis.Targets[0]
+	if result.Version != "current" {
+		t.Errorf("Expected version current, got %q", result.Version)
+	}
+
+	expected := []struct {
+		path   string
+		depth  int
+		isPage bool
+	}{
+		{filepath.Join("includes", "wrapper.rst"), 1, false},
+		{"tutorial.txt", 1, true},
+		{"index.txt", 2, true},
+	}
+
+	if len(result.Includers) != len(expected) {
+		t.Fatalf("Expected %d includers, got %d: %+v", len(expected), len(result.Includers), result.Includers)
+	}
+	for i, want := range expected {
+		got := result.Includers[i]
+		if rel := relativeToSource(result.SourceDir, got.FilePath); rel != want.path {
+			t.Errorf("Includer %d: expected %s, got %s", i, want.path, rel)
+		}
+		if got.Depth != want.depth {
+			t.Errorf("Includer %s: expected depth %d, got %d", want.path, want.depth, got.Depth)
+		}
+		if got.IsPage != want.isPage {
+			t.Errorf("Includer %s: expected IsPage %v, got %v", want.path, want.isPage, got.IsPage)
+		}
+	}
+
+	if result.PageCount() != 2 {
+		t.Errorf("Expected 2 pages, got %d", result.PageCount())
+	}
+
+	var buf bytes.Buffer
+	PrintText(&buf, analysis, true)
+	output := buf.String()
+	if strings.Contains(output, "wrapper.rst\n") {
+		t.Errorf("Expected --pages-only output to omit includes/wrapper.rst, got:\n%s", output)
+	}
+	if !strings.Contains(output, "[depth 2] index.txt (via "+filepath.Join("includes", "wrapper.rst")+")") {
+		t.Errorf("Expected transitive includer in output, got:\n%s", output)
+	}
+}
+
+// TestSourceVersion tests version detection from source directory paths.
+func TestSourceVersion(t *testing.T) {
+	tests := []struct {
+		sourceDir string
+		expected  string
+	}{
+		{"/repo/content/atlas/source", ""},
+		{"/repo/content/manual/manual/source", "manual"},
+		{"/repo/content/golang/current/source", "current"},
+		{"/repo/content/golang/v1.12/source", "v1.12"},
+		{"/elsewhere/project/source", ""},
+		{"/elsewhere/project/v2.0/source", "v2.0"},
+	}
+
+	for _, tt := range tests {
+		if got := sourceVersion(tt.sourceDir); got != tt.expected {
+			t.Errorf("sourceVersion(%q) = %q, want %q", tt.sourceDir, got, tt.expected)
+		}
+	}
+}
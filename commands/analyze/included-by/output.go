@@ -0,0 +1,74 @@
+package includedby
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// PrintText prints the reverse include lookup results in text format.
+//
+// Parameters:
+//   - w: Writer for the output
+//   - analysis: The analysis results
+//   - pagesOnly: If true, only list includers that are documentation pages (.txt)
+func PrintText(w io.Writer, analysis *IncludedByAnalysis, pagesOnly bool) {
+	fmt.Fprintln(w, strings.Repeat("=", 80))
+	fmt.Fprintln(w, "INCLUDED BY")
+	fmt.Fprintln(w, strings.Repeat("=", 80))
+	fmt.Fprintf(w, "Include path: %s\n", analysis.IncludePath)
+	fmt.Fprintf(w, "Matching files: %d\n", len(analysis.Targets))
+
+	for _, target := range analysis.Targets {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "Target: %s\n", target.TargetFile)
+		if target.Version != "" {
+			fmt.Fprintf(w, "Version: %s\n", target.Version)
+		}
+		fmt.Fprintf(w, "Files including target: %d (%d pages)\n", len(target.Includers), target.PageCount())
+		fmt.Fprintln(w, strings.Repeat("-", 80))
+
+		if len(target.Includers) == 0 {
+			fmt.Fprintln(w, "  Not included by any file")
+			continue
+		}
+
+		for _, includer := range target.Includers {
+			if pagesOnly && !includer.IsPage {
+				continue
+			}
+			path := relativeToSource(target.SourceDir, includer.FilePath)
+			if includer.Depth == 1 {
+				fmt.Fprintf(w, "  [direct] %s\n", path)
+			} else {
+				fmt.Fprintf(w, "  [depth %d] %s (via %s)\n", includer.Depth, path, relativeToSource(target.SourceDir, includer.Via))
+			}
+		}
+	}
+}
+
+// PrintJSON prints the reverse include lookup results in JSON format.
+//
+// Parameters:
+//   - w: Writer for the output
+//   - analysis: The analysis results
+//
+// Returns:
+//   - error: Any error encountered during encoding
+func PrintJSON(w io.Writer, analysis *IncludedByAnalysis) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(analysis)
+}
+
+// relativeToSource returns path relative to the source directory, or path unchanged
+// if it is outside the source directory.
+func relativeToSource(sourceDir, path string) string {
+	rel, err := filepath.Rel(sourceDir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return rel
+}
@@ -0,0 +1,37 @@
+package includedby
+
+// Includer is a file that includes the target, directly or through other includes.
+type Includer struct {
+	FilePath string // Absolute path to the including file
+	Depth    int    // 1 if the file includes the target directly, 2 if through one other include, etc.
+	Via      string // The file this includer includes on the way to the target (the target itself at depth 1)
+	IsPage   bool   // True for .txt files, which are rendered as documentation pages
+}
+
+// TargetResult holds the includers found for one copy of the target file.
+//
+// A source-relative include path (e.g., /includes/fact.rst) can match a file in several
+// projects or versions; each match gets its own result.
+type TargetResult struct {
+	TargetFile string     // Absolute path to the target file
+	SourceDir  string     // The source directory that was searched
+	Version    string     // Version directory name (empty for non-versioned projects)
+	Includers  []Includer // Sorted by depth, then path
+}
+
+// PageCount returns the number of includers that are documentation pages.
+func (r *TargetResult) PageCount() int {
+	count := 0
+	for _, includer := range r.Includers {
+		if includer.IsPage {
+			count++
+		}
+	}
+	return count
+}
+
+// IncludedByAnalysis contains the results of a reverse include lookup.
+type IncludedByAnalysis struct {
+	IncludePath string         // The include path as given on the command line
+	Targets     []TargetResult // One result per matching target file
+}
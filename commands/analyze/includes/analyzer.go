@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/grove-platform/audit-cli/internal/rst"
 )
@@ -62,6 +63,7 @@ func AnalyzeIncludes(filePath string, verbose bool) (*IncludeAnalysis, error) {
 		TotalIncludeDirectives: totalDirectives,
 		MaxDepth:               maxDepth,
 	}
+	summarizeTree(tree, analysis)
 
 	return analysis, nil
 }
@@ -91,10 +93,13 @@ func buildIncludeTree(filePath string, recursionPath map[string]bool, seenFiles
 	node := &IncludeNode{
 		FilePath: absPath,
 		Children: []*IncludeNode{},
+		Resolved: true,
+		FileType: classifyFile(absPath),
 	}
 
 	// Check if this file is already in the current recursion path (true circular include)
 	if recursionPath[absPath] {
+		node.Cycle = true
 		if verbose {
 			indent := getIndent(depth)
 			fmt.Printf("%s⚠ Circular include detected: %s\n", indent, formatDisplayPath(absPath))
@@ -107,11 +112,13 @@ func buildIncludeTree(filePath string, recursionPath map[string]bool, seenFiles
 	// Ensure we remove it when we're done processing this branch
 	defer delete(recursionPath, absPath)
 
-	// Find include directives in this file
-	includeFiles, err := rst.FindIncludeDirectives(absPath)
+	node.CodeExamples = countCodeExamples(absPath)
+
+	// Find include directives in this file, keeping unresolved ones so they show in the tree
+	includeRefs, err := rst.FindIncludeReferences(absPath)
 	if err != nil {
 		// Not a fatal error - file might not have includes
-		includeFiles = []string{}
+		includeRefs = []rst.IncludeReference{}
 	}
 
 	// Print verbose output for this file
@@ -125,22 +132,32 @@ func buildIncludeTree(filePath string, recursionPath map[string]bool, seenFiles
 			seenFiles[absPath] = true
 		}
 
-		if len(includeFiles) > 0 {
+		if len(includeRefs) > 0 {
 			directiveWord := "include directives"
-			if len(includeFiles) == 1 {
+			if len(includeRefs) == 1 {
 				directiveWord = "include directive"
 			}
-			fmt.Printf("%s%s %s (%d %s)\n", indent, bullet, formatDisplayPath(absPath), len(includeFiles), directiveWord)
+			fmt.Printf("%s%s %s (%d %s)\n", indent, bullet, formatDisplayPath(absPath), len(includeRefs), directiveWord)
 		} else {
 			fmt.Printf("%s%s %s\n", indent, bullet, formatDisplayPath(absPath))
 		}
 	}
 
 	// Recursively process each included file
-	for _, includeFile := range includeFiles {
-		childNode, err := buildIncludeTree(includeFile, recursionPath, seenFiles, verbose, depth+1)
+	for _, ref := range includeRefs {
+		if ref.Err != nil {
+			if verbose {
+				fmt.Printf("%s✗ Unresolved include: %s\n", getIndent(depth+1), ref.Path)
+			}
+			node.Children = append(node.Children, &IncludeNode{
+				FilePath: ref.Path,
+				Children: []*IncludeNode{},
+			})
+			continue
+		}
+		childNode, err := buildIncludeTree(ref.ResolvedPath, recursionPath, seenFiles, verbose, depth+1)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to process file %s: %v\n", includeFile, err)
+			fmt.Fprintf(os.Stderr, "Warning: failed to process file %s: %v\n", ref.ResolvedPath, err)
 			continue
 		}
 		node.Children = append(node.Children, childNode)
@@ -216,8 +233,8 @@ func collectUniqueFiles(node *IncludeNode) []string {
 			return
 		}
 
-		// Add this file if we haven't seen it before
-		if !visited[n.FilePath] {
+		// Add this file if we haven't seen it before (unresolved includes are not files)
+		if n.Resolved && !visited[n.FilePath] {
 			visited[n.FilePath] = true
 			files = append(files, n.FilePath)
 		}
@@ -259,3 +276,94 @@ func countIncludeDirectives(node *IncludeNode) int {
 	return count
 }
 
+
+// summarizeTree fills in the code example, unresolved include, and cycle totals.
+//
+// Code examples are counted once per unique file, even if the file is included from
+// several places, matching how report testable-code counts a page's examples.
+//
+// Parameters:
+//   - node: The root node of the tree to traverse
+//   - analysis: The analysis to update
+func summarizeTree(node *IncludeNode, analysis *IncludeAnalysis) {
+	counted := make(map[string]bool)
+
+	var traverse func(*IncludeNode)
+	traverse = func(n *IncludeNode) {
+		if n == nil {
+			return
+		}
+
+		switch {
+		case !n.Resolved:
+			analysis.UnresolvedIncludes++
+		case n.Cycle:
+			analysis.Cycles++
+		case !counted[n.FilePath]:
+			counted[n.FilePath] = true
+			analysis.TotalCodeExamples += n.CodeExamples
+		}
+
+		for _, child := range n.Children {
+			traverse(child)
+		}
+	}
+
+	traverse(node)
+}
+
+// classifyFile returns the include file type for a path.
+//
+// MongoDB docs use naming conventions for YAML include files:
+//   - includes/steps-*.yaml: procedure steps
+//   - includes/extracts-*.yaml: ref-based content blocks
+//   - includes/release-*.yaml: ref-based release notes blocks
+//
+// Parameters:
+//   - filePath: Path to the file
+//
+// Returns:
+//   - string: One of the FileType* constants
+func classifyFile(filePath string) string {
+	base := filepath.Base(filePath)
+	switch strings.ToLower(filepath.Ext(base)) {
+	case ".rst", ".txt":
+		return FileTypeRST
+	case ".yaml", ".yml":
+		switch {
+		case strings.HasPrefix(base, "steps-"):
+			return FileTypeStepsYAML
+		case strings.HasPrefix(base, "extracts-"):
+			return FileTypeExtract
+		case strings.HasPrefix(base, "release-"):
+			return FileTypeRelease
+		}
+	}
+	return FileTypeOther
+}
+
+// countCodeExamples counts the code example directives defined directly in a file.
+//
+// Counts code-block, code, literalinclude, and io-code-block directives. Includes are
+// not followed; each file in the tree reports its own count.
+//
+// Parameters:
+//   - filePath: Path to the file
+//
+// Returns:
+//   - int: Number of code examples (0 if the file cannot be parsed)
+func countCodeExamples(filePath string) int {
+	directives, err := rst.ParseDirectives(filePath)
+	if err != nil {
+		return 0
+	}
+
+	count := 0
+	for _, directive := range directives {
+		switch directive.Type {
+		case rst.CodeBlock, rst.Code, rst.LiteralInclude, rst.IoCodeBlock:
+			count++
+		}
+	}
+	return count
+}
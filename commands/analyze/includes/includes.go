@@ -6,11 +6,12 @@
 //   - A flat list of all files referenced through includes
 //
 // This helps writers understand the impact of changes to files that are widely included
-// across the documentation.
+// across the documentation, and how a page is composed.
 package includes
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/grove-platform/audit-cli/internal/config"
 	"github.com/spf13/cobra"
@@ -19,8 +20,15 @@ import (
 // NewIncludesCommand creates the includes subcommand.
 //
 // This command analyzes include directive relationships in RST files.
+// The page can be given as a file path or as a documentation URL, which is resolved
+// to its source file using the monorepo's snooty.toml files.
 // Supports flags for different output formats (tree or list).
 //
+// Usage:
+//
+//	analyze includes path/to/file.txt
+//	analyze includes www.mongodb.com/docs/atlas/some-page/ [monorepo-path]
+//
 // Flags:
 //   - --tree: Display results as a hierarchical tree structure
 //   - --list: Display results as a flat list of all files
@@ -33,7 +41,7 @@ func NewIncludesCommand() *cobra.Command {
 	)
 
 	cmd := &cobra.Command{
-		Use:   "includes [filepath]",
+		Use:   "includes <url-or-path> [monorepo-path]",
 		Short: "Analyze include relationships in RST files",
 		Long: `Analyze include directive relationships to understand file dependencies.
 
@@ -41,6 +49,11 @@ This command recursively follows .. include:: directives and shows all files
 that are referenced. This helps writers understand the impact of changes to
 files that are widely included across the documentation.
 
+Each file in the tree is annotated with its type (rst, steps-yaml, extract,
+release, or other) and the number of code examples it defines directly.
+Includes that cannot be resolved are marked [unresolved], and includes that
+loop back to a file already being processed are marked as a cycle.
+
 Output formats:
   --tree: Show hierarchical tree structure of includes
   --list: Show flat list of all included files
@@ -51,15 +64,40 @@ File Path Resolution:
   Paths can be specified as:
     1. Absolute path: /full/path/to/file.rst
     2. Relative to monorepo root (if configured): manual/manual/source/file.rst
-    3. Relative to current directory: ./file.rst`,
-		Args: cobra.ExactArgs(1),
+    3. Relative to current directory: ./file.rst
+
+Page URLs:
+  A documentation URL (e.g., www.mongodb.com/docs/atlas/some-page/) is resolved to
+  its source file. The monorepo path is taken from the second argument, the
+  AUDIT_CLI_MONOREPO_PATH environment variable, or the config file.
+
+Examples:
+  # Show the include tree for a file
+  analyze includes content/atlas/source/some-page.txt --tree
+
+  # Show the include tree for a page URL
+  analyze includes www.mongodb.com/docs/atlas/some-page/ /path/to/docs-monorepo --tree`,
+		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Resolve file path (supports absolute, monorepo-relative, or cwd-relative)
-			filePath, err := config.ResolveFilePath(args[0])
+			var pageURL string
+			var filePath string
+			var err error
+			if isPageURL(args[0]) {
+				// Resolve the URL to its source file in the monorepo
+				pageURL = args[0]
+				var cmdLineArg string
+				if len(args) > 1 {
+					cmdLineArg = args[1]
+				}
+				filePath, err = resolvePageURL(pageURL, cmdLineArg)
+			} else {
+				// Resolve file path (supports absolute, monorepo-relative, or cwd-relative)
+				filePath, err = config.ResolveFilePath(args[0])
+			}
 			if err != nil {
 				return err
 			}
-			return runAnalyze(filePath, showTree, showList, verbose)
+			return runAnalyze(filePath, pageURL, showTree, showList, verbose)
 		},
 	}
 
@@ -77,18 +115,20 @@ File Path Resolution:
 //
 // Parameters:
 //   - filePath: Path to the RST file to analyze
+//   - pageURL: The page URL the file was resolved from (empty if given as a path)
 //   - showTree: If true, display tree structure
 //   - showList: If true, display flat list
 //   - verbose: If true, show detailed processing information
 //
 // Returns:
 //   - error: Any error encountered during analysis
-func runAnalyze(filePath string, showTree bool, showList bool, verbose bool) error {
+func runAnalyze(filePath string, pageURL string, showTree bool, showList bool, verbose bool) error {
 	// Perform the analysis
 	analysis, err := AnalyzeIncludes(filePath, verbose)
 	if err != nil {
 		return fmt.Errorf("failed to analyze includes: %w", err)
 	}
+	analysis.RootURL = pageURL
 
 	// Display results based on flags
 	if showTree && showList {
@@ -110,3 +150,44 @@ func runAnalyze(filePath string, showTree bool, showList bool, verbose bool) err
 	return nil
 }
 
+
+// isPageURL reports whether the argument is a documentation URL rather than a file path.
+//
+// Parameters:
+//   - arg: The command-line argument
+//
+// Returns:
+//   - bool: True for arguments like "https://www.mongodb.com/docs/..." or "www.mongodb.com/docs/..."
+func isPageURL(arg string) bool {
+	return strings.HasPrefix(arg, "http://") ||
+		strings.HasPrefix(arg, "https://") ||
+		strings.HasPrefix(arg, "www.") ||
+		strings.HasPrefix(arg, "mongodb.com/")
+}
+
+// resolvePageURL resolves a documentation URL to its source file in the monorepo.
+//
+// Parameters:
+//   - pageURL: The documentation URL
+//   - monorepoArg: Monorepo path from the command line (may be empty)
+//
+// Returns:
+//   - string: Absolute path to the page's source file
+//   - error: Error if the monorepo path is not configured or the URL cannot be resolved
+func resolvePageURL(pageURL, monorepoArg string) (string, error) {
+	monorepoPath, err := config.GetMonorepoPath(monorepoArg)
+	if err != nil {
+		return "", err
+	}
+
+	urlMapping, err := config.GetURLMapping(monorepoPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to load URL mapping: %w", err)
+	}
+
+	sourcePath, _, _, err := urlMapping.ResolveURL(pageURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve URL %s: %w", pageURL, err)
+	}
+	return sourcePath, nil
+}
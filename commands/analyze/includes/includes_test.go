@@ -0,0 +1,120 @@
+package includes
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestAnalyzeIncludesCycle tests that cycles are marked in the tree and not followed.
+func TestAnalyzeIncludesCycle(t *testing.T) {
+	testDataDir := filepath.Join("..", "..", "..", "testdata", "testable-code-test", "content", "test-project", "source")
+
+	analysis, err := AnalyzeIncludes(filepath.Join(testDataDir, "with-include-cycle.rst"), false)
+	if err != nil {
+		t.Fatalf("AnalyzeIncludes failed: %v", err)
+	}
+
+	if analysis.TotalFiles != 3 {
+		t.Errorf("Expected 3 unique files, got %d", analysis.TotalFiles)
+	}
+	if analysis.Cycles != 1 {
+		t.Errorf("Expected 1 cycle, got %d", analysis.Cycles)
+	}
+	// One example each in the page, cycle-a.rst, and cycle-b.rst
+	if analysis.TotalCodeExamples != 3 {
+		t.Errorf("Expected 3 code examples, got %d", analysis.TotalCodeExamples)
+	}
+
+	// page -> cycle-a -> cycle-b -> cycle-a (cycle)
+	node := analysis.Tree
+	for i := 0; i < 3; i++ {
+		if len(node.Children) != 1 {
+			t.Fatalf("Expected 1 child at depth %d, got %d", i, len(node.Children))
+		}
+		node = node.Children[0]
+	}
+	if !node.Cycle || filepath.Base(node.FilePath) != "cycle-a.rst" {
+		t.Errorf("Expected cycle node for cycle-a.rst, got %+v", node)
+	}
+	if len(node.Children) != 0 {
+		t.Errorf("Expected cycle node to have no children, got %d", len(node.Children))
+	}
+}
+
+// TestAnalyzeIncludesUnresolved tests that unresolved includes appear in the tree.
+func TestAnalyzeIncludesUnresolved(t *testing.T) {
+	testDataDir := filepath.Join("..", "..", "..", "testdata", "testable-code-test", "content", "test-project", "source")
+
+	analysis, err := AnalyzeIncludes(filepath.Join(testDataDir, "with-missing-include.rst"), false)
+	if err != nil {
+		t.Fatalf("AnalyzeIncludes failed: %v", err)
+	}
+
+	if analysis.UnresolvedIncludes != 1 {
+		t.Errorf("Expected 1 unresolved include, got %d", analysis.UnresolvedIncludes)
+	}
+	if analysis.TotalIncludeDirectives != 2 {
+		t.Errorf("Expected 2 include directives, got %d", analysis.TotalIncludeDirectives)
+	}
+	// The unresolved include is not a file
+	if analysis.TotalFiles != 2 {
+		t.Errorf("Expected 2 unique files, got %d", analysis.TotalFiles)
+	}
+
+	children := analysis.Tree.Children
+	if len(children) != 2 {
+		t.Fatalf("Expected 2 children, got %d", len(children))
+	}
+	if !children[0].Resolved || children[0].CodeExamples != 1 {
+		t.Errorf("Expected resolved include with 1 code example, got %+v", children[0])
+	}
+	if children[1].Resolved || children[1].FilePath != "/includes/does-not-exist.rst" {
+		t.Errorf("Expected unresolved include /includes/does-not-exist.rst, got %+v", children[1])
+	}
+	if got := formatNodeLabel(children[1]); got != "/includes/does-not-exist.rst [unresolved]" {
+		t.Errorf("Unexpected label for unresolved include: %q", got)
+	}
+}
+
+// TestClassifyFile tests include file type detection.
+func TestClassifyFile(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"source/index.txt", FileTypeRST},
+		{"source/includes/intro.rst", FileTypeRST},
+		{"source/includes/steps-install.yaml", FileTypeStepsYAML},
+		{"source/includes/extracts-install.yaml", FileTypeExtract},
+		{"source/includes/release-pinning.yaml", FileTypeRelease},
+		{"source/includes/options.yaml", FileTypeOther},
+		{"source/includes/example.py", FileTypeOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := classifyFile(tt.path); got != tt.expected {
+				t.Errorf("classifyFile(%q) = %q, want %q", tt.path, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestIsPageURL tests distinguishing page URLs from file paths.
+func TestIsPageURL(t *testing.T) {
+	tests := []struct {
+		arg      string
+		expected bool
+	}{
+		{"https://www.mongodb.com/docs/atlas/some-page/", true},
+		{"www.mongodb.com/docs/atlas/some-page/", true},
+		{"content/atlas/source/some-page.txt", false},
+		{"/abs/path/source/page.txt", false},
+	}
+
+	for _, tt := range tests {
+		if got := isPageURL(tt.arg); got != tt.expected {
+			t.Errorf("isPageURL(%q) = %v, want %v", tt.arg, got, tt.expected)
+		}
+	}
+}
@@ -19,10 +19,9 @@ func PrintTree(analysis *IncludeAnalysis) {
 	fmt.Println("============================================================")
 	fmt.Println("INCLUDE TREE")
 	fmt.Println("============================================================")
-	fmt.Printf("Root File: %s\n", analysis.RootFile)
-	fmt.Printf("Unique Files: %d\n", analysis.TotalFiles)
-	fmt.Printf("Include Directives: %d\n", analysis.TotalIncludeDirectives)
+	printHeader(analysis)
 	fmt.Printf("Max Depth: %d\n", analysis.MaxDepth)
+	printTreeStats(analysis)
 	fmt.Println("============================================================")
 	fmt.Println()
 
@@ -49,13 +48,13 @@ func printTreeNode(node *IncludeNode, prefix string, isLast bool, isRoot bool) {
 
 	// Print the current node
 	if isRoot {
-		fmt.Printf("%s\n", formatDisplayPath(node.FilePath))
+		fmt.Printf("%s\n", formatNodeLabel(node))
 	} else {
 		connector := "├── "
 		if isLast {
 			connector = "└── "
 		}
-		fmt.Printf("%s%s%s\n", prefix, connector, formatDisplayPath(node.FilePath))
+		fmt.Printf("%s%s%s\n", prefix, connector, formatNodeLabel(node))
 	}
 
 	// Print children
@@ -85,9 +84,7 @@ func PrintList(analysis *IncludeAnalysis) {
 	fmt.Println("============================================================")
 	fmt.Println("INCLUDE FILE LIST")
 	fmt.Println("============================================================")
-	fmt.Printf("Root File: %s\n", analysis.RootFile)
-	fmt.Printf("Unique Files: %d\n", analysis.TotalFiles)
-	fmt.Printf("Include Directives: %d\n", analysis.TotalIncludeDirectives)
+	printHeader(analysis)
 	fmt.Println("============================================================")
 	fmt.Println()
 
@@ -109,10 +106,9 @@ func PrintSummary(analysis *IncludeAnalysis) {
 	fmt.Println("============================================================")
 	fmt.Println("INCLUDE ANALYSIS SUMMARY")
 	fmt.Println("============================================================")
-	fmt.Printf("Root File: %s\n", analysis.RootFile)
-	fmt.Printf("Unique Files: %d\n", analysis.TotalFiles)
-	fmt.Printf("Include Directives: %d\n", analysis.TotalIncludeDirectives)
+	printHeader(analysis)
 	fmt.Printf("Max Depth: %d\n", analysis.MaxDepth)
+	printTreeStats(analysis)
 	fmt.Println("============================================================")
 	fmt.Println()
 	fmt.Println("Use --tree to see the hierarchical structure")
@@ -120,6 +116,57 @@ func PrintSummary(analysis *IncludeAnalysis) {
 	fmt.Println()
 }
 
+// printHeader prints the root file and file counts shared by all output formats.
+//
+// Parameters:
+//   - analysis: The analysis results
+func printHeader(analysis *IncludeAnalysis) {
+	if analysis.RootURL != "" {
+		fmt.Printf("URL: %s\n", analysis.RootURL)
+	}
+	fmt.Printf("Root File: %s\n", analysis.RootFile)
+	fmt.Printf("Unique Files: %d\n", analysis.TotalFiles)
+	fmt.Printf("Include Directives: %d\n", analysis.TotalIncludeDirectives)
+}
+
+// printTreeStats prints the code example, unresolved include, and cycle totals.
+//
+// Parameters:
+//   - analysis: The analysis results
+func printTreeStats(analysis *IncludeAnalysis) {
+	fmt.Printf("Code Examples: %d\n", analysis.TotalCodeExamples)
+	fmt.Printf("Unresolved Includes: %d\n", analysis.UnresolvedIncludes)
+	fmt.Printf("Cycles: %d\n", analysis.Cycles)
+}
+
+// formatNodeLabel formats a tree node with its annotations.
+//
+// Examples:
+//   - "includes/intro.rst [rst, 2 code examples]"
+//   - "includes/steps-install.yaml [steps-yaml, 0 code examples]"
+//   - "includes/a.rst [rst, cycle]"
+//   - "/includes/missing.rst [unresolved]"
+//
+// Parameters:
+//   - node: The node to format
+//
+// Returns:
+//   - string: Display path followed by its annotations
+func formatNodeLabel(node *IncludeNode) string {
+	if !node.Resolved {
+		return fmt.Sprintf("%s [unresolved]", node.FilePath)
+	}
+	if node.Cycle {
+		return fmt.Sprintf("%s [%s, cycle]", formatDisplayPath(node.FilePath), node.FileType)
+	}
+
+	exampleWord := "code examples"
+	if node.CodeExamples == 1 {
+		exampleWord = "code example"
+	}
+	return fmt.Sprintf("%s [%s, %d %s]", formatDisplayPath(node.FilePath), node.FileType, node.CodeExamples, exampleWord)
+}
+
 // formatDisplayPath formats a file path for display in the tree or verbose output.
 //
 // This function returns:
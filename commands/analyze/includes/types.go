@@ -1,12 +1,30 @@
 package includes
 
+// File types reported for each node in the include tree.
+const (
+	// FileTypeRST is a regular RST or TXT file.
+	FileTypeRST = "rst"
+	// FileTypeStepsYAML is a YAML steps file (includes/steps-*.yaml).
+	FileTypeStepsYAML = "steps-yaml"
+	// FileTypeExtract is a ref-based YAML content file (includes/extracts-*.yaml).
+	FileTypeExtract = "extract"
+	// FileTypeRelease is a ref-based YAML release notes file (includes/release-*.yaml).
+	FileTypeRelease = "release"
+	// FileTypeOther is any other file type.
+	FileTypeOther = "other"
+)
+
 // IncludeNode represents a file and its included files in a tree structure.
 //
 // This type is used to build a hierarchical representation of include relationships,
 // where each node represents a file and its children are the files it includes.
 type IncludeNode struct {
-	FilePath string         // Absolute path to the file
-	Children []*IncludeNode // Files included by this file
+	FilePath     string         // Absolute path to the file (or the include path as written, if unresolved)
+	Children     []*IncludeNode // Files included by this file
+	Resolved     bool           // False if the include directive could not be resolved to a file
+	FileType     string         // One of the FileType* constants (empty if unresolved)
+	CodeExamples int            // Code examples defined directly in this file (not its includes)
+	Cycle        bool           // True if this file is already being processed higher in the tree
 }
 
 // IncludeAnalysis contains the results of analyzing include directives.
@@ -14,11 +32,14 @@ type IncludeNode struct {
 // This type holds both the tree structure and the flat list of all files
 // discovered through include directives.
 type IncludeAnalysis struct {
-	RootFile              string       // The original file that was analyzed
-	Tree                  *IncludeNode // Tree structure of include relationships
-	AllFiles              []string     // Flat list of all files (in order discovered)
-	TotalFiles            int          // Total number of unique files
-	TotalIncludeDirectives int         // Total number of include directive instances across all files
-	MaxDepth              int          // Maximum depth of include nesting
+	RootFile               string       // The original file that was analyzed
+	RootURL                string       // The page URL, if the root file was resolved from a URL
+	Tree                   *IncludeNode // Tree structure of include relationships
+	AllFiles               []string     // Flat list of all files (in order discovered)
+	TotalFiles             int          // Total number of unique files
+	TotalIncludeDirectives int          // Total number of include directive instances across all files
+	MaxDepth               int          // Maximum depth of include nesting
+	TotalCodeExamples      int          // Code examples across all unique files
+	UnresolvedIncludes     int          // Include directives that could not be resolved
+	Cycles                 int          // Include directives that loop back to a file being processed
 }
-
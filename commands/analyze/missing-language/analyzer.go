@@ -0,0 +1,99 @@
+// Package missinglanguage provides functionality for finding code examples without a language.
+package missinglanguage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/grove-platform/audit-cli/internal/language"
+	"github.com/grove-platform/audit-cli/internal/projectinfo"
+	"github.com/grove-platform/audit-cli/internal/rst"
+)
+
+// FindMissingLanguages lists the code examples whose language resolves to "undefined".
+//
+// This function walks every .txt and .rst file in the content directory, parses its
+// code-block, code, literalinclude, and io-code-block directives, and resolves each
+// example's language with rst.Directive.ResolveLanguage, the same way count languages
+// and report testable-code do. An example with no language is reported as product
+// "Unknown" by report testable-code unless a tab, composable, or content directory
+// supplies one, so these are usually a missing :language: option or argument.
+//
+// With includeUnrecognized, examples whose language has no product mapping (for example,
+// a typo such as "pyhton") are listed too.
+//
+// Includes are not followed: each file is checked once, where it lives.
+//
+// Parameters:
+//   - dirPath: Path to the monorepo root or content directory
+//   - currentOnly: If true, skip non-current version directories of versioned projects
+//   - includeUnrecognized: If true, also list examples with an unrecognized language
+//
+// Returns:
+//   - *Result: The examples found, sorted by file and line
+//   - error: Any error encountered during the walk
+func FindMissingLanguages(dirPath string, currentOnly, includeUnrecognized bool) (*Result, error) {
+	absDirPath, err := filepath.Abs(dirPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	if _, err := os.Stat(absDirPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("directory does not exist: %s", absDirPath)
+	}
+
+	contentDir, err := projectinfo.FindContentDirectory(absDirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{ContentDir: contentDir}
+	result.FilesScanned, err = rst.WalkContentDirectives(contentDir, currentOnly, func(path string, directives []rst.Directive) {
+		relPath, err := filepath.Rel(contentDir, path)
+		if err != nil {
+			relPath = path
+		}
+		for _, directive := range directives {
+			for _, ex := range directive.ExampleLanguages() {
+				result.TotalExamples++
+				reason := missingReason(ex.Language, includeUnrecognized)
+				if reason == "" {
+					continue
+				}
+				result.Examples = append(result.Examples, MissingLanguageExample{
+					File:     filepath.ToSlash(relPath),
+					Line:     directive.LineNum,
+					Type:     string(directive.Type),
+					Part:     ex.Part,
+					Language: ex.Language,
+					Reason:   reason,
+				})
+			}
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk content directory: %w", err)
+	}
+
+	sort.SliceStable(result.Examples, func(i, j int) bool {
+		if result.Examples[i].File != result.Examples[j].File {
+			return result.Examples[i].File < result.Examples[j].File
+		}
+		return result.Examples[i].Line < result.Examples[j].Line
+	})
+	return result, nil
+}
+
+// missingReason returns why an example's language is a problem, or "" if it isn't.
+func missingReason(lang string, includeUnrecognized bool) string {
+	if lang == language.Undefined || lang == "" {
+		return ReasonNoLanguage
+	}
+	if includeUnrecognized {
+		if _, ok := language.LanguageToProduct[lang]; !ok {
+			return ReasonUnrecognized
+		}
+	}
+	return ""
+}
@@ -0,0 +1,104 @@
+// Package missinglanguage implements the missing-language subcommand for finding code
+// examples without a language.
+package missinglanguage
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/grove-platform/audit-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// NewMissingLanguageCommand creates the missing-language subcommand.
+//
+// This command lists every code example in the MongoDB documentation monorepo whose
+// language resolves to "undefined", with its file, line number, and directive type.
+//
+// Usage:
+//
+//	analyze missing-language /path/to/docs-monorepo
+//	analyze missing-language /path/to/docs-monorepo --current-only
+//	analyze missing-language /path/to/docs-monorepo --include-unrecognized --format csv
+//
+// Flags:
+//   - --current-only: Only check examples in the current version of versioned projects
+//   - --include-unrecognized: Also list examples whose language has no product mapping
+//   - --format: Output format (text, json, or csv)
+func NewMissingLanguageCommand() *cobra.Command {
+	var (
+		currentOnly         bool
+		includeUnrecognized bool
+		format              string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "missing-language [monorepo-path]",
+		Short: "List code examples that have no language",
+		Long: `List code examples whose language resolves to "undefined".
+
+This command walks every .txt and .rst file in the content directory, parses its
+code-block, code, literalinclude, and io-code-block directives, and lists each code
+example without a language: its file (relative to the content directory), the line of
+the directive, and the directive type. Languages are resolved the same way as in
+count languages and report testable-code. An example with no language is reported as
+product "Unknown" unless a tab, composable, or content directory supplies one, so this
+is usually a writer's missing :language: option or argument.
+
+Use --include-unrecognized to also list examples whose language has no product mapping,
+which catches typos such as "pyhton".
+
+Includes are not followed: each file is checked once, where it lives. Files in
+code-examples directories are skipped.
+
+Monorepo Path Configuration:
+  The monorepo path can be specified in three ways (in order of priority):
+    1. Command-line argument: analyze missing-language /path/to/monorepo
+    2. Environment variable: export AUDIT_CLI_MONOREPO_PATH=/path/to/monorepo
+    3. Config file (.audit-cli.yaml):
+       monorepo_path: /path/to/monorepo
+
+Examples:
+  # List examples without a language across the monorepo
+  analyze missing-language /path/to/docs-monorepo
+
+  # Only current versions, including unrecognized languages
+  analyze missing-language --current-only --include-unrecognized
+
+  # Output as CSV for a cleanup spreadsheet
+  analyze missing-language --format csv > missing-language.csv`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Resolve monorepo path from args, env, or config
+			var cmdLineArg string
+			if len(args) > 0 {
+				cmdLineArg = args[0]
+			}
+			monorepoPath, err := config.GetMonorepoPath(cmdLineArg)
+			if err != nil {
+				return err
+			}
+			return runMissingLanguage(monorepoPath, currentOnly, includeUnrecognized, format)
+		},
+	}
+
+	cmd.Flags().BoolVar(&currentOnly, "current-only", false, "Only check examples in the current version")
+	cmd.Flags().BoolVar(&includeUnrecognized, "include-unrecognized", false, "Also list examples whose language has no product mapping (e.g., typos)")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text, json, or csv")
+
+	return cmd
+}
+
+// runMissingLanguage executes the missing-language operation.
+func runMissingLanguage(monorepoPath string, currentOnly, includeUnrecognized bool, format string) error {
+	if format != "text" && format != "json" && format != "csv" {
+		return fmt.Errorf("invalid format: %s (must be 'text', 'json', or 'csv')", format)
+	}
+
+	result, err := FindMissingLanguages(monorepoPath, currentOnly, includeUnrecognized)
+	if err != nil {
+		return fmt.Errorf("failed to find code examples without a language: %w", err)
+	}
+
+	return PrintResults(os.Stdout, result, format)
+}
@@ -0,0 +1,129 @@
+// Package missinglanguage provides tests for finding code examples without a language.
+package missinglanguage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestFindMissingLanguages tests finding code examples without a language in the monorepo.
+func TestFindMissingLanguages(t *testing.T) {
+	testDataDir := filepath.Join("..", "..", "..", "testdata", "count-test-monorepo")
+
+	result, err := FindMissingLanguages(testDataDir, false, false)
+	if err != nil {
+		t.Fatalf("FindMissingLanguages failed: %v", err)
+	}
+
+	// Matches count languages: 7 examples, one of them a code directive with no language
+	if result.TotalExamples != 7 {
+		t.Errorf("Expected 7 examples checked, got %d", result.TotalExamples)
+	}
+	if len(result.Examples) != 1 {
+		t.Fatalf("Expected 1 example without a language, got %v", result.Examples)
+	}
+	ex := result.Examples[0]
+	if ex.File != "manual/source/tutorial.txt" || ex.Line != 13 || ex.Type != "code" || ex.Reason != ReasonNoLanguage {
+		t.Errorf("Expected the code directive at manual/source/tutorial.txt:13, got %+v", ex)
+	}
+}
+
+// TestFindMissingLanguagesUnrecognized tests listing io-code-block parts and unrecognized languages.
+func TestFindMissingLanguagesUnrecognized(t *testing.T) {
+	monorepo := t.TempDir()
+	sourceDir := filepath.Join(monorepo, "content", "proj", "source")
+	if err := os.MkdirAll(sourceDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	page := `.. code-block:: pyhton
+
+   print("typo")
+
+.. code-block:: python
+
+   print("fine")
+
+.. io-code-block::
+
+   .. input::
+      :language: javascript
+
+      db.test.find()
+
+   .. output::
+
+      { "_id": 1 }
+`
+	if err := os.WriteFile(filepath.Join(sourceDir, "page.txt"), []byte(page), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := FindMissingLanguages(monorepo, false, false)
+	if err != nil {
+		t.Fatalf("FindMissingLanguages failed: %v", err)
+	}
+	if len(result.Examples) != 1 || result.Examples[0].Part != "output" || result.Examples[0].Line != 9 {
+		t.Fatalf("Expected only the io-code-block output at line 9, got %+v", result.Examples)
+	}
+
+	result, err = FindMissingLanguages(monorepo, false, true)
+	if err != nil {
+		t.Fatalf("FindMissingLanguages failed: %v", err)
+	}
+	if len(result.Examples) != 2 {
+		t.Fatalf("Expected the typo and the output, got %+v", result.Examples)
+	}
+	if typo := result.Examples[0]; typo.Line != 1 || typo.Language != "pyhton" || typo.Reason != ReasonUnrecognized {
+		t.Errorf("Expected the unrecognized pyhton example first, got %+v", typo)
+	}
+}
+
+// TestPrintResults tests the text, JSON, and CSV output formats.
+func TestPrintResults(t *testing.T) {
+	result := &Result{
+		TotalExamples: 10,
+		FilesScanned:  3,
+		Examples: []MissingLanguageExample{
+			{File: "atlas/source/a.txt", Line: 4, Type: "code-block", Language: "undefined", Reason: ReasonNoLanguage},
+			{File: "atlas/source/b.txt", Line: 9, Type: "io-code-block", Part: "output", Language: "undefined", Reason: ReasonNoLanguage},
+			{File: "atlas/source/b.txt", Line: 20, Type: "code-block", Language: "pyhton", Reason: ReasonUnrecognized},
+		},
+	}
+
+	tests := []struct {
+		format   string
+		expected []string
+	}{
+		{"text", []string{
+			"atlas/source/a.txt:4",
+			"io-code-block (output)",
+			`code-block (unrecognized language "pyhton")`,
+			"Total: 3 of 10 code examples in 3 files",
+		}},
+		{"json", []string{`"File": "atlas/source/a.txt"`, `"Part": "output"`, `"TotalExamples": 10`}},
+		{"csv", []string{"File,Line,Type,Part,Language,Reason\natlas/source/a.txt,4,code-block,,undefined,no language\n"}},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := PrintResults(&buf, result, tt.format); err != nil {
+			t.Fatalf("PrintResults(%s) failed: %v", tt.format, err)
+		}
+		for _, want := range tt.expected {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("%s output missing %q:\n%s", tt.format, want, buf.String())
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := PrintResults(&buf, &Result{TotalExamples: 5, FilesScanned: 2}, "text"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Every code example has a language") {
+		t.Errorf("Expected a clean result message, got %q", buf.String())
+	}
+}
@@ -0,0 +1,80 @@
+// Package missinglanguage provides output formatting for missing language results.
+package missinglanguage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/grove-platform/audit-cli/internal/csv"
+)
+
+// PrintResults writes the examples without a usable language in the given format
+// (text, json, or csv).
+//
+// Parameters:
+//   - w: Writer for the output
+//   - result: The examples found
+//   - format: Output format: text, json, or csv
+//
+// Returns:
+//   - error: Any error encountered while writing
+func PrintResults(w io.Writer, result *Result, format string) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	case "csv":
+		return printCSV(w, result)
+	default:
+		printText(w, result)
+		return nil
+	}
+}
+
+// printText prints one "file:line  directive" line per example, as a worklist.
+func printText(w io.Writer, result *Result) {
+	if len(result.Examples) == 0 {
+		fmt.Fprintf(w, "Every code example has a language (%d examples in %d files)\n", result.TotalExamples, result.FilesScanned)
+		return
+	}
+
+	fmt.Fprintln(w, "Code Examples Without a Language:")
+	fmt.Fprintln(w)
+	for _, ex := range result.Examples {
+		location := fmt.Sprintf("%s:%d", ex.File, ex.Line)
+		line := fmt.Sprintf("  %-60s %s", location, directiveName(ex))
+		if ex.Reason == ReasonUnrecognized {
+			line += fmt.Sprintf(" (unrecognized language %q)", ex.Language)
+		}
+		fmt.Fprintln(w, line)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Total: %d of %d code examples in %d files\n", len(result.Examples), result.TotalExamples, result.FilesScanned)
+}
+
+// printCSV prints one row per example with a header row.
+func printCSV(w io.Writer, result *Result) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"File", "Line", "Type", "Part", "Language", "Reason"}); err != nil {
+		return err
+	}
+	for _, ex := range result.Examples {
+		row := []string{ex.File, strconv.Itoa(ex.Line), ex.Type, ex.Part, ex.Language, ex.Reason}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// directiveName returns the directive type, with the part for io-code-block examples.
+func directiveName(ex MissingLanguageExample) string {
+	if ex.Part != "" {
+		return ex.Type + " (" + ex.Part + ")"
+	}
+	return ex.Type
+}
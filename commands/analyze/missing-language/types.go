@@ -0,0 +1,38 @@
+// Package missinglanguage provides functionality for finding code examples without a language.
+package missinglanguage
+
+// Reasons an example is listed.
+const (
+	// ReasonNoLanguage means the example has no language, so it resolves to "undefined"
+	ReasonNoLanguage = "no language"
+	// ReasonUnrecognized means the example's language has no product mapping (often a typo)
+	ReasonUnrecognized = "unrecognized language"
+)
+
+// MissingLanguageExample is a code example whose language is missing or unrecognized.
+type MissingLanguageExample struct {
+	// File is the source file, relative to the content directory
+	File string
+	// Line is the line number of the directive (for io-code-block, the parent directive)
+	Line int
+	// Type is the directive type (code-block, code, literalinclude, or io-code-block)
+	Type string
+	// Part is "input" or "output" for io-code-block examples, empty otherwise
+	Part string `json:",omitempty"`
+	// Language is the resolved language ("undefined" if none)
+	Language string
+	// Reason is ReasonNoLanguage or ReasonUnrecognized
+	Reason string
+}
+
+// Result represents the code examples found without a usable language.
+type Result struct {
+	// TotalExamples is the number of code examples checked
+	TotalExamples int
+	// FilesScanned is the number of .txt and .rst files parsed
+	FilesScanned int
+	// Examples lists the examples without a usable language, sorted by file, then line
+	Examples []MissingLanguageExample
+	// ContentDir is the path to the content directory
+	ContentDir string `json:"-"`
+}
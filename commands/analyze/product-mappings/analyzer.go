@@ -0,0 +1,60 @@
+package productmappings
+
+import (
+	"sort"
+
+	testablecode "github.com/grove-platform/audit-cli/commands/report/testable-code"
+)
+
+// BuildTables converts product mappings into sorted tables.
+//
+// When merged is non-nil, the tables show merged (base plus project composables) and
+// mark each entry that the project added or overrode. Entries are sorted by ID.
+//
+// Parameters:
+//   - base: Mappings loaded from rstspec.toml
+//   - merged: Mappings after MergeProjectComposables, or nil
+//
+// Returns:
+//   - []MappingTable: One table per ProductMappings field
+func BuildTables(base, merged *testablecode.ProductMappings) []MappingTable {
+	effective := base
+	if merged != nil {
+		effective = merged
+	}
+
+	return []MappingTable{
+		{
+			Name:    "DriversTabIDToProduct",
+			Source:  "[tabs.drivers]",
+			Entries: buildEntries(base.DriversTabIDToProduct, effective.DriversTabIDToProduct),
+		},
+		{
+			Name:    "ComposableLanguageToProduct",
+			Source:  "[[composables]] id=\"language\"",
+			Entries: buildEntries(base.ComposableLanguageToProduct, effective.ComposableLanguageToProduct),
+		},
+		{
+			Name:    "ComposableInterfaceToProduct",
+			Source:  "[[composables]] id=\"interface\"",
+			Entries: buildEntries(base.ComposableInterfaceToProduct, effective.ComposableInterfaceToProduct),
+		},
+	}
+}
+
+// buildEntries returns the effective mapping sorted by ID, marking entries that differ from base.
+func buildEntries(base, effective map[string]string) []MappingEntry {
+	entries := make([]MappingEntry, 0, len(effective))
+	for id, product := range effective {
+		baseProduct, ok := base[id]
+		entries = append(entries, MappingEntry{
+			ID:          id,
+			Product:     product,
+			FromProject: !ok || baseProduct != product,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID < entries[j].ID
+	})
+	return entries
+}
@@ -0,0 +1,60 @@
+package productmappings
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/grove-platform/audit-cli/internal/rst"
+)
+
+// PrintReport prints the product mapping tables in text format.
+//
+// Parameters:
+//   - w: Writer for the output
+//   - report: The mappings to print
+//   - now: The current time, used to report the rstspec cache age
+func PrintReport(w io.Writer, report *MappingsReport, now time.Time) {
+	fmt.Fprintln(w, strings.Repeat("=", 80))
+	fmt.Fprintln(w, "PRODUCT MAPPINGS")
+	fmt.Fprintln(w, strings.Repeat("=", 80))
+
+	if report.CacheTime.IsZero() {
+		fmt.Fprintln(w, "rstspec.toml cache: not found (mappings were fetched without caching)")
+	} else {
+		age := now.Sub(report.CacheTime).Truncate(time.Minute)
+		note := ""
+		if age > rst.RstspecCacheTTL {
+			note = ", expired"
+		}
+		fmt.Fprintf(w, "rstspec.toml cache: %s (fetched %s ago%s; refreshed after %s)\n",
+			report.CachePath, age, note, rst.RstspecCacheTTL)
+	}
+
+	if report.SourceFile != "" {
+		fmt.Fprintf(w, "Source file: %s\n", report.SourceFile)
+		if report.SnootyPath != "" {
+			fmt.Fprintf(w, "Project composables: %s (entries marked * come from the project)\n", report.SnootyPath)
+		} else {
+			fmt.Fprintln(w, "Project composables: none")
+		}
+	}
+
+	for _, table := range report.Tables {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "%s (%d) - from %s\n", table.Name, len(table.Entries), table.Source)
+		fmt.Fprintln(w, strings.Repeat("-", 80))
+		if len(table.Entries) == 0 {
+			fmt.Fprintln(w, "  (none)")
+			continue
+		}
+		for _, entry := range table.Entries {
+			marker := " "
+			if entry.FromProject {
+				marker = "*"
+			}
+			fmt.Fprintf(w, "%s %-30s %s\n", marker, entry.ID, entry.Product)
+		}
+	}
+}
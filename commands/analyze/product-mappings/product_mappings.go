@@ -0,0 +1,104 @@
+// Package productmappings provides functionality for inspecting rstspec-derived product mappings.
+//
+// This package implements the "analyze product-mappings" subcommand, which prints the
+// tab ID and composable mappings that report testable-code uses to attribute code
+// examples to products. When attribution looks wrong, this shows exactly what
+// LoadProductMappings built from rstspec.toml, and optionally what a project's
+// snooty.toml composables add on top.
+package productmappings
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	testablecode "github.com/grove-platform/audit-cli/commands/report/testable-code"
+	"github.com/grove-platform/audit-cli/internal/config"
+	"github.com/grove-platform/audit-cli/internal/rst"
+	"github.com/grove-platform/audit-cli/internal/snooty"
+	"github.com/spf13/cobra"
+)
+
+// NewProductMappingsCommand creates the product-mappings subcommand.
+//
+// This command prints the effective product mappings as sorted tables:
+//   - DriversTabIDToProduct
+//   - ComposableLanguageToProduct
+//   - ComposableInterfaceToProduct
+//
+// Usage:
+//
+//	analyze product-mappings
+//	analyze product-mappings /path/to/source/page.txt
+func NewProductMappingsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "product-mappings [source-file]",
+		Short: "Print the rstspec-derived product mappings used by report testable-code",
+		Long: `Print the product mappings that report testable-code uses to attribute code examples.
+
+The mappings are built from rstspec.toml (fetched from snooty-parser and cached in
+~/.audit-cli/rstspec-cache.json for 24 hours):
+  - DriversTabIDToProduct: driver tab :tabid: values, from [tabs.drivers]
+  - ComposableLanguageToProduct: language composable options
+  - ComposableInterfaceToProduct: interface composable options
+
+Each table is sorted by ID. The output also shows how old the cached rstspec.toml is.
+
+If a source file is given, the composables from its project's snooty.toml are merged
+in, as they are when that page is analyzed. Entries added or overridden by the project
+are marked with *.
+
+Examples:
+  # Check whether a new driver tabid is mapped
+  analyze product-mappings
+
+  # Include the Atlas project's composables
+  analyze product-mappings content/atlas/source/some-page.txt`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var sourceFile string
+			if len(args) > 0 {
+				filePath, err := config.ResolveFilePath(args[0])
+				if err != nil {
+					return err
+				}
+				sourceFile = filePath
+			}
+			return runProductMappings(sourceFile)
+		},
+	}
+
+	return cmd
+}
+
+// runProductMappings loads the product mappings and prints them.
+//
+// Parameters:
+//   - sourceFile: Source file whose project composables should be merged (may be empty)
+//
+// Returns:
+//   - error: Any error encountered while loading the mappings
+func runProductMappings(sourceFile string) error {
+	base, err := testablecode.GetProductMappings()
+	if err != nil {
+		return fmt.Errorf("failed to load product mappings: %w", err)
+	}
+
+	report := &MappingsReport{SourceFile: sourceFile}
+	if cachePath, cacheTime, err := rst.GetRstspecCacheInfo(); err == nil {
+		report.CachePath = cachePath
+		report.CacheTime = cacheTime
+	}
+
+	var merged *testablecode.ProductMappings
+	if sourceFile != "" {
+		merged = testablecode.MergeProjectComposables(base, sourceFile)
+		if snootyPath, err := snooty.FindProjectSnootyTOML(sourceFile); err == nil {
+			report.SnootyPath = snootyPath
+		}
+	}
+	report.Tables = BuildTables(base, merged)
+
+	PrintReport(os.Stdout, report, time.Now())
+	return nil
+}
@@ -0,0 +1,90 @@
+// Package productmappings provides tests for the product-mappings subcommand.
+package productmappings
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	testablecode "github.com/grove-platform/audit-cli/commands/report/testable-code"
+)
+
+// TestBuildTables tests sorting entries and marking project overrides.
+func TestBuildTables(t *testing.T) {
+	base := &testablecode.ProductMappings{
+		DriversTabIDToProduct:        map[string]string{"python": "Python", "java-sync": "Java (Sync)", "c": "C"},
+		ComposableLanguageToProduct:  map[string]string{"nodejs": "Node.js", "go": "Go"},
+		ComposableInterfaceToProduct: map[string]string{"mongosh": "MongoDB Shell"},
+	}
+	merged := &testablecode.ProductMappings{
+		DriversTabIDToProduct:        base.DriversTabIDToProduct,
+		ComposableLanguageToProduct:  map[string]string{"nodejs": "Node.js", "go": "Golang", "rust": "Rust"},
+		ComposableInterfaceToProduct: base.ComposableInterfaceToProduct,
+	}
+
+	tables := BuildTables(base, nil)
+	if len(tables) != 3 {
+		t.Fatalf("Expected 3 tables, got %d", len(tables))
+	}
+	var ids []string
+	for _, entry := range tables[0].Entries {
+		ids = append(ids, entry.ID)
+		if entry.FromProject {
+			t.Errorf("Expected no project entries without a source file, got %s", entry.ID)
+		}
+	}
+	if strings.Join(ids, ",") != "c,java-sync,python" {
+		t.Errorf("Expected entries sorted by ID, got %v", ids)
+	}
+
+	tables = BuildTables(base, merged)
+	language := tables[1].Entries
+	expected := []MappingEntry{
+		{ID: "go", Product: "Golang", FromProject: true},
+		{ID: "nodejs", Product: "Node.js", FromProject: false},
+		{ID: "rust", Product: "Rust", FromProject: true},
+	}
+	if len(language) != len(expected) {
+		t.Fatalf("Expected %d language entries, got %d: %+v", len(expected), len(language), language)
+	}
+	for i, want := range expected {
+		if language[i] != want {
+			t.Errorf("Language entry %d: expected %+v, got %+v", i, want, language[i])
+		}
+	}
+}
+
+// TestPrintReport tests the text output, including the cache age note.
+func TestPrintReport(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	report := &MappingsReport{
+		Tables: BuildTables(&testablecode.ProductMappings{
+			DriversTabIDToProduct: map[string]string{"python": "Python"},
+		}, nil),
+		CachePath: "/home/user/.audit-cli/rstspec-cache.json",
+		CacheTime: now.Add(-3 * time.Hour),
+	}
+
+	var buf bytes.Buffer
+	PrintReport(&buf, report, now)
+	output := buf.String()
+
+	for _, want := range []string{
+		"fetched 3h0m0s ago; refreshed after 24h0m0s",
+		"DriversTabIDToProduct (1) - from [tabs.drivers]",
+		"  python                         Python",
+		"ComposableLanguageToProduct (0)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	report.CacheTime = now.Add(-30 * time.Hour)
+	buf.Reset()
+	PrintReport(&buf, report, now)
+	if !strings.Contains(buf.String(), "ago, expired;") {
+		t.Errorf("Expected expired cache note, got:\n%s", buf.String())
+	}
+}
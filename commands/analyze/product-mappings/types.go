@@ -0,0 +1,31 @@
+// Package productmappings provides functionality for inspecting rstspec-derived product mappings.
+package productmappings
+
+import "time"
+
+// MappingEntry is a single ID-to-product mapping.
+type MappingEntry struct {
+	ID          string
+	Product     string
+	FromProject bool // True if the entry was added or overridden by the project's snooty.toml
+}
+
+// MappingTable is one of the product mapping tables used by report testable-code.
+type MappingTable struct {
+	Name    string // Field name in testablecode.ProductMappings (e.g., "DriversTabIDToProduct")
+	Source  string // Where the mapping is loaded from in rstspec.toml
+	Entries []MappingEntry
+}
+
+// MappingsReport contains the effective product mappings and where they came from.
+type MappingsReport struct {
+	Tables []MappingTable
+	// CachePath is the rstspec.toml cache file; empty if the cache could not be found.
+	CachePath string
+	// CacheTime is when the cached rstspec.toml was fetched; zero if unknown.
+	CacheTime time.Time
+	// SourceFile is the source file whose project composables were merged, if any.
+	SourceFile string
+	// SnootyPath is the project snooty.toml that was merged, if any.
+	SnootyPath string
+}
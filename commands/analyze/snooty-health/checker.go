@@ -0,0 +1,126 @@
+// Package snootyhealth provides functionality for validating snooty.toml files.
+package snootyhealth
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/grove-platform/audit-cli/internal/projectinfo"
+	"github.com/grove-platform/audit-cli/internal/snooty"
+)
+
+// CheckSnootyFiles checks every snooty.toml file in the monorepo.
+//
+// It looks in the same places that URL resolution does:
+//   - content/{project}/snooty.toml (non-versioned projects)
+//   - content/{project}/{version}/snooty.toml (versioned projects)
+//
+// With --follow-symlinks, symlinked project and version directories are checked too
+// (see projectinfo.EntryIsDir).
+//
+// Each file is parsed with snooty.ParseFile. The report records files that fail to
+// parse, files without a name field, and project names that are declared by more
+// than one content directory. Versions of the same project sharing a name are expected
+// and are not reported. For duplicates, the first content directory in alphabetical
+// order is treated as the owner.
+//
+// Parameters:
+//   - monorepoPath: Path to the MongoDB documentation monorepo
+//
+// Returns:
+//   - *HealthReport: The files checked and any issues found
+//   - error: Any error encountered reading the content directory
+func CheckSnootyFiles(monorepoPath string) (*HealthReport, error) {
+	absPath, err := filepath.Abs(monorepoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	contentDir := filepath.Join(absPath, "content")
+	entries, err := os.ReadDir(contentDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content directory: %w", err)
+	}
+
+	report := &HealthReport{ContentDir: contentDir}
+
+	// Track which content directory first declared each project name
+	nameOwners := make(map[string]string)
+
+	for _, entry := range entries {
+		if !projectinfo.EntryIsDir(contentDir, entry) {
+			continue
+		}
+		dirName := entry.Name()
+
+		// Non-versioned snooty.toml directly in the project directory
+		checkFile(report, contentDir, dirName, "", nameOwners)
+
+		// Versioned subdirectories
+		projectDir := filepath.Join(contentDir, dirName)
+		subEntries, err := os.ReadDir(projectDir)
+		if err != nil {
+			continue
+		}
+		for _, subEntry := range subEntries {
+			if !projectinfo.EntryIsDir(projectDir, subEntry) {
+				continue
+			}
+			checkFile(report, contentDir, dirName, subEntry.Name(), nameOwners)
+		}
+	}
+
+	return report, nil
+}
+
+// checkFile checks a single snooty.toml file if it exists and records the result.
+func checkFile(report *HealthReport, contentDir, dirName, version string, nameOwners map[string]string) {
+	relPath := filepath.Join(dirName, version, "snooty.toml")
+	path := filepath.Join(contentDir, relPath)
+	if _, err := os.Stat(path); err != nil {
+		return
+	}
+
+	file := SnootyFile{
+		FilePath:   relPath,
+		ContentDir: dirName,
+		Version:    version,
+	}
+
+	config, err := snooty.ParseFile(path)
+	if err != nil {
+		report.Files = append(report.Files, file)
+		report.Issues = append(report.Issues, Issue{
+			Kind:     ParseError,
+			FilePath: relPath,
+			Message:  err.Error(),
+		})
+		return
+	}
+
+	file.Name = config.Name
+	report.Files = append(report.Files, file)
+
+	if config.Name == "" {
+		report.Issues = append(report.Issues, Issue{
+			Kind:     MissingName,
+			FilePath: relPath,
+			Message:  "no name field in snooty.toml",
+		})
+		return
+	}
+
+	owner, exists := nameOwners[config.Name]
+	if !exists {
+		nameOwners[config.Name] = dirName
+		return
+	}
+	if owner != dirName {
+		report.Issues = append(report.Issues, Issue{
+			Kind:     DuplicateName,
+			FilePath: relPath,
+			Message:  fmt.Sprintf("project name %q is already declared by content/%s", config.Name, owner),
+		})
+	}
+}
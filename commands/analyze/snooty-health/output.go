@@ -0,0 +1,50 @@
+// Package snootyhealth provides functionality for validating snooty.toml files.
+package snootyhealth
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PrintReport prints the health check results in text format.
+//
+// Parameters:
+//   - w: Writer for the output
+//   - report: The health check results
+//   - verbose: If true, list every snooty.toml file checked
+func PrintReport(w io.Writer, report *HealthReport, verbose bool) {
+	fmt.Fprintln(w, strings.Repeat("=", 80))
+	fmt.Fprintln(w, "SNOOTY.TOML HEALTH CHECK")
+	fmt.Fprintln(w, strings.Repeat("=", 80))
+	fmt.Fprintf(w, "Content directory: %s\n", report.ContentDir)
+	fmt.Fprintf(w, "Files checked: %d\n", len(report.Files))
+	fmt.Fprintf(w, "Issues found: %d\n", len(report.Issues))
+
+	if verbose && len(report.Files) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "FILES")
+		fmt.Fprintln(w, strings.Repeat("-", 80))
+		for _, file := range report.Files {
+			name := file.Name
+			if name == "" {
+				name = "(none)"
+			}
+			fmt.Fprintf(w, "  %-50s %s\n", file.FilePath, name)
+		}
+	}
+
+	if len(report.Issues) == 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "All snooty.toml files are healthy.")
+		return
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "ISSUES")
+	fmt.Fprintln(w, strings.Repeat("-", 80))
+	for _, issue := range report.Issues {
+		fmt.Fprintf(w, "  [%s] %s\n", issue.Kind, issue.FilePath)
+		fmt.Fprintf(w, "    %s\n", issue.Message)
+	}
+}
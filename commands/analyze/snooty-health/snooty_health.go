@@ -0,0 +1,108 @@
+// Package snootyhealth provides functionality for validating snooty.toml files.
+//
+// This package implements the "analyze snooty-health" subcommand, which checks that
+// every snooty.toml file in the MongoDB documentation monorepo parses and declares a
+// unique project name. URL resolution (see internal/config) silently skips snooty.toml
+// files it cannot parse, so a malformed file shows up later as unresolvable pages.
+// Running this check before an audit surfaces those problems directly.
+package snootyhealth
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/grove-platform/audit-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// NewSnootyHealthCommand creates the snooty-health subcommand for analysis.
+//
+// This command checks every snooty.toml file under content/ and content/{project}/{version}/
+// and reports:
+//   - Files that fail to parse
+//   - Files with no name field
+//   - Project names declared by more than one content directory
+//
+// Usage:
+//
+//	analyze snooty-health /path/to/docs-monorepo
+//	analyze snooty-health --verbose
+//
+// Flags:
+//   - --verbose: List every snooty.toml file checked with its project name
+func NewSnootyHealthCommand() *cobra.Command {
+	var verbose bool
+
+	cmd := &cobra.Command{
+		Use:   "snooty-health [monorepo-path]",
+		Short: "Check that every snooty.toml file parses and has a unique name",
+		Long: `Check that every snooty.toml file in the MongoDB documentation monorepo is valid.
+
+This command looks for snooty.toml files in each project directory under content/
+and in each versioned subdirectory, and reports:
+  - Parse errors (malformed TOML)
+  - Missing name fields
+  - Duplicate project names declared by different content directories
+
+Versions of the same project (e.g., manual/v8.0 and manual/v7.0) are expected to share
+a name and are not reported as duplicates.
+
+The command exits with a non-zero status if any issue is found, so it can be used as
+a guard in CI or before running a large audit.
+
+Monorepo Path Configuration:
+  The monorepo path can be specified in three ways (in order of priority):
+    1. Command-line argument: analyze snooty-health /path/to/monorepo
+    2. Environment variable: export AUDIT_CLI_MONOREPO_PATH=/path/to/monorepo
+    3. Config file (.audit-cli.yaml):
+       monorepo_path: /path/to/monorepo
+
+Examples:
+  # Check all snooty.toml files in the monorepo
+  analyze snooty-health /path/to/docs-monorepo
+
+  # Use configured monorepo path
+  analyze snooty-health
+
+  # List every file checked
+  analyze snooty-health --verbose`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Resolve monorepo path from args, env, or config
+			var cmdLineArg string
+			if len(args) > 0 {
+				cmdLineArg = args[0]
+			}
+			monorepoPath, err := config.GetMonorepoPath(cmdLineArg)
+			if err != nil {
+				return err
+			}
+			if err := runSnootyHealth(monorepoPath, verbose); err != nil {
+				// Validation failures are not usage errors
+				cmd.SilenceUsage = true
+				return err
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "List every snooty.toml file checked")
+
+	return cmd
+}
+
+// runSnootyHealth executes the snooty.toml health check.
+// Returns an error if the check could not run or if any issue was found.
+func runSnootyHealth(monorepoPath string, verbose bool) error {
+	report, err := CheckSnootyFiles(monorepoPath)
+	if err != nil {
+		return err
+	}
+
+	PrintReport(os.Stdout, report, verbose)
+
+	if report.HasErrors() {
+		return fmt.Errorf("found %d snooty.toml issue(s)", len(report.Issues))
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+// Package snootyhealth provides tests for the snooty-health subcommand.
+package snootyhealth
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grove-platform/audit-cli/internal/projectinfo"
+)
+
+// TestCheckSnootyFiles tests checking a monorepo with healthy and broken snooty.toml files.
+func TestCheckSnootyFiles(t *testing.T) {
+	testDataDir := filepath.Join("..", "..", "..", "testdata", "snooty-health-test")
+
+	report, err := CheckSnootyFiles(testDataDir)
+	if err != nil {
+		t.Fatalf("CheckSnootyFiles failed: %v", err)
+	}
+
+	// atlas, atlas-copy, broken, manual/current, manual/v8.0, unnamed
+	if len(report.Files) != 6 {
+		t.Errorf("Expected 6 files checked, got %d", len(report.Files))
+	}
+
+	expected := map[string]IssueKind{
+		filepath.Join("atlas-copy", "snooty.toml"): DuplicateName,
+		filepath.Join("broken", "snooty.toml"):     ParseError,
+		filepath.Join("unnamed", "snooty.toml"):    MissingName,
+	}
+
+	if len(report.Issues) != len(expected) {
+		t.Fatalf("Expected %d issues, got %d: %+v", len(expected), len(report.Issues), report.Issues)
+	}
+
+	for _, issue := range report.Issues {
+		kind, ok := expected[issue.FilePath]
+		if !ok {
+			t.Errorf("Unexpected issue for %s: %s", issue.FilePath, issue.Message)
+			continue
+		}
+		if issue.Kind != kind {
+			t.Errorf("Expected %s for %s, got %s", kind, issue.FilePath, issue.Kind)
+		}
+	}
+
+	if !report.HasErrors() {
+		t.Error("Expected HasErrors to be true")
+	}
+}
+
+// TestCheckSnootyFilesVersionsShareName tests that versions of one project are not duplicates.
+func TestCheckSnootyFilesVersionsShareName(t *testing.T) {
+	testDataDir := filepath.Join("..", "..", "..", "testdata", "snooty-health-test")
+
+	report, err := CheckSnootyFiles(testDataDir)
+	if err != nil {
+		t.Fatalf("CheckSnootyFiles failed: %v", err)
+	}
+
+	for _, issue := range report.Issues {
+		if strings.HasPrefix(issue.FilePath, "manual") {
+			t.Errorf("Expected no issues for manual versions, got %s: %s", issue.Kind, issue.Message)
+		}
+	}
+}
+
+// TestCheckSnootyFilesHealthy tests a monorepo with only valid snooty.toml files.
+func TestCheckSnootyFilesHealthy(t *testing.T) {
+	testDataDir := filepath.Join("..", "..", "..", "testdata", "composables-test")
+
+	report, err := CheckSnootyFiles(testDataDir)
+	if err != nil {
+		t.Fatalf("CheckSnootyFiles failed: %v", err)
+	}
+
+	if report.HasErrors() {
+		t.Errorf("Expected no issues, got %+v", report.Issues)
+	}
+
+	var buf bytes.Buffer
+	PrintReport(&buf, report, false)
+	if !strings.Contains(buf.String(), "All snooty.toml files are healthy.") {
+		t.Errorf("Expected healthy message in output, got:\n%s", buf.String())
+	}
+}
+
+// TestCheckSnootyFilesMissingContentDir tests error handling for a path without content/.
+func TestCheckSnootyFilesMissingContentDir(t *testing.T) {
+	_, err := CheckSnootyFiles(t.TempDir())
+	if err == nil {
+		t.Error("Expected error for missing content directory")
+	}
+}
+
+// TestCheckSnootyFilesFollowSymlinks tests checking symlinked project and version
+// directories with --follow-symlinks, as URL resolution does.
+func TestCheckSnootyFilesFollowSymlinks(t *testing.T) {
+	defer projectinfo.SetFollowSymlinks(false)
+
+	monorepo := t.TempDir()
+	contentDir := filepath.Join(monorepo, "content")
+	external := t.TempDir()
+	versionDir := filepath.Join(external, "golang", "current")
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(versionDir, "snooty.toml"), []byte("name = \"golang\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(contentDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(external, "golang"), filepath.Join(contentDir, "golang")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	report, err := CheckSnootyFiles(monorepo)
+	if err != nil {
+		t.Fatalf("CheckSnootyFiles failed: %v", err)
+	}
+	if len(report.Files) != 0 {
+		t.Errorf("Expected the symlinked project to be skipped by default, got %+v", report.Files)
+	}
+
+	projectinfo.SetFollowSymlinks(true)
+	report, err = CheckSnootyFiles(monorepo)
+	if err != nil {
+		t.Fatalf("CheckSnootyFiles failed: %v", err)
+	}
+	if len(report.Files) != 1 || report.Files[0].Name != "golang" {
+		t.Errorf("Expected the symlinked golang/current snooty.toml, got %+v", report.Files)
+	}
+}
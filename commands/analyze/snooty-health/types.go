@@ -0,0 +1,41 @@
+// Package snootyhealth provides functionality for validating snooty.toml files.
+package snootyhealth
+
+// IssueKind identifies the type of problem found in a snooty.toml file.
+type IssueKind string
+
+const (
+	// ParseError means the snooty.toml file could not be parsed as TOML.
+	ParseError IssueKind = "parse-error"
+	// MissingName means the snooty.toml file has no name field.
+	MissingName IssueKind = "missing-name"
+	// DuplicateName means two different content directories declare the same project name.
+	DuplicateName IssueKind = "duplicate-name"
+)
+
+// Issue represents a single problem found in a snooty.toml file.
+type Issue struct {
+	Kind     IssueKind
+	FilePath string // Path relative to the content directory
+	Message  string
+}
+
+// SnootyFile represents a snooty.toml file that was checked.
+type SnootyFile struct {
+	FilePath   string // Path relative to the content directory
+	ContentDir string // Top-level directory under content/ (e.g., "manual")
+	Version    string // Version directory (e.g., "v8.0"), empty for non-versioned projects
+	Name       string // Project name from the name field, empty if missing or unparseable
+}
+
+// HealthReport contains the results of checking all snooty.toml files.
+type HealthReport struct {
+	ContentDir string       // Absolute path to the monorepo content directory
+	Files      []SnootyFile // All snooty.toml files found, in walk order
+	Issues     []Issue      // All problems found, in walk order
+}
+
+// HasErrors returns true if any issue was found.
+func (r *HealthReport) HasErrors() bool {
+	return len(r.Issues) > 0
+}
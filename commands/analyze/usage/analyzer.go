@@ -66,14 +66,14 @@ func AnalyzeUsage(targetFile string, includeToctree bool, verbose bool, excludeP
 	}
 
 	// Walk through all RST and YAML files in the source directory
-	err = filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
+	err = projectinfo.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
-		// Skip directories
+		// Skip directories, and don't descend into excluded ones
 		if info.IsDir() {
-			return nil
+			return projectinfo.SkipExcludedDir(sourceDir, path, info.Name())
 		}
 
 		// Only process RST files (.rst, .txt) and YAML files (.yaml, .yml)
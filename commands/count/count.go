@@ -4,11 +4,13 @@
 // Currently supports:
 //   - tested-examples: Count tested code examples in the MongoDB documentation monorepo
 //   - pages: Count documentation pages (.txt files) in the MongoDB documentation monorepo
+//   - languages: Count code examples by language across the MongoDB documentation monorepo
 //
 // These commands help writers track coverage metrics and report to stakeholders.
 package count
 
 import (
+	"github.com/grove-platform/audit-cli/commands/count/languages"
 	"github.com/grove-platform/audit-cli/commands/count/pages"
 	"github.com/grove-platform/audit-cli/commands/count/tested-examples"
 	"github.com/spf13/cobra"
@@ -28,12 +30,14 @@ Helps writers track coverage metrics and report statistics to stakeholders.
 
 Currently supports:
   - tested-examples: Count tested code examples in the documentation monorepo
-  - pages: Count documentation pages (.txt files) in the documentation monorepo`,
+  - pages: Count documentation pages (.txt files) in the documentation monorepo
+  - languages: Count code examples by language across the documentation monorepo`,
 	}
 
 	// Add subcommands
 	cmd.AddCommand(tested_examples.NewTestedExamplesCommand())
 	cmd.AddCommand(pages.NewPagesCommand())
+	cmd.AddCommand(languages.NewLanguagesCommand())
 
 	return cmd
 }
@@ -0,0 +1,84 @@
+// Package languages provides counting functionality for code examples by language.
+package languages
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/grove-platform/audit-cli/internal/projectinfo"
+	"github.com/grove-platform/audit-cli/internal/rst"
+)
+
+// CountLanguages counts code examples by language across all source files.
+//
+// This function walks every .txt and .rst file in the content directory, parses its
+// code-block, code, literalinclude, and io-code-block directives, and counts each code
+// example under its resolved language. Each io-code-block counts its input and output
+// as separate examples. Languages are resolved with rst.Directive.ResolveLanguage, so
+// aliases are normalized (e.g., "py" -> "python") and examples without a language
+// are counted as "undefined".
+//
+// Includes are not followed: each file is counted once, where it lives.
+//
+// Parameters:
+//   - dirPath: Path to the monorepo root or content directory
+//   - currentOnly: If true, skip non-current version directories of versioned projects
+//
+// Returns:
+//   - *CountResult: The counting results
+//   - error: Any error encountered during counting
+func CountLanguages(dirPath string, currentOnly bool) (*CountResult, error) {
+	absDirPath, err := filepath.Abs(dirPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	if _, err := os.Stat(absDirPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("directory does not exist: %s", absDirPath)
+	}
+
+	contentDir, err := projectinfo.FindContentDirectory(absDirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CountResult{ContentDir: contentDir}
+	counts := make(map[string]int)
+
+	result.FilesScanned, err = rst.WalkContentDirectives(contentDir, currentOnly, func(path string, directives []rst.Directive) {
+		for _, directive := range directives {
+			for _, ex := range directive.ExampleLanguages() {
+				counts[ex.Language]++
+				result.TotalCount++
+			}
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk content directory: %w", err)
+	}
+
+	result.Languages = sortedLanguageCounts(counts, result.TotalCount)
+	return result, nil
+}
+
+// sortedLanguageCounts converts language counts to a slice sorted by count
+// (descending), then language name, with each language's percentage of total.
+func sortedLanguageCounts(counts map[string]int, total int) []LanguageCount {
+	languages := make([]LanguageCount, 0, len(counts))
+	for lang, count := range counts {
+		languages = append(languages, LanguageCount{
+			Language:   lang,
+			Count:      count,
+			Percentage: float64(count) / float64(total) * 100,
+		})
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		if languages[i].Count != languages[j].Count {
+			return languages[i].Count > languages[j].Count
+		}
+		return languages[i].Language < languages[j].Language
+	})
+	return languages
+}
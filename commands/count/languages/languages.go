@@ -0,0 +1,98 @@
+// Package languages implements the languages subcommand for counting code examples by language.
+package languages
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/grove-platform/audit-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// NewLanguagesCommand creates the languages subcommand.
+//
+// This command counts code examples by language across every source file in the
+// MongoDB documentation monorepo.
+//
+// Usage:
+//
+//	count languages /path/to/docs-monorepo
+//	count languages /path/to/docs-monorepo --current-only
+//	count languages /path/to/docs-monorepo --format csv
+//
+// Flags:
+//   - --current-only: Only count examples in the current version of versioned projects
+//   - --format: Output format (text, json, or csv)
+func NewLanguagesCommand() *cobra.Command {
+	var (
+		currentOnly bool
+		format      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "languages [monorepo-path]",
+		Short: "Count code examples by language across the monorepo",
+		Long: `Count code examples by language across the MongoDB documentation monorepo.
+
+This command walks every .txt and .rst file in the content directory, parses its
+code-block, code, literalinclude, and io-code-block directives, and prints a table of
+languages with the number of code examples in each and their share of the total.
+Each io-code-block counts its input and output as separate examples.
+
+Languages are normalized (e.g., "py" -> "python"). Examples with no language are
+counted as "undefined". Includes are not followed: each file is counted once, where it
+lives. Files in code-examples directories are skipped.
+
+Monorepo Path Configuration:
+  The monorepo path can be specified in three ways (in order of priority):
+    1. Command-line argument: count languages /path/to/monorepo
+    2. Environment variable: export AUDIT_CLI_MONOREPO_PATH=/path/to/monorepo
+    3. Config file (.audit-cli.yaml):
+       monorepo_path: /path/to/monorepo
+
+Examples:
+  # Count code examples by language across the monorepo
+  count languages /path/to/docs-monorepo
+
+  # Use configured monorepo path
+  count languages
+
+  # Count only current versions
+  count languages --current-only
+
+  # Output as CSV for a spreadsheet
+  count languages --format csv > languages.csv`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Resolve monorepo path from args, env, or config
+			var cmdLineArg string
+			if len(args) > 0 {
+				cmdLineArg = args[0]
+			}
+			monorepoPath, err := config.GetMonorepoPath(cmdLineArg)
+			if err != nil {
+				return err
+			}
+			return runLanguages(monorepoPath, currentOnly, format)
+		},
+	}
+
+	cmd.Flags().BoolVar(&currentOnly, "current-only", false, "Only count examples in the current version")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text, json, or csv")
+
+	return cmd
+}
+
+// runLanguages executes the languages counting operation.
+func runLanguages(monorepoPath string, currentOnly bool, format string) error {
+	if format != "text" && format != "json" && format != "csv" {
+		return fmt.Errorf("invalid format: %s (must be 'text', 'json', or 'csv')", format)
+	}
+
+	result, err := CountLanguages(monorepoPath, currentOnly)
+	if err != nil {
+		return fmt.Errorf("failed to count languages: %w", err)
+	}
+
+	return PrintResults(os.Stdout, result, format)
+}
@@ -0,0 +1,130 @@
+// Package languages provides tests for the languages counting functionality.
+package languages
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grove-platform/audit-cli/internal/projectinfo"
+)
+
+// TestCountLanguages tests counting code examples by language across the monorepo.
+func TestCountLanguages(t *testing.T) {
+	testDataDir := filepath.Join("..", "..", "..", "testdata", "count-test-monorepo")
+
+	tests := []struct {
+		name           string
+		currentOnly    bool
+		expectedTotal  int
+		expectedCounts map[string]int
+	}{
+		{
+			name:          "all versions",
+			currentOnly:   false,
+			expectedTotal: 7,
+			// python: drivers/manual, drivers/v7.0 (py alias), manual
+			// javascript/json: io-code-block input and output in drivers/manual
+			// shell: literalinclude of a .sh file; undefined: code with no language
+			expectedCounts: map[string]int{"python": 3, "javascript": 1, "json": 1, "shell": 1, "undefined": 1},
+		},
+		{
+			name:           "current only skips drivers/v7.0",
+			currentOnly:    true,
+			expectedTotal:  6,
+			expectedCounts: map[string]int{"python": 2, "javascript": 1, "json": 1, "shell": 1, "undefined": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CountLanguages(testDataDir, tt.currentOnly)
+			if err != nil {
+				t.Fatalf("CountLanguages failed: %v", err)
+			}
+
+			if result.TotalCount != tt.expectedTotal {
+				t.Errorf("Expected total count %d, got %d", tt.expectedTotal, result.TotalCount)
+			}
+			if len(result.Languages) != len(tt.expectedCounts) {
+				t.Errorf("Expected %d languages, got %v", len(tt.expectedCounts), result.Languages)
+			}
+			for _, lang := range result.Languages {
+				if lang.Count != tt.expectedCounts[lang.Language] {
+					t.Errorf("Expected %s count %d, got %d", lang.Language, tt.expectedCounts[lang.Language], lang.Count)
+				}
+			}
+
+			// Sorted by count, then name
+			if result.Languages[0].Language != "python" || result.Languages[1].Language != "javascript" {
+				t.Errorf("Expected python then javascript first, got %v", result.Languages)
+			}
+		})
+	}
+}
+
+// TestCountLanguagesSkipsExcludedDirs tests that code examples in excluded directories are ignored.
+func TestCountLanguagesSkipsExcludedDirs(t *testing.T) {
+	defer projectinfo.SetExcludedDirs(nil)
+
+	monorepo := t.TempDir()
+	page := ".. code-block:: go\n\n   fmt.Println()\n"
+	for _, dir := range []string{"source", "build/source", "source/snapshots"} {
+		dirPath := filepath.Join(monorepo, "content", "proj", dir)
+		if err := os.MkdirAll(dirPath, 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dirPath, err)
+		}
+		if err := os.WriteFile(filepath.Join(dirPath, "page.txt"), []byte(page), 0644); err != nil {
+			t.Fatalf("failed to write page: %v", err)
+		}
+	}
+
+	result, err := CountLanguages(monorepo, false)
+	if err != nil {
+		t.Fatalf("CountLanguages failed: %v", err)
+	}
+	if result.TotalCount != 2 {
+		t.Errorf("Expected build directory to be skipped (total 2), got %d", result.TotalCount)
+	}
+
+	projectinfo.SetExcludedDirs([]string{"snapshots"})
+	result, err = CountLanguages(monorepo, false)
+	if err != nil {
+		t.Fatalf("CountLanguages failed: %v", err)
+	}
+	if result.TotalCount != 1 {
+		t.Errorf("Expected snapshots directory to be skipped (total 1), got %d", result.TotalCount)
+	}
+}
+
+// TestPrintResults tests the text, JSON, and CSV output formats.
+func TestPrintResults(t *testing.T) {
+	result := &CountResult{
+		TotalCount:   4,
+		FilesScanned: 2,
+		Languages:    sortedLanguageCounts(map[string]int{"python": 3, "go": 1}, 4),
+	}
+
+	tests := []struct {
+		format   string
+		expected []string
+	}{
+		{"text", []string{"python                     3   75.0%", "go                         1   25.0%", "Total: 4 code examples in 2 files"}},
+		{"json", []string{`"Language": "python"`, `"Percentage": 75`, `"TotalCount": 4`}},
+		{"csv", []string{"Language,Count,Percentage\npython,3,75.0\ngo,1,25.0\n"}},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := PrintResults(&buf, result, tt.format); err != nil {
+			t.Fatalf("PrintResults(%s) failed: %v", tt.format, err)
+		}
+		for _, want := range tt.expected {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("%s output missing %q:\n%s", tt.format, want, buf.String())
+			}
+		}
+	}
+}
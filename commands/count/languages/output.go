@@ -0,0 +1,66 @@
+// Package languages provides output formatting for language count results.
+package languages
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/grove-platform/audit-cli/internal/csv"
+)
+
+// PrintResults writes the counting results in the given format (text, json, or csv).
+//
+// Parameters:
+//   - w: Writer for the output
+//   - result: The counting results
+//   - format: Output format: text, json, or csv
+//
+// Returns:
+//   - error: Any error encountered while writing
+func PrintResults(w io.Writer, result *CountResult, format string) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	case "csv":
+		return printCSV(w, result)
+	default:
+		printText(w, result)
+		return nil
+	}
+}
+
+// printText prints a table of language, count, and percentage.
+func printText(w io.Writer, result *CountResult) {
+	if result.TotalCount == 0 {
+		fmt.Fprintln(w, "No code examples found")
+		return
+	}
+
+	fmt.Fprintln(w, "Code Examples by Language:")
+	fmt.Fprintln(w)
+	for _, lang := range result.Languages {
+		fmt.Fprintf(w, "  %-20s %7d  %5.1f%%\n", lang.Language, lang.Count, lang.Percentage)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Total: %d code examples in %d files\n", result.TotalCount, result.FilesScanned)
+}
+
+// printCSV prints one row per language with a header row.
+func printCSV(w io.Writer, result *CountResult) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"Language", "Count", "Percentage"}); err != nil {
+		return err
+	}
+	for _, lang := range result.Languages {
+		row := []string{lang.Language, strconv.Itoa(lang.Count), strconv.FormatFloat(lang.Percentage, 'f', 1, 64)}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
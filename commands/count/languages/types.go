@@ -0,0 +1,24 @@
+// Package languages provides functionality for counting code examples by language.
+package languages
+
+// LanguageCount is the number of code examples in one language.
+type LanguageCount struct {
+	// Language is the normalized language name (e.g., "python", "undefined")
+	Language string
+	// Count is the number of code examples in this language
+	Count int
+	// Percentage is Count as a percentage of all code examples
+	Percentage float64
+}
+
+// CountResult represents the result of counting code examples by language.
+type CountResult struct {
+	// TotalCount is the total number of code examples counted
+	TotalCount int
+	// FilesScanned is the number of .txt and .rst files parsed
+	FilesScanned int
+	// Languages lists the count for each language, sorted by count (descending), then name
+	Languages []LanguageCount
+	// ContentDir is the path to the content directory
+	ContentDir string `json:"-"`
+}
@@ -85,6 +85,10 @@ func CountPages(dirPath string, forProject string, excludeDirs []string, current
 		if info.IsDir() {
 			dirName := info.Name()
 
+			if projectinfo.IsExcludedDir(dirName) {
+				return filepath.SkipDir
+			}
+
 			// Check if this is a code-examples directory at root of content or source
 			if dirName == "code-examples" {
 				parentDir := filepath.Dir(path)
@@ -102,7 +102,7 @@ Examples:
 
 	cmd.Flags().StringVar(&forProject, "for-project", "", "Only count pages for a specific project")
 	cmd.Flags().BoolVar(&countByProject, "count-by-project", false, "Display counts for each project")
-	cmd.Flags().StringVar(&excludeDirs, "exclude-dirs", "", "Comma-separated list of directory names to exclude")
+	cmd.Flags().StringVar(&excludeDirs, "exclude-dirs", "", "Comma-separated list of directory names to exclude (in addition to the global --exclude-dir)")
 	cmd.Flags().BoolVar(&currentOnly, "current-only", false, "Only count pages in the current version")
 	cmd.Flags().BoolVar(&byVersion, "by-version", false, "Display counts grouped by project and version")
 
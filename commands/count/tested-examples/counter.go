@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/grove-platform/audit-cli/internal/projectinfo"
 )
 
 // CountTestedExamples counts tested code examples in the monorepo.
@@ -51,9 +53,9 @@ func CountTestedExamples(monorepoPath string, forProduct string, excludeOutput b
 			return err
 		}
 
-		// Skip directories
+		// Skip directories, and don't descend into excluded ones
 		if info.IsDir() {
-			return nil
+			return projectinfo.SkipExcludedDir(testedDir, path, info.Name())
 		}
 
 		// Get the file extension
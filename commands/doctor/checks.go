@@ -0,0 +1,186 @@
+package doctor
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/grove-platform/audit-cli/internal/config"
+	"github.com/grove-platform/audit-cli/internal/rst"
+)
+
+// checkMonorepo checks that the monorepo path resolved and contains a content directory.
+//
+// Parameters:
+//   - path: The path returned by config.GetMonorepoPath
+//   - err: The error returned by config.GetMonorepoPath
+func checkMonorepo(path string, err error) CheckResult {
+	result := CheckResult{Name: "Monorepo path"}
+	if err != nil {
+		// GetMonorepoPath's error is a one-line summary followed by setup instructions
+		summary, hint, _ := strings.Cut(err.Error(), "\n")
+		result.Status = StatusFail
+		result.Detail = summary
+		result.Hint = strings.TrimSpace(hint)
+		return result
+	}
+
+	contentDir := filepath.Join(path, "content")
+	info, statErr := os.Stat(contentDir)
+	if statErr != nil || !info.IsDir() {
+		result.Status = StatusFail
+		result.Detail = fmt.Sprintf("%s has no content/ directory", path)
+		result.Hint = "Set the monorepo path to the root of the docs monorepo (the directory that contains content/)."
+		return result
+	}
+
+	result.Status = StatusPass
+	result.Detail = fmt.Sprintf("%s (content/ found)", path)
+	return result
+}
+
+// checkCache checks that a cache file exists and is younger than its TTL.
+// A missing or stale cache is only a warning: it is refetched when a command needs it.
+//
+// Parameters:
+//   - name: Name of the check (e.g., "URL mapping cache")
+//   - path, fetched, err: The cache file's path and timestamp, and the error from reading it
+//   - ttl: How long the cache is used before it is refetched
+//   - now: The current time
+func checkCache(name, path string, fetched time.Time, err error, ttl time.Duration, now time.Time) CheckResult {
+	result := CheckResult{Name: name}
+	switch {
+	case os.IsNotExist(err):
+		result.Status = StatusWarn
+		result.Detail = fmt.Sprintf("%s not found", path)
+		result.Hint = "It is fetched and cached by the next command that needs it (requires network access)."
+	case err != nil:
+		result.Status = StatusWarn
+		result.Detail = err.Error()
+		result.Hint = fmt.Sprintf("Delete %s; it is refetched by the next command that needs it.", path)
+	default:
+		age := now.Sub(fetched).Truncate(time.Minute)
+		if age > ttl {
+			result.Status = StatusWarn
+			result.Detail = fmt.Sprintf("%s (fetched %s ago, expired after %s)", path, age, ttl)
+			result.Hint = "It is refetched by the next command that needs it, or used as-is if the network is unavailable."
+		} else {
+			result.Status = StatusPass
+			result.Detail = fmt.Sprintf("%s (fetched %s ago)", path, age)
+		}
+	}
+	return result
+}
+
+// checkEndpoint checks that a URL responds with HTTP 200.
+//
+// Parameters:
+//   - client: HTTP client to use (with a timeout)
+//   - name: Name of the check
+//   - url: URL to fetch
+//   - failStatus: Status to report if the URL can't be fetched
+//   - hint: Remediation hint if the URL can't be fetched
+//
+// In local mode (see config.SetLocal) the URL isn't fetched and the check is skipped.
+func checkEndpoint(client *http.Client, name, url string, failStatus Status, hint string) CheckResult {
+	result := CheckResult{Name: name}
+	if config.IsLocal() {
+		result.Status = StatusSkip
+		result.Detail = fmt.Sprintf("%s not checked (--local)", url)
+		return result
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		result.Status = failStatus
+		result.Detail = err.Error()
+		result.Hint = hint
+		return result
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		result.Status = failStatus
+		result.Detail = fmt.Sprintf("%s returned HTTP %d", url, resp.StatusCode)
+		result.Hint = hint
+		return result
+	}
+
+	result.Status = StatusPass
+	result.Detail = fmt.Sprintf("%s reachable", url)
+	return result
+}
+
+// checkRstspec checks the rstspec.toml that commands load: an overriding file
+// (--rstspec-file) must load and parse, and an overriding URL (--rstspec-url) or the
+// canonical rst.RstspecURL must be reachable. The check is named for the source used.
+//
+// Parameters:
+//   - client: HTTP client to use (with a timeout)
+//   - cached: Whether there is a cached rstspec.toml, which commands fall back to when
+//     the canonical URL can't be fetched
+func checkRstspec(client *http.Client, cached bool) CheckResult {
+	url, file, err := rst.RstspecSource()
+	if err != nil {
+		return CheckResult{Name: "rstspec.toml", Status: StatusFail, Detail: err.Error(),
+			Hint: "Unset one of them; flags take precedence over environment variables."}
+	}
+
+	if file != "" {
+		result := CheckResult{Name: "rstspec.toml override file"}
+		spec, err := rst.LoadRstspecFile(file)
+		if err != nil {
+			result.Status = StatusFail
+			result.Detail = err.Error()
+			result.Hint = fmt.Sprintf("Fix the file, or unset --rstspec-file and %s to use the canonical rstspec.toml.", rst.RstspecFileEnvVar)
+			return result
+		}
+		result.Status = StatusPass
+		result.Detail = fmt.Sprintf("%s loaded (%d composables)", file, len(spec.Composables))
+		return result
+	}
+
+	if url != "" {
+		hint := fmt.Sprintf("An overriding rstspec.toml isn't cached, so commands that need it fail. Check the URL, or unset --rstspec-url and %s.", rst.RstspecURLEnvVar)
+		if config.IsLocal() {
+			return CheckResult{Name: "rstspec.toml override URL", Status: StatusFail,
+				Detail: fmt.Sprintf("%s can't be fetched with --local", url), Hint: hint}
+		}
+		return checkEndpoint(client, "rstspec.toml override URL", url, StatusFail, hint)
+	}
+
+	// Without a cached copy, commands that need product mappings can't run offline
+	failStatus := StatusFail
+	if cached {
+		failStatus = StatusWarn
+	}
+	return checkEndpoint(client, "rstspec.toml", rst.RstspecURL, failStatus,
+		"report testable-code and analyze product-mappings need rstspec.toml; they use the cached copy if there is one. Check your network or proxy.")
+}
+
+// checkCacheDirWritable checks that audit-cli can create files in its cache directory.
+func checkCacheDirWritable(dir string) CheckResult {
+	result := CheckResult{Name: "Cache directory"}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		result.Status = StatusFail
+		result.Detail = err.Error()
+		result.Hint = fmt.Sprintf("Create %s and make it writable; audit-cli caches API data there.", dir)
+		return result
+	}
+
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		result.Status = StatusFail
+		result.Detail = err.Error()
+		result.Hint = fmt.Sprintf("Make %s writable; audit-cli caches API data there.", dir)
+		return result
+	}
+	f.Close()
+	os.Remove(f.Name())
+
+	result.Status = StatusPass
+	result.Detail = fmt.Sprintf("%s is writable", dir)
+	return result
+}
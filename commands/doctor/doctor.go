@@ -0,0 +1,153 @@
+// Package doctor provides the doctor command, which checks the audit-cli setup.
+//
+// New users hit failures that have nothing to do with the docs: no monorepo configured,
+// a stale cache, no network access. This command checks each prerequisite and prints a
+// checklist with a hint for anything that needs fixing.
+package doctor
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/grove-platform/audit-cli/internal/config"
+	"github.com/grove-platform/audit-cli/internal/rst"
+	"github.com/spf13/cobra"
+)
+
+// requestTimeout bounds each network check so doctor doesn't hang when offline.
+const requestTimeout = 10 * time.Second
+
+// NewDoctorCommand creates the doctor command.
+//
+// This command checks that audit-cli is set up correctly:
+//   - The monorepo path is configured and contains content/
+//   - The URL mapping and rstspec.toml caches are present and fresh
+//   - The Snooty Data API and rstspec.toml are reachable
+//   - The cache directory (~/.audit-cli) is writable
+//
+// Usage:
+//
+//	doctor
+//	doctor /path/to/docs-monorepo
+func NewDoctorCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor [monorepo-path]",
+		Short: "Check the audit-cli setup and print how to fix problems",
+		Long: `Check that audit-cli is set up correctly and print a checklist.
+
+Checks:
+  - Monorepo path: configured (argument, AUDIT_CLI_MONOREPO_PATH, or .audit-cli.yaml)
+    and contains a content/ directory
+  - URL mapping cache: ~/.audit-cli/url-mapping-cache.json is present and fresh
+  - rstspec.toml cache: ~/.audit-cli/rstspec-cache.json is present and fresh
+  - Snooty Data API: reachable (otherwise a static URL mapping is used)
+  - rstspec.toml: reachable (required unless it is already cached), or the
+    --rstspec-file / --rstspec-url override loads
+  - Cache directory: ~/.audit-cli is writable
+
+Each check is reported as PASS, WARN, or FAIL, with a hint for warnings and
+failures. Warnings don't stop audit-cli from running; failures do. With --local the
+network checks are reported as SKIP. The command exits with a non-zero status if
+any check fails.
+
+Examples:
+  # Check the configured monorepo
+  doctor
+
+  # Check a specific monorepo path
+  doctor /path/to/docs-monorepo`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var cmdLineArg string
+			if len(args) > 0 {
+				cmdLineArg = args[0]
+			}
+			if err := runDoctor(cmdLineArg); err != nil {
+				// Failed checks are not usage errors
+				cmd.SilenceUsage = true
+				return err
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// runDoctor runs every check, prints the checklist, and returns an error if any check failed.
+func runDoctor(cmdLineArg string) error {
+	results := runChecks(cmdLineArg, &http.Client{Timeout: requestTimeout}, time.Now())
+	PrintResults(os.Stdout, results)
+
+	if failed := countStatus(results, StatusFail); failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+// runChecks runs every check in the order they are printed.
+func runChecks(cmdLineArg string, client *http.Client, now time.Time) []CheckResult {
+	var results []CheckResult
+
+	results = append(results, checkMonorepo(config.GetMonorepoPath(cmdLineArg)))
+
+	urlPath, urlTime, urlErr := config.GetURLMappingCacheInfo()
+	results = append(results, checkCache("URL mapping cache", urlPath, urlTime, urlErr, config.CacheTTL, now))
+
+	rstspecPath, rstspecTime, rstspecErr := rst.GetRstspecCacheInfo()
+	results = append(results, checkCache("rstspec.toml cache", rstspecPath, rstspecTime, rstspecErr, rst.RstspecCacheTTL, now))
+
+	results = append(results, checkEndpoint(client, "Snooty Data API", config.SnootyDataAPIURL, StatusWarn,
+		"URL resolution falls back to a built-in mapping, which may be missing new projects. Check your network or proxy."))
+
+	results = append(results, checkRstspec(client, rstspecErr == nil))
+
+	if cacheDir, err := config.GetCacheDir(); err != nil {
+		results = append(results, CheckResult{
+			Name:   "Cache directory",
+			Status: StatusFail,
+			Detail: err.Error(),
+			Hint:   "Set HOME or " + config.EnvVarCacheDir + "; audit-cli caches API data in ~/.audit-cli by default.",
+		})
+	} else {
+		results = append(results, checkCacheDirWritable(cacheDir))
+	}
+
+	return results
+}
+
+// PrintResults prints the checklist and a summary line.
+func PrintResults(w io.Writer, results []CheckResult) {
+	for _, result := range results {
+		fmt.Fprintf(w, "[%s] %s: %s\n", result.Status, result.Name, result.Detail)
+		if (result.Status == StatusWarn || result.Status == StatusFail) && result.Hint != "" {
+			for _, line := range strings.Split(result.Hint, "\n") {
+				if line == "" {
+					continue
+				}
+				fmt.Fprintf(w, "       %s\n", line)
+			}
+		}
+	}
+	fmt.Fprintf(w, "\n%d passed, %d warning(s), %d failed",
+		countStatus(results, StatusPass), countStatus(results, StatusWarn), countStatus(results, StatusFail))
+	if skipped := countStatus(results, StatusSkip); skipped > 0 {
+		fmt.Fprintf(w, ", %d skipped", skipped)
+	}
+	fmt.Fprintln(w)
+}
+
+// countStatus returns the number of results with the given status.
+func countStatus(results []CheckResult, status Status) int {
+	count := 0
+	for _, result := range results {
+		if result.Status == status {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,200 @@
+package doctor
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grove-platform/audit-cli/internal/config"
+	"github.com/grove-platform/audit-cli/internal/rst"
+)
+
+// TestCheckMonorepo tests the monorepo path check.
+func TestCheckMonorepo(t *testing.T) {
+	monorepo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(monorepo, "content"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name           string
+		path           string
+		err            error
+		expectedStatus Status
+		expectedHint   string
+	}{
+		{"configured", monorepo, nil, StatusPass, ""},
+		{"no content directory", t.TempDir(), nil, StatusFail, "contains content/"},
+		{"not configured", "", errors.New("no monorepo path configured\n\nPlease configure the monorepo path"), StatusFail, "Please configure"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := checkMonorepo(tc.path, tc.err)
+			if result.Status != tc.expectedStatus {
+				t.Errorf("Expected status %s, got %s (%s)", tc.expectedStatus, result.Status, result.Detail)
+			}
+			if !strings.Contains(result.Hint, tc.expectedHint) {
+				t.Errorf("Expected hint containing %q, got %q", tc.expectedHint, result.Hint)
+			}
+		})
+	}
+
+	if result := checkMonorepo("", errors.New("no monorepo path configured\n\nPlease configure")); result.Detail != "no monorepo path configured" {
+		t.Errorf("Expected the first line of the error as detail, got %q", result.Detail)
+	}
+}
+
+// TestCheckCache tests the cache freshness check.
+func TestCheckCache(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		fetched        time.Time
+		err            error
+		expectedStatus Status
+	}{
+		{"fresh", now.Add(-time.Hour), nil, StatusPass},
+		{"expired", now.Add(-48 * time.Hour), nil, StatusWarn},
+		{"missing", time.Time{}, os.ErrNotExist, StatusWarn},
+		{"unreadable", time.Time{}, errors.New("failed to parse cache"), StatusWarn},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := checkCache("URL mapping cache", "/cache.json", tc.fetched, tc.err, 24*time.Hour, now)
+			if result.Status != tc.expectedStatus {
+				t.Errorf("Expected status %s, got %s (%s)", tc.expectedStatus, result.Status, result.Detail)
+			}
+		})
+	}
+}
+
+// TestCheckEndpoint tests the network reachability check.
+func TestCheckEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := server.Client()
+	if result := checkEndpoint(client, "API", server.URL+"/ok", StatusFail, "hint"); result.Status != StatusPass {
+		t.Errorf("Expected pass, got %s (%s)", result.Status, result.Detail)
+	}
+	result := checkEndpoint(client, "API", server.URL+"/missing", StatusWarn, "hint")
+	if result.Status != StatusWarn || !strings.Contains(result.Detail, "HTTP 404") || result.Hint != "hint" {
+		t.Errorf("Expected warning for HTTP 404, got %+v", result)
+	}
+
+	config.SetLocal(true)
+	defer config.SetLocal(false)
+	result = checkEndpoint(client, "API", server.URL+"/ok", StatusFail, "hint")
+	if result.Status != StatusSkip || !strings.Contains(result.Detail, "--local") {
+		t.Errorf("Expected the check to be skipped with --local, got %+v", result)
+	}
+}
+
+// TestCheckRstspec tests that the rstspec.toml check follows --rstspec-file and --rstspec-url.
+func TestCheckRstspec(t *testing.T) {
+	t.Setenv(rst.RstspecURLEnvVar, "")
+	t.Setenv(rst.RstspecFileEnvVar, "")
+	defer rst.SetRstspecSource("", "")
+
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "rstspec.toml")
+	if err := os.WriteFile(valid, []byte("[[composables]]\nid = \"language\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	invalid := filepath.Join(dir, "invalid.toml")
+	if err := os.WriteFile(invalid, []byte("name = \"not rstspec\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// An unreachable client shows the override file is loaded instead of fetched
+	client := &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("offline")
+	})}
+	rst.SetRstspecSource("", valid)
+	if result := checkRstspec(client, false); result.Status != StatusPass || result.Name != "rstspec.toml override file" {
+		t.Errorf("Expected the override file to pass, got %+v", result)
+	}
+	rst.SetRstspecSource("", invalid)
+	if result := checkRstspec(client, true); result.Status != StatusFail || !strings.Contains(result.Detail, "no [[composables]]") {
+		t.Errorf("Expected an invalid override file to fail, got %+v", result)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	rst.SetRstspecSource(server.URL+"/rstspec.toml", "")
+	if result := checkRstspec(server.Client(), false); result.Status != StatusPass || result.Name != "rstspec.toml override URL" {
+		t.Errorf("Expected the override URL to pass, got %+v", result)
+	}
+
+	rst.SetRstspecSource("", "")
+	if result := checkRstspec(client, true); result.Status != StatusWarn || result.Name != "rstspec.toml" {
+		t.Errorf("Expected a warning for the unreachable canonical URL with a cache, got %+v", result)
+	}
+}
+
+// roundTripFunc adapts a function to an http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f.
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// TestCheckCacheDirWritable tests the cache directory check.
+func TestCheckCacheDirWritable(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), ".audit-cli")
+	if result := checkCacheDirWritable(dir); result.Status != StatusPass {
+		t.Errorf("Expected pass, got %s (%s)", result.Status, result.Detail)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Expected the probe file to be removed, found %d file(s)", len(entries))
+	}
+
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if result := checkCacheDirWritable(filepath.Join(file, ".audit-cli")); result.Status != StatusFail {
+		t.Errorf("Expected fail when the cache directory can't be created, got %s", result.Status)
+	}
+}
+
+// TestPrintResults tests the checklist output.
+func TestPrintResults(t *testing.T) {
+	results := []CheckResult{
+		{Name: "Monorepo path", Status: StatusPass, Detail: "/docs (content/ found)", Hint: "not shown"},
+		{Name: "Snooty Data API", Status: StatusWarn, Detail: "offline", Hint: "Check your network."},
+		{Name: "Cache directory", Status: StatusFail, Detail: "permission denied", Hint: "Line one.\n\nLine two."},
+		{Name: "rstspec.toml", Status: StatusSkip, Detail: "not checked (--local)", Hint: "not shown"},
+	}
+
+	var buf bytes.Buffer
+	PrintResults(&buf, results)
+
+	expected := `[PASS] Monorepo path: /docs (content/ found)
+[WARN] Snooty Data API: offline
+       Check your network.
+[FAIL] Cache directory: permission denied
+       Line one.
+       Line two.
+[SKIP] rstspec.toml: not checked (--local)
+
+1 passed, 1 warning(s), 1 failed, 1 skipped
+`
+	if buf.String() != expected {
+		t.Errorf("Unexpected output:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
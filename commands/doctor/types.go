@@ -0,0 +1,28 @@
+package doctor
+
+// Status is the outcome of a single doctor check.
+type Status string
+
+// Check statuses, from best to worst, then StatusSkip for checks that weren't run.
+const (
+	// StatusPass means the check found no problem.
+	StatusPass Status = "PASS"
+	// StatusWarn means audit-cli can still run, but may be slow, stale, or offline.
+	StatusWarn Status = "WARN"
+	// StatusFail means commands that depend on this check will fail.
+	StatusFail Status = "FAIL"
+	// StatusSkip means the check wasn't run (e.g., network checks with --local).
+	StatusSkip Status = "SKIP"
+)
+
+// CheckResult is the outcome of one doctor check.
+type CheckResult struct {
+	// Name identifies what was checked (e.g., "Monorepo path").
+	Name string
+	// Status is pass, warn, fail, or skip.
+	Status Status
+	// Detail describes what was found (a path, an age, or an error).
+	Detail string
+	// Hint tells the user how to fix a warning or failure. May span several lines.
+	Hint string
+}
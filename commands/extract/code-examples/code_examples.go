@@ -30,8 +30,10 @@ import (
 //   - -f, --follow-includes: Follow .. include:: directives
 //   - -o, --output: Output directory for extracted files
 //   - --dry-run: Show what would be extracted without writing files
+//   - --summary-only: Only print the per-language summary, without writing files
 //   - -v, --verbose: Show detailed processing information
 //   - --preserve-dirs: Preserve directory structure when used with --recursive
+//   - --strict-extensions: Keep shell dialects distinct in output file extensions
 func NewCodeExamplesCommand() *cobra.Command {
 	var (
 		recursive      bool
@@ -40,6 +42,8 @@ func NewCodeExamplesCommand() *cobra.Command {
 		dryRun         bool
 		verbose        bool
 		preserveDirs   bool
+		strictExt      bool
+		summaryOnly    bool
 	)
 
 	cmd := &cobra.Command{
@@ -52,7 +56,19 @@ File Path Resolution:
   Paths can be specified as:
     1. Absolute path: /full/path/to/file.rst
     2. Relative to monorepo root (if configured): manual/manual/source/file.rst
-    3. Relative to current directory: ./file.rst`,
+    3. Relative to current directory: ./file.rst
+
+Output File Extensions:
+  By default, bash, sh, shell, zsh, and console examples are all written as .sh files.
+  Use --strict-extensions to keep shell dialects distinct: bash is written as .bash,
+  zsh as .zsh, and console (commands mixed with their output) as .txt. sh and shell
+  stay .sh, and PowerShell stays .ps1. This helps test harnesses that dispatch by
+  file extension.
+
+Summary:
+  After the report, a summary lists the number of files written, with files, lines,
+  and bytes per language and in total. Use --summary-only to print just the summary
+  without writing any files, for quick scoping.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Resolve file path (supports absolute, monorepo-relative, or cwd-relative)
@@ -60,7 +76,7 @@ File Path Resolution:
 			if err != nil {
 				return err
 			}
-			return runExtract(filePath, recursive, followIncludes, outputDir, dryRun, verbose, preserveDirs)
+			return runExtract(filePath, recursive, followIncludes, outputDir, dryRun, verbose, preserveDirs, strictExt, summaryOnly)
 		},
 	}
 
@@ -70,6 +86,8 @@ File Path Resolution:
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be outputted without writing files")
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Provide additional information during execution")
 	cmd.Flags().BoolVar(&preserveDirs, "preserve-dirs", false, "Preserve directory structure in output (use with --recursive)")
+	cmd.Flags().BoolVar(&summaryOnly, "summary-only", false, "Only print the per-language summary of what would be extracted, without writing files")
+	cmd.Flags().BoolVar(&strictExt, "strict-extensions", false, "Keep shell dialects distinct in file extensions (.bash, .zsh; console as .txt)")
 
 	return cmd
 }
@@ -87,12 +105,13 @@ File Path Resolution:
 //   - dryRun: If true, show what would be extracted without writing files
 //   - verbose: If true, show detailed processing information
 //   - preserveDirs: If true, preserve directory structure in output (use with recursive)
+//   - strictExt: If true, keep shell dialects distinct in file extensions (see language.GetExtensionFromLanguageStrict)
 //
 // Returns:
 //   - *Report: Statistics about the extraction operation
 //   - error: Any error encountered during extraction
-func RunExtract(filePath string, outputDir string, recursive bool, followIncludes bool, dryRun bool, verbose bool, preserveDirs bool) (*Report, error) {
-	report, err := runExtractInternal(filePath, recursive, followIncludes, outputDir, dryRun, verbose, preserveDirs)
+func RunExtract(filePath string, outputDir string, recursive bool, followIncludes bool, dryRun bool, verbose bool, preserveDirs bool, strictExt bool) (*Report, error) {
+	report, err := runExtractInternal(filePath, recursive, followIncludes, outputDir, dryRun, verbose, preserveDirs, strictExt, false)
 	return report, err
 }
 
@@ -100,13 +119,20 @@ func RunExtract(filePath string, outputDir string, recursive bool, followInclude
 //
 // This is a thin wrapper around runExtractInternal that discards the report
 // and only returns errors, suitable for use in the CLI command handler.
-func runExtract(filePath string, recursive bool, followIncludes bool, outputDir string, dryRun bool, verbose bool, preserveDirs bool) error {
-	_, err := runExtractInternal(filePath, recursive, followIncludes, outputDir, dryRun, verbose, preserveDirs)
+func runExtract(filePath string, recursive bool, followIncludes bool, outputDir string, dryRun bool, verbose bool, preserveDirs bool, strictExt bool, summaryOnly bool) error {
+	_, err := runExtractInternal(filePath, recursive, followIncludes, outputDir, dryRun, verbose, preserveDirs, strictExt, summaryOnly)
 	return err
 }
 
-// runExtractInternal executes the extraction operation
-func runExtractInternal(filePath string, recursive bool, followIncludes bool, outputDir string, dryRun bool, verbose bool, preserveDirs bool) (*Report, error) {
+// runExtractInternal executes the extraction operation.
+// If summaryOnly is true, no files are written and only the summary is printed.
+func runExtractInternal(filePath string, recursive bool, followIncludes bool, outputDir string, dryRun bool, verbose bool, preserveDirs bool, strictExt bool, summaryOnly bool) (*Report, error) {
+	// --summary-only is a quieter dry run
+	if summaryOnly {
+		dryRun = true
+		verbose = false
+	}
+
 	fileInfo, err := os.Stat(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to access path %s: %w", filePath, err)
@@ -170,7 +196,7 @@ func runExtractInternal(filePath string, recursive bool, followIncludes bool, ou
 		}
 
 		for _, example := range examples {
-			outputPath, err := WriteCodeExample(example, outputDir, rootPath, dryRun, preserveDirs)
+			outputPath, err := WriteCodeExample(example, outputDir, rootPath, dryRun, preserveDirs, strictExt)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to write code example: %v\n", err)
 				continue
@@ -191,10 +217,13 @@ func runExtractInternal(filePath string, recursive bool, followIncludes bool, ou
 		}
 	}
 
-	if dryRun {
-		fmt.Println("\n[DRY RUN MODE - No files were written]")
+	if !summaryOnly {
+		if dryRun {
+			fmt.Println("\n[DRY RUN MODE - No files were written]")
+		}
+		PrintReport(report, verbose)
 	}
-	PrintReport(report, verbose)
+	PrintSummary(os.Stdout, report, !dryRun)
 
 	return report, nil
 }
@@ -1,8 +1,11 @@
 package code_examples
 
 import (
+	"bytes"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/grove-platform/audit-cli/internal/rst"
@@ -23,7 +26,7 @@ func TestLiteralIncludeDirective(t *testing.T) {
 	defer os.RemoveAll(tempDir)
 
 	// Run the extract command
-	report, err := RunExtract(inputFile, tempDir, false, false, false, false, false)
+	report, err := RunExtract(inputFile, tempDir, false, false, false, false, false, false)
 	if err != nil {
 		t.Fatalf("RunExtract failed: %v", err)
 	}
@@ -112,7 +115,7 @@ func TestIncludeDirectiveFollowing(t *testing.T) {
 	defer os.RemoveAll(tempDir)
 
 	// Run the extract command with include following enabled
-	report, err := RunExtract(inputFile, tempDir, false, true, false, false, false)
+	report, err := RunExtract(inputFile, tempDir, false, true, false, false, false, false)
 	if err != nil {
 		t.Fatalf("RunExtract failed: %v", err)
 	}
@@ -168,7 +171,7 @@ func TestCodeBlockDirective(t *testing.T) {
 	defer os.RemoveAll(tempDir)
 
 	// Run extract on code-block test file
-	report, err := RunExtract(inputFile, tempDir, false, false, false, false, false)
+	report, err := RunExtract(inputFile, tempDir, false, false, false, false, false, false)
 	if err != nil {
 		t.Fatalf("RunExtract failed: %v", err)
 	}
@@ -231,7 +234,7 @@ func TestNestedCodeBlockDirective(t *testing.T) {
 	defer os.RemoveAll(tempDir)
 
 	// Run extract on nested code-block test file
-	report, err := RunExtract(inputFile, tempDir, false, false, false, false, false)
+	report, err := RunExtract(inputFile, tempDir, false, false, false, false, false, false)
 	if err != nil {
 		t.Fatalf("RunExtract failed: %v", err)
 	}
@@ -306,7 +309,7 @@ func TestIoCodeBlockDirective(t *testing.T) {
 	defer os.RemoveAll(tempDir)
 
 	// Run extract on io-code-block test file
-	report, err := RunExtract(inputFile, tempDir, false, false, false, false, false)
+	report, err := RunExtract(inputFile, tempDir, false, false, false, false, false, false)
 	if err != nil {
 		t.Fatalf("RunExtract failed: %v", err)
 	}
@@ -407,7 +410,7 @@ func TestEmptyFile(t *testing.T) {
 	}
 
 	// Run the extract command
-	report, err := RunExtract(emptyFile, outputDir, false, false, false, false, false)
+	report, err := RunExtract(emptyFile, outputDir, false, false, false, false, false, false)
 	if err != nil {
 		t.Fatalf("RunExtract failed: %v", err)
 	}
@@ -437,7 +440,7 @@ func TestRecursiveDirectoryScanning(t *testing.T) {
 	defer os.RemoveAll(tempDir)
 
 	// Run the extract command with recursive=true, followIncludes=false
-	report, err := RunExtract(inputDir, tempDir, true, false, false, false, false)
+	report, err := RunExtract(inputDir, tempDir, true, false, false, false, false, false)
 	if err != nil {
 		t.Fatalf("RunExtract failed: %v", err)
 	}
@@ -487,7 +490,7 @@ func TestFollowIncludesWithoutRecursive(t *testing.T) {
 	defer os.RemoveAll(tempDir)
 
 	// Run the extract command with recursive=false, followIncludes=true
-	report, err := RunExtract(inputFile, tempDir, false, true, false, false, false)
+	report, err := RunExtract(inputFile, tempDir, false, true, false, false, false, false)
 	if err != nil {
 		t.Fatalf("RunExtract failed: %v", err)
 	}
@@ -528,7 +531,7 @@ func TestRecursiveWithFollowIncludes(t *testing.T) {
 	defer os.RemoveAll(tempDir)
 
 	// Run the extract command with recursive=true, followIncludes=true
-	report, err := RunExtract(inputDir, tempDir, true, true, false, false, false)
+	report, err := RunExtract(inputDir, tempDir, true, true, false, false, false, false)
 	if err != nil {
 		t.Fatalf("RunExtract failed: %v", err)
 	}
@@ -575,7 +578,7 @@ func TestNoFlagsOnDirectory(t *testing.T) {
 	defer os.RemoveAll(tempDir)
 
 	// Run the extract command with recursive=false, followIncludes=false on a directory
-	report, err := RunExtract(inputDir, tempDir, false, false, false, false, false)
+	report, err := RunExtract(inputDir, tempDir, false, false, false, false, false, false)
 	if err != nil {
 		t.Fatalf("RunExtract failed: %v", err)
 	}
@@ -614,7 +617,7 @@ func TestPreserveDirs(t *testing.T) {
 	defer os.RemoveAll(tempDir)
 
 	// Run the extract command with recursive=true, preserveDirs=true
-	report, err := RunExtract(inputDir, tempDir, true, false, false, false, true)
+	report, err := RunExtract(inputDir, tempDir, true, false, false, false, true, false)
 	if err != nil {
 		t.Fatalf("RunExtract failed: %v", err)
 	}
@@ -678,7 +681,7 @@ func TestPreserveDirsWithoutRecursive(t *testing.T) {
 
 	// Run the extract command with recursive=false, preserveDirs=true
 	// This should work but have no effect since we're processing a single file
-	report, err := RunExtract(inputFile, tempDir, false, false, false, false, true)
+	report, err := RunExtract(inputFile, tempDir, false, false, false, false, true, false)
 	if err != nil {
 		t.Fatalf("RunExtract failed: %v", err)
 	}
@@ -698,3 +701,129 @@ func TestPreserveDirsWithoutRecursive(t *testing.T) {
 		t.Errorf("Expected 7 files in output directory, got %d", len(files))
 	}
 }
+
+// TestStrictExtensions tests that --strict-extensions keeps shell dialects distinct in filenames.
+func TestStrictExtensions(t *testing.T) {
+	tests := []struct {
+		language    string
+		defaultName string
+		strictName  string
+	}{
+		{"bash", "page.code-block.1.sh", "page.code-block.1.bash"},
+		{"zsh", "page.code-block.1.sh", "page.code-block.1.zsh"},
+		{"shell", "page.code-block.1.sh", "page.code-block.1.sh"},
+		{"console", "page.code-block.1.sh", "page.code-block.1.txt"},
+		{"powershell", "page.code-block.1.ps1", "page.code-block.1.ps1"},
+		{"python", "page.code-block.1.py", "page.code-block.1.py"},
+	}
+
+	for _, tt := range tests {
+		example := CodeExample{
+			SourceFile:    "page.rst",
+			DirectiveName: rst.CodeBlock,
+			Language:      tt.language,
+			Index:         1,
+		}
+		if got := GenerateOutputFilename(example, false); got != tt.defaultName {
+			t.Errorf("%s: expected default filename %s, got %s", tt.language, tt.defaultName, got)
+		}
+		if got := GenerateOutputFilename(example, true); got != tt.strictName {
+			t.Errorf("%s: expected strict filename %s, got %s", tt.language, tt.strictName, got)
+		}
+	}
+
+	// io-code-block output in console stays distinct from its bash input
+	output := CodeExample{
+		SourceFile:    "page.rst",
+		DirectiveName: rst.IoCodeBlock,
+		Language:      "console",
+		Index:         2,
+		SubType:       "output",
+	}
+	if got := GenerateOutputFilename(output, true); got != "page.io-code-block.2.output.txt" {
+		t.Errorf("Expected page.io-code-block.2.output.txt, got %s", got)
+	}
+}
+
+// TestCodeDirectiveFilename tests that .. code:: is extracted with the same filenames as .. code-block::.
+func TestCodeDirectiveFilename(t *testing.T) {
+	sourceFile := filepath.Join(t.TempDir(), "page.rst")
+	content := "Page\n====\n\n.. code:: python\n\n   print(\"code\")\n\nSame thing:\n\n.. code-block:: python\n\n   print(\"code-block\")\n"
+	if err := os.WriteFile(sourceFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create source file: %v", err)
+	}
+
+	examples, err := ParseFile(sourceFile)
+	if err != nil {
+		t.Fatalf("ParseFile failed: %v", err)
+	}
+	if len(examples) != 2 {
+		t.Fatalf("Expected 2 examples, got %d", len(examples))
+	}
+	for _, example := range examples {
+		if example.DirectiveName != rst.CodeBlock {
+			t.Errorf("Expected directive %s, got %s", rst.CodeBlock, example.DirectiveName)
+		}
+		expected := fmt.Sprintf("page.code-block.%d.py", example.Index)
+		if got := GenerateOutputFilename(example, false); got != expected {
+			t.Errorf("Expected %s, got %s", expected, got)
+		}
+	}
+}
+
+// TestSummaryOnly tests that --summary-only writes no files and the summary totals lines and bytes by language
+func TestSummaryOnly(t *testing.T) {
+	testDataDir := filepath.Join("..", "..", "..", "testdata")
+	inputFile := filepath.Join(testDataDir, "input-files", "source", "literalinclude-test.rst")
+	outputDir := filepath.Join(t.TempDir(), "output")
+
+	report, err := runExtractInternal(inputFile, false, false, outputDir, false, false, false, false, true)
+	if err != nil {
+		t.Fatalf("runExtractInternal failed: %v", err)
+	}
+	if _, err := os.Stat(outputDir); !os.IsNotExist(err) {
+		t.Errorf("Expected --summary-only not to create the output directory, got %v", err)
+	}
+	if report.OutputFilesWritten != 0 {
+		t.Errorf("Expected 0 output files written, got %d", report.OutputFilesWritten)
+	}
+
+	python := report.LanguageSummaries["python"]
+	if python == nil || python.Files != 1 {
+		t.Fatalf("Expected 1 python file in the summary, got %+v", python)
+	}
+	expected, err := os.ReadFile(filepath.Join(testDataDir, "expected-output", "literalinclude-test.literalinclude.1.py"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if python.Bytes != len(expected) || python.Lines != countLines(string(expected)) {
+		t.Errorf("Expected python %d lines, %d bytes, got %d lines, %d bytes",
+			countLines(string(expected)), len(expected), python.Lines, python.Bytes)
+	}
+
+	var buf bytes.Buffer
+	PrintSummary(&buf, report, false)
+	output := buf.String()
+	if !strings.Contains(output, "7 file(s) would be written") {
+		t.Errorf("Expected 7 files that would be written, got:\n%s", output)
+	}
+	if strings.Index(output, "go ") > strings.Index(output, "python ") {
+		t.Errorf("Expected languages sorted alphabetically, got:\n%s", output)
+	}
+}
+
+// TestCountLines tests counting lines with and without a trailing newline
+func TestCountLines(t *testing.T) {
+	tests := map[string]int{
+		"":             0,
+		"one":          1,
+		"one\n":        1,
+		"one\ntwo":     2,
+		"one\ntwo\n\n": 3,
+	}
+	for content, expected := range tests {
+		if got := countLines(content); got != expected {
+			t.Errorf("countLines(%q) = %d, expected %d", content, got, expected)
+		}
+	}
+}
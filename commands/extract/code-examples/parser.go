@@ -12,7 +12,8 @@ import (
 // This function parses all supported RST directives (literalinclude, code-block, code, io-code-block)
 // and converts them into CodeExample structs ready for writing to files.
 // Note: The "code" directive is a shorter alias for "code-block" in standard reStructuredText
-// and is treated identically.
+// and is treated identically: its DirectiveName is "code-block", so output filenames and
+// directive counts don't depend on which spelling the page uses.
 //
 // Parameters:
 //   - filePath: Path to the RST file to parse
@@ -46,7 +47,7 @@ func ParseFile(filePath string) ([]CodeExample, error) {
 			}
 			examples = append(examples, example)
 
-		case rst.CodeBlock:
+		case rst.CodeBlock, rst.Code:
 			example, err := parseCodeBlock(filePath, directive, index)
 			if err != nil {
 				// Log warning but continue processing
@@ -97,7 +98,7 @@ func parseCodeBlock(sourceFile string, directive rst.Directive, index int) (Code
 
 	return CodeExample{
 		SourceFile:    sourceFile,
-		DirectiveName: directive.Type,
+		DirectiveName: rst.CodeBlock, // Also for .. code::, which internal/rst tags as rst.Code
 		Language:      directive.ResolveLanguage(),
 		Content:       content,
 		Index:         index,
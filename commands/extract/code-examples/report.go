@@ -2,6 +2,7 @@ package code_examples
 
 import (
 	"fmt"
+	"io"
 	"sort"
 	"strings"
 
@@ -114,3 +115,44 @@ func PrintReport(report *Report, verbose bool) {
 
 	fmt.Println("\n" + strings.Repeat("=", 60))
 }
+
+// PrintSummary prints the number of code example files extracted, with a per-language
+// table of files, lines, and bytes sorted by language, and grand totals.
+//
+// Parameters:
+//   - w: Where to print the summary
+//   - report: The report to summarize
+//   - written: If false, files were not written (--dry-run or --summary-only)
+func PrintSummary(w io.Writer, report *Report, written bool) {
+	languages := make([]string, 0, len(report.LanguageSummaries))
+	var total LanguageSummary
+	for lang, summary := range report.LanguageSummaries {
+		languages = append(languages, lang)
+		total.Files += summary.Files
+		total.Lines += summary.Lines
+		total.Bytes += summary.Bytes
+	}
+	sort.Strings(languages)
+
+	verb := "written"
+	if !written {
+		verb = "would be written"
+	}
+	fmt.Fprintf(w, "\n%d file(s) %s: %d lines, %d bytes\n", total.Files, verb, total.Lines, total.Bytes)
+	if len(languages) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "  %-15s %8s %8s %10s\n", "Language", "Files", "Lines", "Bytes")
+	fmt.Fprintln(w, "  "+strings.Repeat("-", 44))
+	for _, lang := range languages {
+		summary := report.LanguageSummaries[lang]
+		name := lang
+		if name == "" {
+			name = "(unknown)"
+		}
+		fmt.Fprintf(w, "  %-15s %8d %8d %10d\n", name, summary.Files, summary.Lines, summary.Bytes)
+	}
+	fmt.Fprintln(w, "  "+strings.Repeat("-", 44))
+	fmt.Fprintf(w, "  %-15s %8d %8d %10d\n", "TOTAL", total.Files, total.Lines, total.Bytes)
+}
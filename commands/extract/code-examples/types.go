@@ -1,6 +1,11 @@
 package code_examples
 
-import "github.com/grove-platform/audit-cli/internal/rst"
+import (
+	"strings"
+
+	"github.com/grove-platform/audit-cli/internal/language"
+	"github.com/grove-platform/audit-cli/internal/rst"
+)
 
 // CodeExample represents a single code example extracted from an RST file.
 //
@@ -19,12 +24,20 @@ type CodeExample struct {
 //
 // Tracks overall statistics as well as per-source-file statistics for detailed reporting.
 type Report struct {
-	FilesTraversed     int                          // Total number of RST files processed
-	TraversedFilepaths []string                     // List of all processed file paths
-	OutputFilesWritten int                          // Total number of code example files written
-	LanguageCounts     map[string]int               // Count of examples by language
-	DirectiveCounts    map[rst.DirectiveType]int    // Count of examples by directive type
-	SourcePathStats    map[string]*SourceStats      // Per-file statistics
+	FilesTraversed     int                         // Total number of RST files processed
+	TraversedFilepaths []string                    // List of all processed file paths
+	OutputFilesWritten int                         // Total number of code example files written
+	LanguageCounts     map[string]int              // Count of examples by language
+	DirectiveCounts    map[rst.DirectiveType]int   // Count of examples by directive type
+	SourcePathStats    map[string]*SourceStats     // Per-file statistics
+	LanguageSummaries  map[string]*LanguageSummary // Files, lines, and bytes by language (see PrintSummary)
+}
+
+// LanguageSummary totals the code example files extracted for one language.
+type LanguageSummary struct {
+	Files int // Number of code example files
+	Lines int // Total lines of code
+	Bytes int // Total size in bytes
 }
 
 // SourceStats contains statistics for a single source file.
@@ -43,6 +56,7 @@ func NewReport() *Report {
 		LanguageCounts:     make(map[string]int),
 		DirectiveCounts:    make(map[rst.DirectiveType]int),
 		SourcePathStats:    make(map[string]*SourceStats),
+		LanguageSummaries:  make(map[string]*LanguageSummary),
 	}
 }
 
@@ -72,6 +86,33 @@ func (r *Report) AddCodeExample(example CodeExample, outputPath string) {
 	stats.DirectiveCounts[example.DirectiveName]++
 	stats.LanguageCounts[example.Language]++
 	stats.OutputFiles = append(stats.OutputFiles, outputPath)
+
+	// Examples without a directive language are summarized by their output file extension
+	lang := example.Language
+	if lang == "" {
+		lang = language.GetLanguageFromExtension(outputPath)
+	}
+	summary, exists := r.LanguageSummaries[lang]
+	if !exists {
+		summary = &LanguageSummary{}
+		r.LanguageSummaries[lang] = summary
+	}
+	summary.Files++
+	summary.Lines += countLines(example.Content)
+	summary.Bytes += len(example.Content)
+}
+
+// countLines returns the number of lines in content, counting a final line without a
+// trailing newline.
+func countLines(content string) int {
+	if content == "" {
+		return 0
+	}
+	lines := strings.Count(content, "\n")
+	if !strings.HasSuffix(content, "\n") {
+		lines++
+	}
+	return lines
 }
 
 // AddTraversedFile adds a file to the list of traversed files.
@@ -23,12 +23,13 @@ import (
 //   - rootPath: Root directory for computing relative paths (empty string if not preserving dirs)
 //   - dryRun: If true, skip writing and only return the filename
 //   - preserveDirs: If true, preserve directory structure in output
+//   - strictExt: If true, keep shell dialects distinct in the file extension
 //
 // Returns:
 //   - string: The full path to the output file
 //   - error: Any error encountered during writing
-func WriteCodeExample(example CodeExample, outputDir string, rootPath string, dryRun bool, preserveDirs bool) (string, error) {
-	filename := GenerateOutputFilename(example)
+func WriteCodeExample(example CodeExample, outputDir string, rootPath string, dryRun bool, preserveDirs bool, strictExt bool) (string, error) {
+	filename := GenerateOutputFilename(example, strictExt)
 
 	var outputPath string
 	var targetDir string
@@ -88,14 +89,19 @@ func WriteCodeExample(example CodeExample, outputDir string, rootPath string, dr
 //
 // Parameters:
 //   - example: The code example to generate a filename for
+//   - strictExt: If true, use language.GetExtensionFromLanguageStrict so shell dialects
+//     get distinct extensions (e.g., my-doc.code-block.1.bash)
 //
 // Returns:
 //   - string: The generated filename (without directory path)
-func GenerateOutputFilename(example CodeExample) string {
+func GenerateOutputFilename(example CodeExample, strictExt bool) string {
 	sourceBase := filepath.Base(example.SourceFile)
 	sourceBase = strings.TrimSuffix(sourceBase, filepath.Ext(sourceBase))
 
 	extension := language.GetExtensionFromLanguage(example.Language)
+	if strictExt {
+		extension = language.GetExtensionFromLanguageStrict(example.Language)
+	}
 
 	// For io-code-block, include the subtype (input/output) in the filename
 	if example.DirectiveName == rst.IoCodeBlock && example.SubType != "" {
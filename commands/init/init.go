@@ -0,0 +1,105 @@
+// Package initcmd provides the init command, which creates a .audit-cli.yaml config file.
+//
+// config.CreateSampleConfig writes the config file; this command makes first-run setup
+// discoverable instead of requiring users to write the YAML by hand.
+package initcmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/grove-platform/audit-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// NewInitCommand creates the init command.
+//
+// This command creates .audit-cli.yaml in the current directory with the monorepo path
+// set, and prints the file. An existing file is only replaced with --force.
+//
+// Usage:
+//
+//	init /path/to/docs-monorepo
+//	init --force /path/to/docs-monorepo
+//
+// Flags:
+//   - --force: Overwrite an existing .audit-cli.yaml
+func NewInitCommand() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "init [monorepo-path]",
+		Short: "Create a .audit-cli.yaml config file in the current directory",
+		Long: `Create a .audit-cli.yaml config file in the current directory.
+
+The file sets monorepo_path, so commands that need the docs monorepo can be run
+without passing its path. The path is stored as an absolute path. If no path is
+given, the current directory is used.
+
+An existing .audit-cli.yaml is not overwritten unless --force is passed. A warning
+is printed if the path has no content/ directory.
+
+Alternatively, set the AUDIT_CLI_MONOREPO_PATH environment variable, which takes
+precedence over the config file.
+
+Examples:
+  # Create the config file for a monorepo checkout
+  init /path/to/docs-monorepo
+
+  # Replace an existing config file
+  init --force /path/to/docs-monorepo`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			monorepoPath := "."
+			if len(args) > 0 {
+				monorepoPath = args[0]
+			}
+			return runInit(os.Stdout, monorepoPath, force)
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite an existing .audit-cli.yaml")
+
+	return cmd
+}
+
+// runInit creates the config file in the current directory and prints it.
+//
+// Parameters:
+//   - w: Writer for the output
+//   - monorepoPath: Path to the docs monorepo (made absolute)
+//   - force: Overwrite an existing config file
+//
+// Returns:
+//   - error: Error if the config file exists (without force) or can't be written
+func runInit(w io.Writer, monorepoPath string, force bool) error {
+	absPath, err := filepath.Abs(monorepoPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve monorepo path: %w", err)
+	}
+
+	if _, err := os.Stat(config.ConfigFileName); err == nil && !force {
+		return fmt.Errorf("%s already exists in the current directory (use --force to overwrite)", config.ConfigFileName)
+	}
+
+	if info, err := os.Stat(filepath.Join(absPath, "content")); err != nil || !info.IsDir() {
+		fmt.Fprintf(os.Stderr, "Warning: %s has no content/ directory; is it the root of the docs monorepo?\n", absPath)
+	}
+
+	if err := config.CreateSampleConfig(absPath); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(config.ConfigFileName)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	fmt.Fprintf(w, "Created %s:\n\n%s\n", config.ConfigFileName, data)
+	fmt.Fprintf(w, "Alternatively, set the environment variable instead of using a config file:\n")
+	fmt.Fprintf(w, "  export %s=%s\n", config.EnvVarMonorepoPath, absPath)
+	fmt.Fprintf(w, "\nRun \"audit-cli doctor\" to check the rest of your setup.\n")
+	return nil
+}
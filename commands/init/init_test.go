@@ -0,0 +1,58 @@
+package initcmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grove-platform/audit-cli/internal/config"
+)
+
+// TestRunInit tests creating the config file and refusing to overwrite it without --force.
+func TestRunInit(t *testing.T) {
+	tempDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	monorepo := filepath.Join(tempDir, "docs-monorepo")
+	if err := os.MkdirAll(filepath.Join(monorepo, "content"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := runInit(&buf, "docs-monorepo", false); err != nil {
+		t.Fatalf("runInit failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, config.ConfigFileName))
+	if err != nil {
+		t.Fatalf("Config file was not created: %v", err)
+	}
+	if !strings.Contains(string(data), "monorepo_path: "+monorepo) {
+		t.Errorf("Expected absolute monorepo path in config, got:\n%s", data)
+	}
+	output := buf.String()
+	if !strings.Contains(output, string(data)) || !strings.Contains(output, "export AUDIT_CLI_MONOREPO_PATH="+monorepo) {
+		t.Errorf("Expected output to show the config and the env-var alternative, got:\n%s", output)
+	}
+
+	// An existing file is only replaced with --force
+	if err := runInit(&buf, "/other/path", false); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("Expected error mentioning --force, got %v", err)
+	}
+	if data, _ := os.ReadFile(config.ConfigFileName); !strings.Contains(string(data), monorepo) {
+		t.Errorf("Expected existing config to be kept, got:\n%s", data)
+	}
+
+	if err := runInit(&buf, "/other/path", true); err != nil {
+		t.Fatalf("runInit with force failed: %v", err)
+	}
+	if data, _ := os.ReadFile(config.ConfigFileName); !strings.Contains(string(data), "monorepo_path: /other/path") {
+		t.Errorf("Expected config to be overwritten, got:\n%s", data)
+	}
+}
@@ -0,0 +1,219 @@
+package testablecode
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/grove-platform/audit-cli/internal/config"
+)
+
+// AnalysisCacheFileName is the name of the page analysis cache file in ~/.audit-cli.
+const AnalysisCacheFileName = "analysis-cache.json"
+
+// analysisCacheVersion is stored in the cache file. Bump it when PageAnalysis or the
+// collection logic changes, so analyses cached by an older audit-cli are not reused.
+const analysisCacheVersion = 7
+
+// AnalysisCache stores page analyses on disk so repeated audits of an unchanged monorepo
+// can skip re-parsing (enabled with --cache-analysis). An entry is reused only while its
+// fingerprint (product mappings, testable products, and collection options) matches and
+// every file read for the page keeps its size and modification time.
+//
+// A nil *AnalysisCache is valid and caches nothing.
+type AnalysisCache struct {
+	Version int
+	Entries map[string]*analysisCacheEntry
+
+	path   string
+	dirty  bool
+	hits   int
+	misses int
+}
+
+// analysisCacheEntry is the cached analysis of one page.
+type analysisCacheEntry struct {
+	Fingerprint string
+	Files       []cachedFile
+	Analysis    PageAnalysis
+}
+
+// cachedFile records the size and modification time of a file read for a page.
+type cachedFile struct {
+	Path    string
+	Size    int64
+	ModTime time.Time
+}
+
+// DefaultAnalysisCachePath returns the path to the analysis cache file
+// (~/.audit-cli/analysis-cache.json, or in the directory set with --cache-dir).
+func DefaultAnalysisCachePath() (string, error) {
+	cacheDir, err := config.GetCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, AnalysisCacheFileName), nil
+}
+
+// LoadAnalysisCache loads the analysis cache from path.
+//
+// A missing file, or one written by a different cache version, gives an empty cache.
+// A file that can't be parsed is reported as an error; callers can start with an empty
+// cache instead, which overwrites it on Save.
+//
+// Parameters:
+//   - path: Path to the cache file
+//
+// Returns:
+//   - *AnalysisCache: The loaded cache (never nil)
+//   - error: Error if the file exists but can't be read or parsed
+func LoadAnalysisCache(path string) (*AnalysisCache, error) {
+	cache := &AnalysisCache{
+		Version: analysisCacheVersion,
+		Entries: make(map[string]*analysisCacheEntry),
+		path:    path,
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cache, nil
+	}
+	if err != nil {
+		return cache, fmt.Errorf("failed to read analysis cache: %w", err)
+	}
+
+	var loaded AnalysisCache
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		return cache, fmt.Errorf("failed to parse analysis cache: %w", err)
+	}
+	if loaded.Version == analysisCacheVersion && loaded.Entries != nil {
+		cache.Entries = loaded.Entries
+	}
+	return cache, nil
+}
+
+// Save writes the cache to disk if any entries were added or replaced.
+// Nothing is written in local mode (see config.SetLocal).
+func (c *AnalysisCache) Save() error {
+	if c == nil || !c.dirty || config.IsLocal() {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to marshal analysis cache: %w", err)
+	}
+
+	if err := os.WriteFile(c.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write analysis cache: %w", err)
+	}
+
+	c.dirty = false
+	return nil
+}
+
+// Stats returns the number of cache hits and misses so far.
+func (c *AnalysisCache) Stats() (hits, misses int) {
+	if c == nil {
+		return 0, 0
+	}
+	return c.hits, c.misses
+}
+
+// load copies the cached collection results for sourcePath into analysis.
+// Returns false if there is no entry, the fingerprint differs, or any file changed.
+func (c *AnalysisCache) load(sourcePath, fingerprint string, analysis *PageAnalysis) bool {
+	if c == nil {
+		return false
+	}
+
+	entry, ok := c.Entries[sourcePath]
+	if !ok || entry.Fingerprint != fingerprint || !filesUnchanged(entry.Files) {
+		c.misses++
+		return false
+	}
+	c.hits++
+
+	cached := entry.Analysis
+	analysis.CodeExamples = append([]CodeExample(nil), cached.CodeExamples...)
+	analysis.IncludeDepth = cached.IncludeDepth
+	analysis.IncludeCycles = cached.IncludeCycles
+	analysis.DepthLimitedIncludes = cached.DepthLimitedIncludes
+	analysis.OversizedFiles = cached.OversizedFiles
+	analysis.IncludeParseErrors = cached.IncludeParseErrors
+	analysis.FragmentErrors = cached.FragmentErrors
+	analysis.UnresolvedIncludes = cached.UnresolvedIncludes
+	analysis.FilesScanned = cached.FilesScanned
+	analysis.BytesScanned = cached.BytesScanned
+	return true
+}
+
+// store records the collection results in analysis for sourcePath.
+func (c *AnalysisCache) store(sourcePath, fingerprint string, files []cachedFile, analysis *PageAnalysis) {
+	if c == nil {
+		return
+	}
+
+	// Only the collection results are cached; the page's rank, URL, and version come
+	// from the CSV entry, and verification runs on every hit.
+	cached := PageAnalysis{
+		CodeExamples:         append([]CodeExample(nil), analysis.CodeExamples...),
+		IncludeDepth:         analysis.IncludeDepth,
+		IncludeCycles:        analysis.IncludeCycles,
+		DepthLimitedIncludes: analysis.DepthLimitedIncludes,
+		OversizedFiles:       analysis.OversizedFiles,
+		IncludeParseErrors:   analysis.IncludeParseErrors,
+		FragmentErrors:       analysis.FragmentErrors,
+		UnresolvedIncludes:   analysis.UnresolvedIncludes,
+		FilesScanned:         analysis.FilesScanned,
+		BytesScanned:         analysis.BytesScanned,
+	}
+	c.Entries[sourcePath] = &analysisCacheEntry{
+		Fingerprint: fingerprint,
+		Files:       files,
+		Analysis:    cached,
+	}
+	c.dirty = true
+}
+
+// filesUnchanged reports whether every file still has its recorded size and modification time.
+func filesUnchanged(files []cachedFile) bool {
+	for _, file := range files {
+		info, err := os.Stat(file.Path)
+		if err != nil || info.Size() != file.Size || !info.ModTime().Equal(file.ModTime) {
+			return false
+		}
+	}
+	return true
+}
+
+// analysisFingerprint hashes everything besides file contents that affects how a page's
+// code examples are collected and classified.
+func analysisFingerprint(contentDir string, mappings *ProductMappings, opts AnalyzeOptions) string {
+	// json.Marshal sorts map keys, so equal mappings always hash the same
+	data, _ := json.Marshal(struct {
+		Version              int
+		ContentDir           string
+		Mappings             *ProductMappings
+		Testable             map[string]bool
+		MaybeTestable        map[string]bool
+		MaxIncludeDepth      int
+		MaxFileSize          int64
+		ContextAware         bool
+		RenderedOnly         bool
+		IncludeSource        bool
+		ContentDirOverrides  map[string]string
+		JavaScriptAsNodeJS   bool
+		UseComposableDefault bool
+	}{analysisCacheVersion, contentDir, mappings, TestableProducts, MaybeTestableProducts, opts.MaxIncludeDepth, opts.MaxFileSize, opts.ContextAware, opts.RenderedOnly, opts.IncludeSource, opts.ContentDirOverrides, opts.JavaScriptAsNodeJS, opts.UseComposableDefault})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
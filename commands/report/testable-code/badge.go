@@ -0,0 +1,72 @@
+package testablecode
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DefaultBadgeThresholds are the --badge-thresholds used when the flag isn't set:
+// coverage below 50% is red, below 80% is yellow, and 80% or more is green.
+const DefaultBadgeThresholds = "50:80"
+
+// Badge is a Shields.io endpoint badge (https://shields.io/badges/endpoint-badge) for
+// --badge, showing the site-wide coverage on any dashboard that can fetch the file.
+type Badge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// BuildBadge builds the coverage badge for a report summary. Coverage below low is red,
+// below high is yellow, and the rest is green. A report with no testable examples has
+// no coverage to show, so its badge says "n/a" in lightgrey.
+func BuildBadge(summary *ReportSummary, low, high float64) Badge {
+	badge := Badge{SchemaVersion: 1, Label: "code coverage", Message: "n/a", Color: "lightgrey"}
+	if summary.TotalTestable == 0 {
+		return badge
+	}
+	badge.Message = fmt.Sprintf("%.0f%%", summary.Coverage)
+	switch {
+	case summary.Coverage < low:
+		badge.Color = "red"
+	case summary.Coverage < high:
+		badge.Color = "yellow"
+	default:
+		badge.Color = "green"
+	}
+	return badge
+}
+
+// parseBadgeThresholds parses a --badge-thresholds value ("LOW:HIGH", coverage
+// percentages, e.g. 50:80).
+func parseBadgeThresholds(value string) (float64, float64, error) {
+	lowStr, highStr, ok := strings.Cut(value, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid --badge-thresholds %q (expected LOW:HIGH, e.g. %s)", value, DefaultBadgeThresholds)
+	}
+	low, lowErr := strconv.ParseFloat(strings.TrimSpace(lowStr), 64)
+	high, highErr := strconv.ParseFloat(strings.TrimSpace(highStr), 64)
+	if lowErr != nil || highErr != nil {
+		return 0, 0, fmt.Errorf("invalid --badge-thresholds %q (expected LOW:HIGH, e.g. %s)", value, DefaultBadgeThresholds)
+	}
+	if low < 0 || high > 100 || low > high {
+		return 0, 0, fmt.Errorf("invalid --badge-thresholds %q: thresholds must be percentages with LOW not greater than HIGH", value)
+	}
+	return low, high, nil
+}
+
+// WriteBadge writes the badge as JSON to path.
+func WriteBadge(path string, badge Badge) error {
+	data, err := json.Marshal(badge)
+	if err != nil {
+		return fmt.Errorf("failed to encode badge: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write badge: %w", err)
+	}
+	return nil
+}
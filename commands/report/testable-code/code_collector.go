@@ -2,7 +2,9 @@ package testablecode
 
 import (
 	"bufio"
+	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/grove-platform/audit-cli/internal/config"
@@ -22,16 +24,23 @@ import (
 //
 // The contentDir is extracted from the source path and used for product determination
 // when no explicit context (tabs, composables) is available.
-func AnalyzePage(entry PageEntry, urlMapping *config.URLMapping, mappings *ProductMappings) (*PageAnalysis, error) {
-	// Resolve URL to source file
-	sourcePath, contentDir, err := urlMapping.ResolveURL(entry.URL)
-	if err != nil {
-		return nil, err
+//
+// The opts control include traversal (see AnalyzeOptions). Include cycles and includes
+// skipped because of the depth limit are recorded on the returned PageAnalysis.
+func AnalyzePage(entry PageEntry, urlMapping *config.URLMapping, mappings *ProductMappings, opts AnalyzeOptions) (*PageAnalysis, error) {
+	// Resolve URL to source file; pages found with --dir already have one
+	resolution := config.URLResolution{SourcePath: entry.SourcePath, ContentDir: entry.ContentDir}
+	if entry.SourcePath == "" {
+		var err error
+		if resolution, err = urlMapping.Resolve(entry.URL); err != nil {
+			return nil, err
+		}
 	}
+	sourcePath, contentDir := resolution.SourcePath, resolution.ContentDir
 
 	// Check if source file exists
 	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
-		return nil, err
+		return nil, fmt.Errorf("%w: %w", config.ErrSourceNotFound, err)
 	}
 
 	// Merge project-specific composables from snooty.toml
@@ -43,19 +52,104 @@ func AnalyzePage(entry PageEntry, urlMapping *config.URLMapping, mappings *Produ
 		URL:        entry.URL,
 		SourcePath: sourcePath,
 		ContentDir: contentDir,
+		Version:    resolution.Version,
+
+		RequestedVersion: resolution.RequestedVersion,
+		RedirectedFrom:   resolution.RedirectedFrom,
+		RedirectedTo:     resolution.RedirectedTo,
+		Monorepo:         resolution.Monorepo,
 	}
 
-	// Collect code examples from the file and its includes
-	visited := make(map[string]bool)
-	examples, err := collectCodeExamples(sourcePath, contentDir, visited, mergedMappings)
-	if err != nil {
-		return nil, err
+	// Reuse the cached collection results if the page and its includes are unchanged
+	var fingerprint string
+	if opts.Cache != nil {
+		fingerprint = analysisFingerprint(contentDir, mergedMappings, opts)
+	}
+	if !opts.Cache.load(sourcePath, fingerprint, analysis) {
+		// Collect code examples from the file and its includes
+		walk := newIncludeWalk(opts.MaxIncludeDepth)
+		walk.maxFileSize = opts.MaxFileSize
+		walk.contextAware = opts.ContextAware
+		walk.renderedOnly = opts.RenderedOnly
+		walk.opts = &opts
+		examples, err := collectCodeExamples(sourcePath, contentDir, walk, mergedMappings)
+		if err != nil {
+			return nil, err
+		}
+		if !opts.IncludeSource {
+			// Bodies are only kept when they're reported, so they don't fill the cache
+			for i := range examples {
+				examples[i].Body = ""
+			}
+		}
+
+		analysis.CodeExamples = examples
+		analysis.IncludeDepth = walk.deepest
+		analysis.IncludeCycles = walk.cycles
+		analysis.DepthLimitedIncludes = walk.depthLimited
+		analysis.OversizedFiles = walk.oversized
+		analysis.IncludeParseErrors = walk.parseErrors
+		analysis.FragmentErrors = walk.fragmentErrs
+		analysis.UnresolvedIncludes = walk.unresolved
+		analysis.FilesScanned = walk.filesScanned
+		analysis.BytesScanned = walk.bytesScanned
+		opts.Cache.store(sourcePath, fingerprint, walk.files, analysis)
+	}
+
+	if opts.VerifyTested {
+		analysis.MissingTestedFiles = verifyTestedExamples(analysis.CodeExamples)
+	}
+	if opts.StrictPaths {
+		analysis.BadIncludeTargets = checkIncludeTargets(analysis.CodeExamples, monorepoRootOf(sourcePath, contentDir))
+	}
+	if opts.IncludeSource && opts.MaxSourceBytes > 0 {
+		embedIncludedFiles(analysis.CodeExamples, opts.MaxSourceBytes)
 	}
 
-	analysis.CodeExamples = examples
 	return analysis, nil
 }
 
+// includeWalk tracks include traversal state while collecting code examples for one page.
+// The visited ranges process each line of a shared include once; the chain of files being
+// processed tells a true include cycle apart from a file included again on another branch.
+type includeWalk struct {
+	visited      map[string][]rst.LineRange
+	chain        []string // Files currently being processed, from the page to the current file
+	maxDepth     int      // Maximum include depth to follow (0 = unlimited)
+	deepest      int      // Deepest include level reached (the page itself is level 0)
+	cycles       []IncludeCycle
+	depthLimited []string // Includes not followed because of maxDepth
+	maxFileSize  int64    // Maximum file size to scan in bytes (0 = unlimited)
+	oversized    []OversizedFile
+	parseErrors  []IncludeParseError
+	fragmentErrs []string // Partial includes whose fragment wasn't found (the whole file was counted)
+	unresolved   []string // Includes whose path couldn't be resolved (see PageAnalysis.UnresolvedIncludes)
+	filesScanned int
+	bytesScanned int64
+	files        []cachedFile    // Every file read, for AnalysisCache invalidation
+	contextAware bool            // Use only line-range context for examples (see AnalyzeOptions.ContextAware)
+	renderedOnly bool            // Skip examples that aren't rendered (see AnalyzeOptions.RenderedOnly)
+	opts         *AnalyzeOptions // Options that change how examples are classified (see classifyExample)
+}
+
+// newIncludeWalk creates an includeWalk with the given depth limit (0 = unlimited).
+func newIncludeWalk(maxDepth int) *includeWalk {
+	return &includeWalk{
+		visited:  make(map[string][]rst.LineRange),
+		maxDepth: maxDepth,
+	}
+}
+
+// onChain reports whether filePath is currently being processed further up the include chain.
+func (w *includeWalk) onChain(filePath string) bool {
+	for _, f := range w.chain {
+		if f == filePath {
+			return true
+		}
+	}
+	return false
+}
+
 // collectCodeExamples collects all code examples from a file and its includes.
 //
 // This is the public entry point that starts collection with no inherited context.
@@ -77,8 +171,8 @@ func AnalyzePage(entry PageEntry, urlMapping *config.URLMapping, mappings *Produ
 //	  └── collectCodeExamplesWithContext(main.txt, nil)
 //	        └── collectCodeExamplesWithContext(included.rst, inherited context)
 //	              └── collectCodeExamplesWithContext(nested.rst, inherited context)
-func collectCodeExamples(filePath, contentDir string, visited map[string]bool, mappings *ProductMappings) ([]CodeExample, error) {
-	return collectCodeExamplesWithContext(filePath, contentDir, visited, nil, mappings)
+func collectCodeExamples(filePath, contentDir string, walk *includeWalk, mappings *ProductMappings) ([]CodeExample, error) {
+	return collectCodeExamplesWithContext(filePath, rst.LineRange{}, contentDir, walk, nil, mappings)
 }
 
 // collectCodeExamplesWithContext collects code examples with inherited context from parent.
@@ -97,6 +191,9 @@ func collectCodeExamples(filePath, contentDir string, visited map[string]bool, m
 //  1. RST-in-YAML: RST directives embedded in YAML content (e.g., `.. code-block::` in `content: |` blocks)
 //  2. YAML-native: Legacy `action:` blocks with `language:` and `code:` fields (added January 2026)
 //
+// Markdown files (.md, .mdx) are parsed with rst.ParseMarkdownFile instead: each fenced
+// code block is a code example, with its language from the fence's info string.
+//
 // CONTEXT INHERITANCE:
 // When a file is included via `.. include::` within a `.. selected-content::` block
 // or a `.. tab::` block, the code examples in that included file should inherit the
@@ -112,18 +209,79 @@ func collectCodeExamples(filePath, contentDir string, visited map[string]bool, m
 //     (e.g., "Python" driver).
 //
 // The parentContext parameter carries this inherited context through the include chain.
-func collectCodeExamplesWithContext(filePath, contentDir string, visited map[string]bool, parentContext *CodeContext, mappings *ProductMappings) ([]CodeExample, error) {
-	if visited[filePath] {
+//
+// PARTIAL INCLUDES:
+// An include with :start-after: and/or :end-before: pulls in only part of a file. The
+// lines parameter is that part (the zero LineRange is the whole file): only directives
+// and nested includes that start within it are processed, so code examples outside the
+// fragment are not counted. The walk keeps the line ranges of each file it has processed,
+// so lines shared by overlapping fragments, or by a fragment and a whole-file include, are
+// only counted once. If the fragment's text isn't found, the whole file is counted and
+// the problem is recorded on the walk.
+//
+// INCLUDE TRAVERSAL:
+// The walk records each file as visited so shared includes are only counted once. If a
+// file is reached while it is still on the include chain, the include is a cycle: it is
+// recorded on the walk and not followed. When walk.maxDepth is set, includes below that
+// depth are recorded as depth-limited and not followed. When walk.maxFileSize is set, a
+// file larger than the limit is recorded as oversized and not scanned, so a few enormous
+// generated pages can't dominate the run.
+//
+// RENDERED EXAMPLES:
+// When walk.renderedOnly is set, directives with :visible: false, io-code-block outputs
+// with :visible: false, and directives and includes inside a collapsed collapsible block
+// are skipped, so only examples a reader sees when the page loads are counted.
+//
+// PARSE ERRORS:
+// Only a parse error in the page itself is returned. An included file that can't be
+// parsed (unreadable, or a line too long to scan) is recorded on the walk and skipped,
+// so one broken include doesn't lose the examples from the rest of the page.
+func collectCodeExamplesWithContext(filePath string, lines rst.LineRange, contentDir string, walk *includeWalk, parentContext *CodeContext, mappings *ProductMappings) ([]CodeExample, error) {
+	if walk.onChain(filePath) {
+		chain := append(append([]string{}, walk.chain...), filePath)
+		walk.cycles = append(walk.cycles, IncludeCycle{Chain: chain})
 		return nil, nil
 	}
-	visited[filePath] = true
+	seen := walk.visited[filePath]
+	for _, r := range seen {
+		if coversRange(r, lines) {
+			return nil, nil
+		}
+	}
+	walk.visited[filePath] = append(seen, lines)
+
+	walk.chain = append(walk.chain, filePath)
+	defer func() { walk.chain = walk.chain[:len(walk.chain)-1] }()
+
+	depth := len(walk.chain) - 1
+	if depth > walk.deepest {
+		walk.deepest = depth
+	}
+
+	if info, err := os.Stat(filePath); err == nil {
+		walk.files = append(walk.files, cachedFile{Path: filePath, Size: info.Size(), ModTime: info.ModTime()})
+		if walk.maxFileSize > 0 && info.Size() > walk.maxFileSize {
+			walk.oversized = append(walk.oversized, OversizedFile{Path: filePath, Size: info.Size()})
+			return nil, nil
+		}
+		walk.filesScanned++
+		walk.bytesScanned += info.Size()
+	}
 
 	var examples []CodeExample
 
-	// Parse directives from the file
-	directives, err := rst.ParseDirectives(filePath)
+	// Parse directives from the file; Markdown sources have fenced code blocks instead
+	parse := rst.ParseDirectives
+	if rst.IsMarkdownFile(filePath) {
+		parse = rst.ParseMarkdownFile
+	}
+	directives, err := parse(filePath)
 	if err != nil {
-		return nil, err
+		if depth == 0 {
+			return nil, err
+		}
+		walk.parseErrors = append(walk.parseErrors, IncludeParseError{Path: filePath, Error: err.Error()})
+		return nil, nil
 	}
 
 	// Parse selected-content blocks to get context for includes
@@ -150,20 +308,69 @@ func collectCodeExamplesWithContext(filePath, contentDir string, visited map[str
 		if err != nil {
 			fileContext = []CodeContext{{}}
 		}
+		if walk.contextAware {
+			// Tabs only apply within their line range, which contextBlocks covers
+			fileContext = withoutTabContexts(fileContext)
+		}
+	}
+
+	// Collapsed blocks hide their directives and includes when only rendered examples count
+	var collapsed []rst.LineRange
+	if walk.renderedOnly {
+		collapsed, err = rst.FindCollapsedBlocks(filePath)
+		if err != nil {
+			collapsed = nil
+		}
 	}
 
 	// Process each directive with its specific context
 	for _, directive := range directives {
+		if !lines.Contains(directive.LineNum) || inAnyRange(directive.LineNum, seen) || inAnyRange(directive.LineNum, collapsed) {
+			continue
+		}
+		if walk.renderedOnly {
+			if isHidden(directive.Options) {
+				continue
+			}
+			if directive.OutputDirective != nil && isHidden(directive.OutputDirective.Options) {
+				// directive is a copy, so this doesn't change the parsed directives
+				directive.OutputDirective = nil
+			}
+		}
 		// Find the context for this directive based on its line number
 		contexts := findContextForLine(directive.LineNum, contextBlocks, fileContext)
-		exs := processDirective(directive, filePath, contentDir, contexts, mappings)
+		exs := processDirective(directive, filePath, contentDir, contexts, mappings, walk.opts)
 		examples = append(examples, exs...)
 	}
 
 	// Follow includes with their selected-content context
-	includeFiles, err := rst.FindIncludeDirectives(filePath)
-	if err == nil {
-		for _, includeFile := range includeFiles {
+	includeRefs, err := rst.FindIncludeReferences(filePath)
+	var includes []rst.IncludeReference
+	for _, ref := range includeRefs {
+		if !lines.Contains(ref.LineNum) || inAnyRange(ref.LineNum, seen) || inAnyRange(ref.LineNum, collapsed) {
+			continue
+		}
+		if ref.Err != nil {
+			walk.unresolved = append(walk.unresolved, fmt.Sprintf("%s: %v", ref.Path, ref.Err))
+			continue
+		}
+		includes = append(includes, ref)
+	}
+	if err == nil && walk.maxDepth > 0 && depth >= walk.maxDepth {
+		// Depth limit reached - record the includes we are not following
+		for _, ref := range includes {
+			walk.depthLimited = append(walk.depthLimited, ref.ResolvedPath)
+		}
+	} else if err == nil {
+		for _, ref := range includes {
+			includeFile := ref.ResolvedPath
+			includeLines, err := ref.IncludedLines()
+			if err != nil {
+				// Count the whole file rather than silently dropping its examples
+				walk.fragmentErrs = append(walk.fragmentErrs, err.Error())
+				includeLines = rst.LineRange{}
+			}
+
 			// Check if this include has a selected-content or tab context
 			var includeContext *CodeContext
 			if selection, ok := selectedContentMap[includeFile]; ok {
@@ -179,7 +386,7 @@ func collectCodeExamplesWithContext(filePath, contentDir string, visited map[str
 				includeContext = parentContext
 			}
 
-			includedExamples, err := collectCodeExamplesWithContext(includeFile, contentDir, visited, includeContext, mappings)
+			includedExamples, err := collectCodeExamplesWithContext(includeFile, includeLines, contentDir, walk, includeContext, mappings)
 			if err == nil {
 				examples = append(examples, includedExamples...)
 			}
@@ -189,6 +396,33 @@ func collectCodeExamplesWithContext(filePath, contentDir string, visited map[str
 	return examples, nil
 }
 
+// isHidden reports whether a directive's options hide it when the page loads (:visible: false).
+func isHidden(options map[string]string) bool {
+	return strings.EqualFold(strings.TrimSpace(options["visible"]), "false")
+}
+
+// coversRange reports whether every line of inner is also in outer.
+func coversRange(outer, inner rst.LineRange) bool {
+	if inner.Last != 0 && inner.Last < inner.First {
+		return true // An empty fragment adds no lines
+	}
+	first := inner.First
+	if first < 1 {
+		first = 1
+	}
+	return outer.Contains(first) && (outer.Last == 0 || (inner.Last != 0 && inner.Last <= outer.Last))
+}
+
+// inAnyRange reports whether lineNum is in any of the ranges.
+func inAnyRange(lineNum int, ranges []rst.LineRange) bool {
+	for _, r := range ranges {
+		if r.Contains(lineNum) {
+			return true
+		}
+	}
+	return false
+}
+
 // contextBlock represents a context-providing block (tab or selected-content) with its line range.
 type contextBlock struct {
 	context   CodeContext
@@ -216,6 +450,7 @@ func parseContextBlocks(filePath string) ([]contextBlock, error) {
 		indent  int
 	}
 	var openBlocks []openBlock
+	var comments rst.CommentTracker
 
 	for scanner.Scan() {
 		lineNum++
@@ -239,6 +474,11 @@ func parseContextBlocks(filePath string) ([]contextBlock, error) {
 			}
 		}
 
+		// Commented-out tabs and selected-content blocks give no context
+		if comments.InComment(line) {
+			continue
+		}
+
 		// Check for tab directive
 		if rst.TabDirectiveRegex.MatchString(trimmedLine) {
 			openBlocks = append(openBlocks, openBlock{
@@ -302,6 +542,24 @@ func findContextForLine(lineNum int, contextBlocks []contextBlock, fileContext [
 	return fileContext
 }
 
+// withoutTabContexts returns the file-wide contexts from parseFileContexts (composable-tutorial
+// options), dropping the driver tab contexts it collects from anywhere in the file.
+//
+// With --context-aware, an example outside every tab block gets only these contexts, so
+// it falls back to its own language or content directory instead of the file's first tab.
+func withoutTabContexts(contexts []CodeContext) []CodeContext {
+	var fileWide []CodeContext
+	for _, ctx := range contexts {
+		if ctx.TabID == "" {
+			fileWide = append(fileWide, ctx)
+		}
+	}
+	if len(fileWide) == 0 {
+		fileWide = []CodeContext{{}}
+	}
+	return fileWide
+}
+
 // CodeContext represents the context in which a code example appears.
 //
 // MongoDB documentation uses several mechanisms to provide context for code examples:
@@ -322,23 +580,24 @@ func findContextForLine(lineNum int, contextBlocks []contextBlock, fileContext [
 // determines whether the example is testable (has test infrastructure).
 type CodeContext struct {
 	TabID      string // From :tabid: option in .. tab:: directive
-	Composable string // From composable-tutorial options (unused, kept for future)
+	Composable string // Language composable a composable-tutorial offers without a selection (e.g., "language")
 	Interface  string // From interface composable (e.g., "mongosh", "driver", "compass")
 	Language   string // From language composable (e.g., "python", "nodejs", "java")
 }
 
 // processDirective converts an RST directive to CodeExample(s).
 //
-// This function handles five types of code example directives:
+// This function handles six types of code example directives:
 //   - literalinclude: Transcludes code from an external file
 //   - code-block: Inline code block with language specification
 //   - code: Shorter alias for code-block (standard reStructuredText, parsed as code-block)
 //   - io-code-block: Input/output code example with separate input and output blocks
 //   - yaml-code-block: YAML-native code examples from legacy steps files (action: blocks)
+//   - fenced-code: Fenced code blocks in Markdown (.md, .mdx) source files
 //
 // For each directive, it determines the product based on the language and context,
 // checks if the example is tested (references tested code), and checks if it's testable.
-func processDirective(directive rst.Directive, sourceFile, contentDir string, contexts []CodeContext, mappings *ProductMappings) []CodeExample {
+func processDirective(directive rst.Directive, sourceFile, contentDir string, contexts []CodeContext, mappings *ProductMappings, opts *AnalyzeOptions) []CodeExample {
 	var examples []CodeExample
 
 	switch directive.Type {
@@ -350,23 +609,23 @@ func processDirective(directive rst.Directive, sourceFile, contentDir string, co
 		}
 		ex.Language = directive.ResolveLanguage()
 		ex.IsTested = isTestedPath(directive.Argument)
-		ex.Product = determineProduct(ex.Language, contentDir, contexts, mappings)
-		ex.IsTestable = isTestable(ex.Product, contentDir)
-		ex.IsMaybeTestable = isMaybeTestable(ex.Product)
+		classifyExample(&ex, contentDir, contexts, mappings, opts)
 		examples = append(examples, ex)
 
-	case rst.CodeBlock:
+	case rst.CodeBlock, rst.Code, rst.MarkdownCodeBlock:
+		// code and Markdown fences are classified like code-block, but keep their own Type
 		ex := CodeExample{
-			Type:       string(rst.CodeBlock),
+			Type:       string(directive.Type),
 			SourceFile: sourceFile,
+			Body:       directive.Content,
 		}
 		ex.Language = getLanguage(directive, directive.Argument)
-		ex.Product = determineProduct(ex.Language, contentDir, contexts, mappings)
-		ex.IsTestable = isTestable(ex.Product, contentDir)
-		ex.IsMaybeTestable = isMaybeTestable(ex.Product)
+		classifyExample(&ex, contentDir, contexts, mappings, opts)
 		examples = append(examples, ex)
 
 	case rst.IoCodeBlock:
+		// The input is always emitted first, even if the output is written first in the
+		// source, so CountIOPairsAsOne can pair them
 		// Process input directive
 		if directive.InputDirective != nil {
 			ex := CodeExample{
@@ -374,12 +633,11 @@ func processDirective(directive rst.Directive, sourceFile, contentDir string, co
 				IsInput:    true,
 				FilePath:   directive.InputDirective.Argument,
 				SourceFile: sourceFile,
+				Body:       directive.InputDirective.Content,
 			}
 			ex.Language = directive.InputDirective.ResolveLanguage(directive.Options)
 			ex.IsTested = isTestedPath(directive.InputDirective.Argument)
-			ex.Product = determineProduct(ex.Language, contentDir, contexts, mappings)
-			ex.IsTestable = isTestable(ex.Product, contentDir)
-			ex.IsMaybeTestable = isMaybeTestable(ex.Product)
+			classifyExample(&ex, contentDir, contexts, mappings, opts)
 			examples = append(examples, ex)
 		}
 
@@ -390,12 +648,11 @@ func processDirective(directive rst.Directive, sourceFile, contentDir string, co
 				IsOutput:   true,
 				FilePath:   directive.OutputDirective.Argument,
 				SourceFile: sourceFile,
+				Body:       directive.OutputDirective.Content,
 			}
 			ex.Language = directive.OutputDirective.ResolveLanguage(directive.Options)
 			ex.IsTested = isTestedPath(directive.OutputDirective.Argument)
-			ex.Product = determineProduct(ex.Language, contentDir, contexts, mappings)
-			ex.IsTestable = isTestable(ex.Product, contentDir)
-			ex.IsMaybeTestable = isMaybeTestable(ex.Product)
+			classifyExample(&ex, contentDir, contexts, mappings, opts)
 			examples = append(examples, ex)
 		}
 
@@ -404,17 +661,47 @@ func processDirective(directive rst.Directive, sourceFile, contentDir string, co
 		ex := CodeExample{
 			Type:       string(rst.YAMLCodeBlock),
 			SourceFile: sourceFile,
+			Body:       directive.Content,
 		}
 		ex.Language = getLanguage(directive, directive.Argument)
-		ex.Product = determineProduct(ex.Language, contentDir, contexts, mappings)
-		ex.IsTestable = isTestable(ex.Product, contentDir)
-		ex.IsMaybeTestable = isMaybeTestable(ex.Product)
+		classifyExample(&ex, contentDir, contexts, mappings, opts)
 		examples = append(examples, ex)
 	}
 
 	return examples
 }
 
+// classifyExample sets an example's product, origin, and testability from its language
+// and the surrounding context. opts may be nil for the default classification.
+func classifyExample(ex *CodeExample, contentDir string, contexts []CodeContext, mappings *ProductMappings, opts *AnalyzeOptions) {
+	var fromComposable string
+	if ex.Language == lang.Undefined && opts != nil && opts.UseComposableDefault {
+		if id, def := composableDefaultLanguage(contexts, mappings); def != "" {
+			ex.Language, fromComposable = def, id
+		}
+	}
+	ex.Product, ex.Origin, ex.ProductReason, ex.Provenance = explainProduct(ex.Language, contentDir, contexts, mappings, opts)
+	if fromComposable != "" {
+		ex.ProductReason += " (language from the " + fromComposable + " composable default in snooty.toml)"
+	}
+	ex.IsTestable = isTestable(ex.Product, contentDir)
+	ex.IsMaybeTestable = isMaybeTestable(ex.Product)
+}
+
+// composableDefaultLanguage returns the first language composable offered in contexts
+// that has a default in the project's snooty.toml, and that default.
+func composableDefaultLanguage(contexts []CodeContext, mappings *ProductMappings) (id, def string) {
+	for _, ctx := range contexts {
+		if ctx.Composable == "" {
+			continue
+		}
+		if def := mappings.ComposableDefaults[ctx.Composable]; def != "" {
+			return ctx.Composable, def
+		}
+	}
+	return "", ""
+}
+
 // getLanguage extracts the language from a directive.
 // Checks the :language: option first, then falls back to defaultLang.
 // If defaultLang is empty, returns lang.Undefined.
@@ -433,6 +720,124 @@ func isTestedPath(path string) bool {
 	return strings.Contains(path, "/tested/")
 }
 
+// verifyTestedExamples checks that each tested example's file exists on disk.
+//
+// isTestedPath only looks at the path string, so a stale /tested/ reference to a file
+// that was moved or deleted would still claim test coverage. Examples whose file cannot
+// be resolved are marked as not tested.
+//
+// Returns the paths (as written in the directive) of the missing files.
+func verifyTestedExamples(examples []CodeExample) []string {
+	var missing []string
+	for i := range examples {
+		ex := &examples[i]
+		if !ex.IsTested {
+			continue
+		}
+		if _, err := rst.ResolveIncludePath(ex.SourceFile, ex.FilePath); err != nil {
+			ex.IsTested = false
+			missing = append(missing, ex.FilePath)
+		}
+	}
+	return missing
+}
+
+// embedIncludedFiles sets the Body of each literalinclude and io-code-block file example
+// to the contents of the file it includes, for --include-source.
+//
+// The whole file is embedded: :start-after:, :end-before:, and other options that select
+// part of the file aren't recorded on the example. Files that can't be resolved or read,
+// or that are larger than maxBytes, are skipped and keep an empty Body.
+func embedIncludedFiles(examples []CodeExample, maxBytes int64) {
+	for i := range examples {
+		ex := &examples[i]
+		if ex.FilePath == "" || ex.Body != "" {
+			continue
+		}
+		resolved, err := rst.ResolveIncludePath(ex.SourceFile, ex.FilePath)
+		if err != nil {
+			continue
+		}
+		info, err := os.Stat(resolved)
+		if err != nil || info.Size() > maxBytes {
+			continue
+		}
+		data, err := os.ReadFile(resolved)
+		if err != nil {
+			continue
+		}
+		ex.Body = strings.TrimSpace(string(data))
+	}
+}
+
+// checkIncludeTargets checks that each literalinclude and io-code-block file resolves
+// inside the monorepo and exists.
+//
+// A path that climbs out of the project with ../ (e.g., /../../other/file.py) almost
+// always means a broken example reference: Snooty won't build it, but the page would
+// still be counted as if the example were fine. Each path is reported once per page.
+//
+// Parameters:
+//   - examples: The page's code examples
+//   - monorepoRoot: The monorepo the page is in ("" to only check that files exist)
+//
+// Returns:
+//   - []BadIncludeTarget: Paths that escape the monorepo or don't exist, in page order
+func checkIncludeTargets(examples []CodeExample, monorepoRoot string) []BadIncludeTarget {
+	var bad []BadIncludeTarget
+	seen := make(map[string]bool)
+	for _, ex := range examples {
+		if ex.FilePath == "" || seen[ex.FilePath] {
+			continue
+		}
+		seen[ex.FilePath] = true
+
+		resolved, err := rst.ResolveIncludePath(ex.SourceFile, ex.FilePath)
+		if err != nil {
+			// Not found: still check where the path points, lexically
+			if sourceDir, dirErr := projectinfo.FindSourceDirectory(ex.SourceFile); dirErr == nil {
+				resolved = filepath.Join(sourceDir, strings.TrimPrefix(ex.FilePath, "/"))
+			}
+		}
+		switch {
+		case monorepoRoot != "" && resolved != "" && !isWithinDir(resolved, monorepoRoot):
+			bad = append(bad, BadIncludeTarget{Type: ex.Type, Path: ex.FilePath, Outside: true})
+		case err != nil:
+			bad = append(bad, BadIncludeTarget{Type: ex.Type, Path: ex.FilePath})
+		}
+	}
+	return bad
+}
+
+// monorepoRootOf returns the monorepo a page's source file is in: the directory holding
+// content/<contentDir>. Returns "" if the path doesn't have that layout.
+func monorepoRootOf(sourcePath, contentDir string) string {
+	absPath, err := filepath.Abs(sourcePath)
+	if err != nil {
+		return ""
+	}
+	slashPath := filepath.ToSlash(absPath)
+	idx := strings.LastIndex(slashPath, "/content/"+contentDir+"/")
+	if idx < 0 {
+		return ""
+	}
+	return filepath.FromSlash(slashPath[:idx])
+}
+
+// isWithinDir reports whether path is dir or inside it.
+func isWithinDir(path, dir string) bool {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(absDir, absPath)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
 // isTestable checks if a code example is testable based on its product and content directory.
 //
 // A code example is considered testable if it meets one of these criteria:
@@ -470,6 +875,9 @@ func isMaybeTestable(product string) bool {
 }
 
 // determineProduct determines the product from language, content dir, and context.
+// It also returns the origin of the decision (one of the Origin* constants), which
+// records whether the product came from a tab, a composable, the content directory,
+// or the raw language.
 //
 // The logic handles several special cases:
 //
@@ -481,12 +889,29 @@ func isMaybeTestable(product string) bool {
 //   - In MongoDB Shell context (mongosh content dir or mongosh interface) → "MongoDB Shell"
 //   - "shell" outside MongoDB Shell context → "Shell" (not testable)
 //   - "javascript/js" outside MongoDB Shell context → use driver context or "JavaScript"
-func determineProduct(language, contentDir string, contexts []CodeContext, mappings *ProductMappings) string {
+//   - With AnalyzeOptions.JavaScriptAsNodeJS, "javascript/js" in a Node.js driver context →
+//     "Node.js", even if another context (e.g., the driver interface) comes first
+func determineProduct(language, contentDir string, contexts []CodeContext, mappings *ProductMappings) (string, string) {
+	product, origin, _, _ := explainProduct(language, contentDir, contexts, mappings, nil)
+	return product, origin
+}
+
+// explainProduct is determineProduct with a human-readable reason for the decision,
+// naming the specific context value that applied (used by --explain), and the
+// provenance of the mapping that applied (used by --provenance). opts adds the run's
+// classification options, such as content directory overrides; nil uses none.
+func explainProduct(language, contentDir string, contexts []CodeContext, mappings *ProductMappings, opts *AnalyzeOptions) (product, origin, reason string, provenance ProductProvenance) {
+	if opts == nil {
+		opts = &AnalyzeOptions{}
+	}
+	builtIn := ProductProvenance{Source: ProvenanceBuiltIn}
+
 	// Check if this is a non-driver language that should bypass context inheritance.
 	// These languages should be reported based on their actual language, not the
 	// surrounding composable/tab context.
 	if language != "" && lang.IsNonDriverLanguage(language) {
-		return lang.GetProductFromLanguage(language)
+		return lang.GetProductFromLanguage(language), OriginLanguage,
+			"non-driver language " + language + " ignores tab and composable context", builtIn
 	}
 
 	// Check if we're in a MongoDB Shell context
@@ -495,47 +920,101 @@ func determineProduct(language, contentDir string, contexts []CodeContext, mappi
 	// Handle MongoDB Shell languages specially
 	if language != "" && lang.IsMongoShellLanguage(language) {
 		if inMongoShellContext {
-			return "MongoDB Shell"
+			if contentDir == "mongodb-shell" {
+				return "MongoDB Shell", OriginContentDir, "language " + language + " in the mongodb-shell content directory", builtIn
+			}
+			return "MongoDB Shell", OriginComposableInterface, "language " + language + " with interface mongosh", builtIn
 		}
 		// "shell" outside MongoDB Shell context is just a shell command
 		langLower := strings.ToLower(language)
 		if langLower == "shell" {
-			return "Shell"
+			return "Shell", OriginLanguage, "shell outside a MongoDB Shell context is a system shell command", builtIn
 		}
 		// "javascript" or "js" outside MongoDB Shell context - check for driver context
 		// (fall through to normal context checking below)
+		if opts.JavaScriptAsNodeJS {
+			if origin, reason, ok := nodeJSContext(contentDir, contexts, mappings, opts); ok {
+				return "Node.js", origin, "language " + language + " in " + reason + " (--javascript-as-nodejs-in-context)", builtIn
+			}
+		}
 	}
 
 	// Check if we have a context with a specific product
 	for _, ctx := range contexts {
 		if ctx.TabID != "" {
 			if product, ok := mappings.DriversTabIDToProduct[ctx.TabID]; ok {
-				return product
+				return product, OriginTab, "driver tab :tabid: " + ctx.TabID,
+					ProductProvenance{Source: ProvenanceRstspec}
 			}
 		}
 		if ctx.Language != "" {
 			if product, ok := mappings.ComposableLanguageToProduct[ctx.Language]; ok {
-				return product
+				return product, OriginComposableLanguage, "language composable " + ctx.Language,
+					composableProvenance(ctx.Language, mappings.ProjectComposableLanguages, product, mappings)
 			}
 		}
 		if ctx.Interface != "" {
 			if product, ok := mappings.ComposableInterfaceToProduct[ctx.Interface]; ok {
-				return product
+				return product, OriginComposableInterface, "interface composable " + ctx.Interface,
+					composableProvenance(ctx.Interface, mappings.ProjectComposableInterfaces, product, mappings)
 			}
 		}
 	}
 
-	// Map content directory to product using shared mapping
+	// Map content directory to product, preferring configured overrides
+	if product, ok := opts.ContentDirOverrides[contentDir]; ok {
+		return product, OriginContentDir, "content directory override " + contentDir,
+			ProductProvenance{Source: ProvenanceConfig}
+	}
 	if product := projectinfo.GetProductFromContentDir(contentDir); product != "" {
-		return product
+		return product, OriginContentDir, "content directory " + contentDir, builtIn
 	}
 
 	// Fall back to language
 	if language != "" {
-		return lang.GetProductFromLanguage(language)
+		return lang.GetProductFromLanguage(language), OriginLanguage, languageFallbackReason + language, builtIn
 	}
 
-	return "Unknown"
+	return "Unknown", OriginUnknown, "no language and no context", builtIn
+}
+
+// languageFallbackReason starts the ProductReason of examples whose product fell back to
+// their language because nothing else, including the content directory, mapped to a product.
+const languageFallbackReason = "no tab, composable, or content directory context; product from language "
+
+// composableProvenance returns the provenance of a composable ID's mapping: the project's
+// snooty.toml if it defines the ID (see MergeProjectComposables), and rstspec.toml otherwise.
+func composableProvenance(id string, projectIDs map[string]string, product string, mappings *ProductMappings) ProductProvenance {
+	rstspecProduct, ok := projectIDs[id]
+	if !ok {
+		return ProductProvenance{Source: ProvenanceRstspec}
+	}
+	provenance := ProductProvenance{Source: ProvenanceSnootyTOML, File: mappings.ProjectSnootyPath}
+	if rstspecProduct != product {
+		provenance.Overrides = rstspecProduct
+	}
+	return provenance
+}
+
+// nodeJSContext finds a Node.js driver context: a driver tab or language composable that
+// maps to Node.js (or is "nodejs"/"node"), or the node content directory. It returns the
+// origin and a description of the context that applied.
+func nodeJSContext(contentDir string, contexts []CodeContext, mappings *ProductMappings, opts *AnalyzeOptions) (origin, reason string, ok bool) {
+	isNodeJS := func(id string, mapping map[string]string) bool {
+		return id == "nodejs" || id == "node" || mapping[id] == "Node.js"
+	}
+	for _, ctx := range contexts {
+		if ctx.TabID != "" && isNodeJS(ctx.TabID, mappings.DriversTabIDToProduct) {
+			return OriginTab, "driver tab :tabid: " + ctx.TabID, true
+		}
+		if ctx.Language != "" && isNodeJS(ctx.Language, mappings.ComposableLanguageToProduct) {
+			return OriginComposableLanguage, "language composable " + ctx.Language, true
+		}
+	}
+	if contentDir == "node" || opts.ContentDirOverrides[contentDir] == "Node.js" {
+		return OriginContentDir, "content directory " + contentDir, true
+	}
+	return "", "", false
 }
 
 // isMongoShellContext checks if we're in a MongoDB Shell context based on
@@ -595,9 +1074,13 @@ func parseSelectedContentBlocks(filePath string) (map[string]string, error) {
 	var inSelectedContent bool
 	var inTab bool
 	var blockIndent int
+	var comments rst.CommentTracker
 
 	for scanner.Scan() {
 		line := scanner.Text()
+		if comments.InComment(line) {
+			continue
+		}
 
 		// Calculate indentation
 		trimmed := strings.TrimLeft(line, " ")
@@ -701,9 +1184,11 @@ func parseSelectedContentBlocks(filePath string) (map[string]string, error) {
 //     intentional JSON data files shown across multiple driver tabs, which should
 //     be attributed to "JSON", not the driver.
 //
-// A more accurate implementation would track line ranges for each context block
-// and match code examples to their containing block, but this adds significant
-// complexity for zero practical benefit given the above findings.
+// collectCodeExamplesWithContext matches each example to its containing tab or
+// selected-content block first (see parseContextBlocks and findContextForLine), so this
+// flat list only applies to examples outside every block. For pages that mix languages
+// without an explicit :language:, --context-aware drops the tab contexts from it (see
+// withoutTabContexts), keeping only the file-wide composable-tutorial options.
 func parseFileContexts(filePath string) ([]CodeContext, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -713,10 +1198,14 @@ func parseFileContexts(filePath string) ([]CodeContext, error) {
 
 	var contexts []CodeContext
 	scanner := bufio.NewScanner(file)
+	var comments rst.CommentTracker
 
 	for scanner.Scan() {
 		line := scanner.Text()
 		trimmedLine := strings.TrimSpace(line)
+		if comments.InComment(line) {
+			continue
+		}
 
 		// Check for tab directive
 		if rst.TabDirectiveRegex.MatchString(trimmedLine) {
@@ -799,6 +1288,14 @@ func parseComposableOptions(options string) CodeContext {
 	for _, part := range parts {
 		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
 		if len(kv) != 2 {
+			// Options without selections ("language, interface") only name the composables
+			// the tutorial offers; keep the language one for its snooty.toml default
+			for _, id := range strings.Split(part, ",") {
+				id = strings.TrimSpace(id)
+				if ctx.Composable == "" && (strings.Contains(id, "language") || strings.Contains(id, "lang")) {
+					ctx.Composable = id
+				}
+			}
 			continue
 		}
 		key := strings.TrimSpace(kv[0])
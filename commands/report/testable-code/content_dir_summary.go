@@ -0,0 +1,124 @@
+package testablecode
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/grove-platform/audit-cli/internal/csv"
+)
+
+// GroupByContentDir is the --group-by value that totals pages by content directory.
+const GroupByContentDir = "content-dir"
+
+// ContentDirSummary totals the pages that resolved to one content directory, which is
+// one docs project (e.g., pymongo-driver) with one owning team.
+type ContentDirSummary struct {
+	ContentDir         string
+	Pages              int
+	Errors             int // Pages that could not be analyzed
+	TotalExamples      int
+	TotalTested        int
+	TotalTestable      int
+	TotalMaybeTestable int
+	// SharedExamples is the number of examples from shared includes that --own-content-only
+	// excluded from the totals.
+	SharedExamples int `json:",omitempty" toml:",omitempty"`
+}
+
+// SummarizeByContentDir totals the reports by content directory, sorted by content
+// directory. Pages that could not be resolved are totaled under "unresolved".
+func SummarizeByContentDir(reports []PageReport) []ContentDirSummary {
+	groups := GroupReportsByProject(reports)
+	summaries := make([]ContentDirSummary, 0, len(groups))
+	for contentDir, group := range groups {
+		summary := ContentDirSummary{ContentDir: contentDir, Pages: len(group)}
+		for _, report := range group {
+			if report.Error != "" {
+				summary.Errors++
+			}
+			summary.TotalExamples += report.TotalExamples
+			summary.TotalTested += report.TotalTested
+			summary.TotalTestable += report.TotalTestable
+			summary.TotalMaybeTestable += report.TotalMaybeTestable
+			summary.SharedExamples += report.SharedExamples
+		}
+		summaries = append(summaries, summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].ContentDir < summaries[j].ContentDir
+	})
+	return summaries
+}
+
+// TOMLContentDirReport wraps the content directory summaries for TOML output. Each
+// content directory is a [[ContentDirs]] table.
+type TOMLContentDirReport struct {
+	ContentDirs []ContentDirSummary
+}
+
+// writeContentDirSummary writes the content directory summaries to w in the given
+// format (text, json, jsonl, toml, or csv). JSON is indented unless compactJSON is set.
+func writeContentDirSummary(w io.Writer, summaries []ContentDirSummary, outputFormat string, compactJSON bool) error {
+	switch outputFormat {
+	case "json":
+		encoder := json.NewEncoder(w)
+		if !compactJSON {
+			encoder.SetIndent("", "  ")
+		}
+		return encoder.Encode(summaries)
+	case "jsonl":
+		encoder := json.NewEncoder(w)
+		for _, summary := range summaries {
+			if err := encoder.Encode(summary); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "toml":
+		return toml.NewEncoder(w).Encode(TOMLContentDirReport{ContentDirs: summaries})
+	case "csv":
+		fmt.Fprintln(w, "ContentDir,Pages,Errors,Total,Tested,Testable,Maybe,Coverage,Shared")
+		for _, s := range summaries {
+			fmt.Fprintf(w, "%s,%d,%d,%d,%d,%d,%d,%s,%d\n",
+				csv.Escape(s.ContentDir), s.Pages, s.Errors, s.TotalExamples,
+				s.TotalTested, s.TotalTestable, s.TotalMaybeTestable,
+				formatCoverage(s.TotalTested, s.TotalTestable), s.SharedExamples)
+		}
+		return nil
+	default:
+		return outputContentDirText(w, summaries)
+	}
+}
+
+// outputContentDirText writes the content directory summaries as a table with a total row.
+func outputContentDirText(w io.Writer, summaries []ContentDirSummary) error {
+	fmt.Fprintln(w, "="+strings.Repeat("=", 89))
+	fmt.Fprintln(w, "CONTENT DIRECTORY REPORT")
+	fmt.Fprintln(w, "="+strings.Repeat("=", 89))
+	fmt.Fprintf(w, "%-30s %6s %6s %6s %6s %8s %6s %8s\n",
+		"Content Directory", "Pages", "Errors", "Total", "Tested", "Testable", "Maybe", "Coverage")
+	fmt.Fprintln(w, "-"+strings.Repeat("-", 89))
+
+	var total ContentDirSummary
+	for _, s := range summaries {
+		fmt.Fprintf(w, "%-30s %6d %6d %6d %6d %8d %6d %8s\n",
+			s.ContentDir, s.Pages, s.Errors, s.TotalExamples, s.TotalTested,
+			s.TotalTestable, s.TotalMaybeTestable, formatCoverage(s.TotalTested, s.TotalTestable))
+		total.Pages += s.Pages
+		total.Errors += s.Errors
+		total.TotalExamples += s.TotalExamples
+		total.TotalTested += s.TotalTested
+		total.TotalTestable += s.TotalTestable
+		total.TotalMaybeTestable += s.TotalMaybeTestable
+	}
+
+	fmt.Fprintln(w, "-"+strings.Repeat("-", 89))
+	fmt.Fprintf(w, "%-30s %6d %6d %6d %6d %8d %6d %8s\n",
+		"TOTAL", total.Pages, total.Errors, total.TotalExamples, total.TotalTested,
+		total.TotalTestable, total.TotalMaybeTestable, formatCoverage(total.TotalTested, total.TotalTestable))
+	return nil
+}
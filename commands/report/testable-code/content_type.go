@@ -0,0 +1,145 @@
+package testablecode
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/grove-platform/audit-cli/internal/config"
+	"github.com/grove-platform/audit-cli/internal/rst"
+)
+
+// Page content types for --content-type.
+const (
+	ContentTypeTutorial  = "tutorial"
+	ContentTypeReference = "reference"
+	ContentTypeLanding   = "landing"
+	ContentTypeOther     = "other"
+)
+
+// ContentTypes lists the valid --content-type values.
+var ContentTypes = []string{ContentTypeTutorial, ContentTypeReference, ContentTypeLanding, ContentTypeOther}
+
+// templateRegex matches a page-level Snooty template option, such as ":template: product-landing".
+var templateRegex = regexp.MustCompile(`^:template:\s*(\S+)`)
+
+// Snooty templates that identify a page's content type. Other templates (such as
+// document) say nothing about the type, so the path decides.
+var templateContentTypes = map[string]string{
+	"landing":         ContentTypeLanding,
+	"product-landing": ContentTypeLanding,
+	"drivers-index":   ContentTypeLanding,
+	"changelog":       ContentTypeReference,
+	"openapi":         ContentTypeReference,
+}
+
+// Path segments that identify a page's content type, for pages without a telling template.
+var pathSegmentContentTypes = map[string]string{
+	"tutorial":        ContentTypeTutorial,
+	"tutorials":       ContentTypeTutorial,
+	"quick-start":     ContentTypeTutorial,
+	"quickstart":      ContentTypeTutorial,
+	"get-started":     ContentTypeTutorial,
+	"getting-started": ContentTypeTutorial,
+	"usage-examples":  ContentTypeTutorial,
+	"reference":       ContentTypeReference,
+	"api":             ContentTypeReference,
+	"api-reference":   ContentTypeReference,
+}
+
+// DetectContentType classifies a page as a tutorial, reference, or landing page from its
+// source file. The heuristic, in order:
+//
+//  1. A page-level :template: option: landing, product-landing, and drivers-index are
+//     landing pages; changelog and openapi are reference pages.
+//  2. A page with a composable-tutorial directive is a tutorial.
+//  3. The index page at the root of a source directory is a landing page.
+//  4. The deepest path segment (below source/) that names a type: tutorial(s),
+//     quick-start, get-started, and usage-examples are tutorials; reference, api, and
+//     api-reference are reference pages.
+//
+// Anything else is "other". A file that can't be read is classified by its path alone.
+func DetectContentType(sourcePath string) string {
+	if contentType := contentTypeFromFile(sourcePath); contentType != "" {
+		return contentType
+	}
+
+	relPath := sourcePath
+	if idx := strings.LastIndex(filepath.ToSlash(sourcePath), "/source/"); idx >= 0 {
+		relPath = filepath.ToSlash(sourcePath)[idx+len("/source/"):]
+	}
+	segments := strings.Split(filepath.ToSlash(relPath), "/")
+	page := strings.TrimSuffix(segments[len(segments)-1], filepath.Ext(segments[len(segments)-1]))
+	if len(segments) == 1 && page == "index" {
+		return ContentTypeLanding
+	}
+	segments[len(segments)-1] = page
+	for i := len(segments) - 1; i >= 0; i-- {
+		if contentType, ok := pathSegmentContentTypes[strings.ToLower(segments[i])]; ok {
+			return contentType
+		}
+	}
+	return ContentTypeOther
+}
+
+// contentTypeFromFile returns the content type given by a page's :template: option or a
+// composable-tutorial directive, or "" if the file doesn't say.
+func contentTypeFromFile(sourcePath string) string {
+	file, err := os.Open(sourcePath)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	tutorial := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if matches := templateRegex.FindStringSubmatch(line); matches != nil {
+			if contentType, ok := templateContentTypes[strings.ToLower(matches[1])]; ok {
+				return contentType
+			}
+		}
+		if rst.ComposableTutorialDirectiveRegex.MatchString(line) {
+			tutorial = true
+		}
+	}
+	if tutorial {
+		return ContentTypeTutorial
+	}
+	return ""
+}
+
+// validateContentType checks a --content-type value.
+func validateContentType(contentType string) error {
+	if !slices.Contains(ContentTypes, contentType) {
+		return fmt.Errorf("invalid --content-type %q (must be one of: %s)", contentType, strings.Join(ContentTypes, ", "))
+	}
+	return nil
+}
+
+// filterContentTypeEntries keeps entries whose source file is of the given content type.
+//
+// Entries whose URL cannot be resolved are kept, so they are still reported as errors.
+//
+// Parameters:
+//   - entries: Page entries to filter
+//   - contentType: One of ContentTypes
+//   - urlMapping: URL mapping used to resolve each entry's source file
+//
+// Returns:
+//   - []PageEntry: Entries of the content type, plus unresolvable entries
+func filterContentTypeEntries(entries []PageEntry, contentType string, urlMapping *config.URLMapping) []PageEntry {
+	var filtered []PageEntry
+	for _, entry := range entries {
+		sourcePath, err := entrySourcePath(entry, urlMapping)
+		if err != nil || DetectContentType(sourcePath) == contentType {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
@@ -3,20 +3,37 @@ package testablecode
 import (
 	"encoding/csv"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/grove-platform/audit-cli/internal/config"
 )
 
+// CSVAuthHeaderEnvVar names the environment variable holding an HTTP header sent when
+// fetching a remote analytics CSV, in "Name: value" form
+// (e.g., "Authorization: Bearer <token>").
+const CSVAuthHeaderEnvVar = "AUDIT_CLI_CSV_AUTH_HEADER"
+
+// csvFetchTimeout bounds the request for a remote analytics CSV.
+const csvFetchTimeout = 30 * time.Second
+
 // ParseCSV parses a CSV file with page rankings and URLs.
 // Supports both header and headerless formats:
 //   - With header: rank,url (first row contains column names)
 //   - Without header: 1,www.mongodb.com/docs/... (first row is data)
+//
+// The path may be an http:// or https:// URL, in which case the CSV is fetched
+// (see openCSV).
 // Returns a slice of PageEntry structs.
 func ParseCSV(path string) ([]PageEntry, error) {
-	file, err := os.Open(path)
+	file, err := openCSV(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open CSV file: %w", err)
+		return nil, err
 	}
 	defer file.Close()
 
@@ -68,7 +85,8 @@ func ParseCSV(path string) ([]PageEntry, error) {
 	}
 
 	if len(records) <= startIdx {
-		return nil, fmt.Errorf("no data rows found in CSV")
+		// Distinct from an empty file: an analytics export with no rows still has a header
+		return nil, fmt.Errorf("CSV contained a header but no data rows")
 	}
 
 	// Parse data rows
@@ -108,3 +126,121 @@ func ParseCSV(path string) ([]PageEntry, error) {
 	return entries, nil
 }
 
+
+// isRemoteCSV reports whether a CSV path is an http:// or https:// URL.
+func isRemoteCSV(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// openCSV opens a local CSV file, or fetches a remote one.
+//
+// Remote CSVs are fetched with csvFetchTimeout. If CSVAuthHeaderEnvVar is set, its
+// header is sent with the request, so dashboards that require a token can be read
+// without downloading the CSV by hand. In local mode (see config.SetLocal), remote CSVs
+// fail with config.ErrLocal.
+func openCSV(path string) (io.ReadCloser, error) {
+	if !isRemoteCSV(path) {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open CSV file: %w", err)
+		}
+		return file, nil
+	}
+	if config.IsLocal() {
+		return nil, fmt.Errorf("%w: can't fetch CSV %s", config.ErrLocal, path)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV URL: %w", err)
+	}
+	if header := os.Getenv(CSVAuthHeaderEnvVar); header != "" {
+		name, value, ok := strings.Cut(header, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("%s must be in \"Name: value\" form", CSVAuthHeaderEnvVar)
+		}
+		req.Header.Set(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	client := &http.Client{Timeout: csvFetchTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch CSV: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch CSV: HTTP %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+// ParseCSVFiles parses one or more analytics CSV files and merges their entries.
+//
+// Entries are de-duplicated by URL. When the same URL appears more than once (in the
+// same file or across files), the entry with the best (lowest) rank is kept. Merged
+// entries are then renumbered 1..n (see MergeEntries).
+//
+// Returns the merged entries and the number of duplicate entries that were merged away.
+func ParseCSVFiles(paths []string) ([]PageEntry, int, error) {
+	var lists [][]PageEntry
+	for _, path := range paths {
+		entries, err := ParseCSV(path)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", path, err)
+		}
+		lists = append(lists, entries)
+	}
+
+	merged, duplicates := MergeEntries(lists...)
+	return merged, duplicates, nil
+}
+
+// NormalizeEntries canonicalizes each entry's URL with config.NormalizeURL, for
+// --normalize-urls, and merges entries that become duplicates (keeping the lowest rank).
+// Returns the merged entries and the number of duplicates removed.
+func NormalizeEntries(entries []PageEntry) ([]PageEntry, int) {
+	normalized := make([]PageEntry, len(entries))
+	for i, entry := range entries {
+		entry.URL = config.NormalizeURL(entry.URL)
+		normalized[i] = entry
+	}
+	return MergeEntries(normalized)
+}
+
+// MergeEntries merges page entry lists, de-duplicating by URL and keeping the lowest rank.
+// When more than one list is given or any duplicate was removed, the merged entries are
+// sorted by rank (ties keep the order of first appearance) and renumbered 1..n, so the
+// result has no gaps or repeated ranks. A single list without duplicates is returned as is.
+// Returns the merged entries and the number of duplicates removed.
+func MergeEntries(lists ...[]PageEntry) ([]PageEntry, int) {
+	var merged []PageEntry
+	indexByURL := make(map[string]int)
+	duplicates := 0
+
+	for _, entries := range lists {
+		for _, entry := range entries {
+			idx, seen := indexByURL[entry.URL]
+			if !seen {
+				indexByURL[entry.URL] = len(merged)
+				merged = append(merged, entry)
+				continue
+			}
+			duplicates++
+			if entry.Rank < merged[idx].Rank {
+				merged[idx].Rank = entry.Rank
+			}
+		}
+	}
+
+	if len(lists) > 1 || duplicates > 0 {
+		sort.SliceStable(merged, func(i, j int) bool {
+			return merged[i].Rank < merged[j].Rank
+		})
+		for i := range merged {
+			merged[i].Rank = i + 1
+		}
+	}
+
+	return merged, duplicates
+}
@@ -0,0 +1,94 @@
+package testablecode
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grove-platform/audit-cli/internal/config"
+	"github.com/grove-platform/audit-cli/internal/projectinfo"
+)
+
+// DirEntries finds the pages in a directory of RST source files, for --dir. Each .txt
+// and .rst file is a page, with its content directory inferred from its path.
+//
+// Files under includes/ and code-examples/ directories are skipped: includes are counted
+// through the pages that include them, and tested code example files aren't RST. Pages
+// are ranked by path, in walk (lexical) order, and their URL is their path relative to
+// dir, with forward slashes.
+//
+// Parameters:
+//   - dir: Directory to walk
+//
+// Returns:
+//   - []PageEntry: One entry per page, with SourcePath and ContentDir set
+//   - error: If dir can't be walked
+func DirEntries(dir string) ([]PageEntry, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve --dir %s: %w", dir, err)
+	}
+
+	var entries []PageEntry
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if path != root && (info.Name() == "includes" || info.Name() == "code-examples") {
+				return filepath.SkipDir
+			}
+			return projectinfo.SkipExcludedDir(root, path, info.Name())
+		}
+
+		ext := filepath.Ext(path)
+		if ext != ".txt" && ext != ".rst" {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, PageEntry{
+			Rank:       len(entries) + 1,
+			URL:        filepath.ToSlash(rel),
+			SourcePath: path,
+			ContentDir: inferContentDir(path),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", dir, err)
+	}
+	return entries, nil
+}
+
+// inferContentDir returns the content directory of a page found with --dir: the
+// directory after content/ in a monorepo checkout (content/golang/current/source/...),
+// or else the name of the directory holding the source directory (e.g., docs-golang for
+// a standalone repo checkout). Returns "" if neither applies.
+func inferContentDir(path string) string {
+	slashPath := filepath.ToSlash(path)
+	if idx := strings.LastIndex(slashPath, "/content/"); idx >= 0 {
+		if contentDir, _, ok := strings.Cut(slashPath[idx+len("/content/"):], "/"); ok {
+			return contentDir
+		}
+	}
+	if sourceDir, err := projectinfo.FindSourceDirectory(path); err == nil {
+		return filepath.Base(filepath.Dir(sourceDir))
+	}
+	return ""
+}
+
+// entrySourcePath returns the source file of a page: the one found with --dir, or the
+// one its URL resolves to.
+func entrySourcePath(entry PageEntry, urlMapping *config.URLMapping) (string, error) {
+	if entry.SourcePath != "" {
+		return entry.SourcePath, nil
+	}
+	sourcePath, _, _, err := urlMapping.ResolveURL(entry.URL)
+	return sourcePath, err
+}
@@ -0,0 +1,78 @@
+package testablecode
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/grove-platform/audit-cli/internal/csv"
+)
+
+// ExampleRecord is one code example with the page it was found on, for --format examples-csv.
+type ExampleRecord struct {
+	Rank            int
+	URL             string
+	SourceFile      string
+	Type            string
+	Language        string
+	Product         string
+	IsInput         bool
+	IsOutput        bool
+	IsTested        bool
+	IsTestable      bool
+	IsMaybeTestable bool
+	FilePath        string
+}
+
+// BuildExampleRecords returns one record per code example on the page, in page order.
+// url is the page URL as reported (after --trim-url-prefix).
+func BuildExampleRecords(analysis *PageAnalysis, url string) []ExampleRecord {
+	records := make([]ExampleRecord, 0, len(analysis.CodeExamples))
+	for _, ex := range analysis.CodeExamples {
+		records = append(records, ExampleRecord{
+			Rank:            analysis.Rank,
+			URL:             url,
+			SourceFile:      ex.SourceFile,
+			Type:            ex.Type,
+			Language:        ex.Language,
+			Product:         ex.Product,
+			IsInput:         ex.IsInput,
+			IsOutput:        ex.IsOutput,
+			IsTested:        ex.IsTested,
+			IsTestable:      ex.IsTestable,
+			IsMaybeTestable: ex.IsMaybeTestable,
+			FilePath:        ex.FilePath,
+		})
+	}
+	return records
+}
+
+// OutputExamplesCSV writes one CSV row per code example. Pages that failed have no rows.
+func OutputExamplesCSV(w io.Writer, records []ExampleRecord) error {
+	writer := csv.NewWriter(w)
+	header := []string{"url", "rank", "source_file", "type", "language", "product",
+		"is_input", "is_output", "is_tested", "is_testable", "is_maybe_testable", "file_path"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, record := range records {
+		row := []string{
+			record.URL,
+			strconv.Itoa(record.Rank),
+			record.SourceFile,
+			record.Type,
+			record.Language,
+			record.Product,
+			strconv.FormatBool(record.IsInput),
+			strconv.FormatBool(record.IsOutput),
+			strconv.FormatBool(record.IsTested),
+			strconv.FormatBool(record.IsTestable),
+			strconv.FormatBool(record.IsMaybeTestable),
+			record.FilePath,
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
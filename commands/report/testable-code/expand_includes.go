@@ -0,0 +1,116 @@
+package testablecode
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ExpandedPage is the flattened example list of a single page, in collection order.
+// It is the JSON form of --expand-includes.
+type ExpandedPage struct {
+	URL          string
+	SourcePath   string
+	ContentDir   string
+	Version      string   `json:",omitempty"`
+	Warnings     []string `json:",omitempty"`
+	CodeExamples []ExpandedExample
+}
+
+// ExpandedExample is one code example as collected, with the RST file it came from.
+type ExpandedExample struct {
+	Index           int
+	SourceFile      string
+	Type            string
+	Language        string
+	Product         string
+	FilePath        string `json:",omitempty"`
+	IsInput         bool
+	IsOutput        bool
+	IsTested        bool
+	IsTestable      bool
+	IsMaybeTestable bool
+}
+
+// BuildExpandedPage flattens a page analysis into its example list. SourceFile is
+// relative to the page's own file, so examples from includes stand out.
+func BuildExpandedPage(analysis *PageAnalysis) ExpandedPage {
+	page := ExpandedPage{
+		URL:          analysis.URL,
+		SourcePath:   analysis.SourcePath,
+		ContentDir:   analysis.ContentDir,
+		Version:      analysis.Version,
+		Warnings:     BuildPageReport(analysis).Warnings,
+		CodeExamples: make([]ExpandedExample, 0, len(analysis.CodeExamples)),
+	}
+	for i, ex := range analysis.CodeExamples {
+		page.CodeExamples = append(page.CodeExamples, ExpandedExample{
+			Index:           i + 1,
+			SourceFile:      relativeToPage(ex.SourceFile, analysis.SourcePath),
+			Type:            ex.Type,
+			Language:        ex.Language,
+			Product:         ex.Product,
+			FilePath:        ex.FilePath,
+			IsInput:         ex.IsInput,
+			IsOutput:        ex.IsOutput,
+			IsTested:        ex.IsTested,
+			IsTestable:      ex.IsTestable,
+			IsMaybeTestable: ex.IsMaybeTestable,
+		})
+	}
+	return page
+}
+
+// OutputExpandedIncludes prints a page's flattened example list as text, one line per
+// example in collection order. Unlike OutputExplain it gives no reasons; it is the
+// compact view to attach to a miscount report.
+func OutputExpandedIncludes(w io.Writer, analysis *PageAnalysis) {
+	page := BuildExpandedPage(analysis)
+
+	fmt.Fprintf(w, "URL: %s\n", page.URL)
+	fmt.Fprintf(w, "Source: %s\n", page.SourcePath)
+	fmt.Fprintf(w, "Code examples: %d\n", len(page.CodeExamples))
+	for _, warning := range page.Warnings {
+		fmt.Fprintf(w, "Warning: %s\n", warning)
+	}
+	if len(page.CodeExamples) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%4s  %-30s %-22s %-12s %-20s %s\n", "#", "Source file", "Type", "Language", "Product", "Flags")
+	fmt.Fprintln(w, strings.Repeat("-", 100))
+	for _, ex := range page.CodeExamples {
+		fmt.Fprintf(w, "%4d  %-30s %-22s %-12s %-20s %s\n",
+			ex.Index, ex.SourceFile, describeDirective(analysis.CodeExamples[ex.Index-1]),
+			ex.Language, ex.Product, expandedFlags(ex))
+	}
+}
+
+// OutputExpandedIncludesJSON writes a page's flattened example list as an ExpandedPage.
+func OutputExpandedIncludesJSON(w io.Writer, analysis *PageAnalysis, compact bool) error {
+	encoder := json.NewEncoder(w)
+	if !compact {
+		encoder.SetIndent("", "  ")
+	}
+	return encoder.Encode(BuildExpandedPage(analysis))
+}
+
+// expandedFlags lists the classification booleans that are set, or "-" if none are.
+func expandedFlags(ex ExpandedExample) string {
+	var flags []string
+	if ex.IsTested {
+		flags = append(flags, "tested")
+	}
+	if ex.IsTestable {
+		flags = append(flags, "testable")
+	}
+	if ex.IsMaybeTestable {
+		flags = append(flags, "maybe")
+	}
+	if len(flags) == 0 {
+		return "-"
+	}
+	return strings.Join(flags, ",")
+}
@@ -0,0 +1,119 @@
+package testablecode
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// OutputExplain prints the classification decision for every code example on a page.
+//
+// This is a per-example trace for debugging determineProduct: for each example it shows
+// the directive type, language, resolved product, the context that applied, and the
+// tested/testable/maybe-testable flags with the reason for each.
+//
+// Parameters:
+//   - w: Writer for the output
+//   - analysis: The analysis of a single page
+func OutputExplain(w io.Writer, analysis *PageAnalysis) {
+	report := BuildPageReport(analysis)
+
+	fmt.Fprintln(w, strings.Repeat("=", 80))
+	fmt.Fprintln(w, "TESTABLE CODE EXPLAIN")
+	fmt.Fprintln(w, strings.Repeat("=", 80))
+	fmt.Fprintf(w, "URL: %s\n", analysis.URL)
+	fmt.Fprintf(w, "Source: %s\n", analysis.SourcePath)
+	fmt.Fprintf(w, "Content directory: %s\n", analysis.ContentDir)
+	if analysis.Version != "" {
+		fmt.Fprintf(w, "Version: %s\n", analysis.Version)
+	}
+	fmt.Fprintf(w, "Code examples: %d (tested: %d, testable: %d, maybe testable: %d)\n",
+		report.TotalExamples, report.TotalTested, report.TotalTestable, report.TotalMaybeTestable)
+	for _, warning := range report.Warnings {
+		fmt.Fprintf(w, "Warning: %s\n", warning)
+	}
+
+	for i, ex := range analysis.CodeExamples {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "[%d] %s\n", i+1, describeDirective(ex))
+		fmt.Fprintf(w, "    Source file:    %s\n", relativeToPage(ex.SourceFile, analysis.SourcePath))
+		if ex.FilePath != "" {
+			fmt.Fprintf(w, "    File:           %s\n", ex.FilePath)
+		}
+		fmt.Fprintf(w, "    Language:       %s\n", ex.Language)
+		fmt.Fprintf(w, "    Product:        %s (origin: %s)\n", ex.Product, ex.Origin)
+		fmt.Fprintf(w, "    Context:        %s\n", ex.ProductReason)
+		fmt.Fprintf(w, "    Provenance:     %s\n", formatProvenance(ex.Provenance))
+		fmt.Fprintf(w, "    Tested:         %s\n", formatDecision(testedReason(ex)))
+		fmt.Fprintf(w, "    Testable:       %s\n", formatDecision(ex.IsTestable, testableReason(ex)))
+		fmt.Fprintf(w, "    Maybe testable: %s\n", formatDecision(ex.IsMaybeTestable, maybeTestableReason(ex)))
+	}
+}
+
+// describeDirective returns the directive type, with input/output for io-code-block examples.
+func describeDirective(ex CodeExample) string {
+	switch {
+	case ex.IsInput:
+		return ex.Type + " (input)"
+	case ex.IsOutput:
+		return ex.Type + " (output)"
+	}
+	return ex.Type
+}
+
+// relativeToPage returns sourceFile relative to the source directory containing the
+// page, or "(page)" for the page itself.
+func relativeToPage(sourceFile, pagePath string) string {
+	if sourceFile == pagePath {
+		return "(page)"
+	}
+	return formatIncludeChain([]string{sourceFile}, pagePath)
+}
+
+// formatProvenance describes where the mapping that determined the product was defined.
+func formatProvenance(p ProductProvenance) string {
+	switch {
+	case p.Overrides != "":
+		return fmt.Sprintf("%s (%s), overrides rstspec.toml: %s", p.Source, p.File, p.Overrides)
+	case p.File != "":
+		return fmt.Sprintf("%s (%s)", p.Source, p.File)
+	}
+	return p.Source
+}
+
+// formatDecision formats a boolean decision with its reason.
+func formatDecision(decision bool, reason string) string {
+	if decision {
+		return "yes - " + reason
+	}
+	return "no - " + reason
+}
+
+// testedReason explains why an example is or is not counted as tested.
+func testedReason(ex CodeExample) (bool, string) {
+	switch {
+	case ex.IsTested:
+		return true, "file path contains /tested/"
+	case isTestedPath(ex.FilePath):
+		return false, "/tested/ file not found on disk (--verify-tested)"
+	case ex.FilePath == "":
+		return false, "inline code is not tested"
+	}
+	return false, "file path does not contain /tested/"
+}
+
+// testableReason explains why an example is or is not testable.
+func testableReason(ex CodeExample) string {
+	if ex.IsTestable {
+		return ex.Product + " has test infrastructure"
+	}
+	return ex.Product + " has no test infrastructure"
+}
+
+// maybeTestableReason explains why an example is or is not in the maybe-testable grey area.
+func maybeTestableReason(ex CodeExample) string {
+	if ex.IsMaybeTestable {
+		return ex.Product + " could be driver or MongoDB Shell code; needs review"
+	}
+	return ex.Product + " is not ambiguous"
+}
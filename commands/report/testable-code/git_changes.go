@@ -0,0 +1,122 @@
+package testablecode
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/grove-platform/audit-cli/internal/config"
+)
+
+// ChangedFilesSince returns the files changed in a git repository since a ref or date.
+//
+// The since value is treated as a git ref (commit, branch, or tag) if git can resolve it;
+// the result is then every file touched by commits in since..HEAD. Otherwise it is passed
+// to git log --since as a date (e.g., "2025-01-01" or "2 weeks ago"). Only committed
+// changes are considered.
+//
+// Parameters:
+//   - repoPath: Any path inside the git repository (e.g., the monorepo root)
+//   - since: A git ref or a date understood by git log --since
+//
+// Returns:
+//   - map[string]bool: Absolute paths of changed files
+//   - error: Error if git is not installed, repoPath is not a git repository, or git fails
+func ChangedFilesSince(repoPath, since string) (map[string]bool, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git is not available: %w", err)
+	}
+
+	topLevel, err := runGit(repoPath, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a git repository: %w", repoPath, err)
+	}
+	topLevel = strings.TrimSpace(topLevel)
+
+	var out string
+	if _, refErr := runGit(repoPath, "rev-parse", "--verify", "--quiet", since+"^{commit}"); refErr == nil {
+		out, err = runGit(repoPath, "log", "--name-only", "--pretty=format:", since+"..HEAD")
+	} else {
+		out, err = runGit(repoPath, "log", "--name-only", "--pretty=format:", "--since="+since)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make(map[string]bool)
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		changed[filepath.Join(topLevel, filepath.FromSlash(line))] = true
+	}
+	return changed, nil
+}
+
+// changedFilesInMonorepos merges ChangedFilesSince for each monorepo, so pages from
+// additional monorepos (--monorepo-path) are filtered against their own git history.
+// It fails if any of the monorepos can't be checked.
+func changedFilesInMonorepos(monorepoRoots []string, since string) (map[string]bool, error) {
+	changed := make(map[string]bool)
+	for _, root := range monorepoRoots {
+		files, err := ChangedFilesSince(root, since)
+		if err != nil {
+			return nil, err
+		}
+		for file := range files {
+			changed[file] = true
+		}
+	}
+	return changed, nil
+}
+
+// runGit runs a git command in dir and returns its standard output.
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return "", fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
+		}
+		return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), msg)
+	}
+	return stdout.String(), nil
+}
+
+// filterChangedEntries keeps entries whose source file is in the changed set.
+//
+// Entries whose URL cannot be resolved are kept, so they are still reported as errors.
+//
+// Parameters:
+//   - entries: Page entries to filter
+//   - changed: Absolute paths of changed files (from ChangedFilesSince)
+//   - urlMapping: URL mapping used to resolve each entry's source file
+//
+// Returns:
+//   - []PageEntry: Entries whose source file changed, plus unresolvable entries
+func filterChangedEntries(entries []PageEntry, changed map[string]bool, urlMapping *config.URLMapping) []PageEntry {
+	var filtered []PageEntry
+	for _, entry := range entries {
+		sourcePath, err := entrySourcePath(entry, urlMapping)
+		if err != nil {
+			filtered = append(filtered, entry)
+			continue
+		}
+		// git reports paths under the resolved repository root
+		if realPath, evalErr := filepath.EvalSymlinks(sourcePath); evalErr == nil {
+			sourcePath = realPath
+		} else if absPath, absErr := filepath.Abs(sourcePath); absErr == nil {
+			sourcePath = absPath
+		}
+		if changed[sourcePath] {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
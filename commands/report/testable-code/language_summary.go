@@ -0,0 +1,158 @@
+package testablecode
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/grove-platform/audit-cli/internal/csv"
+)
+
+// GroupByLanguage is the --group-by value that totals code examples by language.
+const GroupByLanguage = "language"
+
+// LanguageSummary totals the code examples written in one language across all pages.
+// The language is the one in the directive (e.g., javascript), before product resolution.
+type LanguageSummary struct {
+	Language           string
+	Pages              int // Pages with at least one example in the language
+	TotalExamples      int
+	TotalTested        int
+	TotalTestable      int
+	TotalMaybeTestable int
+	// ByProduct counts the examples in the language by the product they resolved to
+	ByProduct map[string]int
+}
+
+// LanguageTotals accumulates code examples by language across pages for --group-by language.
+type LanguageTotals map[string]*LanguageSummary
+
+// Add counts every code example on a page under its language.
+func (t LanguageTotals) Add(analysis *PageAnalysis) {
+	onPage := make(map[string]bool)
+	for _, ex := range analysis.CodeExamples {
+		summary, ok := t[ex.Language]
+		if !ok {
+			summary = &LanguageSummary{Language: ex.Language, ByProduct: make(map[string]int)}
+			t[ex.Language] = summary
+		}
+		if !onPage[ex.Language] {
+			onPage[ex.Language] = true
+			summary.Pages++
+		}
+		summary.TotalExamples++
+		if ex.IsTested {
+			summary.TotalTested++
+		}
+		if ex.IsTestable {
+			summary.TotalTestable++
+		}
+		if ex.IsMaybeTestable {
+			summary.TotalMaybeTestable++
+		}
+		summary.ByProduct[ex.Product]++
+	}
+}
+
+// Summaries returns the totals with the most examples first, then by language.
+func (t LanguageTotals) Summaries() []LanguageSummary {
+	summaries := make([]LanguageSummary, 0, len(t))
+	for _, summary := range t {
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].TotalExamples != summaries[j].TotalExamples {
+			return summaries[i].TotalExamples > summaries[j].TotalExamples
+		}
+		return summaries[i].Language < summaries[j].Language
+	})
+	return summaries
+}
+
+// TOMLLanguageReport wraps the language summaries for TOML output. Each language is a
+// [[Languages]] table.
+type TOMLLanguageReport struct {
+	Languages []LanguageSummary
+}
+
+// writeLanguageSummary writes the language summaries to w in the given format (text,
+// json, jsonl, toml, or csv). JSON is indented unless compactJSON is set.
+func writeLanguageSummary(w io.Writer, summaries []LanguageSummary, outputFormat string, compactJSON bool) error {
+	switch outputFormat {
+	case "json":
+		encoder := json.NewEncoder(w)
+		if !compactJSON {
+			encoder.SetIndent("", "  ")
+		}
+		return encoder.Encode(summaries)
+	case "jsonl":
+		encoder := json.NewEncoder(w)
+		for _, summary := range summaries {
+			if err := encoder.Encode(summary); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "toml":
+		return toml.NewEncoder(w).Encode(TOMLLanguageReport{Languages: summaries})
+	case "csv":
+		fmt.Fprintln(w, "Language,Pages,Total,Tested,Testable,Maybe,Products")
+		for _, s := range summaries {
+			fmt.Fprintf(w, "%s,%d,%d,%d,%d,%d,%s\n",
+				csv.Escape(s.Language), s.Pages, s.TotalExamples, s.TotalTested,
+				s.TotalTestable, s.TotalMaybeTestable, csv.Escape(formatLanguageProducts(s.ByProduct)))
+		}
+		return nil
+	default:
+		return outputLanguageText(w, summaries)
+	}
+}
+
+// outputLanguageText writes the language summaries as a table with a total row.
+func outputLanguageText(w io.Writer, summaries []LanguageSummary) error {
+	fmt.Fprintln(w, "="+strings.Repeat("=", 89))
+	fmt.Fprintln(w, "LANGUAGE REPORT")
+	fmt.Fprintln(w, "="+strings.Repeat("=", 89))
+	fmt.Fprintf(w, "%-20s %6s %6s %6s %8s %6s  %s\n",
+		"Language", "Pages", "Total", "Tested", "Testable", "Maybe", "Resolved Products")
+	fmt.Fprintln(w, "-"+strings.Repeat("-", 89))
+
+	var total LanguageSummary
+	for _, s := range summaries {
+		fmt.Fprintf(w, "%-20s %6d %6d %6d %8d %6d  %s\n",
+			s.Language, s.Pages, s.TotalExamples, s.TotalTested, s.TotalTestable,
+			s.TotalMaybeTestable, formatLanguageProducts(s.ByProduct))
+		total.TotalExamples += s.TotalExamples
+		total.TotalTested += s.TotalTested
+		total.TotalTestable += s.TotalTestable
+		total.TotalMaybeTestable += s.TotalMaybeTestable
+	}
+
+	fmt.Fprintln(w, "-"+strings.Repeat("-", 89))
+	fmt.Fprintf(w, "%-20s %6s %6d %6d %8d %6d\n",
+		"TOTAL", "", total.TotalExamples, total.TotalTested, total.TotalTestable, total.TotalMaybeTestable)
+	return nil
+}
+
+// formatLanguageProducts lists the products a language resolved to, most examples first
+// (e.g., "JavaScript (30), Node.js (12)").
+func formatLanguageProducts(byProduct map[string]int) string {
+	products := make([]string, 0, len(byProduct))
+	for product := range byProduct {
+		products = append(products, product)
+	}
+	sort.Slice(products, func(i, j int) bool {
+		if byProduct[products[i]] != byProduct[products[j]] {
+			return byProduct[products[i]] > byProduct[products[j]]
+		}
+		return products[i] < products[j]
+	})
+	parts := make([]string, len(products))
+	for i, product := range products {
+		parts[i] = fmt.Sprintf("%s (%d)", product, byProduct[product])
+	}
+	return strings.Join(parts, ", ")
+}
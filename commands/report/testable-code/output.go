@@ -2,21 +2,99 @@ package testablecode
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/grove-platform/audit-cli/internal/config"
+	"github.com/grove-platform/audit-cli/internal/csv"
+	lang "github.com/grove-platform/audit-cli/internal/language"
+	"github.com/grove-platform/audit-cli/internal/projectinfo"
 )
 
+// BuildExampleSources lists a page's code examples with their bodies, in page order,
+// for PageReport.Examples.
+func BuildExampleSources(analysis *PageAnalysis) []ExampleSource {
+	sources := make([]ExampleSource, 0, len(analysis.CodeExamples))
+	for _, ex := range analysis.CodeExamples {
+		sources = append(sources, ExampleSource{
+			Type:       ex.Type,
+			Language:   ex.Language,
+			Product:    ex.Product,
+			IsInput:    ex.IsInput,
+			IsOutput:   ex.IsOutput,
+			FilePath:   ex.FilePath,
+			SourceFile: ex.SourceFile,
+			Body:       ex.Body,
+		})
+	}
+	return sources
+}
+
 // BuildPageReport builds a PageReport from a PageAnalysis.
 func BuildPageReport(analysis *PageAnalysis) PageReport {
 	report := PageReport{
-		Rank:       analysis.Rank,
-		URL:        analysis.URL,
-		SourcePath: analysis.SourcePath,
-		ContentDir: analysis.ContentDir,
-		Error:      analysis.Error,
-		ByProduct:  make(map[string]*ProductStats),
+		Rank:         analysis.Rank,
+		URL:          analysis.URL,
+		SourcePath:   analysis.SourcePath,
+		ContentDir:   analysis.ContentDir,
+		Version:      analysis.Version,
+		Error:        analysis.Error,
+		ByProduct:    make(map[string]*ProductStats),
+		IncludeDepth: analysis.IncludeDepth,
+		Monorepo:     analysis.Monorepo,
+	}
+
+	if analysis.RedirectedFrom != "" {
+		report.Warnings = append(report.Warnings,
+			fmt.Sprintf("URL slug %s redirected to %s", analysis.RedirectedFrom, analysis.RedirectedTo))
+	}
+	if analysis.RequestedVersion != "" {
+		report.Warnings = append(report.Warnings,
+			fmt.Sprintf("driver version %s not found, analyzed %s instead", analysis.RequestedVersion, analysis.Version))
+	}
+	for _, cycle := range analysis.IncludeCycles {
+		report.Warnings = append(report.Warnings,
+			"include cycle: "+formatIncludeChain(cycle.Chain, analysis.SourcePath))
+	}
+	for _, path := range analysis.MissingTestedFiles {
+		report.Warnings = append(report.Warnings,
+			fmt.Sprintf("tested file not found: %s (not counted as tested)", path))
+	}
+	for _, target := range analysis.BadIncludeTargets {
+		if target.Outside {
+			report.Warnings = append(report.Warnings,
+				fmt.Sprintf("%s target outside the monorepo: %s", target.Type, target.Path))
+		} else {
+			report.Warnings = append(report.Warnings,
+				fmt.Sprintf("%s target not found: %s", target.Type, target.Path))
+		}
+	}
+	if len(analysis.DepthLimitedIncludes) > 0 {
+		report.Warnings = append(report.Warnings,
+			fmt.Sprintf("max include depth reached: %d include(s) not followed", len(analysis.DepthLimitedIncludes)))
+	}
+	for _, file := range analysis.OversizedFiles {
+		report.Warnings = append(report.Warnings,
+			fmt.Sprintf("file not scanned, larger than max file size: %s (%d bytes)", relativeToPage(file.Path, analysis.SourcePath), file.Size))
+	}
+	for _, parseErr := range analysis.IncludeParseErrors {
+		report.Warnings = append(report.Warnings,
+			fmt.Sprintf("include not parsed: %s: %s", relativeToPage(parseErr.Path, analysis.SourcePath), parseErr.Error))
+	}
+	for _, fragmentErr := range analysis.FragmentErrors {
+		report.Warnings = append(report.Warnings,
+			fmt.Sprintf("partial include counted in full: %s", fragmentErr))
+	}
+	for _, unresolved := range analysis.UnresolvedIncludes {
+		report.Warnings = append(report.Warnings,
+			fmt.Sprintf("failed to resolve include path %s", unresolved))
 	}
 
 	for _, ex := range analysis.CodeExamples {
@@ -44,7 +122,7 @@ func BuildPageReport(analysis *PageAnalysis) PageReport {
 		}
 		stats, ok := report.ByProduct[product]
 		if !ok {
-			stats = &ProductStats{Product: product}
+			stats = &ProductStats{Product: product, ByOrigin: make(map[string]int)}
 			report.ByProduct[product] = stats
 		}
 		stats.TotalCount++
@@ -63,11 +141,425 @@ func BuildPageReport(analysis *PageAnalysis) PageReport {
 		if ex.IsMaybeTestable {
 			stats.MaybeTestableCount++
 		}
+		if ex.Origin != "" {
+			stats.ByOrigin[ex.Origin]++
+		}
+	}
+	for _, stats := range report.ByProduct {
+		stats.PartiallyTested = isPartiallyTested(stats)
 	}
 
 	return report
 }
 
+// isPartiallyTested reports whether some, but not all, of a product's testable examples are tested.
+func isPartiallyTested(stats *ProductStats) bool {
+	return stats.TestedCount > 0 && stats.TestedCount < stats.TestableCount
+}
+
+// StripSharedIncludes removes the code examples that come from files other than the
+// page's own source file (shared includes), for --own-content-only. Returns the number
+// of examples removed.
+func StripSharedIncludes(analysis *PageAnalysis) int {
+	// Build a new slice: the analysis may also be held by the analysis cache
+	own := make([]CodeExample, 0, len(analysis.CodeExamples))
+	for _, ex := range analysis.CodeExamples {
+		if filepath.Clean(ex.SourceFile) == filepath.Clean(analysis.SourcePath) {
+			own = append(own, ex)
+		}
+	}
+	shared := len(analysis.CodeExamples) - len(own)
+	analysis.CodeExamples = own
+	return shared
+}
+
+// CountIOPairsAsOne counts each io-code-block input/output pair as a single example, for
+// --io-as-one. The output half of a pair stays in TotalOutput and its product's
+// OutputCount, but no longer adds to TotalExamples, TotalTested, TotalTestable,
+// TotalMaybeTestable, or the matching per-product counts. An output with no input
+// before it in the same block still counts as an example. Returns the number of pairs.
+func CountIOPairsAsOne(report *PageReport, analysis *PageAnalysis) int {
+	pairs := 0
+	for i, ex := range analysis.CodeExamples {
+		if !ex.IsOutput || i == 0 {
+			continue
+		}
+		// processDirective emits a block's output directly after its input
+		prev := analysis.CodeExamples[i-1]
+		if !prev.IsInput || prev.SourceFile != ex.SourceFile {
+			continue
+		}
+		pairs++
+
+		report.TotalExamples--
+		if ex.IsTested {
+			report.TotalTested--
+		}
+		if ex.IsTestable {
+			report.TotalTestable--
+		}
+		if ex.IsMaybeTestable {
+			report.TotalMaybeTestable--
+		}
+
+		product := ex.Product
+		if product == "" {
+			product = "Unknown"
+		}
+		stats, ok := report.ByProduct[product]
+		if !ok {
+			continue
+		}
+		stats.TotalCount--
+		if ex.IsTested {
+			stats.TestedCount--
+		}
+		if ex.IsTestable {
+			stats.TestableCount--
+		}
+		if ex.IsMaybeTestable {
+			stats.MaybeTestableCount--
+		}
+		stats.PartiallyTested = isPartiallyTested(stats)
+	}
+	return pairs
+}
+
+// KeepPartiallyTested removes every product that isn't partially tested from the report,
+// for --only-partial. Page totals are unchanged.
+//
+// Returns false if the page has no partially tested products (or failed to analyze), so
+// the caller can drop it.
+func KeepPartiallyTested(report *PageReport) bool {
+	for product, stats := range report.ByProduct {
+		if !stats.PartiallyTested {
+			delete(report.ByProduct, product)
+		}
+	}
+	return len(report.ByProduct) > 0
+}
+
+// AddBaselineProducts adds a zero-valued ProductStats to the report for each product that
+// has no code examples on the page, so every page reports the same products.
+// Reports for pages that failed to analyze are left unchanged.
+func AddBaselineProducts(report *PageReport, products []string) {
+	if report.ByProduct == nil {
+		return
+	}
+	for _, product := range products {
+		if _, ok := report.ByProduct[product]; !ok {
+			report.ByProduct[product] = &ProductStats{Product: product, ByOrigin: make(map[string]int)}
+		}
+	}
+}
+
+// ParseProductAliases parses --product-alias values of the form "Alias=Canonical".
+//
+// Parameters:
+//   - values: Flag values, e.g. "Java (Sync)=Java"
+//
+// Returns:
+//   - map[string]string: Alias product name to canonical product name
+//   - error: Error if a value is not Alias=Canonical, an alias is repeated with a different
+//     canonical name, or a canonical name is itself an alias
+func ParseProductAliases(values []string) (map[string]string, error) {
+	aliases := make(map[string]string)
+	for _, value := range values {
+		alias, canonical, ok := strings.Cut(value, "=")
+		alias = strings.TrimSpace(alias)
+		canonical = strings.TrimSpace(canonical)
+		if !ok || alias == "" || canonical == "" {
+			return nil, fmt.Errorf("invalid product alias %q (must be Alias=Canonical, e.g. \"Java (Sync)=Java\")", value)
+		}
+		if alias == canonical {
+			return nil, fmt.Errorf("invalid product alias %q: product is aliased to itself", value)
+		}
+		if existing, ok := aliases[alias]; ok && existing != canonical {
+			return nil, fmt.Errorf("product %q is aliased to both %q and %q", alias, existing, canonical)
+		}
+		aliases[alias] = canonical
+	}
+	for alias, canonical := range aliases {
+		if _, ok := aliases[canonical]; ok {
+			return nil, fmt.Errorf("invalid product alias %s=%s: %q is itself an alias", alias, canonical, canonical)
+		}
+	}
+	return aliases, nil
+}
+
+// ApplyProductAliases merges the ProductStats of aliased products into their canonical
+// product, summing every count. Page totals are unchanged.
+//
+// Parameters:
+//   - report: The page report to update
+//   - aliases: Alias product name to canonical product name (from ParseProductAliases)
+func ApplyProductAliases(report *PageReport, aliases map[string]string) {
+	for alias, canonical := range aliases {
+		stats, ok := report.ByProduct[alias]
+		if !ok {
+			continue
+		}
+		delete(report.ByProduct, alias)
+
+		target, ok := report.ByProduct[canonical]
+		if !ok {
+			target = &ProductStats{Product: canonical, ByOrigin: make(map[string]int)}
+			report.ByProduct[canonical] = target
+		}
+		target.TotalCount += stats.TotalCount
+		target.InputCount += stats.InputCount
+		target.OutputCount += stats.OutputCount
+		target.TestedCount += stats.TestedCount
+		target.TestableCount += stats.TestableCount
+		target.MaybeTestableCount += stats.MaybeTestableCount
+		target.PartiallyTested = isPartiallyTested(target)
+		for origin, count := range stats.ByOrigin {
+			if target.ByOrigin == nil {
+				target.ByOrigin = make(map[string]int)
+			}
+			target.ByOrigin[origin] += count
+		}
+	}
+}
+
+// isNonDriverProduct reports whether --drivers-strict drops a product from the breakdown.
+//
+// Non-driver products are the ones that non-driver languages (see lang.NonDriverLanguages:
+// bash, json, yaml, text, and so on) and the shell and javascript languages resolve to
+// outside a driver context, plus MongoDB Shell (not a driver) and Unknown. Driver
+// examples resolve to a driver product (e.g., Node.js), never to these.
+func isNonDriverProduct(product string) bool {
+	switch product {
+	case "MongoDB Shell", "Unknown", "PowerShell":
+		return true
+	}
+	for language := range lang.NonDriverLanguages {
+		if lang.GetProductFromLanguage(language) == product {
+			return true
+		}
+	}
+	for language := range lang.MongoShellLanguages {
+		if lang.GetProductFromLanguage(language) == product {
+			return true
+		}
+	}
+	return false
+}
+
+// DropNonDriverProducts removes non-driver products (see isNonDriverProduct) from a page's
+// product breakdown for --drivers-strict, and subtracts their examples from the page
+// totals, so the report counts only driver code.
+//
+// Returns the number of examples dropped.
+func DropNonDriverProducts(report *PageReport) int {
+	dropped := 0
+	for product, stats := range report.ByProduct {
+		if !isNonDriverProduct(product) {
+			continue
+		}
+		delete(report.ByProduct, product)
+		report.TotalExamples -= stats.TotalCount
+		report.TotalInput -= stats.InputCount
+		report.TotalOutput -= stats.OutputCount
+		report.TotalTested -= stats.TestedCount
+		report.TotalTestable -= stats.TestableCount
+		report.TotalMaybeTestable -= stats.MaybeTestableCount
+		dropped += stats.TotalCount
+	}
+	return dropped
+}
+
+// ExpandBaselineProducts returns the products named by --baseline-products, with
+// "default" replaced by DefaultBaselineProducts and duplicates removed.
+func ExpandBaselineProducts(names []string) []string {
+	var products []string
+	seen := make(map[string]bool)
+	add := func(product string) {
+		if product != "" && !seen[product] {
+			seen[product] = true
+			products = append(products, product)
+		}
+	}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "default" {
+			for _, product := range DefaultBaselineProducts {
+				add(product)
+			}
+			continue
+		}
+		add(name)
+	}
+	return products
+}
+
+// TrimURLPrefix removes prefix from the start of url, for display.
+//
+// If url has an http:// or https:// scheme that prefix doesn't, the scheme is removed too,
+// so "www.mongodb.com/docs/" trims both "www.mongodb.com/docs/x/" and
+// "https://www.mongodb.com/docs/x/". URLs without the prefix are returned unchanged.
+func TrimURLPrefix(url, prefix string) string {
+	if prefix == "" {
+		return url
+	}
+	if strings.HasPrefix(url, prefix) {
+		return strings.TrimPrefix(url, prefix)
+	}
+	for _, scheme := range []string{"https://", "http://"} {
+		if rest := strings.TrimPrefix(url, scheme); rest != url && strings.HasPrefix(rest, prefix) {
+			return strings.TrimPrefix(rest, prefix)
+		}
+	}
+	return url
+}
+
+// RelativeSourcePath returns path relative to the first monorepo root that contains it,
+// with forward slashes, for display. A path outside every root is returned unchanged.
+func RelativeSourcePath(path string, roots []string) string {
+	if path == "" {
+		return path
+	}
+	for _, root := range roots {
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// formatIncludeChain formats an include chain as "a.txt -> b.rst -> a.txt".
+// Paths are shown relative to the page's source directory when it can be found.
+func formatIncludeChain(chain []string, sourcePath string) string {
+	sourceDir, err := projectinfo.FindSourceDirectory(sourcePath)
+	parts := make([]string, len(chain))
+	for i, file := range chain {
+		parts[i] = file
+		if err != nil {
+			continue
+		}
+		if absFile, absErr := filepath.Abs(file); absErr == nil {
+			if rel, relErr := filepath.Rel(sourceDir, absFile); relErr == nil && !strings.HasPrefix(rel, "..") {
+				parts[i] = rel
+			}
+		}
+	}
+	return strings.Join(parts, " -> ")
+}
+
+// unresolvedProject is the project name used for pages that could not be resolved to a content directory.
+const unresolvedProject = "unresolved"
+
+// ReportFormat controls how writeReport and OutputByProject write page reports.
+type ReportFormat struct {
+	// Format is text, json, jsonl, toml, csv, or matrix.
+	Format string
+	// ShowDetails writes one CSV row per product per page (see OutputCSV).
+	ShowDetails bool
+	// KeepZero keeps zero-valued products in the detailed CSV (see OutputCSV).
+	KeepZero bool
+	// CompactJSON writes JSON on a single line instead of indented (see OutputJSONCompact).
+	CompactJSON bool
+}
+
+// writeReport writes the reports to w in the given format.
+func writeReport(w io.Writer, reports []PageReport, format ReportFormat) error {
+	switch format.Format {
+	case "json":
+		if format.CompactJSON {
+			return OutputJSONCompact(w, reports)
+		}
+		return OutputJSON(w, reports)
+	case "jsonl":
+		return OutputJSONL(w, reports)
+	case "toml":
+		return OutputTOML(w, reports)
+	case "csv":
+		return OutputCSV(w, reports, format.ShowDetails, format.KeepZero)
+	case "matrix":
+		return OutputMatrix(w, reports)
+	default:
+		return OutputText(w, reports)
+	}
+}
+
+// formatExtension returns the file extension for an output format.
+func formatExtension(outputFormat string) string {
+	switch outputFormat {
+	case "json":
+		return "json"
+	case "jsonl":
+		return "jsonl"
+	case "toml":
+		return "toml"
+	case "csv", "matrix":
+		return "csv"
+	default:
+		return "txt"
+	}
+}
+
+// GroupReportsByProject groups reports by the project they resolved to.
+// The project is the report's content directory (e.g., "pymongo-driver"). For a page
+// from an additional monorepo, it's qualified by the monorepo's directory name (e.g.,
+// "rust-docs/rust"), so content directories with the same name in different monorepos
+// aren't merged. Reports without a content directory (unresolvable pages) are grouped
+// under "unresolved". The original report order is preserved within each group.
+func GroupReportsByProject(reports []PageReport) map[string][]PageReport {
+	groups := make(map[string][]PageReport)
+	for _, report := range reports {
+		project := report.ContentDir
+		if project == "" {
+			project = unresolvedProject
+		} else if report.Monorepo != "" {
+			project = filepath.Base(report.Monorepo) + "/" + project
+		}
+		groups[project] = append(groups[project], report)
+	}
+	return groups
+}
+
+// OutputByProject writes one report file per project to dir, named <project>.<ext>.
+// The directory is created if it doesn't exist; a project from an additional monorepo
+// is written to a subdirectory named for the monorepo (see GroupReportsByProject).
+// Returns the paths of the files written, sorted by project name.
+func OutputByProject(dir string, reports []PageReport, format ReportFormat) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	groups := GroupReportsByProject(reports)
+	projects := make([]string, 0, len(groups))
+	for project := range groups {
+		projects = append(projects, project)
+	}
+	sort.Strings(projects)
+
+	var written []string
+	for _, project := range projects {
+		path := filepath.Join(dir, filepath.FromSlash(project)+"."+formatExtension(format.Format))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return written, fmt.Errorf("failed to create output directory: %w", err)
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			return written, fmt.Errorf("failed to create output file: %w", err)
+		}
+		writeErr := writeReport(f, groups[project], format)
+		closeErr := f.Close()
+		if writeErr != nil {
+			return written, fmt.Errorf("failed to write %s: %w", path, writeErr)
+		}
+		if closeErr != nil {
+			return written, fmt.Errorf("failed to write %s: %w", path, closeErr)
+		}
+		written = append(written, path)
+	}
+
+	return written, nil
+}
+
 // OutputText outputs the reports in text format.
 func OutputText(w io.Writer, reports []PageReport) error {
 	fmt.Fprintln(w, "="+strings.Repeat("=", 89))
@@ -107,6 +599,16 @@ func OutputText(w io.Writer, reports []PageReport) error {
 
 		fmt.Fprintf(w, "\nRank %d: %s\n", report.Rank, report.URL)
 		fmt.Fprintf(w, "Source: %s\n", report.SourcePath)
+		if report.Version != "" {
+			fmt.Fprintf(w, "Version: %s\n", report.Version)
+		}
+		for _, warning := range report.Warnings {
+			fmt.Fprintf(w, "Warning: %s\n", warning)
+		}
+		if report.SharedExamples > 0 {
+			fmt.Fprintf(w, "Shared include examples excluded: %d (%d with shared content)\n",
+				report.SharedExamples, report.TotalExamples+report.SharedExamples)
+		}
 		fmt.Fprintln(w, "-"+strings.Repeat("-", 89))
 
 		if len(report.ByProduct) == 0 {
@@ -121,39 +623,189 @@ func OutputText(w io.Writer, reports []PageReport) error {
 		}
 		sort.Strings(products)
 
-		fmt.Fprintf(w, "  %-20s %6s %6s %6s %6s %8s %6s\n",
-			"Product", "Total", "Input", "Output", "Tested", "Testable", "Maybe")
-		fmt.Fprintln(w, "  "+strings.Repeat("-", 68))
+		fmt.Fprintf(w, "  %-20s %6s %6s %6s %6s %8s %6s %8s\n",
+			"Product", "Total", "Input", "Output", "Tested", "Testable", "Maybe", "Coverage")
+		fmt.Fprintln(w, "  "+strings.Repeat("-", 77))
 
 		for _, product := range products {
 			stats := report.ByProduct[product]
-			fmt.Fprintf(w, "  %-20s %6d %6d %6d %6d %8d %6d\n",
+			fmt.Fprintf(w, "  %-20s %6d %6d %6d %6d %8d %6d %8s\n",
 				product, stats.TotalCount, stats.InputCount, stats.OutputCount,
-				stats.TestedCount, stats.TestableCount, stats.MaybeTestableCount)
+				stats.TestedCount, stats.TestableCount, stats.MaybeTestableCount,
+				formatCoverage(stats.TestedCount, stats.TestableCount))
 		}
 
-		fmt.Fprintf(w, "  %s\n", strings.Repeat("-", 68))
-		fmt.Fprintf(w, "  %-20s %6d %6d %6d %6d %8d %6d\n",
+		fmt.Fprintf(w, "  %s\n", strings.Repeat("-", 77))
+		fmt.Fprintf(w, "  %-20s %6d %6d %6d %6d %8d %6d %8s\n",
 			"TOTAL", report.TotalExamples, report.TotalInput, report.TotalOutput,
-			report.TotalTested, report.TotalTestable, report.TotalMaybeTestable)
+			report.TotalTested, report.TotalTestable, report.TotalMaybeTestable,
+			formatCoverage(report.TotalTested, report.TotalTestable))
 	}
 
 	return nil
 }
 
-// OutputJSON outputs the reports in JSON format.
+// PrintStaticFallbackBanner prints a prominent notice that URLs were resolved with the
+// static fallback mapping, at the end of a run where the earlier warning is easy to miss.
+func PrintStaticFallbackBanner(w io.Writer) {
+	fmt.Fprintln(w, strings.Repeat("!", 90))
+	fmt.Fprintln(w, "WARNING: The Snooty Data API was unavailable and there was no fresh URL mapping cache.")
+	fmt.Fprintln(w, "URLs were resolved with the built-in static mapping, which may be missing recently")
+	fmt.Fprintln(w, "added projects. Pages from those projects are reported as errors. Rerun with network")
+	fmt.Fprintln(w, "access (see 'audit-cli doctor') for a complete report.")
+	fmt.Fprintln(w, strings.Repeat("!", 90))
+}
+
+// GroupFailures groups page analysis errors by reason, most frequent first.
+//
+// URL resolution errors are grouped by their config sentinel error (e.g., "could not
+// resolve URL slug" or "no content directory found for project"), so errors that differ
+// only in the URL or project are grouped together. Other errors are grouped by the text
+// before the first ": ".
+func GroupFailures(errs []error) []FailureGroup {
+	counts := make(map[string]int)
+	for _, err := range errs {
+		counts[failureReason(err)]++
+	}
+
+	groups := make([]FailureGroup, 0, len(counts))
+	for reason, count := range counts {
+		groups = append(groups, FailureGroup{Reason: reason, Count: count})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].Reason < groups[j].Reason
+	})
+	return groups
+}
+
+// resolutionErrors maps each config sentinel error to its ErrorCategory.
+var resolutionErrors = []struct {
+	err      error
+	category string
+}{
+	{config.ErrInvalidURL, ErrorCategoryInvalidURL},
+	{config.ErrSlugNotResolved, ErrorCategorySlugNotResolved},
+	{config.ErrNoContentDir, ErrorCategoryNoContentDir},
+	{config.ErrSourceNotFound, ErrorCategorySourceNotFound},
+}
+
+// errorCategory returns the ErrorCategory for a page analysis error.
+func errorCategory(err error) string {
+	for _, re := range resolutionErrors {
+		if errors.Is(err, re.err) {
+			return re.category
+		}
+	}
+	return ErrorCategoryOther
+}
+
+// failureReason returns the grouping key for a page analysis error.
+func failureReason(err error) string {
+	for _, re := range resolutionErrors {
+		if errors.Is(err, re.err) {
+			return re.err.Error()
+		}
+	}
+	msg := err.Error()
+	// os errors start with the path, which is different for every page
+	if strings.Contains(msg, "no such file or directory") {
+		return config.ErrSourceNotFound.Error()
+	}
+	if idx := strings.Index(msg, ": "); idx != -1 {
+		return msg[:idx]
+	}
+	return msg
+}
+
+// PrintFailureSummary prints the number of pages that failed for each reason.
+// Nothing is printed if no pages failed.
+func PrintFailureSummary(w io.Writer, errs []error) {
+	if len(errs) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%d page(s) failed to analyze:\n", len(errs))
+	for _, group := range GroupFailures(errs) {
+		fmt.Fprintf(w, "  %5d  %s\n", group.Count, group.Reason)
+	}
+}
+
+// OutputJSON outputs the reports in JSON format, indented with two spaces.
 func OutputJSON(w io.Writer, reports []PageReport) error {
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(reports)
 }
 
+// OutputJSONCompact outputs the reports in JSON format with the array on a single line,
+// for --compact-json.
+func OutputJSONCompact(w io.Writer, reports []PageReport) error {
+	return json.NewEncoder(w).Encode(reports)
+}
+
+// OutputJSONL outputs the reports in JSON Lines format: one compact PageReport object per
+// line, with no enclosing array.
+func OutputJSONL(w io.Writer, reports []PageReport) error {
+	encoder := json.NewEncoder(w)
+	for _, report := range reports {
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TOMLReport wraps the page reports for TOML output, because a TOML document can't be a
+// top-level array. Each page is a [[Pages]] table.
+type TOMLReport struct {
+	Pages []PageReport
+}
+
+// OutputTOML outputs the reports in TOML format, as a TOMLReport.
+// ByProduct stays a table keyed by product; the encoder writes keys in sorted order.
+func OutputTOML(w io.Writer, reports []PageReport) error {
+	return toml.NewEncoder(w).Encode(TOMLReport{Pages: reports})
+}
+
+// MarshalJSON encodes a PageReport with ByProduct as a slice of ProductStats sorted by
+// product name, so JSON output is deterministic and diff-friendly across runs.
+// ByProduct stays a map in memory because it is keyed by product while accumulating.
+func (r PageReport) MarshalJSON() ([]byte, error) {
+	// pageReportJSON has PageReport's fields but not its MarshalJSON method
+	type pageReportJSON PageReport
+	return json.Marshal(struct {
+		pageReportJSON
+		ByProduct []ProductStats
+	}{
+		pageReportJSON: pageReportJSON(r),
+		ByProduct:      sortedProductStats(r.ByProduct),
+	})
+}
+
+// sortedProductStats returns the per-product stats sorted by product name.
+func sortedProductStats(byProduct map[string]*ProductStats) []ProductStats {
+	products := make([]string, 0, len(byProduct))
+	for p := range byProduct {
+		products = append(products, p)
+	}
+	sort.Strings(products)
+
+	stats := make([]ProductStats, 0, len(products))
+	for _, p := range products {
+		stats = append(stats, *byProduct[p])
+	}
+	return stats
+}
+
 // OutputCSV outputs the reports in CSV format.
 // If showDetails is false, outputs one row per page (summary).
-// If showDetails is true, outputs one row per product per page (only products with non-zero values).
-func OutputCSV(w io.Writer, reports []PageReport, showDetails bool) error {
+// If showDetails is true, outputs one row per product per page (only products with non-zero
+// values, unless keepZero is true).
+func OutputCSV(w io.Writer, reports []PageReport, showDetails, keepZero bool) error {
 	if showDetails {
-		return outputCSVDetails(w, reports)
+		return outputCSVDetails(w, reports, keepZero)
 	}
 	return outputCSVSummary(w, reports)
 }
@@ -161,56 +813,51 @@ func OutputCSV(w io.Writer, reports []PageReport, showDetails bool) error {
 // outputCSVSummary outputs one row per page with aggregate stats.
 func outputCSVSummary(w io.Writer, reports []PageReport) error {
 	// Header
-	fmt.Fprintln(w, "Rank,URL,SourcePath,ContentDir,Total,Input,Output,Tested,Testable,Maybe,Error")
+	fmt.Fprintln(w, "Rank,URL,SourcePath,ContentDir,Version,Total,Input,Output,Tested,Testable,Maybe,Shared,Error")
 
 	for _, report := range reports {
 		// Escape fields that might contain commas or quotes
-		url := escapeCSV(report.URL)
-		sourcePath := escapeCSV(report.SourcePath)
-		contentDir := escapeCSV(report.ContentDir)
-		errorMsg := escapeCSV(report.Error)
-
-		fmt.Fprintf(w, "%d,%s,%s,%s,%d,%d,%d,%d,%d,%d,%s\n",
-			report.Rank, url, sourcePath, contentDir,
+		url := csv.Escape(report.URL)
+		sourcePath := csv.Escape(report.SourcePath)
+		contentDir := csv.Escape(report.ContentDir)
+		version := csv.Escape(report.Version)
+		errorMsg := csv.Escape(report.Error)
+
+		fmt.Fprintf(w, "%d,%s,%s,%s,%s,%d,%d,%d,%d,%d,%d,%d,%s\n",
+			report.Rank, url, sourcePath, contentDir, version,
 			report.TotalExamples, report.TotalInput, report.TotalOutput,
 			report.TotalTested, report.TotalTestable, report.TotalMaybeTestable,
-			errorMsg)
+			report.SharedExamples, errorMsg)
 	}
 
 	return nil
 }
 
 // outputCSVDetails outputs one row per product per page.
-// Only includes products where at least one column has a non-zero value.
-func outputCSVDetails(w io.Writer, reports []PageReport) error {
+// Unless keepZero is true, only includes products where at least one column has a non-zero
+// value. Zero-valued products come from --baseline-products.
+func outputCSVDetails(w io.Writer, reports []PageReport, keepZero bool) error {
 	// Header
-	fmt.Fprintln(w, "Rank,URL,SourcePath,ContentDir,Product,Total,Input,Output,Tested,Testable,Maybe,Error")
+	fmt.Fprintln(w, "Rank,URL,SourcePath,ContentDir,Version,Product,Total,Input,Output,Tested,Testable,Maybe,Coverage,Origins,Error")
 
 	for _, report := range reports {
 		// Escape fields that might contain commas or quotes
-		url := escapeCSV(report.URL)
-		sourcePath := escapeCSV(report.SourcePath)
-		contentDir := escapeCSV(report.ContentDir)
-		errorMsg := escapeCSV(report.Error)
+		url := csv.Escape(report.URL)
+		sourcePath := csv.Escape(report.SourcePath)
+		contentDir := csv.Escape(report.ContentDir)
+		version := csv.Escape(report.Version)
+		errorMsg := csv.Escape(report.Error)
 
 		if report.Error != "" {
 			// For error rows, output a single row with the error
-			fmt.Fprintf(w, "%d,%s,%s,%s,,%d,%d,%d,%d,%d,%d,%s\n",
-				report.Rank, url, sourcePath, contentDir,
+			fmt.Fprintf(w, "%d,%s,%s,%s,%s,,%d,%d,%d,%d,%d,%d,,,%s\n",
+				report.Rank, url, sourcePath, contentDir, version,
 				report.TotalExamples, report.TotalInput, report.TotalOutput,
 				report.TotalTested, report.TotalTestable, report.TotalMaybeTestable,
 				errorMsg)
 			continue
 		}
 
-		if len(report.ByProduct) == 0 {
-			// No code examples - output a single row with zeros
-			fmt.Fprintf(w, "%d,%s,%s,%s,,%d,%d,%d,%d,%d,%d,\n",
-				report.Rank, url, sourcePath, contentDir,
-				0, 0, 0, 0, 0, 0)
-			continue
-		}
-
 		// Sort products for consistent output
 		products := make([]string, 0, len(report.ByProduct))
 		for p := range report.ByProduct {
@@ -218,46 +865,96 @@ func outputCSVDetails(w io.Writer, reports []PageReport) error {
 		}
 		sort.Strings(products)
 
+		rows := 0
 		for _, product := range products {
 			stats := report.ByProduct[product]
 
 			// Skip products where all columns are zero
-			if stats.TotalCount == 0 && stats.InputCount == 0 && stats.OutputCount == 0 &&
+			if !keepZero && stats.TotalCount == 0 && stats.InputCount == 0 && stats.OutputCount == 0 &&
 				stats.TestedCount == 0 && stats.TestableCount == 0 && stats.MaybeTestableCount == 0 {
 				continue
 			}
+			rows++
 
-			productEscaped := escapeCSV(product)
-			fmt.Fprintf(w, "%d,%s,%s,%s,%s,%d,%d,%d,%d,%d,%d,\n",
-				report.Rank, url, sourcePath, contentDir, productEscaped,
+			productEscaped := csv.Escape(product)
+			origins := csv.Escape(formatOrigins(stats.ByOrigin))
+			fmt.Fprintf(w, "%d,%s,%s,%s,%s,%s,%d,%d,%d,%d,%d,%d,%s,%s,\n",
+				report.Rank, url, sourcePath, contentDir, version, productEscaped,
 				stats.TotalCount, stats.InputCount, stats.OutputCount,
-				stats.TestedCount, stats.TestableCount, stats.MaybeTestableCount)
+				stats.TestedCount, stats.TestableCount, stats.MaybeTestableCount,
+				formatCoverage(stats.TestedCount, stats.TestableCount), origins)
+		}
+
+		if rows == 0 {
+			// No code examples - output a single row with zeros
+			fmt.Fprintf(w, "%d,%s,%s,%s,%s,,%d,%d,%d,%d,%d,%d,%s,,\n",
+				report.Rank, url, sourcePath, contentDir, version,
+				0, 0, 0, 0, 0, 0, formatCoverage(0, 0))
 		}
 	}
 
 	return nil
 }
 
-// escapeCSV escapes a string for CSV output.
-// If the string contains commas, quotes, or newlines, it wraps in quotes and escapes internal quotes.
-func escapeCSV(s string) string {
-	if s == "" {
-		return ""
+// OutputMatrix outputs a CSV grid with one row per page and one column per product, for
+// pivoting in a spreadsheet. Each cell is the page's testable count for the product (0 if
+// the page has none). The columns are the union of products across all reports, sorted by
+// name. Pages that failed to analyze have all zeros and their error in the last column.
+func OutputMatrix(w io.Writer, reports []PageReport) error {
+	productSet := make(map[string]bool)
+	for _, report := range reports {
+		for product := range report.ByProduct {
+			productSet[product] = true
+		}
 	}
+	products := make([]string, 0, len(productSet))
+	for product := range productSet {
+		products = append(products, product)
+	}
+	sort.Strings(products)
+
+	header := []string{"Rank", "URL"}
+	for _, product := range products {
+		header = append(header, csv.Escape(product))
+	}
+	fmt.Fprintln(w, strings.Join(append(header, "Error"), ","))
 
-	needsQuotes := false
-	for _, c := range s {
-		if c == ',' || c == '"' || c == '\n' || c == '\r' {
-			needsQuotes = true
-			break
+	for _, report := range reports {
+		row := []string{strconv.Itoa(report.Rank), csv.Escape(report.URL)}
+		for _, product := range products {
+			testable := 0
+			if stats, ok := report.ByProduct[product]; ok {
+				testable = stats.TestableCount
+			}
+			row = append(row, strconv.Itoa(testable))
 		}
+		fmt.Fprintln(w, strings.Join(append(row, csv.Escape(report.Error)), ","))
 	}
 
-	if !needsQuotes {
-		return s
+	return nil
+}
+
+// formatCoverage formats tested examples as a percentage of testable examples
+// (e.g., "66.7%"), or "—" when there are no testable examples.
+func formatCoverage(tested, testable int) string {
+	if testable == 0 {
+		return "—"
+	}
+	return fmt.Sprintf("%.1f%%", float64(tested)*100/float64(testable))
+}
+
+// formatOrigins formats a per-origin breakdown as "origin:count" pairs separated by
+// semicolons, sorted by origin for consistent output (e.g., "content-dir:2;tab:3").
+func formatOrigins(byOrigin map[string]int) string {
+	origins := make([]string, 0, len(byOrigin))
+	for origin := range byOrigin {
+		origins = append(origins, origin)
 	}
+	sort.Strings(origins)
 
-	// Escape quotes by doubling them and wrap in quotes
-	escaped := strings.ReplaceAll(s, `"`, `""`)
-	return `"` + escaped + `"`
+	parts := make([]string, 0, len(origins))
+	for _, origin := range origins {
+		parts = append(parts, fmt.Sprintf("%s:%d", origin, byOrigin[origin]))
+	}
+	return strings.Join(parts, ";")
 }
@@ -0,0 +1,34 @@
+package testablecode
+
+import (
+	"regexp"
+
+	"github.com/grove-platform/audit-cli/internal/config"
+)
+
+// filterPagePathEntries keeps entries whose source file path matches re, for
+// --page-path-filter.
+//
+// The path is matched relative to the monorepo root that contains it, with forward
+// slashes (e.g., content/golang/current/source/fundamentals/crud.txt), so a pattern works
+// on every checkout. Entries whose URL cannot be resolved are kept, so they are still
+// reported as errors.
+//
+// Parameters:
+//   - entries: Page entries to filter
+//   - re: Pattern the source path must match
+//   - urlMapping: URL mapping used to resolve each entry's source file
+//   - monorepoRoots: The monorepos source paths are made relative to
+//
+// Returns:
+//   - []PageEntry: Entries whose source path matches, plus unresolvable entries
+func filterPagePathEntries(entries []PageEntry, re *regexp.Regexp, urlMapping *config.URLMapping, monorepoRoots []string) []PageEntry {
+	var filtered []PageEntry
+	for _, entry := range entries {
+		sourcePath, err := entrySourcePath(entry, urlMapping)
+		if err != nil || re.MatchString(RelativeSourcePath(sourcePath, monorepoRoots)) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
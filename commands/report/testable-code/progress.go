@@ -0,0 +1,79 @@
+package testablecode
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// progressInterval is the minimum time between progress lines.
+	progressInterval = time.Second
+	// progressEvery prints a progress line every this many pages even if pages finish
+	// faster than progressInterval.
+	progressEvery = 100
+)
+
+// Progress reports how many pages have been analyzed, with an estimate of the time left.
+//
+// Done may be called from several goroutines: the completed count is atomic and
+// printing is serialized. A line like "245/2000 (12%) ETA 3m20s" is printed when at
+// least progressInterval has passed since the last line, every progressEvery pages, and
+// for the last page. The ETA assumes the remaining pages take as long on average as the
+// ones done so far.
+//
+// A nil *Progress prints nothing (--quiet).
+type Progress struct {
+	w     io.Writer
+	total int
+	done  atomic.Int64
+	start time.Time
+	now   func() time.Time // Replaced in tests
+
+	mu        sync.Mutex
+	lastPrint time.Time
+}
+
+// NewProgress creates a Progress for total pages that writes to w, starting the clock now.
+func NewProgress(w io.Writer, total int) *Progress {
+	p := &Progress{w: w, total: total, now: time.Now}
+	p.start = p.now()
+	p.lastPrint = p.start
+	return p
+}
+
+// Done records one completed page and prints a progress line if one is due.
+func (p *Progress) Done() {
+	if p == nil {
+		return
+	}
+	done := int(p.done.Add(1))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	if done < p.total && done%progressEvery != 0 && now.Sub(p.lastPrint) < progressInterval {
+		return
+	}
+	p.lastPrint = now
+	fmt.Fprintf(p.w, "Progress: %s\n", formatProgress(done, p.total, now.Sub(p.start)))
+}
+
+// formatProgress formats done/total with the percentage and the estimated time left.
+func formatProgress(done, total int, elapsed time.Duration) string {
+	percent := 0
+	if total > 0 {
+		percent = done * 100 / total
+	}
+	line := fmt.Sprintf("%d/%d (%d%%)", done, total, percent)
+	if done >= total {
+		return line + " done in " + roundDuration(elapsed).String()
+	}
+	if done == 0 {
+		return line
+	}
+	remaining := time.Duration(float64(elapsed) / float64(done) * float64(total-done))
+	return line + " ETA " + remaining.Round(time.Second).String()
+}
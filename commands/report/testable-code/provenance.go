@@ -0,0 +1,74 @@
+package testablecode
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ProvenanceRow counts the code examples attributed to a product through mappings with
+// the same provenance.
+type ProvenanceRow struct {
+	ProductProvenance
+	Product string
+	Count   int
+}
+
+// ProvenanceSummary accumulates product provenance across pages for --provenance.
+// It counts, per product, the examples attributed through each source, which shows
+// whether a project's snooty.toml composables override rstspec.toml.
+type ProvenanceSummary map[ProvenanceRow]int
+
+// Add counts the provenance of every code example on a page.
+func (s ProvenanceSummary) Add(analysis *PageAnalysis) {
+	for _, ex := range analysis.CodeExamples {
+		s[ProvenanceRow{ProductProvenance: ex.Provenance, Product: ex.Product}]++
+	}
+}
+
+// Rows returns the summary sorted by source, file, and product.
+func (s ProvenanceSummary) Rows() []ProvenanceRow {
+	rows := make([]ProvenanceRow, 0, len(s))
+	for key, count := range s {
+		key.Count = count
+		rows = append(rows, key)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Source != rows[j].Source {
+			return rows[i].Source < rows[j].Source
+		}
+		if rows[i].File != rows[j].File {
+			return rows[i].File < rows[j].File
+		}
+		return rows[i].Product < rows[j].Product
+	})
+	return rows
+}
+
+// PrintProvenanceReport prints the number of examples attributed to each product through
+// each mapping source. Examples attributed through a project's snooty.toml are grouped
+// under the file, and marked when they override a different rstspec.toml product.
+func PrintProvenanceReport(w io.Writer, summary ProvenanceSummary) {
+	fmt.Fprintln(w, "\nProduct provenance:")
+	if len(summary) == 0 {
+		fmt.Fprintln(w, "  (no code examples)")
+		return
+	}
+
+	var lastSource, lastFile string
+	for i, row := range summary.Rows() {
+		if i == 0 || row.Source != lastSource || row.File != lastFile {
+			if row.File != "" {
+				fmt.Fprintf(w, "  %s (%s)\n", row.Source, row.File)
+			} else {
+				fmt.Fprintf(w, "  %s\n", row.Source)
+			}
+			lastSource, lastFile = row.Source, row.File
+		}
+		if row.Overrides != "" {
+			fmt.Fprintf(w, "    %5d  %s (overrides rstspec.toml: %s)\n", row.Count, row.Product, row.Overrides)
+		} else {
+			fmt.Fprintf(w, "    %5d  %s\n", row.Count, row.Product)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package testablecode
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"sort"
+)
+
+// ReportSummary totals every page in a report, for --summary-json. It's written
+// alongside a report in any format, so CI gets the totals without a second run.
+type ReportSummary struct {
+	Pages              int
+	FailedPages        int
+	TotalExamples      int
+	TotalInput         int
+	TotalOutput        int
+	TotalTested        int
+	TotalTestable      int
+	TotalMaybeTestable int
+	// SharedExamples is the number of examples from shared includes that --own-content-only
+	// excluded from the totals.
+	SharedExamples int `json:",omitempty"`
+	// Coverage is TotalTested as a percentage of TotalTestable, rounded to one decimal
+	// place (0 if there are no testable examples).
+	Coverage float64
+	// ByProduct totals each product across pages, keyed by product while accumulating.
+	ByProduct map[string]*ProductStats
+	// UsedStaticFallback is true if URLs were resolved with the built-in static mapping
+	// because the Snooty Data API was unavailable.
+	UsedStaticFallback bool `json:",omitempty"`
+	// Outliers are the pages with more than --warn-over code examples, in report order.
+	Outliers []Outlier `json:",omitempty"`
+}
+
+// Outlier is a page with suspiciously many code examples (see --warn-over), often a URL
+// that resolved to an index page whose counts dominate the totals.
+type Outlier struct {
+	Rank          int
+	URL           string
+	TotalExamples int
+}
+
+// isOutlier reports whether a page has more than warnOver code examples (0 = never).
+func isOutlier(report PageReport, warnOver int) bool {
+	return warnOver > 0 && report.TotalExamples > warnOver
+}
+
+// NewReportSummary creates an empty ReportSummary.
+func NewReportSummary() *ReportSummary {
+	return &ReportSummary{ByProduct: make(map[string]*ProductStats)}
+}
+
+// Add adds a page report's totals to the summary.
+func (s *ReportSummary) Add(report PageReport) {
+	s.Pages++
+	if report.Error != "" {
+		s.FailedPages++
+	}
+	s.TotalExamples += report.TotalExamples
+	s.TotalInput += report.TotalInput
+	s.TotalOutput += report.TotalOutput
+	s.TotalTested += report.TotalTested
+	s.TotalTestable += report.TotalTestable
+	s.TotalMaybeTestable += report.TotalMaybeTestable
+	s.SharedExamples += report.SharedExamples
+
+	for product, stats := range report.ByProduct {
+		total, ok := s.ByProduct[product]
+		if !ok {
+			total = &ProductStats{Product: product, ByOrigin: make(map[string]int)}
+			s.ByProduct[product] = total
+		}
+		total.TotalCount += stats.TotalCount
+		total.InputCount += stats.InputCount
+		total.OutputCount += stats.OutputCount
+		total.TestedCount += stats.TestedCount
+		total.TestableCount += stats.TestableCount
+		total.MaybeTestableCount += stats.MaybeTestableCount
+		for origin, count := range stats.ByOrigin {
+			total.ByOrigin[origin] += count
+		}
+		total.PartiallyTested = isPartiallyTested(total)
+	}
+
+	s.Coverage = 0
+	if s.TotalTestable > 0 {
+		s.Coverage = math.Round(float64(s.TotalTested)*1000/float64(s.TotalTestable)) / 10
+	}
+}
+
+// AddOutlier records the page as an outlier if it has more than warnOver code examples
+// (0 = never).
+func (s *ReportSummary) AddOutlier(report PageReport, warnOver int) {
+	if isOutlier(report, warnOver) {
+		s.Outliers = append(s.Outliers, Outlier{Rank: report.Rank, URL: report.URL, TotalExamples: report.TotalExamples})
+	}
+}
+
+// MarshalJSON encodes a ReportSummary with ByProduct as a slice sorted by product name,
+// like PageReport.
+func (s ReportSummary) MarshalJSON() ([]byte, error) {
+	// reportSummaryJSON has ReportSummary's fields but not its MarshalJSON method
+	type reportSummaryJSON ReportSummary
+	return json.Marshal(struct {
+		reportSummaryJSON
+		ByProduct []ProductStats
+	}{
+		reportSummaryJSON: reportSummaryJSON(s),
+		ByProduct:         sortedProductStats(s.ByProduct),
+	})
+}
+
+// PrintOutliers prints the pages recorded with more than warnOver code examples, most
+// examples first. Nothing is printed if there are none.
+func PrintOutliers(w io.Writer, summary *ReportSummary, warnOver int) {
+	if len(summary.Outliers) == 0 {
+		return
+	}
+	outliers := slices.Clone(summary.Outliers)
+	sort.SliceStable(outliers, func(i, j int) bool {
+		return outliers[i].TotalExamples > outliers[j].TotalExamples
+	})
+	fmt.Fprintf(w, "\nPotential outliers: %d page(s) with more than %d code examples:\n", len(outliers), warnOver)
+	for _, o := range outliers {
+		fmt.Fprintf(w, "  %5d  %s (rank %d)\n", o.TotalExamples, o.URL, o.Rank)
+	}
+	fmt.Fprintln(w, "  Check that these URLs resolved to the right page; an index page that includes everything inflates the totals.")
+}
+
+// WriteSummaryJSON writes the summary as indented JSON to path.
+func WriteSummaryJSON(path string, summary *ReportSummary) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode summary: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+	return nil
+}
@@ -36,21 +36,129 @@
 package testablecode
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/grove-platform/audit-cli/internal/config"
 	"github.com/spf13/cobra"
 )
 
+// reportOptions holds the flag values that control how the testable-code report is built and written.
+type reportOptions struct {
+	csvFiles     []string
+	outputFormat string
+	// top limits --format worklist to this many pages (0 for all).
+	top         int
+	showDetails bool
+	outputFile  string
+	outputDir   string
+	filters     []string
+	maxPages    int
+	// rankRange is "MIN:MAX"; only pages ranked MIN through MAX (inclusive) are analyzed.
+	rankRange string
+	// maxIncludeDepth limits how many levels of includes are followed per page (0 = unlimited).
+	maxIncludeDepth int
+	verifyTested    bool
+	strictPaths     bool
+	// since limits the report to pages whose source file changed since this git ref or date.
+	since string
+	// contentType limits the report to pages of one content type (see DetectContentType).
+	contentType string
+	// pagePathFilter is a regular expression; only pages whose source path (relative to
+	// the monorepo) matches are analyzed.
+	pagePathFilter string
+	// dir is a directory of RST source files to analyze instead of analytics CSV pages.
+	dir string
+	// explain is a page URL whose per-example classification is printed instead of a report.
+	explain string
+	// expandIncludes is a page URL whose flattened example list is printed instead of a report.
+	expandIncludes string
+	// contentDirOverrides maps content directories to products (config file entries, then flags).
+	contentDirOverrides map[string]string
+	// maxFileSize skips source and include files larger than this many bytes (0 = unlimited).
+	maxFileSize int64
+	// verbose reports the bytes scanned for each page and in total.
+	verbose bool
+	// includeSource adds each page's code examples with their code to JSON output.
+	includeSource bool
+	// maxSourceBytes, with includeSource, embeds included files up to this size (0 = inline only).
+	maxSourceBytes int64
+	// failOnUnknownContentDir returns an error after the report if any page's content
+	// directory had no product mapping.
+	failOnUnknownContentDir bool
+	// warnOver warns about pages with more than this many code examples (0 = never).
+	warnOver int
+	// driversStrict analyzes only driver pages and drops non-driver products from the breakdown.
+	driversStrict bool
+	// javascriptAsNodeJS attributes javascript/js examples in a Node.js driver context to Node.js.
+	javascriptAsNodeJS bool
+	// useComposableDefault gives examples with no language their composable tutorial's snooty.toml language default.
+	useComposableDefault bool
+	// baselineProducts are added to every page's ByProduct with zero counts ("default" expands
+	// to DefaultBaselineProducts, which is also the flag default).
+	baselineProducts []string
+	// keepZero keeps zero-valued products in the detailed CSV.
+	keepZero bool
+	// timings prints how long each phase of the run took.
+	timings bool
+	// quiet suppresses the per-page progress lines.
+	quiet bool
+	// compactJSON writes JSON output on a single line instead of indented.
+	compactJSON bool
+	// trimURLPrefix is removed from each page URL in the output (display only).
+	trimURLPrefix string
+	// normalizeURLs canonicalizes page URLs after parsing so variants of a page are merged.
+	normalizeURLs bool
+	// relativePaths shows each page's SourcePath relative to its monorepo root (display only).
+	relativePaths bool
+	// cacheAnalysis reuses cached analyses of unchanged pages from ~/.audit-cli/analysis-cache.json.
+	cacheAnalysis bool
+	// provenance prints where the mapping behind each product decision was defined.
+	provenance bool
+	// driverVersionFallback is how driver URLs with a missing version directory are resolved.
+	driverVersionFallback string
+	// productAliases are "Alias=Canonical" values; aliased products are merged into the canonical one.
+	productAliases []string
+	// onlyPartial reports only pages and products that are partially tested.
+	onlyPartial bool
+	// onlyErrors reports only pages that failed to resolve or analyze.
+	onlyErrors bool
+	// contextAware uses only line-range tab context (see AnalyzeOptions.ContextAware).
+	contextAware bool
+	// renderedOnly skips examples that aren't rendered when the page loads (see AnalyzeOptions.RenderedOnly).
+	renderedOnly bool
+	// ownContentOnly counts only examples in the page's own source file, not its includes.
+	ownContentOnly bool
+	// ioAsOne counts each io-code-block input/output pair as one example.
+	ioAsOne bool
+	// monorepoPaths are more monorepos to look up projects in, after the main one: the
+	// config file's monorepo_paths, then --monorepo-path.
+	monorepoPaths []string
+	// slugRedirects are retired-to-current URL slug redirects from the config file.
+	slugRedirects map[string]string
+	// groupBy aggregates the report instead of listing pages ("content-dir", "language",
+	// or "" for per-page).
+	groupBy string
+	// summaryJSON is a path to also write the report's totals to as JSON, in any format.
+	summaryJSON string
+	// badge is a path to also write a Shields.io endpoint badge of the overall coverage to.
+	badge string
+	// badgeThresholds is "LOW:HIGH": coverage below LOW is red, below HIGH yellow, else green.
+	badgeThresholds string
+}
+
 // NewTestableCodeCommand creates the testable-code subcommand.
 func NewTestableCodeCommand() *cobra.Command {
-	var outputFormat string
-	var showDetails bool
-	var outputFile string
-	var filters []string
+	var opts reportOptions
 	var listDrivers bool
 
 	cmd := &cobra.Command{
@@ -70,6 +178,16 @@ and generates a report with:
 
 The CSV file should have columns for rank and URL. The first row is treated as a header.
 
+Use --csv to add more CSV files (e.g., one per region). Entries from all files are merged
+and de-duplicated by URL, keeping the best (lowest) rank. When --csv is used, the
+<csv-file> argument is optional.
+
+A CSV can also be an http:// or https:// URL, such as an analytics dashboard export. It
+is fetched with a 30-second timeout. To send an authentication header, set
+AUDIT_CLI_CSV_AUTH_HEADER to "Name: value":
+  export AUDIT_CLI_CSV_AUTH_HEADER="Authorization: Bearer <token>"
+  testable-code https://analytics.example.com/top-pages.csv
+
 Example CSV format:
   rank,url
   1,www.mongodb.com/docs/atlas/some-page/
@@ -89,25 +207,330 @@ Filters (use --filter to focus on specific product areas):
 
 Multiple filters can be specified to include pages matching any filter.
 
+Use --drivers-strict for a driver-only report: it filters to driver pages (like --filter
+drivers, or the driver:<name> filters given) and drops non-driver products (Shell,
+MongoDB Shell, JavaScript, JSON, YAML, Text, Unknown, ...) from each page's product
+breakdown and totals, so install commands and config files don't show up as rows. Run
+without it to see the raw data.
+
+Use --max-pages N to analyze only the top N pages by rank (applied after filtering),
+for a quick sanity check or spot-check of a new filter.
+
+Use --rank-range MIN:MAX to analyze only pages ranked MIN through MAX (inclusive), e.g.
+--rank-range 50:150 for mid-tier pages. It combines with --filter: pages must be in the
+range and match a filter.
+
+Use --max-include-depth N to stop following includes more than N levels below the page.
+Include cycles (a file that includes itself through a chain of includes) are never
+followed twice; each cycle is reported as a warning on the page.
+
+Use --max-file-size N to skip any source or include file larger than N bytes instead of
+scanning it. Each skipped file is reported as a warning on the page. Use --verbose to
+print the bytes scanned for each page and in total, to find the files worth skipping.
+
+Use --verify-tested to check that each /tested/ reference exists on disk. A reference
+to a file that was moved or deleted is reported as a warning and not counted as tested.
+
+Use --strict-paths to check every literalinclude and io-code-block file path. A path
+that resolves outside the monorepo (e.g., /../../other/file.py) or to a file that
+doesn't exist is reported as a warning on the page, to catch broken example references
+before they ship.
+
+Each distinct warning is printed to stderr the first time it appears. Repeats (e.g., the
+same broken shared include on 200 pages) are counted and listed once at the end of the
+run with a count like "(x200)". The report still lists every page's warnings.
+
+Use --only-partial to list only partially tested products: those with some, but not all,
+testable examples tested on a page. Other products and pages are left out of the report;
+page totals still count every example. These are the quickest pages to finish converting.
+
+Use --only-errors to list only the pages that failed, each with its error, in any output
+format. This is the worklist for cleaning up a stale analytics export: fix the redirects
+or refresh the URL mapping cache, then rerun.
+
+Use --since <ref-or-date> to only analyze pages whose source file changed since a git
+ref (e.g., main or a commit SHA) or date (e.g., 2025-01-01 or "2 weeks ago"), based on
+git log in each monorepo. Only each page's own source file is compared; changes to its
+includes are not. If git is unavailable, every page is analyzed and a warning is printed.
+
+Use --content-type tutorial|reference|landing|other to only analyze pages of one type,
+for example to focus on tutorials rather than API reference stubs. The type comes from
+the page's :template: option (landing, product-landing, and drivers-index are landing
+pages; changelog and openapi are reference), then a composable-tutorial directive
+(tutorial), then the path: a source root index page is a landing page, and the deepest
+path segment named tutorial(s), quick-start, get-started, or usage-examples (tutorial)
+or reference, api, or api-reference (reference) decides. Anything else is "other".
+
+Use --page-path-filter <regex> to only analyze pages whose source file path matches a
+regular expression, for example every page under source/fundamentals/. The path is
+matched relative to its monorepo root (content/<project>/.../source/...), after the URL
+is resolved. It combines with --filter: a page must pass both. Pages whose URL can't be
+resolved are kept and reported as errors:
+  testable-code analytics.csv --page-path-filter '/source/fundamentals/'
+
+Use --dir <path> instead of a CSV file to analyze every .txt and .rst page in a
+directory, for example a driver docs repository that isn't in the URL mapping or a
+project under review before it has analytics. URLs are not resolved: pages are ranked
+in path order and reported by their path relative to the directory, includes/ and
+code-examples/ are skipped, and the product comes from the content directory (the
+directory after content/, or the repository directory that contains source/). It
+can't be combined with --filter, --drivers-strict, or --normalize-urls:
+  testable-code --dir /path/to/docs-golang/source
+
+Use --explain <url> to debug a single page's numbers. Instead of a report, it prints
+every code example on the page with its directive type, language, resolved product,
+the context that determined the product (tab, composable, or content directory), and
+whether it is tested, testable, and maybe testable, with the reason for each. The CSV
+file is not needed; pass the monorepo path as the only argument or configure it:
+  testable-code --explain www.mongodb.com/docs/drivers/go/current/crud/ /path/to/monorepo
+When a run analyzes only one page, the text report is followed by the same trace.
+
+Use --expand-includes <url> for a compact view of the same page: one line per code
+example in the order collected, with the RST file it came from (relative to the page),
+its directive type, language, product, and tested/testable/maybe flags. With
+--format json it writes the list as JSON, ready to attach to a bug report.
+
+Testable products and drivers can be extended without a code change by adding
+testable_products and testable_drivers to .audit-cli.yaml (true adds, false removes):
+  testable_products:
+    Rust: true
+    rust: true
+  testable_drivers:
+    rust: true
+
+Projects whose docs live outside the main monorepo (e.g., a driver with its own docs
+repository) can be audited in the same run: list the repositories in monorepo_paths in
+.audit-cli.yaml, or pass --monorepo-path (can be repeated). The main monorepo is
+searched first, then each additional one in order; if two have the same project, the
+first wins and the other is reported as a warning.
+
+Content directories that don't map cleanly to a product can be attributed with
+content_dir_overrides in .audit-cli.yaml or --content-dir-override (flags win). These
+are consulted before the built-in content directory mapping:
+  content_dir_overrides:
+    cloud-docs: Atlas
+Content directories with neither an override nor a built-in mapping are listed on
+stderr at the end of the run, with the number of examples whose product fell back to
+their language.
+
+By default, a javascript or js example takes its product from the first tab or
+composable context around it, so it can be attributed to a generic context such as the
+driver interface, or to "JavaScript" (maybe testable) when no context maps. Use
+--javascript-as-nodejs-in-context to attribute javascript/js examples in any Node.js
+driver context (a nodejs tab or language composable, or the node content directory) to
+Node.js, so they are counted as testable. javascript elsewhere is unchanged.
+
+A code example with no :language: (and no language from its file extension) is
+"undefined". Use --use-composable-default to give it the default of the language
+composable its composable tutorial offers (e.g., :options: language, interface), as
+set in the project's snooty.toml (default = "python"), so it counts as Python. It's off
+by default because it changes attribution; --explain names the composable default in
+the product reason.
+
+An example inside a driver tab takes that tab's context. By default, an example outside
+every tab on a page with driver tabs takes the first tab's context. Use --context-aware
+to give it only the page's composable-tutorial context instead, so an extensionless
+literalinclude after a tab set falls back to its own language or content directory.
+
+By default, every code example is counted, including those a reader doesn't see when
+the page loads. Use --rendered-only to skip examples with :visible: false (including
+io-code-block outputs) and directives and includes inside a .. collapsible:: block
+that isn't :expanded: true.
+
+Use --own-content-only to count only the examples written in the page's own source
+file, so driver snippets shared through includes don't inflate per-page totals. Each
+page also reports how many shared examples were excluded (SharedExamples, or the Shared
+CSV column), and --summary-json and --group-by content-dir total them.
+
+By default an io-code-block counts as two examples, its input and its output. Use
+--io-as-one to count each pair once: the output no longer adds to the total, tested,
+testable, or maybe testable counts (page and per product), but input and output
+counts are unchanged. Totals drop by the number of pairs, so compare baselines only
+against runs made with the same setting.
+
+So dashboards get the same columns on every page, the products in --baseline-products
+are reported with zero counts when a page has no examples for them. The default set,
+also named "default", is Python, Node.js, Go, Java (Sync), C#, and MongoDB Shell; other
+products can be added, or pass --baseline-products "" to report only products found:
+  testable-code analytics.csv --baseline-products default,Rust -f csv --details --no-skip-zero
+The detailed CSV skips zero-valued products unless --no-skip-zero is set.
+
+Projects can define their own composables in snooty.toml, which take precedence over
+rstspec.toml. Use --provenance to print, per product, how many examples were
+attributed through rstspec.toml, each project's snooty.toml (noting when it overrides
+an rstspec.toml product), content_dir_overrides, or audit-cli's built-in rules. --explain
+shows the provenance of each example.
+
+Use --timings to print, at the end of the run, how long CSV parsing, URL mapping setup,
+rstspec.toml loading, and page analysis took, with the minimum, median, and maximum
+time per page and the 10 slowest pages.
+
+While pages are analyzed, a progress line with an estimate of the time left, like
+"Progress: 245/2000 (12%) ETA 3m20s", is printed to stderr about once a second (and
+every 100 pages). Use --quiet to turn off the progress lines and the per-page
+"Analyzing page" lines; warnings and end-of-run summaries are still printed.
+
+Analytics can point at driver versions that aren't checked out (e.g., drivers/node/v5.2/
+in a partial monorepo checkout). By default such a page is resolved without a version
+directory, and usually fails with "source file not found". Use --driver-version-fallback
+to choose: nearest (the closest numbered version directory, or current), current, or
+error (fail with the available versions, even if the project has none). Remapped pages
+get a warning.
+
+Use --product-alias to merge products for a coarser summary without changing how
+examples are attributed. Each alias's counts are added to the canonical product:
+  testable-code analytics.csv --product-alias "Java (Sync)=Java" --product-alias "Kotlin (Sync)=Kotlin"
+
+Use --normalize-urls to merge variants of the same page in analytics data. Each URL is
+rewritten to https:// with a lowercase host (www. added to a bare domain) and a trailing
+slash, and a #fragment is dropped, so http://mongodb.com/docs/atlas and
+www.mongodb.com/docs/atlas/ are one page, with the better rank. Reports show the
+normalized URLs.
+
+Use --trim-url-prefix to shorten page URLs in every output format, for example
+--trim-url-prefix www.mongodb.com/docs/ shows www.mongodb.com/docs/atlas/some-page/ as
+atlas/some-page/. URLs are still resolved in full.
+
+Use --relative-paths to show each page's source path relative to its monorepo root
+(for example, content/atlas/source/some-page.txt) in every output format, so shared
+reports don't contain home directories and diff cleanly across machines.
+
+Use --cache-analysis to speed up repeated audits of an unchanged monorepo. Each page's
+collected code examples are stored in ~/.audit-cli/analysis-cache.json and reused while
+the page, its includes, and the product mappings (rstspec.toml, the project's snooty.toml
+composables, and overrides) are unchanged. Files are compared by size and modification
+time. Leave it off for correctness-sensitive runs.
+
 Use --list-drivers to see available Driver filter options
 
 Output formats:
   - text: Human-readable report with summary and detailed sections
-  - json: Machine-readable JSON output
-  - csv: Comma-separated values (summary by default, use --details for per-product breakdown)`,
+  - json: Machine-readable JSON output, indented (use --compact-json for a single line
+    when piping large reports into jq or a loader)
+  - jsonl: JSON Lines, one page report per line with no enclosing array, written as
+    each page finishes (constant memory for very large CSVs)
+  - toml: TOML, with each page report as a [[Pages]] table
+  - csv: Comma-separated values (summary by default, use --details for per-product breakdown)
+  - matrix: CSV grid with one row per page and one column per product (the union of
+    products across all pages); each cell is the page's testable count, 0 if none
+  - worklist: Pages with testable but untested examples, most untested first, with the
+    analytics rank, the number untested, and the product with the most untested
+    examples. Use --top N to list only the first N pages
+  - examples-csv: CSV with one row per code example across all pages (url, rank,
+    source_file, type, language, product, is_input, is_output, is_tested, is_testable,
+    is_maybe_testable, file_path), for pivoting in a spreadsheet or notebook. Pages
+    that failed have no rows
+
+Use --output-dir to write one report file per project (e.g., pymongo-driver.json) instead
+of a single report. Pages that could not be resolved are written to unresolved.<ext>.
+
+Use --group-by content-dir to report totals per content directory (one per docs project,
+e.g., pymongo-driver) instead of per page: pages, failed pages, and total, tested,
+testable, and maybe testable examples, in any output format. Pages that could not be
+resolved are totaled under unresolved. This maps to team ownership for planning:
+  testable-code analytics.csv --group-by content-dir
+
+Use --group-by language to total code examples by the language written in the directive,
+before product resolution (e.g., every javascript block, whether it resolved to Node.js or
+JavaScript), with the products each language resolved to. This shows how much ambiguity
+product resolution is handling, and where explicit tabids would help.
+
+Use --summary-json to also write the report's totals to a small JSON file, whatever the
+--format, so one run gives both a readable report and a metric for CI: pages, failed
+pages, total, input, output, tested, testable, and maybe testable examples, coverage
+(tested as a percentage of testable), and the same totals per product:
+  testable-code analytics.csv --summary-json summary.json
+
+Use --badge to also write a Shields.io endpoint badge JSON file with the overall testable
+coverage (tested as a percentage of testable), for a dashboard:
+  {"schemaVersion":1,"label":"code coverage","message":"62%","color":"yellow"}
+The badge is red below 50%, yellow below 80%, and green otherwise; change the thresholds
+with --badge-thresholds LOW:HIGH (e.g., 40:70). With no testable examples it shows n/a:
+  testable-code analytics.csv --badge coverage-badge.json
+
+Use --warn-over N to warn about pages with more than N code examples. These are usually
+generated references, or URLs that resolved to an index page that includes everything.
+They are listed as potential outliers at the end of the run and in the --summary-json
+file:
+  testable-code analytics.csv --warn-over 200
+
+Use --fail-on-unknown-content-dir in CI to keep the content directory mapping complete:
+the report is written as usual, then the command exits with a non-zero status, listing
+the content directories, if any example's product fell back to its language because the
+page's content directory has no product mapping.
+
+Use --include-source with --format json or jsonl to add an Examples list to each page
+with every code example's type, language, product, and code, for downstream scanning.
+Inline bodies (code-block, code, fenced, and io-code-block content) are included; add
+--include-source-max-bytes N to also embed each literalinclude and io-code-block file up
+to N bytes (the whole file, not the :start-after:/:end-before: selection). The output
+grows with the amount of code and contains docs source verbatim, so treat it like the
+source when sharing it:
+  testable-code analytics.csv --format json --include-source --include-source-max-bytes 65536`,
 		Args: cobra.RangeArgs(0, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			// Merge testable product/driver overrides from the config file
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				return err
+			}
+			ApplyTestableOverrides(cfg)
+			opts.contentDirOverrides = mergeContentDirOverrides(cfg.ContentDirOverrides, opts.contentDirOverrides)
+			opts.slugRedirects = cfg.SlugRedirects
+			opts.monorepoPaths = append(slices.Clone(cfg.MonorepoPaths), opts.monorepoPaths...)
+
 			// Handle --list-drivers flag
 			if listDrivers {
 				return runListDrivers()
 			}
 
-			// Require CSV file if not listing drivers
-			if len(args) < 1 {
+			// Explain or expand a single page; the only positional argument may be the monorepo path
+			if opts.explain != "" || opts.expandIncludes != "" {
+				if opts.explain != "" && opts.expandIncludes != "" {
+					return fmt.Errorf("--explain and --expand-includes cannot be used together")
+				}
+				var cmdLineArg string
+				if len(args) > 1 {
+					cmdLineArg = args[1]
+				} else if len(args) == 1 {
+					if info, err := os.Stat(args[0]); err == nil && info.IsDir() {
+						cmdLineArg = args[0]
+					}
+				}
+				monorepoPath, err := config.GetMonorepoPath(cmdLineArg)
+				if err != nil {
+					return err
+				}
+				if opts.expandIncludes != "" {
+					return runExpandIncludes(opts.expandIncludes, monorepoPath, opts)
+				}
+				return runExplain(opts.explain, monorepoPath, opts)
+			}
+
+			// Analyze a directory of source files; there's no CSV or monorepo to resolve URLs in
+			if opts.dir != "" {
+				if len(args) > 0 || len(opts.csvFiles) > 0 {
+					return fmt.Errorf("--dir can't be used with CSV files")
+				}
+				// Absolute, so --relative-paths and --page-path-filter can relate source paths to it
+				dir, err := filepath.Abs(opts.dir)
+				if err != nil {
+					return fmt.Errorf("failed to resolve --dir %s: %w", opts.dir, err)
+				}
+				return runTestableCode(nil, dir, opts)
+			}
+
+			// Require at least one CSV file if not listing drivers
+			if len(args) < 1 && len(opts.csvFiles) == 0 {
 				return fmt.Errorf("requires at least 1 arg(s), only received 0")
 			}
 
-			csvPath := args[0]
+			// The first positional argument is always a CSV file; --csv adds more
+			var csvPaths []string
+			if len(args) > 0 {
+				csvPaths = append(csvPaths, args[0])
+			}
+			csvPaths = append(csvPaths, opts.csvFiles...)
 
 			// Get monorepo path
 			var cmdLineArg string
@@ -119,15 +542,64 @@ Output formats:
 				return err
 			}
 
-			return runTestableCode(csvPath, monorepoPath, outputFormat, showDetails, outputFile, filters)
+			return runTestableCode(csvPaths, monorepoPath, opts)
 		},
 	}
 
-	cmd.Flags().StringVarP(&outputFormat, "format", "f", "text", "Output format: text, json, or csv")
-	cmd.Flags().BoolVar(&showDetails, "details", false, "Show detailed per-product breakdown (for csv: one row per product per page)")
-	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "Output file path (default: stdout)")
-	cmd.Flags().StringSliceVar(&filters, "filter", nil, "Filter pages by product area (search, vector-search, drivers, driver:<name>, mongosh)")
+	cmd.Flags().StringArrayVar(&opts.csvFiles, "csv", nil, "Additional analytics CSV file to merge (can be repeated)")
+	cmd.Flags().StringVar(&opts.dir, "dir", "", "Analyze every .txt and .rst page in this directory instead of analytics CSV pages (no URL resolution)")
+	cmd.Flags().StringArrayVar(&opts.monorepoPaths, "monorepo-path", nil, "Also look up projects in this monorepo, after the main one (can be repeated)")
+	cmd.Flags().StringVarP(&opts.outputFormat, "format", "f", "text", "Output format: text, json, jsonl, toml, csv, matrix, worklist, or examples-csv")
+	cmd.Flags().IntVar(&opts.top, "top", 0, "With --format worklist, only list this many pages (default: all)")
+	cmd.Flags().BoolVar(&opts.showDetails, "details", false, "Show detailed per-product breakdown (for csv: one row per product per page)")
+	cmd.Flags().StringVarP(&opts.outputFile, "output", "o", "", "Output file path (default: stdout)")
+	cmd.Flags().StringVar(&opts.outputDir, "output-dir", "", "Write one report file per project to this directory")
+	cmd.Flags().StringVar(&opts.groupBy, "group-by", "", "Report totals per group instead of per page: content-dir or language")
+	cmd.Flags().StringVar(&opts.summaryJSON, "summary-json", "", "Also write the report's totals and per-product totals to this JSON file, in any --format")
+	cmd.Flags().StringVar(&opts.badge, "badge", "", "Also write a Shields.io endpoint badge JSON file with the overall testable coverage")
+	cmd.Flags().StringVar(&opts.badgeThresholds, "badge-thresholds", DefaultBadgeThresholds, "With --badge, the coverage percentages LOW:HIGH below which the badge is red and yellow (green otherwise)")
+	cmd.Flags().StringSliceVar(&opts.filters, "filter", nil, "Filter pages by product area (search, vector-search, drivers, driver:<name>, mongosh)")
+	cmd.Flags().StringVar(&opts.rankRange, "rank-range", "", "Only analyze pages ranked MIN through MAX, e.g. 50:150")
+	cmd.Flags().IntVar(&opts.maxPages, "max-pages", 0, "Only analyze the top N pages by rank, after filtering (0 = all)")
+	cmd.Flags().IntVar(&opts.maxIncludeDepth, "max-include-depth", 0, "Maximum levels of includes to follow per page (0 = unlimited)")
+	cmd.Flags().BoolVar(&opts.verifyTested, "verify-tested", false, "Check that each /tested/ reference exists on disk; missing files are not counted as tested")
+	cmd.Flags().BoolVar(&opts.strictPaths, "strict-paths", false, "Warn about literalinclude and io-code-block files that resolve outside the monorepo or don't exist")
+	cmd.Flags().StringVar(&opts.since, "since", "", "Only analyze pages whose source file changed since this git ref or date")
+	cmd.Flags().StringVar(&opts.pagePathFilter, "page-path-filter", "", "Only analyze pages whose source path, relative to the monorepo (content/<project>/...), matches this regular expression")
+	cmd.Flags().StringVar(&opts.contentType, "content-type", "", "Only analyze pages of this content type: tutorial, reference, landing, or other")
+	cmd.Flags().StringVar(&opts.explain, "explain", "", "Print the classification of every code example on this page URL instead of a report")
+	cmd.Flags().StringVar(&opts.expandIncludes, "expand-includes", "", "Print the flattened code example list of this page URL, includes expanded, instead of a report (text or json)")
+	cmd.Flags().StringToStringVar(&opts.contentDirOverrides, "content-dir-override", nil, "Attribute a content directory to a product, e.g. cloud-docs=Atlas (can be repeated)")
+	cmd.Flags().Int64Var(&opts.maxFileSize, "max-file-size", 0, "Skip source and include files larger than this many bytes (0 = unlimited)")
+	cmd.Flags().BoolVarP(&opts.verbose, "verbose", "v", false, "Report the bytes scanned for each page and in total")
+	cmd.Flags().StringSliceVar(&opts.baselineProducts, "baseline-products", []string{"default"}, "Always report these products for every page, with zero counts if absent (\"default\" = "+strings.Join(DefaultBaselineProducts, ", ")+"; \"\" for none)")
+	cmd.Flags().BoolVar(&opts.compactJSON, "compact-json", false, "Write --format json output on a single line instead of indented")
+	cmd.Flags().BoolVar(&opts.keepZero, "no-skip-zero", false, "Keep zero-valued products in the detailed CSV (use with --baseline-products)")
+	cmd.Flags().StringArrayVar(&opts.productAliases, "product-alias", nil, "Merge a product into another in the report, e.g. \"Java (Sync)=Java\" (can be repeated)")
+	cmd.Flags().BoolVar(&opts.cacheAnalysis, "cache-analysis", false, "Reuse cached analyses of pages whose files and product mappings are unchanged")
+	cmd.Flags().BoolVar(&opts.normalizeURLs, "normalize-urls", false, "Canonicalize page URLs (https, www, trailing slash, lowercase host) before merging duplicates")
+	cmd.Flags().StringVar(&opts.trimURLPrefix, "trim-url-prefix", "", "Remove this prefix from page URLs in the output, e.g. www.mongodb.com/docs/ (display only)")
+	cmd.Flags().BoolVar(&opts.relativePaths, "relative-paths", false, "Show source paths relative to the monorepo root in the output (display only)")
+	cmd.Flags().StringVar(&opts.driverVersionFallback, "driver-version-fallback", config.DriverVersionFallbackNone, "How to resolve driver URLs whose version directory is missing: none, nearest, current, or error")
+	cmd.Flags().BoolVar(&opts.onlyPartial, "only-partial", false, "Only report pages and products with some, but not all, testable examples tested")
+	cmd.Flags().BoolVar(&opts.onlyErrors, "only-errors", false, "Only report pages that failed to resolve or analyze, with their errors")
+	cmd.Flags().BoolVar(&opts.quiet, "quiet", false, "Don't print per-page progress lines or the progress ETA (warnings and summaries are still printed)")
+	cmd.Flags().BoolVar(&opts.timings, "timings", false, "Print how long CSV parsing, URL mapping, rstspec.toml loading, and page analysis took, and the slowest pages")
+	cmd.Flags().BoolVar(&opts.provenance, "provenance", false, "Print whether each product came from rstspec.toml, a project's snooty.toml, or a built-in rule")
+	cmd.Flags().BoolVar(&opts.contextAware, "context-aware", false, "Attribute examples outside any tab by their own language, not the page's first driver tab")
+	cmd.Flags().BoolVar(&opts.renderedOnly, "rendered-only", false, "Only count code examples rendered when the page loads (skip :visible: false and collapsed collapsible blocks)")
+	cmd.Flags().BoolVar(&opts.ownContentOnly, "own-content-only", false, "Only count code examples in the page's own source file, not in shared includes (reports how many were excluded)")
+	cmd.Flags().BoolVar(&opts.ioAsOne, "io-as-one", false, "Count each io-code-block input/output pair as one example (input and output counts are unchanged)")
+	cmd.Flags().BoolVar(&opts.includeSource, "include-source", false, "Add each page's code examples with their code (inline code-block, code, and io-code-block bodies) to JSON and JSONL output")
+	cmd.Flags().Int64Var(&opts.maxSourceBytes, "include-source-max-bytes", 0, "With --include-source, also embed each literalinclude and io-code-block file up to this many bytes (default: inline bodies only)")
+	cmd.Flags().BoolVar(&opts.failOnUnknownContentDir, "fail-on-unknown-content-dir", false, "Exit with a non-zero status if any page's content directory has no product mapping (after writing the report)")
+	cmd.Flags().IntVar(&opts.warnOver, "warn-over", 0, "Warn about pages with more than this many code examples and list them as potential outliers (default: off)")
+	cmd.Flags().BoolVar(&opts.driversStrict, "drivers-strict", false, "Only analyze driver pages (--filter drivers) and drop non-driver products (Shell, MongoDB Shell, JSON, YAML, ...) from the breakdown and totals")
+	cmd.Flags().BoolVar(&opts.useComposableDefault, "use-composable-default", false, "Give code examples with no language the default of their composable tutorial's language composable in snooty.toml, instead of undefined")
+	cmd.Flags().BoolVar(&opts.javascriptAsNodeJS, "javascript-as-nodejs-in-context", false, "Attribute javascript/js examples in a Node.js driver tab, composable, or content directory to Node.js")
 	cmd.Flags().BoolVar(&listDrivers, "list-drivers", false, "List all drivers from the Snooty Data API")
+	cmd.MarkFlagsMutuallyExclusive("output", "output-dir")
+	cmd.MarkFlagsMutuallyExclusive("only-errors", "only-partial")
 
 	return cmd
 }
@@ -188,87 +660,556 @@ func runListDrivers() error {
 }
 
 // runTestableCode is the main entry point for the testable-code command.
-func runTestableCode(csvPath, monorepoPath, outputFormat string, showDetails bool, outputFile string, filters []string) error {
-	// Parse CSV file
-	entries, err := ParseCSV(csvPath)
+func runTestableCode(csvPaths []string, monorepoPath string, opts reportOptions) error {
+	aliases, err := ParseProductAliases(opts.productAliases)
 	if err != nil {
-		return fmt.Errorf("failed to parse CSV: %w", err)
+		return err
+	}
+	if opts.driverVersionFallback != "" && !slices.Contains(config.DriverVersionFallbacks, opts.driverVersionFallback) {
+		return fmt.Errorf("invalid --driver-version-fallback %q (must be one of: %s)",
+			opts.driverVersionFallback, strings.Join(config.DriverVersionFallbacks, ", "))
+	}
+	if opts.groupBy != "" && opts.groupBy != GroupByContentDir && opts.groupBy != GroupByLanguage {
+		return fmt.Errorf("invalid --group-by %q (must be one of: %s, %s)", opts.groupBy, GroupByContentDir, GroupByLanguage)
+	}
+	if opts.groupBy != "" && opts.outputDir != "" {
+		return fmt.Errorf("--group-by can't be used with --output-dir")
+	}
+	if opts.groupBy != "" && opts.outputFormat == "matrix" {
+		return fmt.Errorf("--group-by can't be used with --format matrix")
+	}
+	if opts.outputFormat == "worklist" && (opts.groupBy != "" || opts.outputDir != "") {
+		return fmt.Errorf("--format worklist can't be used with --group-by or --output-dir")
+	}
+	if opts.outputFormat == "examples-csv" && (opts.groupBy != "" || opts.outputDir != "") {
+		return fmt.Errorf("--format examples-csv can't be used with --group-by or --output-dir")
+	}
+	if opts.includeSource && opts.outputFormat != "json" && opts.outputFormat != "jsonl" {
+		return fmt.Errorf("--include-source only works with --format json or jsonl")
+	}
+	if opts.includeSource && opts.groupBy != "" {
+		return fmt.Errorf("--include-source can't be used with --group-by")
+	}
+	if opts.maxSourceBytes < 0 {
+		return fmt.Errorf("--include-source-max-bytes must be a positive number of bytes")
+	}
+	if opts.maxSourceBytes > 0 && !opts.includeSource {
+		return fmt.Errorf("--include-source-max-bytes requires --include-source")
+	}
+	var pagePathRegex *regexp.Regexp
+	if opts.pagePathFilter != "" {
+		if pagePathRegex, err = regexp.Compile(opts.pagePathFilter); err != nil {
+			return fmt.Errorf("invalid --page-path-filter: %w", err)
+		}
+	}
+	if opts.warnOver < 0 {
+		return fmt.Errorf("--warn-over must be a positive number of code examples")
+	}
+	if opts.top < 0 {
+		return fmt.Errorf("--top must be a positive number of pages")
+	}
+	if opts.maxPages < 0 {
+		return fmt.Errorf("--max-pages must be a positive number of pages")
+	}
+	var badgeLow, badgeHigh float64
+	if opts.badge != "" {
+		if badgeLow, badgeHigh, err = parseBadgeThresholds(opts.badgeThresholds); err != nil {
+			return err
+		}
+	}
+	if opts.dir != "" && (len(opts.filters) > 0 || opts.driversStrict || opts.normalizeURLs) {
+		return fmt.Errorf("--dir can't be used with --filter, --drivers-strict, or --normalize-urls, which need page URLs")
+	}
+	var minRank, maxRank int
+	if opts.rankRange != "" {
+		if minRank, maxRank, err = parseRankRange(opts.rankRange); err != nil {
+			return err
+		}
+	}
+	if opts.contentType != "" {
+		if err := validateContentType(opts.contentType); err != nil {
+			return err
+		}
 	}
 
-	fmt.Fprintf(os.Stderr, "Parsed %d pages from CSV\n", len(entries))
+	// Phases are always timed; the timings are only printed with --timings
+	timings := &RunTimings{}
 
-	// Get URL mapping early - needed for driver filters
-	urlMapping, err := config.GetURLMapping(monorepoPath)
-	if err != nil {
-		return fmt.Errorf("failed to get URL mapping: %w", err)
+	// Parse and merge CSV files, or find the pages in --dir
+	start := time.Now()
+	var entries []PageEntry
+	var duplicates int
+	if opts.dir != "" {
+		if entries, err = DirEntries(opts.dir); err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return fmt.Errorf("no pages to analyze: no .txt or .rst files in %s", opts.dir)
+		}
+		fmt.Fprintf(os.Stderr, "Found %d pages in %s\n", len(entries), opts.dir)
+	} else {
+		if entries, duplicates, err = ParseCSVFiles(csvPaths); err != nil {
+			return fmt.Errorf("failed to parse CSV: %w", err)
+		}
+		if len(entries) == 0 {
+			return fmt.Errorf("no pages to analyze: the CSV contained no data rows")
+		}
+		if len(csvPaths) > 1 {
+			fmt.Fprintf(os.Stderr, "Parsed %d pages from %d CSV files (merged %d duplicate URLs)\n", len(entries), len(csvPaths), duplicates)
+		} else {
+			fmt.Fprintf(os.Stderr, "Parsed %d pages from CSV\n", len(entries))
+		}
+	}
+	timings.CSVParsing = time.Since(start)
+
+	// Collapse http/https, www, and trailing slash variants of the same page
+	if opts.normalizeURLs {
+		entries, duplicates = NormalizeEntries(entries)
+		fmt.Fprintf(os.Stderr, "Normalized URLs: %d pages (merged %d variant URLs)\n", len(entries), duplicates)
+	}
+
+	// Keep only pages in the rank range
+	if opts.rankRange != "" {
+		entries = filterEntriesByRank(entries, minRank, maxRank)
+		fmt.Fprintf(os.Stderr, "Filtered to %d pages ranked %d-%d\n", len(entries), minRank, maxRank)
 	}
 
+	// Get URL mapping early - needed for driver filters. Pages found with --dir have
+	// their source files already, so there are no URLs to resolve.
+	start = time.Now()
+	urlMapping := &config.URLMapping{}
+	monorepoRoots := []string{monorepoPath}
+	if opts.dir == "" {
+		var additionalMonorepos []string
+		if urlMapping, additionalMonorepos, err = opts.loadURLMapping(monorepoPath); err != nil {
+			return err
+		}
+		monorepoRoots = append(monorepoRoots, additionalMonorepos...)
+	}
+	timings.URLMapping = time.Since(start)
+
 	// Validate filters before applying
-	if err := validateFilters(filters); err != nil {
+	if err := validateFilters(opts.filters); err != nil {
 		return err
 	}
+	if opts.driversStrict {
+		for _, filter := range opts.filters {
+			if lower := strings.ToLower(filter); lower != "drivers" && !strings.HasPrefix(lower, "driver:") {
+				return fmt.Errorf("--drivers-strict only works with driver filters (drivers, driver:<name>), not %q", filter)
+			}
+		}
+		if len(opts.filters) == 0 {
+			opts.filters = []string{"drivers"}
+		}
+	}
 
 	// Apply URL filters if specified
-	if len(filters) > 0 {
+	if len(opts.filters) > 0 {
 		originalCount := len(entries)
-		entries = filterEntries(entries, filters, urlMapping)
-		fmt.Fprintf(os.Stderr, "Filtered to %d pages matching filter(s): %v\n", len(entries), filters)
+		entries = filterEntries(entries, opts.filters, urlMapping)
+		fmt.Fprintf(os.Stderr, "Filtered to %d pages matching filter(s): %v\n", len(entries), opts.filters)
 		if len(entries) == 0 {
 			fmt.Fprintf(os.Stderr, "Warning: No pages matched the specified filter(s). Original count: %d\n", originalCount)
 		}
 	}
 
+	// Keep only pages whose source file changed since the given ref or date
+	if opts.since != "" {
+		changed, err := changedFilesInMonorepos(monorepoRoots, opts.since)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: --since ignored, analyzing all pages: %v\n", err)
+		} else {
+			entries = filterChangedEntries(entries, changed, urlMapping)
+			fmt.Fprintf(os.Stderr, "Filtered to %d pages changed since %s\n", len(entries), opts.since)
+		}
+	}
+
+	// Keep only pages of the requested content type
+	if opts.contentType != "" {
+		entries = filterContentTypeEntries(entries, opts.contentType, urlMapping)
+		fmt.Fprintf(os.Stderr, "Filtered to %d %s pages\n", len(entries), opts.contentType)
+	}
+
+	// Keep only pages whose source path matches the pattern
+	if pagePathRegex != nil {
+		entries = filterPagePathEntries(entries, pagePathRegex, urlMapping, monorepoRoots)
+		fmt.Fprintf(os.Stderr, "Filtered to %d pages with source paths matching %s\n", len(entries), opts.pagePathFilter)
+	}
+
+	// Limit to the top N pages by rank if requested
+	if opts.maxPages > 0 && opts.maxPages < len(entries) {
+		totalCount := len(entries)
+		entries = limitEntries(entries, opts.maxPages)
+		fmt.Fprintf(os.Stderr, "Analyzing top %d of %d pages.\n", len(entries), totalCount)
+	}
+
 	// Load product mappings from rstspec.toml
 	fmt.Fprintf(os.Stderr, "Loading product mappings from rstspec.toml...\n")
-	mappings, err := LoadProductMappings()
+	start = time.Now()
+	mappings, err := GetProductMappings()
 	if err != nil {
 		return fmt.Errorf("failed to load product mappings: %w", err)
 	}
+	timings.ProductMappings = time.Since(start)
+
+	// Determine output writer (not used with --output-dir)
+	var writer *os.File
+	if opts.outputDir == "" {
+		if opts.outputFile != "" {
+			f, err := os.Create(opts.outputFile)
+			if err != nil {
+				return fmt.Errorf("failed to create output file: %w", err)
+			}
+			defer f.Close()
+			writer = f
+			fmt.Fprintf(os.Stderr, "Writing output to %s\n", opts.outputFile)
+		} else {
+			writer = os.Stdout
+		}
+	}
+
+	// JSON Lines is written as each page finishes instead of buffering every report,
+	// unless the pages are totaled by group at the end
+	var stream *json.Encoder
+	if opts.outputFormat == "jsonl" && writer != nil && opts.groupBy == "" {
+		stream = json.NewEncoder(writer)
+	}
 
 	// Analyze each page
+	analyzeOpts := opts.analyzeOptions()
+	if opts.cacheAnalysis {
+		analyzeOpts.Cache = loadAnalysisCache()
+	}
+	baselineProducts := ExpandBaselineProducts(opts.baselineProducts)
 	var reports []PageReport
+	var failures []error
+	var totalBytes int64
+	provenance := ProvenanceSummary{}
+	unknownContentDirs := UnknownContentDirs{}
+	languageTotals := LanguageTotals{}
+	var exampleRecords []ExampleRecord
+	var onlyPage *PageAnalysis // The analysis, when the run analyzes a single page
+	summary := NewReportSummary()
+	summary.UsedStaticFallback = urlMapping.UsedStaticFallback
+	var progress *Progress
+	if !opts.quiet {
+		progress = NewProgress(os.Stderr, len(entries))
+	}
 	for i, entry := range entries {
-		fmt.Fprintf(os.Stderr, "Analyzing page %d/%d: %s\n", i+1, len(entries), entry.URL)
+		if !opts.quiet {
+			fmt.Fprintf(os.Stderr, "Analyzing page %d/%d: %s\n", i+1, len(entries), entry.URL)
+		}
 
-		analysis, err := AnalyzePage(entry, urlMapping, mappings)
+		var report PageReport
+		start := time.Now()
+		analysis, err := AnalyzePage(entry, urlMapping, mappings, analyzeOpts)
+		timings.AddPage(entry.URL, time.Since(start))
+		progress.Done()
 		if err != nil {
 			// Log error but continue with other pages
 			fmt.Fprintf(os.Stderr, "  Warning: %v\n", err)
-			reports = append(reports, PageReport{
-				Rank:  entry.Rank,
-				URL:   entry.URL,
-				Error: err.Error(),
-			})
+			failures = append(failures, err)
+			report = PageReport{
+				Rank:          entry.Rank,
+				URL:           entry.URL,
+				Error:         err.Error(),
+				ErrorCategory: errorCategory(err),
+			}
+		} else {
+			var shared int
+			if opts.ownContentOnly {
+				shared = StripSharedIncludes(analysis)
+			}
+			report = BuildPageReport(analysis)
+			report.SharedExamples = shared
+			if opts.ioAsOne {
+				CountIOPairsAsOne(&report, analysis)
+			}
+			AddBaselineProducts(&report, baselineProducts)
+			ApplyProductAliases(&report, aliases)
+			if opts.driversStrict {
+				DropNonDriverProducts(&report)
+			}
+			if opts.includeSource {
+				report.Examples = BuildExampleSources(analysis)
+			}
+			if isOutlier(report, opts.warnOver) {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("page has %d code examples (more than --warn-over %d); check that the URL resolved to the right page", report.TotalExamples, opts.warnOver))
+			}
+			for _, warning := range report.Warnings {
+				analyzeOpts.Warnings.Warn(os.Stderr, "  ", warning)
+			}
+			totalBytes += analysis.BytesScanned
+			provenance.Add(analysis)
+			unknownContentDirs.Add(analysis)
+			if opts.verbose {
+				fmt.Fprintf(os.Stderr, "  Scanned %d bytes in %d file(s)\n", analysis.BytesScanned, analysis.FilesScanned)
+			}
+		}
+		if opts.onlyPartial && !KeepPartiallyTested(&report) {
+			continue
+		}
+		if opts.onlyErrors && report.Error == "" {
 			continue
 		}
 
-		report := BuildPageReport(analysis)
+		// Resolution is done, so the URL is only used for display from here on
+		report.URL = TrimURLPrefix(report.URL, opts.trimURLPrefix)
+		if opts.relativePaths {
+			report.SourcePath = RelativeSourcePath(report.SourcePath, monorepoRoots)
+		}
+		summary.Add(report)
+		summary.AddOutlier(report, opts.warnOver)
+		if opts.groupBy == GroupByLanguage && err == nil {
+			languageTotals.Add(analysis)
+		}
+		if opts.outputFormat == "examples-csv" && err == nil {
+			records := BuildExampleRecords(analysis, report.URL)
+			if opts.relativePaths {
+				for i := range records {
+					records[i].SourceFile = RelativeSourcePath(records[i].SourceFile, monorepoRoots)
+					records[i].FilePath = RelativeSourcePath(records[i].FilePath, monorepoRoots)
+				}
+			}
+			exampleRecords = append(exampleRecords, records...)
+		}
+		if len(entries) == 1 && err == nil {
+			onlyPage = analysis
+		}
+
+		if stream != nil {
+			if err := stream.Encode(report); err != nil {
+				return fmt.Errorf("failed to write output: %w", err)
+			}
+			continue
+		}
 		reports = append(reports, report)
 	}
 
-	// Determine output writer
-	var writer *os.File
-	if outputFile != "" {
-		f, err := os.Create(outputFile)
+	if opts.verbose {
+		fmt.Fprintf(os.Stderr, "Total bytes scanned: %d\n", totalBytes)
+	}
+
+	if analyzeOpts.Cache != nil {
+		hits, misses := analyzeOpts.Cache.Stats()
+		fmt.Fprintf(os.Stderr, "Analysis cache: %d page(s) reused, %d analyzed\n", hits, misses)
+		if err := analyzeOpts.Cache.Save(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
+	if opts.provenance {
+		PrintProvenanceReport(os.Stderr, provenance)
+	}
+
+	PrintUnknownContentDirs(os.Stderr, unknownContentDirs)
+
+	// Group the per-page warnings so patterns (e.g., a project missing from the checkout) stand out
+	PrintFailureSummary(os.Stderr, failures)
+	PrintOutliers(os.Stderr, summary, opts.warnOver)
+	PrintRepeatedWarnings(os.Stderr, analyzeOpts.Warnings)
+
+	if opts.timings {
+		PrintTimings(os.Stderr, timings)
+	}
+
+	if opts.summaryJSON != "" {
+		if err := WriteSummaryJSON(opts.summaryJSON, summary); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Wrote summary to %s\n", opts.summaryJSON)
+	}
+
+	if opts.badge != "" {
+		if err := WriteBadge(opts.badge, BuildBadge(summary, badgeLow, badgeHigh)); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Wrote coverage badge to %s\n", opts.badge)
+	}
+
+	// Deferred so the banner is printed after the report, as the last thing in the terminal
+	if urlMapping.UsedStaticFallback {
+		defer PrintStaticFallbackBanner(os.Stderr)
+	}
+
+	if stream == nil {
+		if err := writeTestableCodeOutput(writer, reports, languageTotals, exampleRecords, opts); err != nil {
+			return err
+		}
+		// A text report of a single page is followed by the --explain trace for it
+		if onlyPage != nil && opts.outputFormat == "text" && opts.groupBy == "" && opts.outputDir == "" {
+			fmt.Fprintln(writer)
+			OutputExplain(writer, onlyPage)
+		}
+	}
+
+	if opts.failOnUnknownContentDir {
+		return unknownContentDirs.Err()
+	}
+	return nil
+}
+
+// writeTestableCodeOutput writes the collected reports in the requested form: one file per
+// project, a worklist, a per-example table, totals by group, or a single report.
+func writeTestableCodeOutput(writer io.Writer, reports []PageReport, languageTotals LanguageTotals, exampleRecords []ExampleRecord, opts reportOptions) error {
+	// Split into one file per project if requested
+	if opts.outputDir != "" {
+		written, err := OutputByProject(opts.outputDir, reports, opts.reportFormat())
 		if err != nil {
-			return fmt.Errorf("failed to create output file: %w", err)
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %d project report(s) to %s\n", len(written), opts.outputDir)
+		return nil
+	}
+
+	if opts.outputFormat == "worklist" {
+		return OutputWorklist(writer, BuildWorklist(reports, opts.top))
+	}
+	if opts.outputFormat == "examples-csv" {
+		return OutputExamplesCSV(writer, exampleRecords)
+	}
+
+	if opts.groupBy == GroupByContentDir {
+		return writeContentDirSummary(writer, SummarizeByContentDir(reports), opts.outputFormat, opts.compactJSON)
+	}
+	if opts.groupBy == GroupByLanguage {
+		return writeLanguageSummary(writer, languageTotals.Summaries(), opts.outputFormat, opts.compactJSON)
+	}
+
+	return writeReport(writer, reports, opts.reportFormat())
+}
+
+// reportFormat returns the ReportFormat for the output flags.
+func (opts reportOptions) reportFormat() ReportFormat {
+	return ReportFormat{
+		Format:      opts.outputFormat,
+		ShowDetails: opts.showDetails,
+		KeepZero:    opts.keepZero,
+		CompactJSON: opts.compactJSON,
+	}
+}
+
+// loadURLMapping loads the URL mapping for monorepoPath and the --monorepo-path
+// checkouts, with --driver-version-fallback and --slug-redirect applied, so every page
+// URL resolves the same way in the report, --explain, and --expand-includes.
+// It also returns the additional monorepos.
+func (opts reportOptions) loadURLMapping(monorepoPath string) (*config.URLMapping, []string, error) {
+	additionalMonorepos := config.AdditionalMonorepoPaths(monorepoPath, opts.monorepoPaths)
+	urlMapping, err := config.GetURLMapping(monorepoPath, additionalMonorepos...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get URL mapping: %w", err)
+	}
+	urlMapping.DriverVersionFallback = opts.driverVersionFallback
+	urlMapping.AddSlugRedirects(opts.slugRedirects)
+	return urlMapping, additionalMonorepos, nil
+}
+
+// analyzeOptions returns the AnalyzeOptions for the analysis flags. The analysis cache
+// isn't set; runTestableCode loads it for --cache-analysis.
+func (opts reportOptions) analyzeOptions() AnalyzeOptions {
+	return AnalyzeOptions{
+		MaxIncludeDepth:      opts.maxIncludeDepth,
+		VerifyTested:         opts.verifyTested,
+		StrictPaths:          opts.strictPaths,
+		ContentDirOverrides:  opts.contentDirOverrides,
+		MaxFileSize:          opts.maxFileSize,
+		JavaScriptAsNodeJS:   opts.javascriptAsNodeJS,
+		UseComposableDefault: opts.useComposableDefault,
+		ContextAware:         opts.contextAware,
+		RenderedOnly:         opts.renderedOnly,
+		IncludeSource:        opts.includeSource,
+		MaxSourceBytes:       opts.maxSourceBytes,
+		Warnings:             NewWarningLog(),
+	}
+}
+
+// loadAnalysisCache loads the analysis cache from its default location.
+// If it can't be loaded, a warning is printed and an empty cache is used.
+func loadAnalysisCache() *AnalysisCache {
+	path, err := DefaultAnalysisCachePath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: --cache-analysis ignored: %v\n", err)
+		return nil
+	}
+	cache, err := LoadAnalysisCache(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v (starting with an empty cache)\n", err)
+	}
+	return cache
+}
+
+// runExplain analyzes a single page URL and prints the classification of each code example.
+func runExplain(url, monorepoPath string, opts reportOptions) error {
+	analysis, urlMapping, err := analyzeSinglePage(url, monorepoPath, opts)
+	if err != nil {
+		return err
+	}
+
+	OutputExplain(os.Stdout, analysis)
+	if urlMapping.UsedStaticFallback {
+		PrintStaticFallbackBanner(os.Stderr)
+	}
+	return nil
+}
+
+// runExpandIncludes handles the --expand-includes flag: it prints a page's code examples
+// in collection order, as text or, with --format json, as an ExpandedPage.
+func runExpandIncludes(url, monorepoPath string, opts reportOptions) error {
+	if opts.outputFormat != "text" && opts.outputFormat != "json" {
+		return fmt.Errorf("--expand-includes supports --format text or json, not %q", opts.outputFormat)
+	}
+
+	analysis, urlMapping, err := analyzeSinglePage(url, monorepoPath, opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.outputFormat == "json" {
+		if err := OutputExpandedIncludesJSON(os.Stdout, analysis, opts.compactJSON); err != nil {
+			return err
 		}
-		defer f.Close()
-		writer = f
-		fmt.Fprintf(os.Stderr, "Writing output to %s\n", outputFile)
 	} else {
-		writer = os.Stdout
+		OutputExpandedIncludes(os.Stdout, analysis)
+	}
+	if urlMapping.UsedStaticFallback {
+		PrintStaticFallbackBanner(os.Stderr)
 	}
+	return nil
+}
 
-	// Output report
-	switch outputFormat {
-	case "json":
-		return OutputJSON(writer, reports)
-	case "csv":
-		return OutputCSV(writer, reports, showDetails)
-	default:
-		return OutputText(writer, reports)
+// analyzeSinglePage resolves and analyzes one page URL for --explain and --expand-includes.
+func analyzeSinglePage(url, monorepoPath string, opts reportOptions) (*PageAnalysis, *config.URLMapping, error) {
+	urlMapping, _, err := opts.loadURLMapping(monorepoPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mappings, err := GetProductMappings()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load product mappings: %w", err)
 	}
+
+	analysis, err := AnalyzePage(PageEntry{URL: url}, urlMapping, mappings, opts.analyzeOptions())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to analyze %s: %w", url, err)
+	}
+	return analysis, urlMapping, nil
+}
+
+// mergeContentDirOverrides combines content directory overrides from the config file and
+// the command line. Command-line entries win for the same content directory.
+func mergeContentDirOverrides(fromConfig, fromFlags map[string]string) map[string]string {
+	if len(fromConfig) == 0 {
+		return fromFlags
+	}
+	merged := make(map[string]string, len(fromConfig)+len(fromFlags))
+	for dir, product := range fromConfig {
+		merged[dir] = product
+	}
+	for dir, product := range fromFlags {
+		merged[dir] = product
+	}
+	return merged
 }
 
 // filterEntries filters page entries based on the specified filters.
@@ -283,6 +1224,53 @@ func filterEntries(entries []PageEntry, filters []string, urlMapping *config.URL
 	return filtered
 }
 
+// parseRankRange parses a --rank-range value of the form "MIN:MAX".
+// Both bounds must be positive and MIN must not exceed MAX.
+func parseRankRange(value string) (int, int, error) {
+	minStr, maxStr, ok := strings.Cut(value, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid --rank-range %q (expected MIN:MAX, e.g. 50:150)", value)
+	}
+	minRank, minErr := strconv.Atoi(strings.TrimSpace(minStr))
+	maxRank, maxErr := strconv.Atoi(strings.TrimSpace(maxStr))
+	if minErr != nil || maxErr != nil {
+		return 0, 0, fmt.Errorf("invalid --rank-range %q (expected MIN:MAX, e.g. 50:150)", value)
+	}
+	if minRank < 1 || maxRank < 1 {
+		return 0, 0, fmt.Errorf("invalid --rank-range %q: ranks must be positive", value)
+	}
+	if minRank > maxRank {
+		return 0, 0, fmt.Errorf("invalid --rank-range %q: MIN must not be greater than MAX", value)
+	}
+	return minRank, maxRank, nil
+}
+
+// filterEntriesByRank returns the entries ranked minRank through maxRank (inclusive),
+// in their original order.
+func filterEntriesByRank(entries []PageEntry, minRank, maxRank int) []PageEntry {
+	var filtered []PageEntry
+	for _, entry := range entries {
+		if entry.Rank >= minRank && entry.Rank <= maxRank {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// limitEntries returns the top n entries by rank (lowest rank first).
+// Entries with equal rank keep their original relative order.
+func limitEntries(entries []PageEntry, n int) []PageEntry {
+	sorted := make([]PageEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Rank < sorted[j].Rank
+	})
+	if n < len(sorted) {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
 // matchesAnyFilter checks if a URL matches any of the specified filters.
 func matchesAnyFilter(url string, filters []string, urlMapping *config.URLMapping) bool {
 	for _, filter := range filters {
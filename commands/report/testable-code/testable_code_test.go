@@ -2,10 +2,25 @@
 package testablecode
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/grove-platform/audit-cli/internal/config"
 	"github.com/grove-platform/audit-cli/internal/rst"
 )
@@ -109,6 +124,58 @@ func TestParseCSVEmptyFile(t *testing.T) {
 	}
 }
 
+// TestParseCSVHeaderOnly tests that a header-only CSV is an error, distinct from an empty
+// file, and stops the report before any pages are analyzed.
+func TestParseCSVHeaderOnly(t *testing.T) {
+	tempDir := t.TempDir()
+	csvPath := filepath.Join(tempDir, "header-only.csv")
+
+	if err := os.WriteFile(csvPath, []byte("Rank,URL\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test CSV: %v", err)
+	}
+
+	_, err := ParseCSV(csvPath)
+	if err == nil || !strings.Contains(err.Error(), "CSV contained a header but no data rows") {
+		t.Errorf("Expected header-only error, got %v", err)
+	}
+
+	err = runTestableCode([]string{csvPath}, tempDir, reportOptions{})
+	if err == nil || !strings.Contains(err.Error(), "header but no data rows") {
+		t.Errorf("Expected runTestableCode to fail with header-only error, got %v", err)
+	}
+}
+
+// TestParseCSVRemote tests fetching an analytics CSV from an http(s) URL.
+func TestParseCSVRemote(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, "Rank,URL\n1,www.mongodb.com/docs/atlas/\n2,www.mongodb.com/docs/manual/\n")
+	}))
+	defer server.Close()
+
+	t.Setenv(CSVAuthHeaderEnvVar, "")
+	if _, err := ParseCSV(server.URL + "/top-pages.csv"); err == nil || !strings.Contains(err.Error(), "HTTP 401") {
+		t.Errorf("Expected HTTP 401 without the auth header, got %v", err)
+	}
+
+	t.Setenv(CSVAuthHeaderEnvVar, "Authorization: Bearer secret")
+	entries, err := ParseCSV(server.URL + "/top-pages.csv")
+	if err != nil {
+		t.Fatalf("ParseCSV failed: %v", err)
+	}
+	if len(entries) != 2 || entries[1].URL != "www.mongodb.com/docs/manual/" {
+		t.Errorf("Expected 2 entries from the remote CSV, got %v", entries)
+	}
+
+	t.Setenv(CSVAuthHeaderEnvVar, "Bearer secret")
+	if _, err := ParseCSV(server.URL + "/top-pages.csv"); err == nil || !strings.Contains(err.Error(), CSVAuthHeaderEnvVar) {
+		t.Errorf("Expected error for a malformed auth header, got %v", err)
+	}
+}
+
 // TestParseCSVMissingFile tests error handling for missing file.
 func TestParseCSVMissingFile(t *testing.T) {
 	_, err := ParseCSV("/nonexistent/path/file.csv")
@@ -117,6 +184,104 @@ func TestParseCSVMissingFile(t *testing.T) {
 	}
 }
 
+// TestNormalizeEntries tests merging URL variants of the same page for --normalize-urls.
+func TestNormalizeEntries(t *testing.T) {
+	entries := []PageEntry{
+		{Rank: 3, URL: "http://mongodb.com/docs/atlas/triggers"},
+		{Rank: 1, URL: "www.mongodb.com/docs/manual/"},
+		{Rank: 2, URL: "https://www.mongodb.com/docs/atlas/triggers/#setup"},
+	}
+
+	normalized, duplicates := NormalizeEntries(entries)
+	if duplicates != 1 || len(normalized) != 2 {
+		t.Fatalf("Expected 2 pages and 1 merged variant, got %d and %d: %v", len(normalized), duplicates, normalized)
+	}
+	if normalized[1].URL != "https://www.mongodb.com/docs/atlas/triggers/" || normalized[1].Rank != 2 {
+		t.Errorf("Expected the triggers page second with its better rank 2, got %+v", normalized[1])
+	}
+	if entries[0].URL != "http://mongodb.com/docs/atlas/triggers" {
+		t.Errorf("Expected the input entries to be unchanged, got %q", entries[0].URL)
+	}
+}
+
+// TestMergeEntries tests merging entry lists with duplicate URLs.
+func TestMergeEntries(t *testing.T) {
+	us := []PageEntry{
+		{Rank: 1, URL: "www.mongodb.com/docs/atlas/page1/"},
+		{Rank: 2, URL: "www.mongodb.com/docs/manual/page2/"},
+	}
+	eu := []PageEntry{
+		{Rank: 1, URL: "www.mongodb.com/docs/manual/page2/"},
+		{Rank: 2, URL: "www.mongodb.com/docs/drivers/page3/"},
+		{Rank: 3, URL: "www.mongodb.com/docs/atlas/page1/"},
+	}
+
+	merged, duplicates := MergeEntries(us, eu)
+
+	if duplicates != 2 {
+		t.Errorf("Expected 2 duplicates, got %d", duplicates)
+	}
+	if len(merged) != 3 {
+		t.Fatalf("Expected 3 merged entries, got %d", len(merged))
+	}
+
+	expected := []PageEntry{
+		{Rank: 1, URL: "www.mongodb.com/docs/atlas/page1/"},
+		{Rank: 2, URL: "www.mongodb.com/docs/manual/page2/"}, // ties page1 at rank 1 (from eu), renumbered
+		{Rank: 3, URL: "www.mongodb.com/docs/drivers/page3/"},
+	}
+	for i, e := range expected {
+		if merged[i] != e {
+			t.Errorf("Entry %d: expected %+v, got %+v", i, e, merged[i])
+		}
+	}
+
+	// Merging reorders by best rank and leaves no gaps
+	a := []PageEntry{{Rank: 5, URL: "a"}, {Rank: 9, URL: "b"}}
+	b := []PageEntry{{Rank: 2, URL: "b"}, {Rank: 7, URL: "c"}}
+	merged, _ = MergeEntries(a, b)
+	expected = []PageEntry{{Rank: 1, URL: "b"}, {Rank: 2, URL: "a"}, {Rank: 3, URL: "c"}}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("Expected renumbered entries %+v, got %+v", expected, merged)
+	}
+
+	// A single list without duplicates keeps its ranks
+	single := []PageEntry{{Rank: 3, URL: "a"}, {Rank: 8, URL: "b"}}
+	merged, _ = MergeEntries(single)
+	if !reflect.DeepEqual(merged, single) {
+		t.Errorf("Expected a single list to keep its ranks, got %+v", merged)
+	}
+}
+
+// TestParseCSVFiles tests parsing and merging multiple CSV files.
+func TestParseCSVFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	first := filepath.Join(tempDir, "us.csv")
+	second := filepath.Join(tempDir, "eu.csv")
+
+	if err := os.WriteFile(first, []byte("rank,url\n1,www.mongodb.com/docs/atlas/page1/\n2,www.mongodb.com/docs/manual/page2/\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test CSV: %v", err)
+	}
+	if err := os.WriteFile(second, []byte("rank,url\n1,www.mongodb.com/docs/manual/page2/\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test CSV: %v", err)
+	}
+
+	entries, duplicates, err := ParseCSVFiles([]string{first, second})
+	if err != nil {
+		t.Fatalf("ParseCSVFiles failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("Expected 2 entries, got %d", len(entries))
+	}
+	if duplicates != 1 {
+		t.Errorf("Expected 1 duplicate, got %d", duplicates)
+	}
+
+	if _, _, err := ParseCSVFiles([]string{first, filepath.Join(tempDir, "missing.csv")}); err == nil {
+		t.Error("Expected error when one CSV file is missing")
+	}
+}
+
 // TestMatchesFilter tests the matchesFilter function.
 func TestMatchesFilter(t *testing.T) {
 	urlMapping := createMockURLMapping()
@@ -266,6 +431,78 @@ func TestFilterEntries(t *testing.T) {
 	})
 }
 
+// TestLimitEntries tests truncating entries to the top N by rank.
+func TestLimitEntries(t *testing.T) {
+	entries := []PageEntry{
+		{Rank: 5, URL: "e"},
+		{Rank: 1, URL: "a"},
+		{Rank: 3, URL: "c"},
+		{Rank: 2, URL: "b"},
+	}
+
+	limited := limitEntries(entries, 2)
+	if len(limited) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(limited))
+	}
+	if limited[0].URL != "a" || limited[1].URL != "b" {
+		t.Errorf("Expected top entries a and b, got %+v", limited)
+	}
+
+	// Original slice should be unchanged
+	if entries[0].URL != "e" {
+		t.Error("Expected limitEntries not to modify its input")
+	}
+
+	if all := limitEntries(entries, 10); len(all) != 4 {
+		t.Errorf("Expected all 4 entries when n exceeds length, got %d", len(all))
+	}
+}
+
+// TestRankRange tests parsing --rank-range and filtering entries by rank.
+func TestRankRange(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantMin int
+		wantMax int
+		wantErr bool
+	}{
+		{"50:150", 50, 150, false},
+		{"3:3", 3, 3, false},
+		{" 1 : 10 ", 1, 10, false},
+		{"150:50", 0, 0, true},
+		{"0:10", 0, 0, true},
+		{"-5:10", 0, 0, true},
+		{"10", 0, 0, true},
+		{"a:b", 0, 0, true},
+	}
+	for _, tt := range tests {
+		minRank, maxRank, err := parseRankRange(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseRankRange(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if minRank != tt.wantMin || maxRank != tt.wantMax {
+			t.Errorf("parseRankRange(%q) = %d, %d, want %d, %d", tt.value, minRank, maxRank, tt.wantMin, tt.wantMax)
+		}
+	}
+
+	entries := []PageEntry{
+		{Rank: 1, URL: "a"},
+		{Rank: 50, URL: "b"},
+		{Rank: 100, URL: "c"},
+		{Rank: 150, URL: "d"},
+		{Rank: 151, URL: "e"},
+	}
+	filtered := filterEntriesByRank(entries, 50, 150)
+	var urls []string
+	for _, entry := range filtered {
+		urls = append(urls, entry.URL)
+	}
+	if strings.Join(urls, ",") != "b,c,d" {
+		t.Errorf("Expected b,c,d in range 50:150, got %v", urls)
+	}
+}
+
 // TestValidateFilters tests the validateFilters function.
 func TestValidateFilters(t *testing.T) {
 	testCases := []struct {
@@ -474,6 +711,48 @@ func TestParseComposableOptions(t *testing.T) {
 	}
 }
 
+// TestUseComposableDefault tests giving examples with no language their composable's snooty.toml default.
+func TestUseComposableDefault(t *testing.T) {
+	if ctx := parseComposableOptions("interface, language-atlas-only"); ctx.Composable != "language-atlas-only" || ctx.Language != "" {
+		t.Errorf("Expected the offered language composable to be recorded, got %+v", ctx)
+	}
+
+	projectDir := filepath.Join("..", "..", "..", "testdata", "testable-code-test", "content", "test-project")
+	mappings := MergeProjectComposables(&ProductMappings{}, filepath.Join(projectDir, "source", "simple-code.rst"))
+	if mappings.ComposableDefaults["language"] != "python" || mappings.ComposableDefaults["interface"] != "driver" {
+		t.Fatalf("Unexpected composable defaults: %v", mappings.ComposableDefaults)
+	}
+
+	pagePath := filepath.Join(t.TempDir(), "tutorial.txt")
+	page := ".. composable-tutorial::\n   :options: language, interface\n\n" +
+		".. code-block::\n\n   client = MongoClient(uri)\n"
+	if err := os.WriteFile(pagePath, []byte(page), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	examples, err := collectCodeExamples(pagePath, "test-project", newIncludeWalk(0), mappings)
+	if err != nil {
+		t.Fatalf("collectCodeExamples failed: %v", err)
+	}
+	if len(examples) != 1 || examples[0].Language != "undefined" {
+		t.Fatalf("Expected one undefined example without --use-composable-default, got %+v", examples)
+	}
+
+	walk := newIncludeWalk(0)
+	walk.opts = &AnalyzeOptions{UseComposableDefault: true}
+	examples, err = collectCodeExamples(pagePath, "test-project", walk, mappings)
+	if err != nil {
+		t.Fatalf("collectCodeExamples failed: %v", err)
+	}
+	ex := examples[0]
+	if ex.Language != "python" || ex.Product != "Python" || !ex.IsTestable {
+		t.Errorf("Expected a testable Python example, got %+v", ex)
+	}
+	if !strings.Contains(ex.ProductReason, "language composable default") {
+		t.Errorf("Expected the reason to name the composable default, got %q", ex.ProductReason)
+	}
+}
+
 // TestBuildPageReport tests the BuildPageReport function.
 func TestBuildPageReport(t *testing.T) {
 	analysis := &PageAnalysis{
@@ -481,9 +760,10 @@ func TestBuildPageReport(t *testing.T) {
 		URL:        "www.mongodb.com/docs/test/",
 		SourcePath: "/path/to/source.rst",
 		ContentDir: "pymongo-driver",
+		Version:    "current",
 		CodeExamples: []CodeExample{
-			{Type: "literalinclude", Language: "python", Product: "Python", IsTestable: true, IsTested: true},
-			{Type: "code-block", Language: "python", Product: "Python", IsTestable: true, IsTested: false},
+			{Type: "literalinclude", Language: "python", Product: "Python", Origin: OriginContentDir, IsTestable: true, IsTested: true},
+			{Type: "code-block", Language: "python", Product: "Python", Origin: OriginTab, IsTestable: true, IsTested: false},
 			{Type: "io-code-block", Language: "javascript", Product: "Node.js", IsInput: true, IsTestable: true},
 			{Type: "io-code-block", Language: "javascript", Product: "Node.js", IsOutput: true, IsTestable: true},
 			{Type: "code-block", Language: "json", Product: "JSON", IsTestable: false},
@@ -495,6 +775,18 @@ func TestBuildPageReport(t *testing.T) {
 	if report.Rank != 1 {
 		t.Errorf("Expected Rank 1, got %d", report.Rank)
 	}
+	if report.Version != "current" {
+		t.Errorf("Expected Version current, got %q", report.Version)
+	}
+	for _, details := range []bool{false, true} {
+		var buf bytes.Buffer
+		if err := OutputCSV(&buf, []PageReport{report}, details, false); err != nil {
+			t.Fatalf("OutputCSV failed: %v", err)
+		}
+		if !strings.Contains(buf.String(), ",pymongo-driver,current,") {
+			t.Errorf("Expected version column in CSV (details=%v), got:\n%s", details, buf.String())
+		}
+	}
 	if report.TotalExamples != 5 {
 		t.Errorf("Expected TotalExamples 5, got %d", report.TotalExamples)
 	}
@@ -522,6 +814,9 @@ func TestBuildPageReport(t *testing.T) {
 	if pythonStats.TestedCount != 1 {
 		t.Errorf("Expected Python TestedCount 1, got %d", pythonStats.TestedCount)
 	}
+	if pythonStats.ByOrigin[OriginContentDir] != 1 || pythonStats.ByOrigin[OriginTab] != 1 {
+		t.Errorf("Expected Python ByOrigin content-dir:1 and tab:1, got %v", pythonStats.ByOrigin)
+	}
 
 	// Check Node.js stats
 	nodeStats, ok := report.ByProduct["Node.js"]
@@ -539,502 +834,2886 @@ func TestBuildPageReport(t *testing.T) {
 	}
 }
 
-// TestEscapeCSV tests the escapeCSV function.
-func TestEscapeCSV(t *testing.T) {
-	testCases := []struct {
-		input    string
-		expected string
-	}{
-		{"simple", "simple"},
-		{"with,comma", `"with,comma"`},
-		{`with"quote`, `"with""quote"`},
-		{"with\nnewline", `"with` + "\n" + `newline"`},
-		{"", ""},
-		{"normal text", "normal text"},
+// TestBaselineProducts tests adding zero-valued baseline products and keeping them in the detailed CSV.
+func TestBaselineProducts(t *testing.T) {
+	expanded := ExpandBaselineProducts([]string{"default", "Rust", "Python"})
+	if len(expanded) != len(DefaultBaselineProducts)+1 || expanded[len(expanded)-1] != "Rust" {
+		t.Errorf("Expected default products plus Rust, got %v", expanded)
 	}
 
-	for _, tc := range testCases {
-		result := escapeCSV(tc.input)
-		if result != tc.expected {
-			t.Errorf("escapeCSV(%q) = %q, expected %q", tc.input, result, tc.expected)
-		}
+	// The flag defaults to the default set, and "" turns it off
+	flag := NewTestableCodeCommand().Flags().Lookup("baseline-products")
+	if flag.DefValue != "[default]" {
+		t.Errorf("Expected --baseline-products to default to [default], got %s", flag.DefValue)
+	}
+	if err := flag.Value.Set(""); err != nil {
+		t.Fatalf("Setting --baseline-products to \"\": %v", err)
+	}
+	if flag.Value.String() != "[]" {
+		t.Errorf("Expected no baseline products, got %s", flag.Value.String())
+	}
+
+	pages := []*PageAnalysis{
+		{
+			Rank: 1, URL: "www.mongodb.com/docs/a/", ContentDir: "pymongo-driver",
+			CodeExamples: []CodeExample{{Type: "code-block", Language: "python", Product: "Python", IsTestable: true}},
+		},
+		{Rank: 2, URL: "www.mongodb.com/docs/b/", ContentDir: "manual"},
+	}
+	var reports []PageReport
+	for _, page := range pages {
+		report := BuildPageReport(page)
+		AddBaselineProducts(&report, []string{"Python", "Go"})
+		reports = append(reports, report)
+	}
+	reports = append(reports, PageReport{Rank: 3, URL: "www.mongodb.com/docs/c/", Error: "not found"})
+
+	if reports[0].ByProduct["Python"].TotalCount != 1 {
+		t.Errorf("Expected existing Python stats to be kept, got %+v", reports[0].ByProduct["Python"])
+	}
+	if stats, ok := reports[1].ByProduct["Go"]; !ok || stats.TotalCount != 0 {
+		t.Errorf("Expected zero-valued Go stats, got %+v", stats)
+	}
+	if reports[2].ByProduct != nil {
+		t.Errorf("Expected error report to be unchanged, got %v", reports[2].ByProduct)
+	}
+
+	tests := []struct {
+		name     string
+		keepZero bool
+		expected []string
+	}{
+		{"skip zero", false, []string{
+			"1,www.mongodb.com/docs/a/,,pymongo-driver,,Python,1,0,0,0,1,0,0.0%,,",
+			"2,www.mongodb.com/docs/b/,,manual,,,0,0,0,0,0,0,—,,",
+		}},
+		{"keep zero", true, []string{
+			"1,www.mongodb.com/docs/a/,,pymongo-driver,,Go,0,0,0,0,0,0,—,,",
+			"1,www.mongodb.com/docs/a/,,pymongo-driver,,Python,1,0,0,0,1,0,0.0%,,",
+			"2,www.mongodb.com/docs/b/,,manual,,Go,0,0,0,0,0,0,—,,",
+			"2,www.mongodb.com/docs/b/,,manual,,Python,0,0,0,0,0,0,—,,",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := OutputCSV(&buf, reports, true, tt.keepZero); err != nil {
+				t.Fatalf("OutputCSV failed: %v", err)
+			}
+			lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+			// Header and the error row surround the page rows
+			if len(lines) != len(tt.expected)+2 {
+				t.Fatalf("Expected %d rows, got:\n%s", len(tt.expected)+2, buf.String())
+			}
+			for i, want := range tt.expected {
+				if lines[i+1] != want {
+					t.Errorf("Row %d: expected %q, got %q", i+1, want, lines[i+1])
+				}
+			}
+		})
 	}
 }
 
-// TestIsMongoShellContext tests the isMongoShellContext function.
-func TestIsMongoShellContext(t *testing.T) {
-	testCases := []struct {
-		contentDir string
-		contexts   []CodeContext
-		expected   bool
+// TestCoverage tests the per-product coverage column in text and detailed CSV output.
+func TestCoverage(t *testing.T) {
+	tests := []struct {
+		tested, testable int
+		expected         string
 	}{
-		{"mongodb-shell", nil, true},
-		{"mongodb-shell", []CodeContext{}, true},
-		{"pymongo-driver", []CodeContext{{Interface: "mongosh"}}, true},
-		{"node", []CodeContext{{Interface: "driver"}}, false},
-		{"manual", []CodeContext{}, false},
-		{"", nil, false},
+		{0, 0, "—"},
+		{0, 3, "0.0%"},
+		{2, 3, "66.7%"},
+		{4, 4, "100.0%"},
+	}
+	for _, tt := range tests {
+		if got := formatCoverage(tt.tested, tt.testable); got != tt.expected {
+			t.Errorf("formatCoverage(%d, %d) = %q, expected %q", tt.tested, tt.testable, got, tt.expected)
+		}
 	}
 
-	for _, tc := range testCases {
-		result := isMongoShellContext(tc.contentDir, tc.contexts)
-		if result != tc.expected {
-			t.Errorf("isMongoShellContext(%q, %v) = %v, expected %v",
-				tc.contentDir, tc.contexts, result, tc.expected)
+	report := BuildPageReport(&PageAnalysis{
+		Rank: 1, URL: "www.mongodb.com/docs/a/", ContentDir: "pymongo-driver",
+		CodeExamples: []CodeExample{
+			{Type: "literalinclude", Product: "Python", IsTestable: true, IsTested: true},
+			{Type: "code-block", Product: "Python", IsTestable: true},
+			{Type: "code-block", Product: "JSON"},
+		},
+	})
+
+	var text bytes.Buffer
+	if err := OutputText(&text, []PageReport{report}); err != nil {
+		t.Fatalf("OutputText failed: %v", err)
+	}
+	for _, want := range []string{"Coverage", "  JSON                      1      0      0      0        0      0        —", "50.0%"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("Expected text output to contain %q, got:\n%s", want, text.String())
 		}
 	}
+
+	var csv bytes.Buffer
+	if err := OutputCSV(&csv, []PageReport{report}, true, false); err != nil {
+		t.Fatalf("OutputCSV failed: %v", err)
+	}
+	if !strings.Contains(csv.String(), ",Maybe,Coverage,Origins,") || !strings.Contains(csv.String(), ",Python,2,0,0,1,2,0,50.0%,,") {
+		t.Errorf("Expected coverage column in detailed CSV, got:\n%s", csv.String())
+	}
 }
 
-// TestDetermineProduct tests the determineProduct function.
-func TestDetermineProduct(t *testing.T) {
-	// Create mock mappings
-	mappings := &ProductMappings{
-		DriversTabIDToProduct: map[string]string{
-			"python":    "Python",
-			"nodejs":    "Node.js",
-			"java-sync": "Java (Sync)",
-		},
-		ComposableLanguageToProduct: map[string]string{
-			"python": "Python",
-			"nodejs": "Node.js",
-			"go":     "Go",
-		},
-		ComposableInterfaceToProduct: map[string]string{
-			"mongosh":  "MongoDB Shell",
-			"driver":   "Driver",
-			"compass":  "Compass",
+// TestPartiallyTested tests the PartiallyTested flag and KeepPartiallyTested.
+func TestPartiallyTested(t *testing.T) {
+	analysis := &PageAnalysis{
+		URL: "www.mongodb.com/docs/drivers/page/",
+		CodeExamples: []CodeExample{
+			{Product: "Python", IsTestable: true, IsTested: true},
+			{Product: "Python", IsTestable: true},
+			{Product: "Go", IsTestable: true, IsTested: true},
+			{Product: "C#", IsTestable: true},
+			{Product: "Java (Sync)", IsTestable: true, IsTested: true},
 		},
 	}
+	report := BuildPageReport(analysis)
 
-	testCases := []struct {
-		name       string
-		language   string
-		contentDir string
-		contexts   []CodeContext
-		expected   string
-	}{
-		// Non-driver languages bypass context
-		{"bash bypasses context", "bash", "pymongo-driver", []CodeContext{{Language: "python"}}, "Shell"},
-		{"json bypasses context", "json", "node", []CodeContext{{TabID: "nodejs"}}, "JSON"},
-		{"yaml bypasses context", "yaml", "golang", nil, "YAML"},
-		{"text bypasses context", "text", "manual", nil, "Text"},
+	want := map[string]bool{"Python": true, "Go": false, "C#": false, "Java (Sync)": false}
+	for product, partial := range want {
+		if got := report.ByProduct[product].PartiallyTested; got != partial {
+			t.Errorf("%s: PartiallyTested = %v, want %v", product, got, partial)
+		}
+	}
 
-		// MongoDB Shell context
-		{"shell in mongosh dir", "shell", "mongodb-shell", nil, "MongoDB Shell"},
-		{"javascript in mongosh context", "javascript", "", []CodeContext{{Interface: "mongosh"}}, "MongoDB Shell"},
-		{"shell outside mongosh", "shell", "manual", nil, "Shell"},
+	// Merging an untested alias into a fully tested product makes it partial
+	ApplyProductAliases(&report, map[string]string{"C#": "Java (Sync)"})
+	if !report.ByProduct["Java (Sync)"].PartiallyTested {
+		t.Error("Expected Java (Sync) to be partially tested after merging C#")
+	}
 
-		// Tab context
-		{"python tab", "python", "", []CodeContext{{TabID: "python"}}, "Python"},
-		{"nodejs tab", "javascript", "", []CodeContext{{TabID: "nodejs"}}, "Node.js"},
+	if !KeepPartiallyTested(&report) {
+		t.Fatal("Expected KeepPartiallyTested to keep the page")
+	}
+	if len(report.ByProduct) != 2 || report.ByProduct["Python"] == nil || report.ByProduct["Java (Sync)"] == nil {
+		t.Errorf("Expected only Python and Java (Sync), got %v", report.ByProduct)
+	}
+	if report.TotalExamples != 5 {
+		t.Errorf("Expected page totals to be unchanged, got %d examples", report.TotalExamples)
+	}
 
-		// Composable language context
-		{"go composable", "go", "", []CodeContext{{Language: "go"}}, "Go"},
+	fullyTested := BuildPageReport(&PageAnalysis{CodeExamples: []CodeExample{{Product: "Go", IsTestable: true, IsTested: true}}})
+	if KeepPartiallyTested(&fullyTested) {
+		t.Error("Expected a page with no partially tested products to be dropped")
+	}
+	failed := PageReport{Error: "could not resolve URL slug"}
+	if KeepPartiallyTested(&failed) {
+		t.Error("Expected a failed page to be dropped")
+	}
+}
 
-		// Content directory fallback
-		{"pymongo content dir", "python", "pymongo-driver", nil, "Python"},
-		{"node content dir", "javascript", "node", nil, "Node.js"},
+// TestStripSharedIncludes tests removing examples from shared includes for --own-content-only.
+func TestStripSharedIncludes(t *testing.T) {
+	filePath := filepath.Join("..", "..", "..", "testdata", "testable-code-test", "content", "test-project", "source", "with-nested-includes.rst")
+	examples, err := collectCodeExamples(filePath, "test-project", newIncludeWalk(0), &ProductMappings{})
+	if err != nil {
+		t.Fatalf("collectCodeExamples failed: %v", err)
+	}
 
-		// Language fallback
-		{"python language", "python", "", nil, "Python"},
-		{"ruby language", "ruby", "", nil, "Ruby"},
+	analysis := &PageAnalysis{SourcePath: filePath, CodeExamples: examples}
+	shared := StripSharedIncludes(analysis)
+	if len(analysis.CodeExamples) != 1 || analysis.CodeExamples[0].Language != "python" {
+		t.Fatalf("Expected only the page's own python example, got %+v", analysis.CodeExamples)
+	}
+	if shared != len(examples)-1 || shared == 0 {
+		t.Errorf("Expected %d shared examples, got %d", len(examples)-1, shared)
+	}
+}
 
-		// Unknown
-		{"empty language", "", "", nil, "Unknown"},
+// TestSharedExamplesTotals tests reporting --own-content-only's shared examples in the CSV and summaries.
+func TestSharedExamplesTotals(t *testing.T) {
+	reports := []PageReport{
+		{Rank: 1, URL: "www.mongodb.com/docs/a/", ContentDir: "manual", TotalExamples: 1, SharedExamples: 2},
+		{Rank: 2, URL: "www.mongodb.com/docs/b/", ContentDir: "manual", TotalExamples: 4, SharedExamples: 3},
 	}
 
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			result := determineProduct(tc.language, tc.contentDir, tc.contexts, mappings)
-			if result != tc.expected {
-				t.Errorf("determineProduct(%q, %q, %v) = %q, expected %q",
-					tc.language, tc.contentDir, tc.contexts, result, tc.expected)
-			}
-		})
+	var buf bytes.Buffer
+	if err := OutputCSV(&buf, reports, false, false); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if !strings.HasSuffix(lines[0], ",Maybe,Shared,Error") || !strings.HasSuffix(lines[1], ",2,") {
+		t.Errorf("Expected a Shared column, got:\n%s", buf.String())
 	}
-}
 
-// TestGetLanguage tests the getLanguage function.
-func TestGetLanguage(t *testing.T) {
-	testCases := []struct {
-		name        string
-		options     map[string]string
-		defaultLang string
-		expected    string
-	}{
-		{"language option", map[string]string{"language": "python"}, "javascript", "python"},
-		{"default lang", map[string]string{}, "javascript", "javascript"},
-		{"empty default", map[string]string{}, "", "undefined"},
-		{"empty language option", map[string]string{"language": ""}, "go", "go"},
+	summary := NewReportSummary()
+	for _, report := range reports {
+		summary.Add(report)
+	}
+	if summary.SharedExamples != 5 {
+		t.Errorf("Expected 5 shared examples in the summary, got %d", summary.SharedExamples)
 	}
 
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			directive := rst.Directive{Options: tc.options}
-			result := getLanguage(directive, tc.defaultLang)
-			if result != tc.expected {
-				t.Errorf("getLanguage(%v, %q) = %q, expected %q",
-					tc.options, tc.defaultLang, result, tc.expected)
-			}
-		})
+	summaries := SummarizeByContentDir(reports)
+	if len(summaries) != 1 || summaries[0].SharedExamples != 5 {
+		t.Fatalf("Expected 5 shared examples for manual, got %+v", summaries)
+	}
+	buf.Reset()
+	if err := writeContentDirSummary(&buf, summaries, "json", true); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"SharedExamples":5`) {
+		t.Errorf("Expected SharedExamples in the content directory JSON, got:\n%s", buf.String())
 	}
 }
 
-// TestProcessDirective tests the processDirective function.
-func TestProcessDirective(t *testing.T) {
-	mappings := &ProductMappings{
-		DriversTabIDToProduct:        map[string]string{"python": "Python", "nodejs": "Node.js"},
-		ComposableLanguageToProduct:  map[string]string{"python": "Python", "nodejs": "Node.js"},
-		ComposableInterfaceToProduct: map[string]string{"mongosh": "MongoDB Shell"},
+// TestCollectCodeExamplesMarkdown tests counting fenced code blocks in a Markdown page.
+func TestCollectCodeExamplesMarkdown(t *testing.T) {
+	filePath := filepath.Join("..", "..", "..", "testdata", "testable-code-test", "content", "test-project", "source", "markdown-page.md")
+	examples, err := collectCodeExamples(filePath, "test-project", newIncludeWalk(0), &ProductMappings{})
+	if err != nil {
+		t.Fatalf("collectCodeExamples failed: %v", err)
 	}
 
-	testCases := []struct {
-		name            string
-		directive       rst.Directive
-		contentDir      string
-		contexts        []CodeContext
-		expectedCount   int
-		expectedType    string
-		expectedLang    string
-		expectedProduct string
-	}{
-		{
-			name: "literalinclude with tested path",
-			directive: rst.Directive{
-				Type:     rst.LiteralInclude,
-				Argument: "/code-examples/tested/python/example.py",
-				Options:  map[string]string{"language": "python"},
-			},
-			contentDir:      "pymongo-driver",
-			contexts:        nil,
-			expectedCount:   1,
-			expectedType:    "literalinclude",
-			expectedLang:    "python",
-			expectedProduct: "Python",
+	var languages []string
+	for _, ex := range examples {
+		if ex.Type != string(rst.MarkdownCodeBlock) {
+			t.Errorf("Expected type %s, got %s", rst.MarkdownCodeBlock, ex.Type)
+		}
+		languages = append(languages, ex.Language)
+	}
+	expected := []string{"python", "javascript", "markdown", "undefined"}
+	if !reflect.DeepEqual(languages, expected) {
+		t.Errorf("Expected languages %v, got %v", expected, languages)
+	}
+	if !examples[0].IsTestable || examples[0].Product != "Python" {
+		t.Errorf("Expected the python fence to be a testable Python example, got %+v", examples[0])
+	}
+}
+
+// TestCountIOPairsAsOne tests counting an io-code-block input/output pair as one example.
+func TestCountIOPairsAsOne(t *testing.T) {
+	analysis := &PageAnalysis{
+		SourcePath: "/path/to/source.rst",
+		CodeExamples: []CodeExample{
+			{Type: "io-code-block", Language: "javascript", Product: "Node.js", IsInput: true, IsTestable: true, SourceFile: "/path/to/source.rst"},
+			{Type: "io-code-block", Language: "javascript", Product: "Node.js", IsOutput: true, IsTestable: true, SourceFile: "/path/to/source.rst"},
+			{Type: "io-code-block", Language: "text", Product: "Node.js", IsOutput: true, SourceFile: "/path/to/source.rst"},
+			{Type: "code-block", Language: "python", Product: "Python", IsTestable: true, SourceFile: "/path/to/source.rst"},
 		},
-		{
-			name: "code-block with language argument",
-			directive: rst.Directive{
-				Type:     rst.CodeBlock,
-				Argument: "javascript",
-				Options:  map[string]string{},
-			},
-			contentDir:      "node",
-			contexts:        nil,
-			expectedCount:   1,
-			expectedType:    "code-block",
-			expectedLang:    "javascript",
-			expectedProduct: "Node.js",
+	}
+	report := BuildPageReport(analysis)
+
+	if pairs := CountIOPairsAsOne(&report, analysis); pairs != 1 {
+		t.Errorf("Expected 1 pair, got %d", pairs)
+	}
+	// The lone output (no input before it) still counts
+	if report.TotalExamples != 3 || report.TotalTestable != 2 {
+		t.Errorf("Expected 3 examples and 2 testable, got %d and %d", report.TotalExamples, report.TotalTestable)
+	}
+	if report.TotalInput != 1 || report.TotalOutput != 2 {
+		t.Errorf("Expected input/output counts unchanged (1/2), got %d/%d", report.TotalInput, report.TotalOutput)
+	}
+	node := report.ByProduct["Node.js"]
+	if node.TotalCount != 2 || node.TestableCount != 1 || node.OutputCount != 2 {
+		t.Errorf("Expected Node.js total 2, testable 1, output 2, got %+v", node)
+	}
+	if python := report.ByProduct["Python"]; python.TotalCount != 1 {
+		t.Errorf("Expected Python unchanged, got %+v", python)
+	}
+}
+
+// TestExpandedIncludes tests the flattened example list printed by --expand-includes.
+func TestExpandedIncludes(t *testing.T) {
+	filePath := filepath.Join("..", "..", "..", "testdata", "testable-code-test", "content", "test-project", "source", "with-nested-includes.rst")
+	examples, err := collectCodeExamples(filePath, "test-project", newIncludeWalk(0), &ProductMappings{})
+	if err != nil {
+		t.Fatalf("collectCodeExamples failed: %v", err)
+	}
+	analysis := &PageAnalysis{URL: "www.mongodb.com/docs/test/page", SourcePath: filePath, CodeExamples: examples}
+
+	page := BuildExpandedPage(analysis)
+	if len(page.CodeExamples) != len(examples) {
+		t.Fatalf("Expected %d examples, got %d", len(examples), len(page.CodeExamples))
+	}
+	for i, ex := range page.CodeExamples {
+		if ex.Index != i+1 {
+			t.Errorf("Expected example %d to have index %d, got %d", i, i+1, ex.Index)
+		}
+		if ex.Language != examples[i].Language || ex.Type != examples[i].Type {
+			t.Errorf("Expected example %d to keep collection order, got %+v", i, ex)
+		}
+	}
+	if page.CodeExamples[0].SourceFile != "(page)" {
+		t.Errorf("Expected the first example to come from the page itself, got %q", page.CodeExamples[0].SourceFile)
+	}
+	if got := page.CodeExamples[len(examples)-1].SourceFile; got != filepath.Join("includes", "nested-level2.rst") {
+		t.Errorf("Expected the last example to come from the level-2 include, got %q", got)
+	}
+
+	var buf bytes.Buffer
+	OutputExpandedIncludes(&buf, analysis)
+	if !strings.Contains(buf.String(), fmt.Sprintf("Code examples: %d", len(examples))) ||
+		!strings.Contains(buf.String(), filepath.Join("includes", "nested-level1.rst")) {
+		t.Errorf("Unexpected text output:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := OutputExpandedIncludesJSON(&buf, analysis, true); err != nil {
+		t.Fatalf("OutputExpandedIncludesJSON failed: %v", err)
+	}
+	var decoded ExpandedPage
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Failed to decode JSON output: %v", err)
+	}
+	if decoded.URL != analysis.URL || len(decoded.CodeExamples) != len(examples) {
+		t.Errorf("Unexpected JSON output: %s", buf.String())
+	}
+}
+
+// TestProgress tests when progress lines are printed and the ETA they show.
+func TestProgress(t *testing.T) {
+	var buf bytes.Buffer
+	progress := NewProgress(&buf, 2000)
+	clock := progress.start
+	progress.now = func() time.Time { return clock }
+
+	// Pages that finish within the interval don't print
+	for i := 0; i < 99; i++ {
+		progress.Done()
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("Expected no progress line yet, got %q", buf.String())
+	}
+
+	// Every progressEvery pages prints, even without time passing
+	progress.Done()
+	if got := buf.String(); got != "Progress: 100/2000 (5%) ETA 0s\n" {
+		t.Errorf("Unexpected progress line at 100 pages: %q", got)
+	}
+
+	// After the interval, the next page prints with an ETA from the average page time
+	buf.Reset()
+	clock = clock.Add(50 * time.Second)
+	for i := 0; i < 400; i++ {
+		progress.Done()
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if lines[0] != "Progress: 101/2000 (5%) ETA 15m40s" {
+		t.Errorf("Unexpected first progress line after the interval: %q", lines[0])
+	}
+	if last := lines[len(lines)-1]; last != "Progress: 500/2000 (25%) ETA 2m30s" {
+		t.Errorf("Unexpected progress line at 500 pages: %q", last)
+	}
+
+	if got := formatProgress(2000, 2000, 3*time.Minute+20*time.Second); got != "2000/2000 (100%) done in 3m20s" {
+		t.Errorf("Unexpected final progress: %q", got)
+	}
+
+	// Done is safe to call concurrently, and a nil Progress prints nothing
+	concurrent := NewProgress(io.Discard, 1000)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				concurrent.Done()
+			}
+		}()
+	}
+	wg.Wait()
+	if done := concurrent.done.Load(); done != 1000 {
+		t.Errorf("Expected 1000 completed pages, got %d", done)
+	}
+	var quiet *Progress
+	quiet.Done()
+}
+
+// TestWarningLog tests printing each warning once and summarizing the repeats.
+func TestWarningLog(t *testing.T) {
+	log := NewWarningLog()
+	var buf bytes.Buffer
+	for i := 0; i < 3; i++ {
+		log.Warn(&buf, "  ", "include not found: /includes/missing.rst")
+	}
+	log.Warn(&buf, "  ", "max include depth reached: 2 include(s) not followed")
+	for i := 0; i < 5; i++ {
+		log.Warn(&buf, "", "bad :lines: option; counting all of shared.rst")
+	}
+
+	expected := "  Warning: include not found: /includes/missing.rst\n" +
+		"  Warning: max include depth reached: 2 include(s) not followed\n" +
+		"Warning: bad :lines: option; counting all of shared.rst\n"
+	if buf.String() != expected {
+		t.Errorf("Expected each warning printed once:\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	buf.Reset()
+	PrintRepeatedWarnings(&buf, log)
+	expected = "\n2 warning(s) repeated (printed once above):\n" +
+		"     (x5)  bad :lines: option; counting all of shared.rst\n" +
+		"     (x3)  include not found: /includes/missing.rst\n"
+	if buf.String() != expected {
+		t.Errorf("Unexpected repeated warnings:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+
+	// A nil log prints every warning and has nothing to summarize
+	var nilLog *WarningLog
+	buf.Reset()
+	nilLog.Warn(&buf, "", "a")
+	nilLog.Warn(&buf, "", "a")
+	PrintRepeatedWarnings(&buf, nilLog)
+	if buf.String() != "Warning: a\nWarning: a\n" {
+		t.Errorf("Expected a nil log to print every warning, got %q", buf.String())
+	}
+}
+
+// TestProductAliases tests parsing --product-alias values and merging aliased products.
+func TestProductAliases(t *testing.T) {
+	invalid := [][]string{
+		{"Java (Sync)"},
+		{"=Java"},
+		{"Java (Sync)="},
+		{"Java=Java"},
+		{"Java (Sync)=Java", "Java (Sync)=Kotlin"},
+		{"Java (Sync)=Java", "Java=JVM"},
+	}
+	for _, values := range invalid {
+		if _, err := ParseProductAliases(values); err == nil {
+			t.Errorf("Expected error for %q", values)
+		}
+	}
+
+	aliases, err := ParseProductAliases([]string{"Java (Sync)=Java", " Java (Reactive Streams) = Java", "Java (Sync)=Java"})
+	if err != nil {
+		t.Fatalf("ParseProductAliases failed: %v", err)
+	}
+
+	report := PageReport{
+		TotalExamples: 4,
+		ByProduct: map[string]*ProductStats{
+			"Java (Sync)":             {Product: "Java (Sync)", TotalCount: 2, TestableCount: 2, ByOrigin: map[string]int{OriginTab: 2}},
+			"Java (Reactive Streams)": {Product: "Java (Reactive Streams)", TotalCount: 1, TestedCount: 1, ByOrigin: map[string]int{OriginTab: 1}},
+			"Python":                  {Product: "Python", TotalCount: 1, ByOrigin: map[string]int{OriginLanguage: 1}},
 		},
-		{
-			name: "code-block with json bypasses context",
-			directive: rst.Directive{
-				Type:     rst.CodeBlock,
-				Argument: "json",
-				Options:  map[string]string{},
-			},
-			contentDir:      "pymongo-driver",
-			contexts:        []CodeContext{{Language: "python"}},
-			expectedCount:   1,
-			expectedType:    "code-block",
-			expectedLang:    "json",
-			expectedProduct: "JSON",
+	}
+	ApplyProductAliases(&report, aliases)
+
+	if len(report.ByProduct) != 2 {
+		t.Fatalf("Expected Java and Python, got %v", report.ByProduct)
+	}
+	java := report.ByProduct["Java"]
+	if java == nil || java.Product != "Java" || java.TotalCount != 3 || java.TestableCount != 2 ||
+		java.TestedCount != 1 || java.ByOrigin[OriginTab] != 3 {
+		t.Errorf("Expected merged Java stats, got %+v", java)
+	}
+	if report.TotalExamples != 4 {
+		t.Errorf("Expected page total to be unchanged, got %d", report.TotalExamples)
+	}
+}
+
+// TestOutputByProject tests splitting reports into one file per project.
+func TestOutputByProject(t *testing.T) {
+	reports := []PageReport{
+		{Rank: 1, URL: "www.mongodb.com/docs/languages/python/pymongo-driver/current/a/", ContentDir: "pymongo-driver"},
+		{Rank: 2, URL: "www.mongodb.com/docs/drivers/node/current/b/", ContentDir: "node"},
+		{Rank: 3, URL: "www.mongodb.com/docs/languages/python/pymongo-driver/current/c/", ContentDir: "pymongo-driver"},
+		{Rank: 4, URL: "www.mongodb.com/docs/unknown/d/", Error: "could not resolve URL slug: unknown/d"},
+	}
+
+	groups := GroupReportsByProject(reports)
+	if len(groups["pymongo-driver"]) != 2 {
+		t.Errorf("Expected 2 pymongo-driver reports, got %d", len(groups["pymongo-driver"]))
+	}
+	if groups["pymongo-driver"][0].Rank != 1 || groups["pymongo-driver"][1].Rank != 3 {
+		t.Error("Expected report order to be preserved within a project")
+	}
+	if len(groups["unresolved"]) != 1 {
+		t.Errorf("Expected 1 unresolved report, got %d", len(groups["unresolved"]))
+	}
+
+	outputDir := filepath.Join(t.TempDir(), "reports")
+	written, err := OutputByProject(outputDir, reports, ReportFormat{Format: "csv"})
+	if err != nil {
+		t.Fatalf("OutputByProject failed: %v", err)
+	}
+
+	expectedFiles := []string{"node.csv", "pymongo-driver.csv", "unresolved.csv"}
+	if len(written) != len(expectedFiles) {
+		t.Fatalf("Expected %d files, got %d: %v", len(expectedFiles), len(written), written)
+	}
+	for i, name := range expectedFiles {
+		if filepath.Base(written[i]) != name {
+			t.Errorf("Expected file %d to be %s, got %s", i, name, filepath.Base(written[i]))
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "pymongo-driver.csv"))
+	if err != nil {
+		t.Fatalf("Failed to read pymongo-driver.csv: %v", err)
+	}
+	// Header plus two data rows
+	if lines := strings.Count(string(content), "\n"); lines != 3 {
+		t.Errorf("Expected 3 lines in pymongo-driver.csv, got %d", lines)
+	}
+}
+
+// TestGroupReportsByProjectMonorepos tests that content directories with the same name in
+// different monorepos are grouped separately.
+func TestGroupReportsByProjectMonorepos(t *testing.T) {
+	reports := []PageReport{
+		{Rank: 1, URL: "www.mongodb.com/docs/drivers/rust/", ContentDir: "rust", TotalExamples: 1},
+		{Rank: 2, URL: "www.mongodb.com/docs/rust-extras/", ContentDir: "rust", Monorepo: "/repos/rust-docs", TotalExamples: 2},
+	}
+
+	groups := GroupReportsByProject(reports)
+	if len(groups["rust"]) != 1 || len(groups["rust-docs/rust"]) != 1 {
+		t.Fatalf("Expected rust and rust-docs/rust groups, got %v", groups)
+	}
+
+	summaries := SummarizeByContentDir(reports)
+	if len(summaries) != 2 || summaries[0].ContentDir != "rust" || summaries[1].ContentDir != "rust-docs/rust" || summaries[1].TotalExamples != 2 {
+		t.Errorf("Expected separate summaries for each monorepo, got %+v", summaries)
+	}
+
+	outputDir := t.TempDir()
+	written, err := OutputByProject(outputDir, reports, ReportFormat{Format: "csv"})
+	if err != nil {
+		t.Fatalf("OutputByProject failed: %v", err)
+	}
+	expected := []string{filepath.Join(outputDir, "rust.csv"), filepath.Join(outputDir, "rust-docs", "rust.csv")}
+	if !reflect.DeepEqual(written, expected) {
+		t.Errorf("Expected %v, got %v", expected, written)
+	}
+}
+
+// TestLanguageTotals tests totaling code examples by raw language for --group-by language.
+func TestLanguageTotals(t *testing.T) {
+	totals := LanguageTotals{}
+	totals.Add(&PageAnalysis{CodeExamples: []CodeExample{
+		{Language: "javascript", Product: "Node.js", IsTestable: true, IsTested: true},
+		{Language: "javascript", Product: "JavaScript", IsMaybeTestable: true},
+		{Language: "python", Product: "Python", IsTestable: true},
+	}})
+	totals.Add(&PageAnalysis{CodeExamples: []CodeExample{
+		{Language: "javascript", Product: "JavaScript", IsMaybeTestable: true},
+		{Language: "shell", Product: "Shell", IsMaybeTestable: true},
+	}})
+
+	summaries := totals.Summaries()
+	want := []LanguageSummary{
+		{Language: "javascript", Pages: 2, TotalExamples: 3, TotalTested: 1, TotalTestable: 1, TotalMaybeTestable: 2,
+			ByProduct: map[string]int{"Node.js": 1, "JavaScript": 2}},
+		{Language: "python", Pages: 1, TotalExamples: 1, TotalTestable: 1, ByProduct: map[string]int{"Python": 1}},
+		{Language: "shell", Pages: 1, TotalExamples: 1, TotalMaybeTestable: 1, ByProduct: map[string]int{"Shell": 1}},
+	}
+	if !reflect.DeepEqual(summaries, want) {
+		t.Errorf("Summaries() = %+v, want %+v", summaries, want)
+	}
+
+	var buf bytes.Buffer
+	if err := writeLanguageSummary(&buf, summaries, "csv", false); err != nil {
+		t.Fatalf("writeLanguageSummary failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `javascript,2,3,1,1,2,"JavaScript (2), Node.js (1)"`) {
+		t.Errorf("Unexpected CSV output:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := writeLanguageSummary(&buf, summaries, "text", false); err != nil {
+		t.Fatalf("writeLanguageSummary failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "LANGUAGE REPORT") || !strings.Contains(buf.String(), "TOTAL") {
+		t.Errorf("Unexpected text output:\n%s", buf.String())
+	}
+}
+
+// TestSummarizeByContentDir tests totaling reports by content directory for --group-by content-dir.
+func TestSummarizeByContentDir(t *testing.T) {
+	reports := []PageReport{
+		{Rank: 1, ContentDir: "pymongo-driver", TotalExamples: 5, TotalTested: 1, TotalTestable: 4, TotalMaybeTestable: 1},
+		{Rank: 2, ContentDir: "node", TotalExamples: 2, TotalTested: 2, TotalTestable: 2},
+		{Rank: 3, ContentDir: "pymongo-driver", TotalExamples: 3, TotalTested: 1, TotalTestable: 2},
+		{Rank: 4, Error: "could not resolve URL slug: unknown/d"},
+	}
+
+	summaries := SummarizeByContentDir(reports)
+	expected := []ContentDirSummary{
+		{ContentDir: "node", Pages: 1, TotalExamples: 2, TotalTested: 2, TotalTestable: 2},
+		{ContentDir: "pymongo-driver", Pages: 2, TotalExamples: 8, TotalTested: 2, TotalTestable: 6, TotalMaybeTestable: 1},
+		{ContentDir: "unresolved", Pages: 1, Errors: 1},
+	}
+	if !reflect.DeepEqual(summaries, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, summaries)
+	}
+
+	var buf bytes.Buffer
+	if err := writeContentDirSummary(&buf, summaries, "csv", false); err != nil {
+		t.Fatalf("writeContentDirSummary failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "pymongo-driver,2,0,8,2,6,1,33.3%,0\n") {
+		t.Errorf("Expected a pymongo-driver CSV row, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := writeContentDirSummary(&buf, summaries, "text", false); err != nil {
+		t.Fatalf("writeContentDirSummary failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "TOTAL") || !strings.Contains(buf.String(), "unresolved") {
+		t.Errorf("Expected a total row and an unresolved row, got:\n%s", buf.String())
+	}
+}
+
+// TestReportSummary tests totaling reports across pages for --summary-json.
+func TestReportSummary(t *testing.T) {
+	page1 := BuildPageReport(&PageAnalysis{
+		Rank: 1, URL: "www.mongodb.com/docs/a/",
+		CodeExamples: []CodeExample{
+			{Product: "Python", IsTestable: true, IsTested: true, Origin: OriginTab},
+			{Product: "Python", IsTestable: true},
+			{Product: "JSON"},
+		},
+	})
+	page2 := BuildPageReport(&PageAnalysis{
+		Rank: 2, URL: "www.mongodb.com/docs/b/",
+		CodeExamples: []CodeExample{
+			{Product: "Python", IsTestable: true, Origin: OriginTab},
 		},
+	})
+
+	summary := NewReportSummary()
+	for _, report := range []PageReport{page1, page2, {Rank: 3, Error: "source file not found"}} {
+		summary.Add(report)
+	}
+	if summary.Pages != 3 || summary.FailedPages != 1 || summary.TotalExamples != 4 || summary.TotalTested != 1 || summary.TotalTestable != 3 {
+		t.Errorf("Unexpected totals: %+v", summary)
+	}
+	if summary.Coverage != 33.3 {
+		t.Errorf("Expected coverage 33.3, got %v", summary.Coverage)
+	}
+	python := summary.ByProduct["Python"]
+	if python.TotalCount != 3 || python.TestableCount != 3 || python.ByOrigin[OriginTab] != 2 || !python.PartiallyTested {
+		t.Errorf("Unexpected Python totals: %+v", python)
+	}
+
+	path := filepath.Join(t.TempDir(), "summary.json")
+	if err := WriteSummaryJSON(path, summary); err != nil {
+		t.Fatalf("WriteSummaryJSON failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read summary: %v", err)
+	}
+	var decoded struct {
+		Pages     int
+		Coverage  float64
+		ByProduct []ProductStats
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to parse summary: %v", err)
+	}
+	if decoded.Pages != 3 || decoded.Coverage != 33.3 || len(decoded.ByProduct) != 2 || decoded.ByProduct[0].Product != "JSON" {
+		t.Errorf("Unexpected summary JSON:\n%s", data)
+	}
+}
+
+// TestBuildBadge tests the --badge Shields.io endpoint JSON and its color thresholds.
+func TestBuildBadge(t *testing.T) {
+	low, high, err := parseBadgeThresholds(DefaultBadgeThresholds)
+	if err != nil {
+		t.Fatalf("parseBadgeThresholds(%q) failed: %v", DefaultBadgeThresholds, err)
+	}
+
+	testCases := []struct {
+		name      string
+		tested    int
+		testable  int
+		wantMsg   string
+		wantColor string
+	}{
+		{"low coverage", 1, 4, "25%", "red"},
+		{"medium coverage", 62, 100, "62%", "yellow"},
+		{"at the high threshold", 4, 5, "80%", "green"},
+		{"no testable examples", 0, 0, "n/a", "lightgrey"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			summary := NewReportSummary()
+			summary.Add(PageReport{TotalTested: tc.tested, TotalTestable: tc.testable})
+			badge := BuildBadge(summary, low, high)
+			if badge.Message != tc.wantMsg || badge.Color != tc.wantColor {
+				t.Errorf("BuildBadge() = %s %s, want %s %s", badge.Message, badge.Color, tc.wantMsg, tc.wantColor)
+			}
+		})
+	}
+
+	summary := NewReportSummary()
+	summary.Add(PageReport{TotalTested: 62, TotalTestable: 100})
+	path := filepath.Join(t.TempDir(), "badge.json")
+	if err := WriteBadge(path, BuildBadge(summary, low, high)); err != nil {
+		t.Fatalf("WriteBadge failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read badge: %v", err)
+	}
+	if want := `{"schemaVersion":1,"label":"code coverage","message":"62%","color":"yellow"}` + "\n"; string(data) != want {
+		t.Errorf("Badge JSON = %s, want %s", data, want)
+	}
+
+	if low, high, err := parseBadgeThresholds("40:70"); err != nil || low != 40 || high != 70 {
+		t.Errorf("parseBadgeThresholds(40:70) = %v, %v, %v", low, high, err)
+	}
+	for _, value := range []string{"50", "a:b", "80:50", "-1:50", "50:101"} {
+		if _, _, err := parseBadgeThresholds(value); err == nil {
+			t.Errorf("Expected an error for --badge-thresholds %q", value)
+		}
+	}
+}
+
+// TestReportSummaryOutliers tests recording pages with more than --warn-over examples.
+func TestReportSummaryOutliers(t *testing.T) {
+	summary := NewReportSummary()
+	for _, report := range []PageReport{
+		{Rank: 1, URL: "www.mongodb.com/docs/a/", TotalExamples: 150},
+		{Rank: 2, URL: "www.mongodb.com/docs/b/", TotalExamples: 100},
+		{Rank: 3, URL: "www.mongodb.com/docs/c/", TotalExamples: 400},
+		{Rank: 4, URL: "www.mongodb.com/docs/d/", Error: "source file not found"},
+	} {
+		summary.Add(report)
+		summary.AddOutlier(report, 100)
+	}
+	expected := []Outlier{
+		{Rank: 1, URL: "www.mongodb.com/docs/a/", TotalExamples: 150},
+		{Rank: 3, URL: "www.mongodb.com/docs/c/", TotalExamples: 400},
+	}
+	if !reflect.DeepEqual(summary.Outliers, expected) {
+		t.Errorf("Expected outliers %+v, got %+v", expected, summary.Outliers)
+	}
+
+	var buf bytes.Buffer
+	PrintOutliers(&buf, summary, 100)
+	out := buf.String()
+	if !strings.Contains(out, "2 page(s) with more than 100 code examples") ||
+		strings.Index(out, "docs/c/") > strings.Index(out, "docs/a/") {
+		t.Errorf("Unexpected outliers output:\n%s", out)
+	}
+
+	// Without a threshold, no page is an outlier
+	summary = NewReportSummary()
+	summary.AddOutlier(PageReport{Rank: 1, TotalExamples: 1000}, 0)
+	buf.Reset()
+	PrintOutliers(&buf, summary, 0)
+	if len(summary.Outliers) != 0 || buf.Len() != 0 {
+		t.Errorf("Expected no outliers without --warn-over, got %+v", summary.Outliers)
+	}
+}
+
+// TestOutputJSONSortedProducts tests that ByProduct is encoded as a slice sorted by product.
+func TestOutputJSONSortedProducts(t *testing.T) {
+	reports := []PageReport{
 		{
-			name: "io-code-block with input and output",
-			directive: rst.Directive{
-				Type:    rst.IoCodeBlock,
-				Options: map[string]string{"language": "python"},
-				InputDirective: &rst.SubDirective{
-					Argument: "/code-examples/input.py",
-					Options:  map[string]string{"language": "python"},
-				},
-				OutputDirective: &rst.SubDirective{
-					Argument: "/code-examples/output.txt",
-					Options:  map[string]string{"language": "text"},
-				},
+			Rank: 1,
+			URL:  "www.mongodb.com/docs/test/page/",
+			ByProduct: map[string]*ProductStats{
+				"Python":  {Product: "Python", TotalCount: 2},
+				"C#":      {Product: "C#", TotalCount: 1},
+				"Node.js": {Product: "Node.js", TotalCount: 3},
 			},
-			contentDir:    "pymongo-driver",
-			contexts:      nil,
-			expectedCount: 2, // input + output
 		},
+		{Rank: 2, URL: "www.mongodb.com/docs/test/empty/", ByProduct: map[string]*ProductStats{}},
+	}
+
+	var buf bytes.Buffer
+	if err := OutputJSON(&buf, reports); err != nil {
+		t.Fatalf("OutputJSON failed: %v", err)
+	}
+
+	var decoded []struct {
+		Rank      int
+		ByProduct []ProductStats
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Expected ByProduct to decode as a slice: %v\n%s", err, buf.String())
+	}
+	if len(decoded) != 2 {
+		t.Fatalf("Expected 2 reports, got %d", len(decoded))
+	}
+
+	expected := []string{"C#", "Node.js", "Python"}
+	if len(decoded[0].ByProduct) != len(expected) {
+		t.Fatalf("Expected %d products, got %d", len(expected), len(decoded[0].ByProduct))
+	}
+	for i, product := range expected {
+		if decoded[0].ByProduct[i].Product != product {
+			t.Errorf("ByProduct[%d]: expected %s, got %s", i, product, decoded[0].ByProduct[i].Product)
+		}
+	}
+	if decoded[0].ByProduct[1].TotalCount != 3 {
+		t.Errorf("Expected Node.js TotalCount 3, got %d", decoded[0].ByProduct[1].TotalCount)
+	}
+
+	// A page without products encodes an empty list, not null
+	if !strings.Contains(buf.String(), `"ByProduct": []`) {
+		t.Errorf("Expected empty ByProduct list in output:\n%s", buf.String())
+	}
+
+	// --compact-json writes the same reports on a single line
+	var compact bytes.Buffer
+	if err := OutputJSONCompact(&compact, reports); err != nil {
+		t.Fatalf("OutputJSONCompact failed: %v", err)
+	}
+	if strings.Count(compact.String(), "\n") != 1 || !strings.Contains(compact.String(), `"ByProduct":[]`) {
+		t.Errorf("Expected single-line JSON, got:\n%s", compact.String())
+	}
+	var indented, unindented []any
+	if err := json.Unmarshal(buf.Bytes(), &indented); err != nil {
+		t.Fatal(err)
 	}
+	if err := json.Unmarshal(compact.Bytes(), &unindented); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(indented, unindented) {
+		t.Error("Expected compact and indented JSON to encode the same reports")
+	}
+}
+
+// TestStaticFallbackFlag tests that UsedStaticFallback is only in the summary when set.
+func TestStaticFallbackFlag(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary.json")
+	summary := NewReportSummary()
+	if err := WriteSummaryJSON(path, summary); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "UsedStaticFallback") {
+		t.Errorf("Expected no UsedStaticFallback key when the API mapping was used, got:\n%s", data)
+	}
+
+	summary.UsedStaticFallback = true
+	if err := WriteSummaryJSON(path, summary); err != nil {
+		t.Fatal(err)
+	}
+	if data, err = os.ReadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"UsedStaticFallback": true`) {
+		t.Errorf("Expected \"UsedStaticFallback\": true, got:\n%s", data)
+	}
+
+	var buf bytes.Buffer
+	PrintStaticFallbackBanner(&buf)
+	if !strings.Contains(buf.String(), "static mapping") {
+		t.Errorf("Expected banner to mention the static mapping, got:\n%s", buf.String())
+	}
+}
+
+// TestGroupFailures tests grouping page failures by reason, most frequent first.
+func TestGroupFailures(t *testing.T) {
+	errs := []error{
+		fmt.Errorf("%w: unknown/page", config.ErrSlugNotResolved),
+		fmt.Errorf("%w: atlas", config.ErrNoContentDir),
+		fmt.Errorf("%w: other/page", config.ErrSlugNotResolved),
+		fmt.Errorf("%w: %w", config.ErrSourceNotFound, errors.New("stat /repo/content/manual/manual/source/missing.txt: no such file or directory")),
+		fmt.Errorf("%w: not-a-url", config.ErrInvalidURL),
+		fmt.Errorf("%w: third", config.ErrSlugNotResolved),
+		errors.New("open /repo/content/atlas/source/gone.txt: no such file or directory"),
+	}
+
+	groups := GroupFailures(errs)
+	expected := []FailureGroup{
+		{Reason: "could not resolve URL slug", Count: 3},
+		{Reason: "source file not found", Count: 2},
+		{Reason: "invalid URL format", Count: 1},
+		{Reason: "no content directory found for project", Count: 1},
+	}
+	if len(groups) != len(expected) {
+		t.Fatalf("Expected %d groups, got %v", len(expected), groups)
+	}
+	for i, want := range expected {
+		if groups[i] != want {
+			t.Errorf("Group %d: expected %+v, got %+v", i, want, groups[i])
+		}
+	}
+
+	var buf bytes.Buffer
+	PrintFailureSummary(&buf, errs)
+	if !strings.Contains(buf.String(), "7 page(s) failed to analyze:") ||
+		!strings.Contains(buf.String(), "      3  could not resolve URL slug") {
+		t.Errorf("Unexpected summary:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	PrintFailureSummary(&buf, nil)
+	if buf.Len() != 0 {
+		t.Errorf("Expected no summary when no pages failed, got:\n%s", buf.String())
+	}
+}
+
+// TestErrorCategory tests classifying page analysis errors with the config sentinel errors.
+func TestErrorCategory(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"invalid URL", fmt.Errorf("%w: not-a-url", config.ErrInvalidURL), ErrorCategoryInvalidURL},
+		{"unresolved slug", fmt.Errorf("%w: unknown/page", config.ErrSlugNotResolved), ErrorCategorySlugNotResolved},
+		{"no content dir", fmt.Errorf("%w: atlas", config.ErrNoContentDir), ErrorCategoryNoContentDir},
+		{"missing source", fmt.Errorf("%w: %w", config.ErrSourceNotFound, os.ErrNotExist), ErrorCategorySourceNotFound},
+		{"wrapped twice", fmt.Errorf("analyzing page: %w", fmt.Errorf("%w: atlas", config.ErrNoContentDir)), ErrorCategoryNoContentDir},
+		{"other", errors.New("failed to parse file"), ErrorCategoryOther},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := errorCategory(tc.err); got != tc.expected {
+				t.Errorf("errorCategory(%v) = %q, expected %q", tc.err, got, tc.expected)
+			}
+		})
+	}
+}
+
+// TestRelativeSourcePath tests showing source paths relative to their monorepo root.
+func TestRelativeSourcePath(t *testing.T) {
+	mainRepo := filepath.Join("/home", "writer", "docs-mongodb-internal")
+	driverRepo := filepath.Join("/home", "writer", "drivers-monorepo")
+	roots := []string{mainRepo, driverRepo}
+
+	testCases := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{"main monorepo", filepath.Join(mainRepo, "content", "atlas", "source", "page.txt"), "content/atlas/source/page.txt"},
+		{"additional monorepo", filepath.Join(driverRepo, "content", "rust", "current", "source", "index.txt"), "content/rust/current/source/index.txt"},
+		{"outside every root", filepath.Join("/tmp", "page.txt"), filepath.Join("/tmp", "page.txt")},
+		{"sibling with a shared prefix", mainRepo + "-old/page.txt", mainRepo + "-old/page.txt"},
+		{"unresolved page", "", ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := RelativeSourcePath(tc.path, roots); got != tc.expected {
+				t.Errorf("RelativeSourcePath(%q) = %q, want %q", tc.path, got, tc.expected)
+			}
+		})
+	}
+}
+
+// TestOutputExamplesCSV tests writing one row per code example for --format examples-csv.
+func TestOutputExamplesCSV(t *testing.T) {
+	analysis := &PageAnalysis{
+		Rank: 3,
+		URL:  "www.mongodb.com/docs/drivers/go/current/crud/",
+		CodeExamples: []CodeExample{
+			{Type: "literalinclude", Language: "go", Product: "Go", IsTested: true, IsTestable: true,
+				SourceFile: "/repo/content/golang/current/source/crud.txt", FilePath: "/repo/content/code-examples/tested/go/crud.go"},
+			{Type: "io-code-block", Language: "javascript", Product: "JavaScript", IsInput: true, IsMaybeTestable: true,
+				SourceFile: "/repo/content/golang/current/source/includes/a,b.rst"},
+		},
+	}
+	records := BuildExampleRecords(analysis, "golang/current/crud/")
+	if len(records) != 2 || records[0].Rank != 3 || records[1].URL != "golang/current/crud/" {
+		t.Fatalf("Unexpected records: %+v", records)
+	}
+
+	var buf bytes.Buffer
+	if err := OutputExamplesCSV(&buf, records); err != nil {
+		t.Fatalf("OutputExamplesCSV failed: %v", err)
+	}
+	want := "url,rank,source_file,type,language,product,is_input,is_output,is_tested,is_testable,is_maybe_testable,file_path\n" +
+		"golang/current/crud/,3,/repo/content/golang/current/source/crud.txt,literalinclude,go,Go,false,false,true,true,false,/repo/content/code-examples/tested/go/crud.go\n" +
+		"golang/current/crud/,3,\"/repo/content/golang/current/source/includes/a,b.rst\",io-code-block,javascript,JavaScript,true,false,false,false,true,\n"
+	if buf.String() != want {
+		t.Errorf("OutputExamplesCSV =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	// Failed pages have no examples, so only the header is written
+	buf.Reset()
+	if err := OutputExamplesCSV(&buf, BuildExampleRecords(&PageAnalysis{}, "")); err != nil {
+		t.Fatalf("OutputExamplesCSV failed: %v", err)
+	}
+	if strings.Count(buf.String(), "\n") != 1 {
+		t.Errorf("Expected only a header, got %q", buf.String())
+	}
+}
+
+// TestBuildWorklist tests ranking pages by testable but untested examples.
+func TestBuildWorklist(t *testing.T) {
+	reports := []PageReport{
+		{Rank: 1, URL: "https://example.com/a", TotalTestable: 3, TotalTested: 3},
+		{Rank: 2, URL: "https://example.com/b", TotalTestable: 4, TotalTested: 1, ByProduct: map[string]*ProductStats{
+			"Python":  {TestableCount: 2, TestedCount: 1},
+			"Node.js": {TestableCount: 2},
+		}},
+		{Rank: 3, URL: "https://example.com/c", TotalTestable: 5, ByProduct: map[string]*ProductStats{
+			"Java (Sync)": {TestableCount: 5},
+		}},
+		{Rank: 4, URL: "https://example.com/d", Error: "failed"},
+		{Rank: 5, URL: "https://example.com/e", TotalTestable: 3, ByProduct: map[string]*ProductStats{
+			"Go": {TestableCount: 3},
+		}},
+	}
+
+	entries := BuildWorklist(reports, 0)
+	want := []WorklistEntry{
+		{Rank: 3, URL: "https://example.com/c", Untested: 5, Product: "Java (Sync)"},
+		{Rank: 2, URL: "https://example.com/b", Untested: 3, Product: "Node.js"},
+		{Rank: 5, URL: "https://example.com/e", Untested: 3, Product: "Go"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("BuildWorklist() = %+v, want %+v", entries, want)
+	}
+
+	if top := BuildWorklist(reports, 2); len(top) != 2 || top[1].Rank != 2 {
+		t.Errorf("BuildWorklist(top=2) = %+v, want the first two entries", top)
+	}
+
+	var buf bytes.Buffer
+	if err := OutputWorklist(&buf, want); err != nil {
+		t.Fatalf("OutputWorklist() error = %v", err)
+	}
+	out := buf.String()
+	for _, s := range []string{"TESTABLE BUT UNTESTED WORKLIST", "Java (Sync)", "https://example.com/e", "3 page(s), 11 testable example(s) to test"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("OutputWorklist() output missing %q:\n%s", s, out)
+		}
+	}
+}
+
+// TestDropNonDriverProducts tests removing non-driver products and their examples for --drivers-strict.
+func TestDropNonDriverProducts(t *testing.T) {
+	report := PageReport{
+		TotalExamples: 10, TotalInput: 1, TotalOutput: 1, TotalTested: 2, TotalTestable: 4, TotalMaybeTestable: 2,
+		ByProduct: map[string]*ProductStats{
+			"Node.js":       {Product: "Node.js", TotalCount: 4, TestedCount: 2, TestableCount: 4},
+			"Shell":         {Product: "Shell", TotalCount: 3, MaybeTestableCount: 2},
+			"JSON":          {Product: "JSON", TotalCount: 2, InputCount: 1, OutputCount: 1},
+			"MongoDB Shell": {Product: "MongoDB Shell"},
+			"Kotlin (Sync)": {Product: "Kotlin (Sync)", TotalCount: 1},
+		},
+	}
+
+	if dropped := DropNonDriverProducts(&report); dropped != 5 {
+		t.Errorf("Expected 5 examples dropped, got %d", dropped)
+	}
+	var products []string
+	for product := range report.ByProduct {
+		products = append(products, product)
+	}
+	sort.Strings(products)
+	if want := []string{"Kotlin (Sync)", "Node.js"}; !reflect.DeepEqual(products, want) {
+		t.Errorf("Expected products %v, got %v", want, products)
+	}
+	if report.TotalExamples != 5 || report.TotalInput != 0 || report.TotalOutput != 0 ||
+		report.TotalTested != 2 || report.TotalTestable != 4 || report.TotalMaybeTestable != 0 {
+		t.Errorf("Unexpected totals after dropping: %+v", report)
+	}
+
+	for product, want := range map[string]bool{"YAML": true, "Text": true, "JavaScript": true, "Unknown": true, "Python": false, "C#": false} {
+		if got := isNonDriverProduct(product); got != want {
+			t.Errorf("isNonDriverProduct(%q) = %v, want %v", product, got, want)
+		}
+	}
+}
+
+// TestTrimURLPrefix tests removing a display prefix from page URLs.
+func TestTrimURLPrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		prefix   string
+		expected string
+	}{
+		{"prefix removed", "www.mongodb.com/docs/atlas/some-page/", "www.mongodb.com/docs/", "atlas/some-page/"},
+		{"scheme removed with prefix", "https://www.mongodb.com/docs/atlas/", "www.mongodb.com/docs/", "atlas/"},
+		{"prefix with scheme", "https://www.mongodb.com/docs/atlas/", "https://www.mongodb.com/docs/", "atlas/"},
+		{"no match unchanged", "https://example.com/docs/atlas/", "www.mongodb.com/docs/", "https://example.com/docs/atlas/"},
+		{"empty prefix unchanged", "www.mongodb.com/docs/atlas/", "", "www.mongodb.com/docs/atlas/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TrimURLPrefix(tt.url, tt.prefix); got != tt.expected {
+				t.Errorf("TrimURLPrefix(%q, %q) = %q, want %q", tt.url, tt.prefix, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestOutputTOML tests that TOML output round-trips through a [[Pages]] table per report.
+func TestOutputTOML(t *testing.T) {
+	reports := []PageReport{
+		{
+			Rank:          1,
+			URL:           "www.mongodb.com/docs/test/page/",
+			SourcePath:    "/path/to/page.txt",
+			ContentDir:    "pymongo-driver",
+			Version:       "current",
+			TotalExamples: 3,
+			TotalTestable: 2,
+			ByProduct: map[string]*ProductStats{
+				"Python":      {Product: "Python", TotalCount: 2, TestableCount: 2, ByOrigin: map[string]int{OriginTab: 2}},
+				"Java (Sync)": {Product: "Java (Sync)", TotalCount: 1, ByOrigin: map[string]int{OriginContentDir: 1}},
+			},
+			IncludeDepth: 2,
+			Warnings:     []string{"include cycle: a.rst -> b.rst -> a.rst"},
+		},
+		{Rank: 2, URL: "www.mongodb.com/docs/test/missing/", Error: "not found"},
+	}
+
+	var buf bytes.Buffer
+	if err := writeReport(&buf, reports, ReportFormat{Format: "toml"}); err != nil {
+		t.Fatalf("writeReport failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "[[Pages]]") {
+		t.Errorf("Expected [[Pages]] tables, got:\n%s", buf.String())
+	}
+
+	var decoded TOMLReport
+	if _, err := toml.Decode(buf.String(), &decoded); err != nil {
+		t.Fatalf("Output is not valid TOML: %v\n%s", err, buf.String())
+	}
+	if !reflect.DeepEqual(decoded.Pages, reports) {
+		t.Errorf("Round trip mismatch:\nexpected %+v\ngot      %+v", reports, decoded.Pages)
+	}
+}
+
+// TestOutputMatrix tests the page-by-product grid of testable counts.
+func TestOutputMatrix(t *testing.T) {
+	reports := []PageReport{
+		{
+			Rank: 1,
+			URL:  "www.mongodb.com/docs/test/page/",
+			ByProduct: map[string]*ProductStats{
+				"Python":      {Product: "Python", TotalCount: 3, TestableCount: 2},
+				"Java (Sync)": {Product: "Java (Sync)", TotalCount: 1, TestableCount: 1},
+			},
+		},
+		{
+			Rank: 2,
+			URL:  "www.mongodb.com/docs/test/other/",
+			ByProduct: map[string]*ProductStats{
+				"Go, Legacy": {Product: "Go, Legacy", TotalCount: 1, TestableCount: 1},
+				"Shell":      {Product: "Shell", TotalCount: 4},
+			},
+		},
+		{Rank: 3, URL: "www.mongodb.com/docs/test/missing/", Error: "not found"},
+	}
+
+	var buf bytes.Buffer
+	if err := writeReport(&buf, reports, ReportFormat{Format: "matrix"}); err != nil {
+		t.Fatalf("writeReport failed: %v", err)
+	}
+
+	expected := "Rank,URL,\"Go, Legacy\",Java (Sync),Python,Shell,Error\n" +
+		"1,www.mongodb.com/docs/test/page/,0,1,2,0,\n" +
+		"2,www.mongodb.com/docs/test/other/,1,0,0,0,\n" +
+		"3,www.mongodb.com/docs/test/missing/,0,0,0,0,not found\n"
+	if buf.String() != expected {
+		t.Errorf("Unexpected matrix:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+	if formatExtension("matrix") != "csv" {
+		t.Errorf("Expected matrix files to use the csv extension, got %s", formatExtension("matrix"))
+	}
+}
+
+// TestOutputCSVFormulaInjection tests that every CSV output guards fields a spreadsheet
+// would evaluate as a formula.
+func TestOutputCSVFormulaInjection(t *testing.T) {
+	reports := []PageReport{
+		{
+			Rank:       1,
+			URL:        "=HYPERLINK(\"http://evil.example\")",
+			SourcePath: "+cmd",
+			ContentDir: "@project",
+			ByProduct: map[string]*ProductStats{
+				"-Product": {Product: "-Product", TotalCount: 1, TestableCount: 1},
+			},
+		},
+		{Rank: 2, URL: "www.mongodb.com/docs/a/", Error: "=1+2"},
+	}
+
+	for _, format := range []string{"csv", "matrix"} {
+		for _, details := range []bool{false, true} {
+			var buf bytes.Buffer
+			if err := writeReport(&buf, reports, ReportFormat{Format: format, ShowDetails: details}); err != nil {
+				t.Fatalf("writeReport(%s) failed: %v", format, err)
+			}
+			for _, field := range strings.FieldsFunc(buf.String(), func(r rune) bool { return r == ',' || r == '\n' }) {
+				field = strings.TrimPrefix(field, `"`)
+				if field != "" && strings.ContainsRune("=+-@", rune(field[0])) {
+					t.Errorf("%s (details %v): unguarded field %q in:\n%s", format, details, field, buf.String())
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := writeContentDirSummary(&buf, SummarizeByContentDir(reports), "csv", false); err != nil {
+		t.Fatalf("writeContentDirSummary failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "'@project,") {
+		t.Errorf("Expected the content directory to be guarded:\n%s", buf.String())
+	}
+}
+
+// TestOutputJSONL tests that JSON Lines output has one report per line and no enclosing array.
+func TestOutputJSONL(t *testing.T) {
+	reports := []PageReport{
+		{
+			Rank: 1,
+			URL:  "www.mongodb.com/docs/test/page/",
+			ByProduct: map[string]*ProductStats{
+				"Python": {Product: "Python", TotalCount: 2},
+				"C#":     {Product: "C#", TotalCount: 1},
+			},
+		},
+		{Rank: 2, URL: "www.mongodb.com/docs/test/missing/", Error: "not found"},
+	}
+
+	var buf bytes.Buffer
+	if err := writeReport(&buf, reports, ReportFormat{Format: "jsonl"}); err != nil {
+		t.Fatalf("writeReport failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d:\n%s", len(lines), buf.String())
+	}
+	for i, line := range lines {
+		var decoded struct {
+			Rank      int
+			Error     string
+			ByProduct []ProductStats
+		}
+		if err := json.Unmarshal([]byte(line), &decoded); err != nil {
+			t.Fatalf("Line %d is not a JSON object: %v\n%s", i+1, err, line)
+		}
+		if decoded.Rank != reports[i].Rank {
+			t.Errorf("Line %d: expected rank %d, got %d", i+1, reports[i].Rank, decoded.Rank)
+		}
+	}
+	if !strings.Contains(lines[0], `"ByProduct":[{"Product":"C#"`) {
+		t.Errorf("Expected sorted ByProduct on line 1, got: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], `"Error":"not found"`) {
+		t.Errorf("Expected error on line 2, got: %s", lines[1])
+	}
+
+	if ext := formatExtension("jsonl"); ext != "jsonl" {
+		t.Errorf("Expected jsonl extension, got %s", ext)
+	}
+}
+
+// TestMergeContentDirOverrides tests that command-line overrides win over the config file.
+func TestMergeContentDirOverrides(t *testing.T) {
+	merged := mergeContentDirOverrides(
+		map[string]string{"cloud-docs": "Atlas", "legacy": "Legacy"},
+		map[string]string{"cloud-docs": "Atlas (CLI)"},
+	)
+	if merged["cloud-docs"] != "Atlas (CLI)" || merged["legacy"] != "Legacy" || len(merged) != 2 {
+		t.Errorf("Unexpected merged overrides: %v", merged)
+	}
+
+	if merged := mergeContentDirOverrides(nil, nil); len(merged) != 0 {
+		t.Errorf("Expected no overrides, got %v", merged)
+	}
+}
+
+// TestTestedReason tests the explanation for an example's tested flag.
+func TestTestedReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		example  CodeExample
+		expected bool
+		reason   string
+	}{
+		{"tested path", CodeExample{FilePath: "/code-examples/tested/python/a.py", IsTested: true}, true, "file path contains /tested/"},
+		{"missing tested file", CodeExample{FilePath: "/code-examples/tested/python/a.py"}, false, "/tested/ file not found on disk (--verify-tested)"},
+		{"inline code", CodeExample{}, false, "inline code is not tested"},
+		{"untested path", CodeExample{FilePath: "/includes/example.py"}, false, "file path does not contain /tested/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tested, reason := testedReason(tt.example)
+			if tested != tt.expected || reason != tt.reason {
+				t.Errorf("testedReason() = (%v, %q), want (%v, %q)", tested, reason, tt.expected, tt.reason)
+			}
+		})
+	}
+}
+
+// TestFormatOrigins tests the formatOrigins function.
+func TestFormatOrigins(t *testing.T) {
+	got := formatOrigins(map[string]int{OriginTab: 3, OriginContentDir: 2})
+	if got != "content-dir:2;tab:3" {
+		t.Errorf("formatOrigins() = %q, expected %q", got, "content-dir:2;tab:3")
+	}
+	if got := formatOrigins(nil); got != "" {
+		t.Errorf("formatOrigins(nil) = %q, expected empty string", got)
+	}
+}
+
+// TestIsMongoShellContext tests the isMongoShellContext function.
+func TestIsMongoShellContext(t *testing.T) {
+	testCases := []struct {
+		contentDir string
+		contexts   []CodeContext
+		expected   bool
+	}{
+		{"mongodb-shell", nil, true},
+		{"mongodb-shell", []CodeContext{}, true},
+		{"pymongo-driver", []CodeContext{{Interface: "mongosh"}}, true},
+		{"node", []CodeContext{{Interface: "driver"}}, false},
+		{"manual", []CodeContext{}, false},
+		{"", nil, false},
+	}
+
+	for _, tc := range testCases {
+		result := isMongoShellContext(tc.contentDir, tc.contexts)
+		if result != tc.expected {
+			t.Errorf("isMongoShellContext(%q, %v) = %v, expected %v",
+				tc.contentDir, tc.contexts, result, tc.expected)
+		}
+	}
+}
+
+// TestDetermineProduct tests the determineProduct function.
+func TestDetermineProduct(t *testing.T) {
+	// Create mock mappings
+	mappings := &ProductMappings{
+		DriversTabIDToProduct: map[string]string{
+			"python":    "Python",
+			"nodejs":    "Node.js",
+			"java-sync": "Java (Sync)",
+		},
+		ComposableLanguageToProduct: map[string]string{
+			"python": "Python",
+			"nodejs": "Node.js",
+			"go":     "Go",
+		},
+		ComposableInterfaceToProduct: map[string]string{
+			"mongosh":  "MongoDB Shell",
+			"driver":   "Driver",
+			"compass":  "Compass",
+		},
+	}
+
+	testCases := []struct {
+		name       string
+		language   string
+		contentDir string
+		contexts   []CodeContext
+		expected   string
+		origin     string
+	}{
+		// Non-driver languages bypass context
+		{"bash bypasses context", "bash", "pymongo-driver", []CodeContext{{Language: "python"}}, "Shell", OriginLanguage},
+		{"json bypasses context", "json", "node", []CodeContext{{TabID: "nodejs"}}, "JSON", OriginLanguage},
+		{"yaml bypasses context", "yaml", "golang", nil, "YAML", OriginLanguage},
+		{"text bypasses context", "text", "manual", nil, "Text", OriginLanguage},
+
+		// MongoDB Shell context
+		{"shell in mongosh dir", "shell", "mongodb-shell", nil, "MongoDB Shell", OriginContentDir},
+		{"javascript in mongosh context", "javascript", "", []CodeContext{{Interface: "mongosh"}}, "MongoDB Shell", OriginComposableInterface},
+		{"shell outside mongosh", "shell", "manual", nil, "Shell", OriginLanguage},
+
+		// Tab context
+		{"python tab", "python", "", []CodeContext{{TabID: "python"}}, "Python", OriginTab},
+		{"nodejs tab", "javascript", "", []CodeContext{{TabID: "nodejs"}}, "Node.js", OriginTab},
+
+		// Composable language context
+		{"go composable", "go", "", []CodeContext{{Language: "go"}}, "Go", OriginComposableLanguage},
+
+		// Content directory fallback
+		{"pymongo content dir", "python", "pymongo-driver", nil, "Python", OriginContentDir},
+		{"node content dir", "javascript", "node", nil, "Node.js", OriginContentDir},
+
+		// Language fallback
+		{"python language", "python", "", nil, "Python", OriginLanguage},
+		{"ruby language", "ruby", "", nil, "Ruby", OriginLanguage},
+
+		// Unknown
+		{"empty language", "", "", nil, "Unknown", OriginUnknown},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, origin := determineProduct(tc.language, tc.contentDir, tc.contexts, mappings)
+			if result != tc.expected {
+				t.Errorf("determineProduct(%q, %q, %v) = %q, expected %q",
+					tc.language, tc.contentDir, tc.contexts, result, tc.expected)
+			}
+			if origin != tc.origin {
+				t.Errorf("determineProduct(%q, %q, %v) origin = %q, expected %q",
+					tc.language, tc.contentDir, tc.contexts, origin, tc.origin)
+			}
+		})
+	}
+}
+
+// TestContentDirOverrides tests attributing content directories to products with
+// AnalyzeOptions.ContentDirOverrides.
+func TestContentDirOverrides(t *testing.T) {
+	mappings := &ProductMappings{
+		DriversTabIDToProduct: map[string]string{"python": "Python"},
+	}
+	opts := &AnalyzeOptions{ContentDirOverrides: map[string]string{
+		"cloud-docs": "Atlas",
+		"golang":     "Go (override)",
+	}}
+
+	testCases := []struct {
+		name       string
+		language   string
+		contentDir string
+		contexts   []CodeContext
+		expected   string
+		origin     string
+	}{
+		{"override for unmapped content dir", "python", "cloud-docs", nil, "Atlas", OriginContentDir},
+		{"override wins over built-in content dir", "go", "golang", nil, "Go (override)", OriginContentDir},
+		{"tab context wins over override", "python", "cloud-docs", []CodeContext{{TabID: "python"}}, "Python", OriginTab},
+		{"non-driver language ignores override", "json", "cloud-docs", nil, "JSON", OriginLanguage},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			product, origin, _, _ := explainProduct(tc.language, tc.contentDir, tc.contexts, mappings, opts)
+			if product != tc.expected || origin != tc.origin {
+				t.Errorf("Expected %q (%s), got %q (%s)", tc.expected, tc.origin, product, origin)
+			}
+		})
+	}
+
+	// Without overrides, the built-in mapping applies
+	if product, _ := determineProduct("go", "golang", nil, mappings); product != "Go" {
+		t.Errorf("Expected the built-in golang mapping without overrides, got %q", product)
+	}
+}
+
+// TestJavaScriptAsNodeJSInContext tests attributing javascript examples in a Node.js context to Node.js.
+func TestJavaScriptAsNodeJSInContext(t *testing.T) {
+	mappings := &ProductMappings{
+		DriversTabIDToProduct:        map[string]string{"nodejs": "Node.js", "python": "Python"},
+		ComposableLanguageToProduct:  map[string]string{"nodejs": "Node.js"},
+		ComposableInterfaceToProduct: map[string]string{"driver": "Driver", "mongosh": "MongoDB Shell"},
+	}
+	withFlag := &AnalyzeOptions{JavaScriptAsNodeJS: true}
+
+	testCases := []struct {
+		name            string
+		language        string
+		contentDir      string
+		contexts        []CodeContext
+		defaultProduct  string
+		flagProduct     string
+		flagOrigin      string
+		flagIsTestable  bool
+		flagIsMaybeTest bool
+	}{
+		// A node tab under the driver interface composable: the interface comes first by default
+		{"javascript in node tab under driver interface", "javascript", "", []CodeContext{{Interface: "driver"}, {TabID: "nodejs"}},
+			"Driver", "Node.js", OriginTab, true, false},
+		{"js in node language composable", "js", "", []CodeContext{{Interface: "driver"}, {Language: "nodejs"}},
+			"Driver", "Node.js", OriginComposableLanguage, true, false},
+		{"javascript in unmapped node tab", "javascript", "", []CodeContext{{TabID: "node"}},
+			"JavaScript", "Node.js", OriginTab, true, false},
+		{"javascript in node content dir", "javascript", "node", nil,
+			"Node.js", "Node.js", OriginContentDir, true, false},
+		// Outside a Node.js context the flag changes nothing
+		{"javascript in the manual", "javascript", "manual", nil,
+			"JavaScript", "JavaScript", OriginLanguage, false, true},
+		{"javascript in python tab", "javascript", "", []CodeContext{{TabID: "python"}},
+			"Python", "Python", OriginTab, true, false},
+		{"javascript in mongosh context", "javascript", "node", []CodeContext{{Interface: "mongosh"}},
+			"MongoDB Shell", "MongoDB Shell", OriginComposableInterface, true, false},
+		{"python in node tab", "python", "", []CodeContext{{Interface: "driver"}, {TabID: "nodejs"}},
+			"Driver", "Driver", OriginComposableInterface, false, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if product, _ := determineProduct(tc.language, tc.contentDir, tc.contexts, mappings); product != tc.defaultProduct {
+				t.Errorf("default: expected %q, got %q", tc.defaultProduct, product)
+			}
+
+			ex := CodeExample{Language: tc.language}
+			classifyExample(&ex, tc.contentDir, tc.contexts, mappings, withFlag)
+			if ex.Product != tc.flagProduct || ex.Origin != tc.flagOrigin {
+				t.Errorf("with flag: expected %q (%s), got %q (%s)", tc.flagProduct, tc.flagOrigin, ex.Product, ex.Origin)
+			}
+			if ex.IsTestable != tc.flagIsTestable || ex.IsMaybeTestable != tc.flagIsMaybeTest {
+				t.Errorf("with flag: expected testable=%v maybe=%v, got testable=%v maybe=%v",
+					tc.flagIsTestable, tc.flagIsMaybeTest, ex.IsTestable, ex.IsMaybeTestable)
+			}
+		})
+	}
+}
+
+// TestGetLanguage tests the getLanguage function.
+func TestGetLanguage(t *testing.T) {
+	testCases := []struct {
+		name        string
+		options     map[string]string
+		defaultLang string
+		expected    string
+	}{
+		{"language option", map[string]string{"language": "python"}, "javascript", "python"},
+		{"default lang", map[string]string{}, "javascript", "javascript"},
+		{"empty default", map[string]string{}, "", "undefined"},
+		{"empty language option", map[string]string{"language": ""}, "go", "go"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			directive := rst.Directive{Options: tc.options}
+			result := getLanguage(directive, tc.defaultLang)
+			if result != tc.expected {
+				t.Errorf("getLanguage(%v, %q) = %q, expected %q",
+					tc.options, tc.defaultLang, result, tc.expected)
+			}
+		})
+	}
+}
+
+// TestProcessDirective tests the processDirective function.
+func TestProcessDirective(t *testing.T) {
+	mappings := &ProductMappings{
+		DriversTabIDToProduct:        map[string]string{"python": "Python", "nodejs": "Node.js"},
+		ComposableLanguageToProduct:  map[string]string{"python": "Python", "nodejs": "Node.js"},
+		ComposableInterfaceToProduct: map[string]string{"mongosh": "MongoDB Shell"},
+	}
+
+	testCases := []struct {
+		name            string
+		directive       rst.Directive
+		contentDir      string
+		contexts        []CodeContext
+		expectedCount   int
+		expectedType    string
+		expectedLang    string
+		expectedProduct string
+	}{
+		{
+			name: "literalinclude with tested path",
+			directive: rst.Directive{
+				Type:     rst.LiteralInclude,
+				Argument: "/code-examples/tested/python/example.py",
+				Options:  map[string]string{"language": "python"},
+			},
+			contentDir:      "pymongo-driver",
+			contexts:        nil,
+			expectedCount:   1,
+			expectedType:    "literalinclude",
+			expectedLang:    "python",
+			expectedProduct: "Python",
+		},
+		{
+			name: "code-block with language argument",
+			directive: rst.Directive{
+				Type:     rst.CodeBlock,
+				Argument: "javascript",
+				Options:  map[string]string{},
+			},
+			contentDir:      "node",
+			contexts:        nil,
+			expectedCount:   1,
+			expectedType:    "code-block",
+			expectedLang:    "javascript",
+			expectedProduct: "Node.js",
+		},
+		{
+			name: "code-block with json bypasses context",
+			directive: rst.Directive{
+				Type:     rst.CodeBlock,
+				Argument: "json",
+				Options:  map[string]string{},
+			},
+			contentDir:      "pymongo-driver",
+			contexts:        []CodeContext{{Language: "python"}},
+			expectedCount:   1,
+			expectedType:    "code-block",
+			expectedLang:    "json",
+			expectedProduct: "JSON",
+		},
+		{
+			name: "code is classified like code-block",
+			directive: rst.Directive{
+				Type:     rst.Code,
+				Argument: "python",
+				Options:  map[string]string{},
+			},
+			contentDir:      "pymongo-driver",
+			contexts:        nil,
+			expectedCount:   1,
+			expectedType:    "code",
+			expectedLang:    "python",
+			expectedProduct: "Python",
+		},
+		{
+			name: "io-code-block with input and output",
+			directive: rst.Directive{
+				Type:    rst.IoCodeBlock,
+				Options: map[string]string{"language": "python"},
+				InputDirective: &rst.SubDirective{
+					Argument: "/code-examples/input.py",
+					Options:  map[string]string{"language": "python"},
+				},
+				OutputDirective: &rst.SubDirective{
+					Argument: "/code-examples/output.txt",
+					Options:  map[string]string{"language": "text"},
+				},
+			},
+			contentDir:    "pymongo-driver",
+			contexts:      nil,
+			expectedCount: 2, // input + output
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			examples := processDirective(tc.directive, "/test/source.rst", tc.contentDir, tc.contexts, mappings, nil)
+
+			if len(examples) != tc.expectedCount {
+				t.Errorf("Expected %d examples, got %d", tc.expectedCount, len(examples))
+				return
+			}
+
+			if tc.expectedCount > 0 && tc.expectedType != "" {
+				if examples[0].Type != tc.expectedType {
+					t.Errorf("Expected type %q, got %q", tc.expectedType, examples[0].Type)
+				}
+			}
+
+			if tc.expectedLang != "" && examples[0].Language != tc.expectedLang {
+				t.Errorf("Expected language %q, got %q", tc.expectedLang, examples[0].Language)
+			}
+
+			if tc.expectedProduct != "" && examples[0].Product != tc.expectedProduct {
+				t.Errorf("Expected product %q, got %q", tc.expectedProduct, examples[0].Product)
+			}
+		})
+	}
+}
+
+// TestProcessDirectiveIOCodeBlock tests io-code-block processing in detail.
+func TestProcessDirectiveIOCodeBlock(t *testing.T) {
+	mappings := &ProductMappings{
+		DriversTabIDToProduct:        map[string]string{},
+		ComposableLanguageToProduct:  map[string]string{},
+		ComposableInterfaceToProduct: map[string]string{},
+	}
+
+	directive := rst.Directive{
+		Type:    rst.IoCodeBlock,
+		Options: map[string]string{},
+		InputDirective: &rst.SubDirective{
+			Argument: "/code-examples/tested/python/input.py",
+			Options:  map[string]string{"language": "python"},
+		},
+		OutputDirective: &rst.SubDirective{
+			Argument: "/code-examples/output.json",
+			Options:  map[string]string{"language": "json"},
+		},
+	}
+
+	examples := processDirective(directive, "/test/source.rst", "pymongo-driver", nil, mappings, nil)
+
+	if len(examples) != 2 {
+		t.Fatalf("Expected 2 examples, got %d", len(examples))
+	}
+
+	// Check input
+	input := examples[0]
+	if !input.IsInput {
+		t.Error("Expected first example to be input")
+	}
+	if input.IsOutput {
+		t.Error("Expected first example to not be output")
+	}
+	if !input.IsTested {
+		t.Error("Expected input to be tested (path contains /tested/)")
+	}
+	if input.Language != "python" {
+		t.Errorf("Expected input language 'python', got %q", input.Language)
+	}
+
+	// Check output
+	output := examples[1]
+	if !output.IsOutput {
+		t.Error("Expected second example to be output")
+	}
+	if output.IsInput {
+		t.Error("Expected second example to not be input")
+	}
+	if output.IsTested {
+		t.Error("Expected output to not be tested")
+	}
+	if output.Language != "json" {
+		t.Errorf("Expected output language 'json', got %q", output.Language)
+	}
+}
+
+// TestCollectCodeExamplesCommentedOut tests that code examples and includes inside RST
+// comment blocks are not counted.
+func TestCollectCodeExamplesCommentedOut(t *testing.T) {
+	testDataDir := filepath.Join("..", "..", "..", "testdata", "testable-code-test", "content", "test-project", "source")
+	filePath := filepath.Join(testDataDir, "with-commented-examples.rst")
+
+	examples, err := collectCodeExamples(filePath, "test-project", newIncludeWalk(0), &ProductMappings{})
+	if err != nil {
+		t.Fatalf("collectCodeExamples failed: %v", err)
+	}
+
+	var languages []string
+	for _, ex := range examples {
+		languages = append(languages, ex.Language)
+	}
+	// The commented-out code-block, literalinclude, and include (python-example.rst) are
+	// skipped; the block after an empty comment is not commented out
+	if want := []string{"python", "javascript"}; !reflect.DeepEqual(languages, want) {
+		t.Errorf("Expected examples %v, got %v", want, languages)
+	}
+}
+
+// TestCollectCodeExamplesIOOutputFirst tests an io-code-block whose output is written
+// before its input: the examples keep the right IsInput/IsOutput flags, with the input
+// first so the pair is still recognized by --io-as-one.
+func TestCollectCodeExamplesIOOutputFirst(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "page.txt")
+	content := `.. io-code-block::
+
+   .. output::
+      :language: json
+
+      { "_id": 1 }
+
+   .. input:: /code-examples/tested/python/find.py
+      :language: python
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	examples, err := collectCodeExamples(path, "pymongo-driver", newIncludeWalk(0), &ProductMappings{})
+	if err != nil {
+		t.Fatalf("collectCodeExamples failed: %v", err)
+	}
+	if len(examples) != 2 {
+		t.Fatalf("Expected input and output examples, got %+v", examples)
+	}
+	input, output := examples[0], examples[1]
+	if !input.IsInput || input.IsOutput || input.Language != "python" || !input.IsTested {
+		t.Errorf("Expected a tested python input first, got %+v", input)
+	}
+	if !output.IsOutput || output.IsInput || output.Language != "json" {
+		t.Errorf("Expected a json output second, got %+v", output)
+	}
+
+	analysis := &PageAnalysis{SourcePath: path, CodeExamples: examples}
+	report := BuildPageReport(analysis)
+	if pairs := CountIOPairsAsOne(&report, analysis); pairs != 1 || report.TotalExamples != 1 {
+		t.Errorf("Expected one io-code-block pair counted once, got %d pair(s) and %d example(s)", pairs, report.TotalExamples)
+	}
+}
+
+// TestProcessDirectiveIOCodeBlockOutputLanguage tests that an output sub-directive's own
+// :language: takes precedence over the parent io-code-block's :language:.
+func TestProcessDirectiveIOCodeBlockOutputLanguage(t *testing.T) {
+	content := `.. io-code-block::
+   :language: python
+   :copyable: true
+
+   .. input::
+
+      print(client.list_database_names())
+
+   .. output::
+      :language: console
+
+      ['admin', 'config', 'local']
+`
+	rstPath := filepath.Join(t.TempDir(), "io-console.rst")
+	if err := os.WriteFile(rstPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	directives, err := rst.ParseDirectives(rstPath)
+	if err != nil {
+		t.Fatalf("ParseDirectives failed: %v", err)
+	}
+	if len(directives) != 1 || directives[0].Type != rst.IoCodeBlock {
+		t.Fatalf("Expected 1 io-code-block, got %+v", directives)
+	}
+
+	mappings := &ProductMappings{
+		DriversTabIDToProduct:        map[string]string{"python": "Python"},
+		ComposableLanguageToProduct:  map[string]string{},
+		ComposableInterfaceToProduct: map[string]string{},
+	}
+	// Inside a Python driver tab, the input inherits the tab but the console output must not
+	contexts := []CodeContext{{TabID: "python"}}
+	examples := processDirective(directives[0], rstPath, "", contexts, mappings, nil)
+	if len(examples) != 2 {
+		t.Fatalf("Expected 2 examples, got %d", len(examples))
+	}
+
+	input, output := examples[0], examples[1]
+	if input.Language != "python" || input.Product != "Python" || !input.IsTestable {
+		t.Errorf("Expected testable Python input, got language=%q product=%q testable=%v",
+			input.Language, input.Product, input.IsTestable)
+	}
+	if output.Language != "console" {
+		t.Errorf("Expected output language console, got %q", output.Language)
+	}
+	if output.Product != "Shell" || output.Origin != OriginLanguage {
+		t.Errorf("Expected output product Shell from language, got %q from %s", output.Product, output.Origin)
+	}
+	if output.IsTestable {
+		t.Error("Expected console output to not be testable")
+	}
+}
+
+// TestParseFileContexts tests the parseFileContexts function.
+func TestParseFileContexts(t *testing.T) {
+	testDataDir := filepath.Join("..", "..", "..", "testdata", "testable-code-test", "content", "test-project", "source")
+
+	t.Run("file with tabs", func(t *testing.T) {
+		filePath := filepath.Join(testDataDir, "with-tabs.rst")
+		contexts, err := parseFileContexts(filePath)
+		if err != nil {
+			t.Fatalf("parseFileContexts failed: %v", err)
+		}
+
+		// Should find tab contexts
+		if len(contexts) == 0 {
+			t.Error("Expected to find contexts")
+		}
+
+		// Check that we found at least one tabid
+		foundTabID := false
+		for _, ctx := range contexts {
+			if ctx.TabID != "" {
+				foundTabID = true
+				break
+			}
+		}
+		if !foundTabID {
+			t.Error("Expected to find at least one TabID context")
+		}
+	})
+
+	t.Run("file with composable tutorial", func(t *testing.T) {
+		filePath := filepath.Join(testDataDir, "with-selected-content.rst")
+		contexts, err := parseFileContexts(filePath)
+		if err != nil {
+			t.Fatalf("parseFileContexts failed: %v", err)
+		}
+
+		// Should find composable context
+		if len(contexts) == 0 {
+			t.Error("Expected to find contexts")
+		}
+
+		// Check that we found language or interface from composable options
+		foundComposable := false
+		for _, ctx := range contexts {
+			if ctx.Language != "" || ctx.Interface != "" {
+				foundComposable = true
+				break
+			}
+		}
+		if !foundComposable {
+			t.Error("Expected to find composable context with language or interface")
+		}
+	})
+
+	t.Run("simple file without context", func(t *testing.T) {
+		filePath := filepath.Join(testDataDir, "simple-code.rst")
+		contexts, err := parseFileContexts(filePath)
+		if err != nil {
+			t.Fatalf("parseFileContexts failed: %v", err)
+		}
+
+		// Should return at least one empty context
+		if len(contexts) == 0 {
+			t.Error("Expected at least one context (even if empty)")
+		}
+	})
+}
+
+// TestParseSelectedContentBlocks tests the parseSelectedContentBlocks function.
+func TestParseSelectedContentBlocks(t *testing.T) {
+	testDataDir := filepath.Join("..", "..", "..", "testdata", "testable-code-test", "content", "test-project", "source")
+	filePath := filepath.Join(testDataDir, "with-selected-content.rst")
+
+	result, err := parseSelectedContentBlocks(filePath)
+	if err != nil {
+		t.Fatalf("parseSelectedContentBlocks failed: %v", err)
+	}
+
+	// The function should map include paths to their selections
+	// We expect to find mappings for the includes in selected-content blocks
+	if len(result) == 0 {
+		t.Log("No selected-content mappings found (this may be expected if includes don't resolve)")
+	}
+
+	// Check that any found mappings have valid selection values
+	for path, selection := range result {
+		if selection == "" {
+			t.Errorf("Empty selection for path %q", path)
+		}
+		t.Logf("Found mapping: %s -> %s", path, selection)
+	}
+}
+
+// TestCollectCodeExamples tests the collectCodeExamples function.
+func TestCollectCodeExamples(t *testing.T) {
+	testDataDir := filepath.Join("..", "..", "..", "testdata", "testable-code-test", "content", "test-project", "source")
+
+	mappings := &ProductMappings{
+		DriversTabIDToProduct:        map[string]string{"python": "Python", "nodejs": "Node.js", "java-sync": "Java (Sync)"},
+		ComposableLanguageToProduct:  map[string]string{"python": "Python", "nodejs": "Node.js", "go": "Go"},
+		ComposableInterfaceToProduct: map[string]string{"mongosh": "MongoDB Shell", "driver": "Driver"},
+	}
+
+	t.Run("simple code file", func(t *testing.T) {
+		filePath := filepath.Join(testDataDir, "simple-code.rst")
+		walk := newIncludeWalk(0)
+
+		examples, err := collectCodeExamples(filePath, "test-project", walk, mappings)
+		if err != nil {
+			t.Fatalf("collectCodeExamples failed: %v", err)
+		}
+
+		// Should find 4 code blocks: python, javascript, json, sh
+		if len(examples) != 4 {
+			t.Errorf("Expected 4 examples, got %d", len(examples))
+		}
+
+		// Check that we found the expected languages
+		languages := make(map[string]bool)
+		for _, ex := range examples {
+			languages[ex.Language] = true
+		}
+
+		expectedLangs := []string{"python", "javascript", "json", "sh"}
+		for _, lang := range expectedLangs {
+			if !languages[lang] {
+				t.Errorf("Expected to find language %q", lang)
+			}
+		}
+	})
+
+	t.Run("file with tabs", func(t *testing.T) {
+		filePath := filepath.Join(testDataDir, "with-tabs.rst")
+		walk := newIncludeWalk(0)
+
+		examples, err := collectCodeExamples(filePath, "test-project", walk, mappings)
+		if err != nil {
+			t.Fatalf("collectCodeExamples failed: %v", err)
+		}
+
+		// Should find 3 code blocks: python, javascript, java
+		if len(examples) < 3 {
+			t.Errorf("Expected at least 3 examples, got %d", len(examples))
+		}
+	})
+
+	t.Run("rendered only", func(t *testing.T) {
+		filePath := filepath.Join(testDataDir, "with-hidden-examples.rst")
+
+		// By default, hidden and collapsed examples are counted: 2 code-blocks, io-code-block
+		// input and output, a collapsed code-block and its include, and an expanded code-block
+		examples, err := collectCodeExamples(filePath, "test-project", newIncludeWalk(0), mappings)
+		if err != nil {
+			t.Fatalf("collectCodeExamples failed: %v", err)
+		}
+		if len(examples) != 7 {
+			t.Errorf("Expected 7 examples by default, got %d", len(examples))
+		}
+
+		walk := newIncludeWalk(0)
+		walk.renderedOnly = true
+		examples, err = collectCodeExamples(filePath, "test-project", walk, mappings)
+		if err != nil {
+			t.Fatalf("collectCodeExamples failed: %v", err)
+		}
+		// The visible code-block, the io-code-block input, and the expanded code-block
+		if len(examples) != 3 {
+			t.Fatalf("Expected 3 rendered examples, got %d", len(examples))
+		}
+		if !examples[1].IsInput {
+			t.Error("Expected the io-code-block input to be counted")
+		}
+		for _, ex := range examples {
+			if ex.IsOutput || ex.SourceFile != filePath {
+				t.Errorf("Expected no hidden output or collapsed include, got %+v", ex)
+			}
+		}
+	})
+
+	t.Run("extensionless includes outside tabs", func(t *testing.T) {
+		filePath := filepath.Join(testDataDir, "with-extensionless-tabs.rst")
+
+		// By default, the include after the tab set takes the first tab's context
+		examples, err := collectCodeExamples(filePath, "test-project", newIncludeWalk(0), mappings)
+		if err != nil {
+			t.Fatalf("collectCodeExamples failed: %v", err)
+		}
+		if len(examples) != 3 {
+			t.Fatalf("Expected 3 examples, got %d", len(examples))
+		}
+		if examples[2].Product != "Python" || examples[2].Origin != OriginTab {
+			t.Errorf("Expected default to attribute the outside example to Python (tab), got %q (%s)", examples[2].Product, examples[2].Origin)
+		}
+
+		// With --context-aware, only examples inside a tab take its context
+		walk := newIncludeWalk(0)
+		walk.contextAware = true
+		examples, err = collectCodeExamples(filePath, "test-project", walk, mappings)
+		if err != nil {
+			t.Fatalf("collectCodeExamples failed: %v", err)
+		}
+		if len(examples) != 3 {
+			t.Fatalf("Expected 3 examples, got %d", len(examples))
+		}
+		if examples[0].Product != "Python" || examples[1].Product != "Node.js" {
+			t.Errorf("Expected in-tab examples to be Python and Node.js, got %q and %q", examples[0].Product, examples[1].Product)
+		}
+		if examples[2].Product == "Python" || examples[2].Origin == OriginTab {
+			t.Errorf("Expected the outside example not to take tab context, got %q (%s)", examples[2].Product, examples[2].Origin)
+		}
+	})
+}
+
+// TestCollectCodeExamplesIncludeWalk tests include cycle detection and the include depth limit.
+func TestCollectCodeExamplesIncludeWalk(t *testing.T) {
+	testDataDir := filepath.Join("..", "..", "..", "testdata", "testable-code-test", "content", "test-project", "source")
+	mappings := &ProductMappings{}
+
+	t.Run("include cycle is recorded once", func(t *testing.T) {
+		filePath := filepath.Join(testDataDir, "with-include-cycle.rst")
+		walk := newIncludeWalk(0)
+
+		examples, err := collectCodeExamples(filePath, "test-project", walk, mappings)
+		if err != nil {
+			t.Fatalf("collectCodeExamples failed: %v", err)
+		}
+
+		// page, cycle-a, cycle-b - each counted once
+		if len(examples) != 3 {
+			t.Errorf("Expected 3 examples, got %d", len(examples))
+		}
+		if len(walk.cycles) != 1 {
+			t.Fatalf("Expected 1 cycle, got %d", len(walk.cycles))
+		}
+
+		chain := walk.cycles[0].Chain
+		if len(chain) != 4 {
+			t.Fatalf("Expected cycle chain of 4 files, got %v", chain)
+		}
+		if filepath.Base(chain[1]) != "cycle-a.rst" || filepath.Base(chain[3]) != "cycle-a.rst" {
+			t.Errorf("Expected chain to start and end at cycle-a.rst, got %v", chain)
+		}
+
+		got := formatIncludeChain(chain, filePath)
+		want := "with-include-cycle.rst -> includes/cycle-a.rst -> includes/cycle-b.rst -> includes/cycle-a.rst"
+		if got != filepath.FromSlash(want) {
+			t.Errorf("formatIncludeChain() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("repeated include is not a cycle", func(t *testing.T) {
+		filePath := filepath.Join(testDataDir, "with-nested-includes.rst")
+		walk := newIncludeWalk(0)
+
+		examples, err := collectCodeExamples(filePath, "test-project", walk, mappings)
+		if err != nil {
+			t.Fatalf("collectCodeExamples failed: %v", err)
+		}
+
+		if len(examples) != 3 {
+			t.Errorf("Expected 3 examples, got %d", len(examples))
+		}
+		if len(walk.cycles) != 0 {
+			t.Errorf("Expected no cycles, got %v", walk.cycles)
+		}
+		if walk.deepest != 2 {
+			t.Errorf("Expected deepest include level 2, got %d", walk.deepest)
+		}
+	})
+
+	t.Run("partial include", func(t *testing.T) {
+		filePath := filepath.Join(testDataDir, "with-partial-include.rst")
+		walk := newIncludeWalk(0)
+
+		examples, err := collectCodeExamples(filePath, "test-project", walk, mappings)
+		if err != nil {
+			t.Fatalf("collectCodeExamples failed: %v", err)
+		}
+
+		// shared-examples.rst has a python and a java block; only the python fragment is included
+		if len(examples) != 1 {
+			t.Fatalf("Expected 1 example, got %d: %v", len(examples), examples)
+		}
+		if examples[0].Language != "python" || filepath.Base(examples[0].SourceFile) != "shared-examples.rst" {
+			t.Errorf("Expected the python example from shared-examples.rst, got %s from %s", examples[0].Language, examples[0].SourceFile)
+		}
+	})
+
+	t.Run("overlapping partial includes", func(t *testing.T) {
+		filePath := filepath.Join(testDataDir, "with-overlapping-includes.rst")
+		walk := newIncludeWalk(0)
+
+		examples, err := collectCodeExamples(filePath, "test-project", walk, mappings)
+		if err != nil {
+			t.Fatalf("collectCodeExamples failed: %v", err)
+		}
+
+		// The python block is in all three includes and the java block in two; each is counted once
+		var languages []string
+		for _, ex := range examples {
+			languages = append(languages, ex.Language)
+		}
+		if !reflect.DeepEqual(languages, []string{"python", "java"}) {
+			t.Errorf("Expected python and java once each, got %v", languages)
+		}
+	})
+
+	t.Run("partial include with missing fragment", func(t *testing.T) {
+		filePath := filepath.Join(testDataDir, "with-missing-fragment.rst")
+		walk := newIncludeWalk(0)
+
+		examples, err := collectCodeExamples(filePath, "test-project", walk, mappings)
+		if err != nil {
+			t.Fatalf("collectCodeExamples failed: %v", err)
+		}
+
+		// The whole file is counted, and the problem becomes a page warning
+		if len(examples) != 2 {
+			t.Errorf("Expected both examples from the whole file, got %d", len(examples))
+		}
+		if len(walk.fragmentErrs) != 1 || !strings.Contains(walk.fragmentErrs[0], "start-rust") {
+			t.Fatalf("Expected a fragment error for start-rust, got %v", walk.fragmentErrs)
+		}
+		report := BuildPageReport(&PageAnalysis{SourcePath: filePath, FragmentErrors: walk.fragmentErrs})
+		if len(report.Warnings) != 1 || !strings.HasPrefix(report.Warnings[0], "partial include counted in full: ") {
+			t.Errorf("Expected a partial include warning, got %v", report.Warnings)
+		}
+
+		// The warning survives the analysis cache
+		cache, err := LoadAnalysisCache(filepath.Join(t.TempDir(), "analysis-cache.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cache.store(filePath, "fingerprint", nil, &PageAnalysis{FragmentErrors: walk.fragmentErrs})
+		var cached PageAnalysis
+		if !cache.load(filePath, "fingerprint", &cached) || !reflect.DeepEqual(cached.FragmentErrors, walk.fragmentErrs) {
+			t.Errorf("Expected cached fragment errors %v, got %v", walk.fragmentErrs, cached.FragmentErrors)
+		}
+	})
+
+	t.Run("missing include", func(t *testing.T) {
+		filePath := filepath.Join(testDataDir, "with-missing-include.rst")
+		walk := newIncludeWalk(0)
+
+		examples, err := collectCodeExamples(filePath, "test-project", walk, mappings)
+		if err != nil {
+			t.Fatalf("collectCodeExamples failed: %v", err)
+		}
+		if len(examples) == 0 {
+			t.Error("Expected the examples from the resolved include")
+		}
+		if len(walk.unresolved) != 1 || !strings.HasPrefix(walk.unresolved[0], "/includes/does-not-exist.rst: ") {
+			t.Fatalf("Expected does-not-exist.rst to be unresolved, got %v", walk.unresolved)
+		}
+
+		// The page warning is the same on every page, so the WarningLog prints it once
+		report := BuildPageReport(&PageAnalysis{SourcePath: filePath, UnresolvedIncludes: walk.unresolved})
+		if len(report.Warnings) != 1 || !strings.HasPrefix(report.Warnings[0], "failed to resolve include path /includes/does-not-exist.rst: ") {
+			t.Fatalf("Expected an unresolved include warning, got %v", report.Warnings)
+		}
+		log := NewWarningLog()
+		var buf bytes.Buffer
+		for i := 0; i < 3; i++ {
+			log.Warn(&buf, "  ", report.Warnings[0])
+		}
+		if strings.Count(buf.String(), "Warning:") != 1 {
+			t.Errorf("Expected the warning to be printed once, got:\n%s", buf.String())
+		}
+
+		// The warning survives the analysis cache
+		cache, err := LoadAnalysisCache(filepath.Join(t.TempDir(), "analysis-cache.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cache.store(filePath, "fingerprint", nil, &PageAnalysis{UnresolvedIncludes: walk.unresolved})
+		var cached PageAnalysis
+		if !cache.load(filePath, "fingerprint", &cached) || !reflect.DeepEqual(cached.UnresolvedIncludes, walk.unresolved) {
+			t.Errorf("Expected cached unresolved includes %v, got %v", walk.unresolved, cached.UnresolvedIncludes)
+		}
+	})
+
+	t.Run("max include depth", func(t *testing.T) {
+		filePath := filepath.Join(testDataDir, "with-nested-includes.rst")
+		walk := newIncludeWalk(1)
+
+		examples, err := collectCodeExamples(filePath, "test-project", walk, mappings)
+		if err != nil {
+			t.Fatalf("collectCodeExamples failed: %v", err)
+		}
+
+		// page and level 1 only
+		if len(examples) != 2 {
+			t.Errorf("Expected 2 examples, got %d", len(examples))
+		}
+		if walk.deepest != 1 {
+			t.Errorf("Expected deepest include level 1, got %d", walk.deepest)
+		}
+		if len(walk.depthLimited) != 1 {
+			t.Errorf("Expected 1 depth-limited include, got %v", walk.depthLimited)
+		}
+	})
+
+	t.Run("max file size", func(t *testing.T) {
+		filePath := filepath.Join(testDataDir, "with-nested-includes.rst")
+		var totalSize int64
+		for _, name := range []string{"with-nested-includes.rst", "includes/nested-level1.rst", "includes/nested-level2.rst"} {
+			info, err := os.Stat(filepath.Join(testDataDir, filepath.FromSlash(name)))
+			if err != nil {
+				t.Fatalf("Failed to stat %s: %v", name, err)
+			}
+			totalSize += info.Size()
+		}
+		pageInfo, err := os.Stat(filePath)
+		if err != nil {
+			t.Fatalf("Failed to stat page: %v", err)
+		}
+
+		// No limit: every file is scanned and counted
+		walk := newIncludeWalk(0)
+		if _, err := collectCodeExamples(filePath, "test-project", walk, mappings); err != nil {
+			t.Fatalf("collectCodeExamples failed: %v", err)
+		}
+		if walk.filesScanned != 3 || walk.bytesScanned != totalSize {
+			t.Errorf("Expected 3 files and %d bytes scanned, got %d files and %d bytes", totalSize, walk.filesScanned, walk.bytesScanned)
+		}
+
+		// A limit below the page's size skips the page without scanning it
+		walk = newIncludeWalk(0)
+		walk.maxFileSize = pageInfo.Size() - 1
+		examples, err := collectCodeExamples(filePath, "test-project", walk, mappings)
+		if err != nil {
+			t.Fatalf("collectCodeExamples failed: %v", err)
+		}
+		if len(examples) != 0 {
+			t.Errorf("Expected no examples from an oversized page, got %d", len(examples))
+		}
+		if len(walk.oversized) != 1 || walk.oversized[0].Path != filePath || walk.oversized[0].Size != pageInfo.Size() {
+			t.Fatalf("Expected the page to be recorded as oversized, got %v", walk.oversized)
+		}
+
+		report := BuildPageReport(&PageAnalysis{SourcePath: filePath, OversizedFiles: walk.oversized})
+		want := fmt.Sprintf("file not scanned, larger than max file size: (page) (%d bytes)", pageInfo.Size())
+		if len(report.Warnings) != 1 || report.Warnings[0] != want {
+			t.Errorf("Expected warning %q, got %v", want, report.Warnings)
+		}
+	})
+
+	t.Run("include parse error", func(t *testing.T) {
+		sourceDir := filepath.Join(t.TempDir(), "source")
+		if err := os.MkdirAll(filepath.Join(sourceDir, "includes"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		files := map[string]string{
+			"page.rst": ".. include:: /includes/good.rst\n\n.. include:: /includes/bad.rst\n\n" +
+				".. code-block:: python\n\n   print('page')\n",
+			"includes/good.rst": ".. code-block:: python\n\n   print('good')\n",
+			// A line longer than the scanner's buffer can't be parsed
+			"includes/bad.rst": ".. code-block:: python\n\n   " + strings.Repeat("x", 100*1024) + "\n",
+		}
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(sourceDir, filepath.FromSlash(name)), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		pagePath := filepath.Join(sourceDir, "page.rst")
 
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			examples := processDirective(tc.directive, "/test/source.rst", tc.contentDir, tc.contexts, mappings)
+		walk := newIncludeWalk(0)
+		examples, err := collectCodeExamples(pagePath, "test-project", walk, mappings)
+		if err != nil {
+			t.Fatalf("collectCodeExamples failed: %v", err)
+		}
+		// The page's own example and the good include's example are still collected
+		if len(examples) != 2 {
+			t.Errorf("Expected 2 examples, got %d", len(examples))
+		}
+		badPath := filepath.Join(sourceDir, "includes", "bad.rst")
+		if len(walk.parseErrors) != 1 || walk.parseErrors[0].Path != badPath {
+			t.Fatalf("Expected a parse error for %s, got %v", badPath, walk.parseErrors)
+		}
 
-			if len(examples) != tc.expectedCount {
-				t.Errorf("Expected %d examples, got %d", tc.expectedCount, len(examples))
-				return
-			}
+		report := BuildPageReport(&PageAnalysis{SourcePath: pagePath, IncludeParseErrors: walk.parseErrors})
+		want := "include not parsed: includes/bad.rst: " + walk.parseErrors[0].Error
+		if len(report.Warnings) != 1 || report.Warnings[0] != want {
+			t.Errorf("Expected warning %q, got %v", want, report.Warnings)
+		}
 
-			if tc.expectedCount > 0 && tc.expectedType != "" {
-				if examples[0].Type != tc.expectedType {
-					t.Errorf("Expected type %q, got %q", tc.expectedType, examples[0].Type)
-				}
-			}
+		// A parse error in the page itself still fails the page
+		if err := os.WriteFile(pagePath, []byte(files["includes/bad.rst"]), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := collectCodeExamples(pagePath, "test-project", newIncludeWalk(0), mappings); err == nil {
+			t.Error("Expected an error for a page that can't be parsed")
+		}
+	})
+}
 
-			if tc.expectedLang != "" && examples[0].Language != tc.expectedLang {
-				t.Errorf("Expected language %q, got %q", tc.expectedLang, examples[0].Language)
-			}
+// TestVerifyTestedExamples tests that missing /tested/ files are not counted as tested.
+func TestVerifyTestedExamples(t *testing.T) {
+	testDataDir := filepath.Join("..", "..", "..", "testdata", "testable-code-test", "content", "test-project", "source")
+	filePath := filepath.Join(testDataDir, "with-tested-refs.rst")
 
-			if tc.expectedProduct != "" && examples[0].Product != tc.expectedProduct {
-				t.Errorf("Expected product %q, got %q", tc.expectedProduct, examples[0].Product)
-			}
-		})
+	examples, err := collectCodeExamples(filePath, "test-project", newIncludeWalk(0), &ProductMappings{})
+	if err != nil {
+		t.Fatalf("collectCodeExamples failed: %v", err)
+	}
+	if len(examples) != 2 {
+		t.Fatalf("Expected 2 examples, got %d", len(examples))
+	}
+	for _, ex := range examples {
+		if !ex.IsTested {
+			t.Fatalf("Expected %s to be flagged tested before verification", ex.FilePath)
+		}
+	}
+
+	missing := verifyTestedExamples(examples)
+
+	if len(missing) != 1 || missing[0] != "/code-examples/tested/python/removed.py" {
+		t.Errorf("Expected removed.py to be missing, got %v", missing)
+	}
+	if !examples[0].IsTested {
+		t.Error("Expected existing tested file to stay tested")
+	}
+	if examples[1].IsTested {
+		t.Error("Expected missing tested file to not be counted as tested")
+	}
+
+	report := BuildPageReport(&PageAnalysis{
+		SourcePath:         filePath,
+		CodeExamples:       examples,
+		MissingTestedFiles: missing,
+	})
+	if report.TotalTested != 1 {
+		t.Errorf("Expected 1 tested example, got %d", report.TotalTested)
+	}
+	if len(report.Warnings) != 1 || !strings.Contains(report.Warnings[0], "removed.py") {
+		t.Errorf("Expected warning for removed.py, got %v", report.Warnings)
 	}
 }
 
-// TestProcessDirectiveIOCodeBlock tests io-code-block processing in detail.
-func TestProcessDirectiveIOCodeBlock(t *testing.T) {
-	mappings := &ProductMappings{
-		DriversTabIDToProduct:        map[string]string{},
-		ComposableLanguageToProduct:  map[string]string{},
-		ComposableInterfaceToProduct: map[string]string{},
+// TestBuildExampleSources tests capturing code example bodies for --include-source.
+func TestBuildExampleSources(t *testing.T) {
+	filePath := filepath.Join("..", "..", "..", "testdata", "testable-code-test", "content", "test-project", "source", "with-escaping-paths.rst")
+	examples, err := collectCodeExamples(filePath, "test-project", newIncludeWalk(0), &ProductMappings{})
+	if err != nil {
+		t.Fatalf("collectCodeExamples failed: %v", err)
 	}
 
-	directive := rst.Directive{
-		Type:    rst.IoCodeBlock,
-		Options: map[string]string{},
-		InputDirective: &rst.SubDirective{
-			Argument: "/code-examples/tested/python/input.py",
-			Options:  map[string]string{"language": "python"},
-		},
-		OutputDirective: &rst.SubDirective{
-			Argument: "/code-examples/output.json",
-			Options:  map[string]string{"language": "json"},
-		},
+	// Inline bodies are captured; included files aren't read
+	sources := BuildExampleSources(&PageAnalysis{CodeExamples: examples})
+	var bodies []string
+	for _, source := range sources {
+		bodies = append(bodies, source.Body)
+	}
+	if want := []string{"", "", "", `{ "ok": 1 }`}; !reflect.DeepEqual(bodies, want) {
+		t.Errorf("Bodies = %q, want %q", bodies, want)
 	}
 
-	examples := processDirective(directive, "/test/source.rst", "pymongo-driver", nil, mappings)
+	// Files under the size cap are embedded; missing and escaping files are skipped
+	embedIncludedFiles(examples, 1024)
+	if examples[0].Body != `print("tested")` || examples[1].Body != "" || examples[2].Body != "" {
+		t.Errorf("Unexpected embedded bodies: %q, %q, %q", examples[0].Body, examples[1].Body, examples[2].Body)
+	}
 
-	if len(examples) != 2 {
-		t.Fatalf("Expected 2 examples, got %d", len(examples))
+	examples[0].Body = ""
+	embedIncludedFiles(examples, 10)
+	if examples[0].Body != "" {
+		t.Errorf("Expected a file over the size cap to be skipped, got %q", examples[0].Body)
 	}
 
-	// Check input
-	input := examples[0]
-	if !input.IsInput {
-		t.Error("Expected first example to be input")
+	data, err := json.Marshal(PageReport{Rank: 1, Examples: BuildExampleSources(&PageAnalysis{CodeExamples: examples[3:]})})
+	if err != nil {
+		t.Fatalf("Failed to encode report: %v", err)
 	}
-	if input.IsOutput {
-		t.Error("Expected first example to not be output")
+	if !strings.Contains(string(data), `"Body":"{ \"ok\": 1 }"`) {
+		t.Errorf("Expected the body in JSON output, got %s", data)
 	}
-	if !input.IsTested {
-		t.Error("Expected input to be tested (path contains /tested/)")
+	if data, _ := json.Marshal(PageReport{Rank: 1}); strings.Contains(string(data), `"Examples"`) {
+		t.Errorf("Expected no Examples without --include-source, got %s", data)
 	}
-	if input.Language != "python" {
-		t.Errorf("Expected input language 'python', got %q", input.Language)
+}
+
+// TestCheckIncludeTargets tests flagging literalinclude and io-code-block files that
+// resolve outside the monorepo or don't exist.
+func TestCheckIncludeTargets(t *testing.T) {
+	monorepo := filepath.Join("..", "..", "..", "testdata", "testable-code-test")
+	filePath := filepath.Join(monorepo, "content", "test-project", "source", "with-escaping-paths.rst")
+
+	examples, err := collectCodeExamples(filePath, "test-project", newIncludeWalk(0), &ProductMappings{})
+	if err != nil {
+		t.Fatalf("collectCodeExamples failed: %v", err)
 	}
 
-	// Check output
-	output := examples[1]
-	if !output.IsOutput {
-		t.Error("Expected second example to be output")
+	root := monorepoRootOf(filePath, "test-project")
+	if absMonorepo, _ := filepath.Abs(monorepo); root != absMonorepo {
+		t.Fatalf("monorepoRootOf() = %q, want %q", root, absMonorepo)
 	}
-	if output.IsInput {
-		t.Error("Expected second example to not be input")
+
+	bad := checkIncludeTargets(examples, root)
+	want := []BadIncludeTarget{
+		{Type: "literalinclude", Path: "/../../../../outside/example.py", Outside: true},
+		{Type: "io-code-block", Path: "/code-examples/missing.js"},
 	}
-	if output.IsTested {
-		t.Error("Expected output to not be tested")
+	if !reflect.DeepEqual(bad, want) {
+		t.Errorf("checkIncludeTargets() = %+v, want %+v", bad, want)
 	}
-	if output.Language != "json" {
-		t.Errorf("Expected output language 'json', got %q", output.Language)
+
+	report := BuildPageReport(&PageAnalysis{SourcePath: filePath, CodeExamples: examples, BadIncludeTargets: bad})
+	wantWarnings := []string{
+		"literalinclude target outside the monorepo: /../../../../outside/example.py",
+		"io-code-block target not found: /code-examples/missing.js",
+	}
+	if !reflect.DeepEqual(report.Warnings, wantWarnings) {
+		t.Errorf("Warnings = %v, want %v", report.Warnings, wantWarnings)
+	}
+
+	// Without a monorepo root, only missing files are flagged
+	if bad := checkIncludeTargets(examples, ""); len(bad) != 2 || bad[0].Outside {
+		t.Errorf("checkIncludeTargets(no root) = %+v, want two not-found targets", bad)
 	}
 }
 
-// TestParseFileContexts tests the parseFileContexts function.
-func TestParseFileContexts(t *testing.T) {
+// TestBuildPageReportIncludeWarnings tests that include cycles and depth limits become page warnings.
+func TestBuildPageReportIncludeWarnings(t *testing.T) {
+	analysis := &PageAnalysis{
+		Rank:       1,
+		URL:        "https://example.com/page",
+		SourcePath: "/nonexistent/page.txt",
+		IncludeCycles: []IncludeCycle{
+			{Chain: []string{"/nonexistent/page.txt", "/nonexistent/a.rst", "/nonexistent/page.txt"}},
+		},
+		DepthLimitedIncludes: []string{"/nonexistent/b.rst", "/nonexistent/c.rst"},
+	}
+
+	report := BuildPageReport(analysis)
+
+	if len(report.Warnings) != 2 {
+		t.Fatalf("Expected 2 warnings, got %v", report.Warnings)
+	}
+	if !strings.HasPrefix(report.Warnings[0], "include cycle: ") || !strings.Contains(report.Warnings[0], " -> ") {
+		t.Errorf("Unexpected cycle warning: %q", report.Warnings[0])
+	}
+	if !strings.Contains(report.Warnings[1], "2 include(s) not followed") {
+		t.Errorf("Unexpected depth warning: %q", report.Warnings[1])
+	}
+}
+
+// TestMergeProjectComposables tests the MergeProjectComposables function.
+func TestMergeProjectComposables(t *testing.T) {
 	testDataDir := filepath.Join("..", "..", "..", "testdata", "testable-code-test", "content", "test-project", "source")
 
-	t.Run("file with tabs", func(t *testing.T) {
-		filePath := filepath.Join(testDataDir, "with-tabs.rst")
-		contexts, err := parseFileContexts(filePath)
-		if err != nil {
-			t.Fatalf("parseFileContexts failed: %v", err)
-		}
+	baseMappings := &ProductMappings{
+		DriversTabIDToProduct:        map[string]string{"python": "Python"},
+		ComposableLanguageToProduct:  map[string]string{"python": "Python"},
+		ComposableInterfaceToProduct: map[string]string{"driver": "Driver"},
+	}
 
-		// Should find tab contexts
-		if len(contexts) == 0 {
-			t.Error("Expected to find contexts")
-		}
+	t.Run("merges project composables", func(t *testing.T) {
+		sourcePath := filepath.Join(testDataDir, "simple-code.rst")
+		absPath, _ := filepath.Abs(sourcePath)
 
-		// Check that we found at least one tabid
-		foundTabID := false
-		for _, ctx := range contexts {
-			if ctx.TabID != "" {
-				foundTabID = true
-				break
-			}
-		}
-		if !foundTabID {
-			t.Error("Expected to find at least one TabID context")
-		}
-	})
+		merged := MergeProjectComposables(baseMappings, absPath)
 
-	t.Run("file with composable tutorial", func(t *testing.T) {
-		filePath := filepath.Join(testDataDir, "with-selected-content.rst")
-		contexts, err := parseFileContexts(filePath)
-		if err != nil {
-			t.Fatalf("parseFileContexts failed: %v", err)
+		// Should have base mappings
+		if merged.DriversTabIDToProduct["python"] != "Python" {
+			t.Error("Expected base mapping for python tab")
 		}
 
-		// Should find composable context
-		if len(contexts) == 0 {
-			t.Error("Expected to find contexts")
+		// Should have project-specific composables merged in
+		// The test project defines nodejs and go in language composable
+		if merged.ComposableLanguageToProduct["nodejs"] != "Node.js" {
+			t.Error("Expected project composable for nodejs")
 		}
-
-		// Check that we found language or interface from composable options
-		foundComposable := false
-		for _, ctx := range contexts {
-			if ctx.Language != "" || ctx.Interface != "" {
-				foundComposable = true
-				break
-			}
+		if merged.ComposableLanguageToProduct["go"] != "Go" {
+			t.Error("Expected project composable for go")
 		}
-		if !foundComposable {
-			t.Error("Expected to find composable context with language or interface")
+
+		// Should have interface composables
+		if merged.ComposableInterfaceToProduct["mongosh"] != "MongoDB Shell" {
+			t.Error("Expected project composable for mongosh interface")
 		}
 	})
 
-	t.Run("simple file without context", func(t *testing.T) {
-		filePath := filepath.Join(testDataDir, "simple-code.rst")
-		contexts, err := parseFileContexts(filePath)
-		if err != nil {
-			t.Fatalf("parseFileContexts failed: %v", err)
-		}
+	t.Run("returns base mappings for nonexistent path", func(t *testing.T) {
+		merged := MergeProjectComposables(baseMappings, "/nonexistent/path/file.rst")
 
-		// Should return at least one empty context
-		if len(contexts) == 0 {
-			t.Error("Expected at least one context (even if empty)")
+		// Should return base mappings unchanged
+		if merged.DriversTabIDToProduct["python"] != "Python" {
+			t.Error("Expected base mapping to be preserved")
 		}
 	})
 }
 
-// TestParseSelectedContentBlocks tests the parseSelectedContentBlocks function.
-func TestParseSelectedContentBlocks(t *testing.T) {
-	testDataDir := filepath.Join("..", "..", "..", "testdata", "testable-code-test", "content", "test-project", "source")
-	filePath := filepath.Join(testDataDir, "with-selected-content.rst")
+// TestProductProvenance tests recording whether a product came from rstspec.toml, the
+// project's snooty.toml, config, or a built-in rule.
+func TestProductProvenance(t *testing.T) {
+	sourcePath, _ := filepath.Abs(filepath.Join("..", "..", "..", "testdata", "testable-code-test", "content", "test-project", "source", "simple-code.rst"))
+	snootyPath := filepath.Join(filepath.Dir(filepath.Dir(sourcePath)), "snooty.toml")
 
-	result, err := parseSelectedContentBlocks(filePath)
-	if err != nil {
-		t.Fatalf("parseSelectedContentBlocks failed: %v", err)
+	base := &ProductMappings{
+		DriversTabIDToProduct:        map[string]string{"python": "Python"},
+		ComposableLanguageToProduct:  map[string]string{"python": "Python", "go": "Golang", "rust": "Rust"},
+		ComposableInterfaceToProduct: map[string]string{"driver": "Driver"},
 	}
+	mappings := MergeProjectComposables(base, sourcePath)
+	opts := &AnalyzeOptions{ContentDirOverrides: map[string]string{"cloud-docs": "Atlas"}}
 
-	// The function should map include paths to their selections
-	// We expect to find mappings for the includes in selected-content blocks
-	if len(result) == 0 {
-		t.Log("No selected-content mappings found (this may be expected if includes don't resolve)")
+	testCases := []struct {
+		name       string
+		language   string
+		contentDir string
+		contexts   []CodeContext
+		expected   ProductProvenance
+	}{
+		{"driver tab", "python", "", []CodeContext{{TabID: "python"}}, ProductProvenance{Source: ProvenanceRstspec}},
+		{"rstspec composable", "rust", "", []CodeContext{{Language: "rust"}}, ProductProvenance{Source: ProvenanceRstspec}},
+		{"project composable", "python", "", []CodeContext{{Language: "python"}}, ProductProvenance{Source: ProvenanceSnootyTOML, File: snootyPath}},
+		{"project override", "go", "", []CodeContext{{Language: "go"}}, ProductProvenance{Source: ProvenanceSnootyTOML, File: snootyPath, Overrides: "Golang"}},
+		{"content dir override", "python", "cloud-docs", nil, ProductProvenance{Source: ProvenanceConfig}},
+		{"built-in content dir", "python", "golang", nil, ProductProvenance{Source: ProvenanceBuiltIn}},
+		{"built-in language", "python", "", nil, ProductProvenance{Source: ProvenanceBuiltIn}},
 	}
 
-	// Check that any found mappings have valid selection values
-	for path, selection := range result {
-		if selection == "" {
-			t.Errorf("Empty selection for path %q", path)
-		}
-		t.Logf("Found mapping: %s -> %s", path, selection)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, _, provenance := explainProduct(tc.language, tc.contentDir, tc.contexts, mappings, opts)
+			if provenance != tc.expected {
+				t.Errorf("Expected provenance %+v, got %+v", tc.expected, provenance)
+			}
+		})
+	}
+
+	summary := ProvenanceSummary{}
+	summary.Add(&PageAnalysis{CodeExamples: []CodeExample{
+		{Product: "Go", Provenance: ProductProvenance{Source: ProvenanceSnootyTOML, File: snootyPath, Overrides: "Golang"}},
+		{Product: "Go", Provenance: ProductProvenance{Source: ProvenanceSnootyTOML, File: snootyPath, Overrides: "Golang"}},
+		{Product: "Python", Provenance: ProductProvenance{Source: ProvenanceRstspec}},
+	}})
+
+	var buf bytes.Buffer
+	PrintProvenanceReport(&buf, summary)
+	expected := "\nProduct provenance:\n" +
+		"  rstspec.toml\n" +
+		"        1  Python\n" +
+		"  snooty.toml (" + snootyPath + ")\n" +
+		"        2  Go (overrides rstspec.toml: Golang)\n"
+	if buf.String() != expected {
+		t.Errorf("Unexpected provenance report:\n%s\nexpected:\n%s", buf.String(), expected)
 	}
 }
 
-// TestCollectCodeExamples tests the collectCodeExamples function.
-func TestCollectCodeExamples(t *testing.T) {
-	testDataDir := filepath.Join("..", "..", "..", "testdata", "testable-code-test", "content", "test-project", "source")
+// TestUnknownContentDirs tests collecting content directories whose examples fell back to their language.
+func TestUnknownContentDirs(t *testing.T) {
+	mappings := &ProductMappings{}
+	opts := &AnalyzeOptions{ContentDirOverrides: map[string]string{"cloud-docs": "Atlas"}}
+	example := func(language, contentDir string) CodeExample {
+		ex := CodeExample{Language: language}
+		ex.Product, ex.Origin, ex.ProductReason, ex.Provenance = explainProduct(language, contentDir, nil, mappings, opts)
+		return ex
+	}
 
-	mappings := &ProductMappings{
-		DriversTabIDToProduct:        map[string]string{"python": "Python", "nodejs": "Node.js", "java-sync": "Java (Sync)"},
-		ComposableLanguageToProduct:  map[string]string{"python": "Python", "nodejs": "Node.js", "go": "Go"},
-		ComposableInterfaceToProduct: map[string]string{"mongosh": "MongoDB Shell", "driver": "Driver"},
+	unknown := UnknownContentDirs{}
+	unknown.Add(&PageAnalysis{ContentDir: "new-driver", CodeExamples: []CodeExample{
+		example("python", "new-driver"),
+		example("", "new-driver"),
+		example("json", "new-driver"), // Non-driver languages never use the content directory
+	}})
+	unknown.Add(&PageAnalysis{ContentDir: "new-driver", CodeExamples: []CodeExample{example("go", "new-driver")}})
+	unknown.Add(&PageAnalysis{ContentDir: "other-project", CodeExamples: []CodeExample{example("c", "other-project")}})
+	unknown.Add(&PageAnalysis{ContentDir: "cloud-docs", CodeExamples: []CodeExample{example("python", "cloud-docs")}})
+	unknown.Add(&PageAnalysis{ContentDir: "golang", CodeExamples: []CodeExample{example("python", "golang")}})
+	unknown.Add(&PageAnalysis{ContentDir: "quiet-project", CodeExamples: []CodeExample{example("yaml", "quiet-project")}})
+
+	var buf bytes.Buffer
+	PrintUnknownContentDirs(&buf, unknown)
+	expected := "\n2 content director(ies) with no product mapping (products fell back to the example language):\n" +
+		"      3  new-driver (2 page(s))\n" +
+		"      1  other-project (1 page(s))\n" +
+		"Map them in internal/projectinfo, or with content_dir_overrides or --content-dir-override <dir>=<product>.\n"
+	if buf.String() != expected {
+		t.Errorf("Unexpected unknown content directory report:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+	err := unknown.Err()
+	if err == nil || err.Error() != "found 2 content director(ies) with no product mapping: new-driver, other-project" {
+		t.Errorf("Unexpected --fail-on-unknown-content-dir error: %v", err)
 	}
 
-	t.Run("simple code file", func(t *testing.T) {
-		filePath := filepath.Join(testDataDir, "simple-code.rst")
-		visited := make(map[string]bool)
+	buf.Reset()
+	PrintUnknownContentDirs(&buf, UnknownContentDirs{})
+	if buf.String() != "" {
+		t.Errorf("Expected no output when every content directory is mapped, got %q", buf.String())
+	}
+	if err := (UnknownContentDirs{}).Err(); err != nil {
+		t.Errorf("Expected no error when every content directory is mapped, got %v", err)
+	}
+}
 
-		examples, err := collectCodeExamples(filePath, "test-project", visited, mappings)
-		if err != nil {
-			t.Fatalf("collectCodeExamples failed: %v", err)
-		}
+// TestPrintTimings tests the --timings phase totals, per-page statistics, and slowest pages.
+func TestPrintTimings(t *testing.T) {
+	timings := &RunTimings{
+		CSVParsing:      1500 * time.Microsecond,
+		URLMapping:      1200 * time.Millisecond,
+		ProductMappings: 40 * time.Microsecond,
+	}
+	timings.AddPage("www.mongodb.com/docs/a/", 30*time.Millisecond)
+	timings.AddPage("www.mongodb.com/docs/b/", 10*time.Millisecond)
+	timings.AddPage("www.mongodb.com/docs/c/", 2*time.Second)
+	timings.AddPage("www.mongodb.com/docs/d/", 20*time.Millisecond)
+
+	var buf bytes.Buffer
+	PrintTimings(&buf, timings)
+	expected := "\nTimings:\n" +
+		"  CSV parsing               2ms\n" +
+		"  URL mapping              1.2s\n" +
+		"  rstspec.toml             40µs\n" +
+		"  Page analysis           2.06s  (4 page(s))\n" +
+		"  Per page: min 10ms, median 25ms, max 2s\n" +
+		"Slowest pages:\n" +
+		"          2s  www.mongodb.com/docs/c/\n" +
+		"        30ms  www.mongodb.com/docs/a/\n" +
+		"        20ms  www.mongodb.com/docs/d/\n" +
+		"        10ms  www.mongodb.com/docs/b/\n"
+	if buf.String() != expected {
+		t.Errorf("Unexpected timings:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
 
-		// Should find 4 code blocks: python, javascript, json, sh
-		if len(examples) != 4 {
-			t.Errorf("Expected 4 examples, got %d", len(examples))
-		}
+	for i := 0; i < 20; i++ {
+		timings.AddPage("www.mongodb.com/docs/fast/", time.Millisecond)
+	}
+	if slowest := timings.SlowestPages(slowestPagesShown); len(slowest) != slowestPagesShown || slowest[0].URL != "www.mongodb.com/docs/c/" {
+		t.Errorf("Expected the %d slowest pages, got %+v", slowestPagesShown, slowest)
+	}
+	if timings.Pages[0].URL != "www.mongodb.com/docs/a/" {
+		t.Error("Expected SlowestPages to leave the pages in the order analyzed")
+	}
+}
+
+// TestApplyTestableOverrides tests merging config overrides over the built-in testable sets.
+func TestApplyTestableOverrides(t *testing.T) {
+	originalProducts := make(map[string]bool)
+	for k, v := range TestableProducts {
+		originalProducts[k] = v
+	}
+	originalDrivers := make(map[string]bool)
+	for k, v := range TestableDrivers {
+		originalDrivers[k] = v
+	}
+	defer func() {
+		TestableProducts = originalProducts
+		TestableDrivers = originalDrivers
+	}()
+
+	ApplyTestableOverrides(&config.Config{
+		TestableProducts: map[string]bool{"Rust": true, "rust": true, "Java": false},
+		TestableDrivers:  map[string]bool{"rust": true, "java": false},
+	})
+
+	if !isTestable("Rust", "") {
+		t.Error("Expected Rust to be testable after override")
+	}
+	if isTestable("Java", "") {
+		t.Error("Expected Java to not be testable after override")
+	}
+	if !isTestable("Python", "") {
+		t.Error("Expected built-in Python to stay testable")
+	}
 
-		// Check that we found the expected languages
-		languages := make(map[string]bool)
-		for _, ex := range examples {
-			languages[ex.Language] = true
+	names := getTestableDriverNames()
+	if !contains(strings.Join(names, ","), "rust") {
+		t.Errorf("Expected rust in testable drivers, got %v", names)
+	}
+	for _, name := range names {
+		if name == "java" {
+			t.Errorf("Expected java to be removed from testable drivers, got %v", names)
 		}
+	}
+}
 
-		expectedLangs := []string{"python", "javascript", "json", "sh"}
-		for _, lang := range expectedLangs {
-			if !languages[lang] {
-				t.Errorf("Expected to find language %q", lang)
-			}
-		}
-	})
+// TestApplyTestableOverridesEmpty tests that an empty config keeps the built-in sets.
+func TestApplyTestableOverridesEmpty(t *testing.T) {
+	productCount := len(TestableProducts)
+	driverCount := len(TestableDrivers)
 
-	t.Run("file with tabs", func(t *testing.T) {
-		filePath := filepath.Join(testDataDir, "with-tabs.rst")
-		visited := make(map[string]bool)
+	ApplyTestableOverrides(&config.Config{})
 
-		examples, err := collectCodeExamples(filePath, "test-project", visited, mappings)
-		if err != nil {
-			t.Fatalf("collectCodeExamples failed: %v", err)
-		}
+	if len(TestableProducts) != productCount || len(TestableDrivers) != driverCount {
+		t.Error("Expected empty config to leave built-in testable sets unchanged")
+	}
+}
 
-		// Should find 3 code blocks: python, javascript, java
-		if len(examples) < 3 {
-			t.Errorf("Expected at least 3 examples, got %d", len(examples))
-		}
-	})
+// useLocalRstspec points rstspec.toml at a local file defining the python driver tab and
+// clears the memoized product mappings, so GetProductMappings runs without the network.
+func useLocalRstspec(tb testing.TB) {
+	tb.Helper()
+	path := filepath.Join(tb.TempDir(), "rstspec.toml")
+	spec := `
+[tabs]
+drivers = [{id = "python", title = "Python"}]
+
+[[composables]]
+id = "language"
+title = "Language"
+default = "python"
+options = [{id = "python", title = "Python"}]
+`
+	if err := os.WriteFile(path, []byte(spec), 0644); err != nil {
+		tb.Fatalf("Failed to write rstspec.toml: %v", err)
+	}
+	rst.SetRstspecSource("", path)
+	tb.Cleanup(func() { rst.SetRstspecSource("", "") })
+	productMappingsMemo.Lock()
+	productMappingsMemo.mappings = nil
+	productMappingsMemo.Unlock()
 }
 
-// TestMergeProjectComposables tests the MergeProjectComposables function.
-func TestMergeProjectComposables(t *testing.T) {
-	testDataDir := filepath.Join("..", "..", "..", "testdata", "testable-code-test", "content", "test-project", "source")
+// TestGetProductMappingsMemoized tests that GetProductMappings returns the same instance.
+func TestGetProductMappingsMemoized(t *testing.T) {
+	useLocalRstspec(t)
 
-	baseMappings := &ProductMappings{
-		DriversTabIDToProduct:        map[string]string{"python": "Python"},
-		ComposableLanguageToProduct:  map[string]string{"python": "Python"},
-		ComposableInterfaceToProduct: map[string]string{"driver": "Driver"},
+	first, err := GetProductMappings()
+	if err != nil {
+		t.Fatalf("GetProductMappings failed: %v", err)
+	}
+	second, err := GetProductMappings()
+	if err != nil {
+		t.Fatalf("GetProductMappings failed: %v", err)
 	}
 
-	t.Run("merges project composables", func(t *testing.T) {
-		sourcePath := filepath.Join(testDataDir, "simple-code.rst")
-		absPath, _ := filepath.Abs(sourcePath)
+	if first != second {
+		t.Error("Expected repeated calls to return the same ProductMappings instance")
+	}
+	if first.DriversTabIDToProduct["python"] != "Python" {
+		t.Error("Expected mapping for python tab")
+	}
+}
 
-		merged := MergeProjectComposables(baseMappings, absPath)
+// BenchmarkGetProductMappings confirms repeated calls are served from memory.
+func BenchmarkGetProductMappings(b *testing.B) {
+	useLocalRstspec(b)
 
-		// Should have base mappings
-		if merged.DriversTabIDToProduct["python"] != "Python" {
-			t.Error("Expected base mapping for python tab")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := GetProductMappings(); err != nil {
+			b.Fatalf("GetProductMappings failed: %v", err)
 		}
+	}
+}
 
-		// Should have project-specific composables merged in
-		// The test project defines nodejs and go in language composable
-		if merged.ComposableLanguageToProduct["nodejs"] != "Node.js" {
-			t.Error("Expected project composable for nodejs")
-		}
-		if merged.ComposableLanguageToProduct["go"] != "Go" {
-			t.Error("Expected project composable for go")
+// TestAnalysisCache tests reusing cached page analyses while the page and its includes are unchanged.
+func TestAnalysisCache(t *testing.T) {
+	monorepo := t.TempDir()
+	sourceDir := filepath.Join(monorepo, "content", "proj", "source")
+	if err := os.MkdirAll(filepath.Join(sourceDir, "includes"), 0755); err != nil {
+		t.Fatalf("Failed to create source dir: %v", err)
+	}
+	includePath := filepath.Join(sourceDir, "includes", "example.rst")
+	writeFile := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
 		}
+	}
+	writeFile(filepath.Join(sourceDir, "page.txt"), "Page\n\n.. include:: /includes/example.rst\n")
+	writeFile(includePath, ".. code-block:: python\n\n   print(1)\n")
 
-		// Should have interface composables
-		if merged.ComposableInterfaceToProduct["mongosh"] != "MongoDB Shell" {
-			t.Error("Expected project composable for mongosh interface")
+	urlMapping := &config.URLMapping{
+		URLSlugToProject:    map[string]string{"proj": "proj"},
+		ProjectToContentDir: map[string]string{"proj": "proj"},
+		MonorepoPath:        monorepo,
+	}
+	mappings := &ProductMappings{DriversTabIDToProduct: map[string]string{"python": "Python"}}
+	entry := PageEntry{Rank: 1, URL: "www.mongodb.com/docs/proj/page/"}
+	cachePath := filepath.Join(t.TempDir(), "analysis-cache.json")
+
+	analyze := func(cache *AnalysisCache, mappings *ProductMappings) *PageAnalysis {
+		t.Helper()
+		analysis, err := AnalyzePage(entry, urlMapping, mappings, AnalyzeOptions{Cache: cache})
+		if err != nil {
+			t.Fatalf("AnalyzePage failed: %v", err)
 		}
-	})
+		return analysis
+	}
+	loadCache := func() *AnalysisCache {
+		t.Helper()
+		cache, err := LoadAnalysisCache(cachePath)
+		if err != nil {
+			t.Fatalf("LoadAnalysisCache failed: %v", err)
+		}
+		return cache
+	}
 
-	t.Run("returns base mappings for nonexistent path", func(t *testing.T) {
-		merged := MergeProjectComposables(baseMappings, "/nonexistent/path/file.rst")
+	// First run: nothing cached
+	cache := loadCache()
+	first := analyze(cache, mappings)
+	if hits, misses := cache.Stats(); hits != 0 || misses != 1 {
+		t.Errorf("Expected 0 hits and 1 miss, got %d and %d", hits, misses)
+	}
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
 
-		// Should return base mappings unchanged
-		if merged.DriversTabIDToProduct["python"] != "Python" {
-			t.Error("Expected base mapping to be preserved")
-		}
-	})
+	// Second run from disk: reused, with the page's own rank and URL
+	cache = loadCache()
+	entry.Rank = 7
+	second := analyze(cache, mappings)
+	if hits, _ := cache.Stats(); hits != 1 {
+		t.Errorf("Expected a cache hit, got %d", hits)
+	}
+	if second.Rank != 7 || second.SourcePath != first.SourcePath {
+		t.Errorf("Expected rank 7 and source %s, got %d and %s", first.SourcePath, second.Rank, second.SourcePath)
+	}
+	if !reflect.DeepEqual(second.CodeExamples, first.CodeExamples) || second.FilesScanned != 2 {
+		t.Errorf("Expected cached examples %v from 2 files, got %v from %d", first.CodeExamples, second.CodeExamples, second.FilesScanned)
+	}
+
+	// Different mappings: not reused
+	changedMappings := &ProductMappings{DriversTabIDToProduct: map[string]string{"python": "Python", "go": "Go"}}
+	analyze(cache, changedMappings)
+	if hits, misses := cache.Stats(); hits != 1 || misses != 1 {
+		t.Errorf("Expected changed mappings to miss, got %d hits and %d misses", hits, misses)
+	}
+
+	// Changed include: not reused, and the new example is found
+	writeFile(includePath, ".. code-block:: python\n\n   print(1)\n\nText\n\n.. code-block:: python\n\n   print(2)\n")
+	third := analyze(cache, mappings)
+	if hits, misses := cache.Stats(); hits != 1 || misses != 2 {
+		t.Errorf("Expected changed include to miss, got %d hits and %d misses", hits, misses)
+	}
+	if len(third.CodeExamples) != 2 {
+		t.Errorf("Expected 2 examples after the include changed, got %d", len(third.CodeExamples))
+	}
+
+	// A corrupt cache file is reported and replaced by an empty cache
+	writeFile(cachePath, "not json")
+	cache, err := LoadAnalysisCache(cachePath)
+	if err == nil {
+		t.Error("Expected an error for a corrupt cache file")
+	}
+	if cache == nil || len(cache.Entries) != 0 {
+		t.Errorf("Expected an empty cache, got %v", cache)
+	}
 }
 
 // TestAnalyzePage tests the AnalyzePage function.
@@ -1087,7 +3766,7 @@ func TestAnalyzePage(t *testing.T) {
 			URL:  "https://www.mongodb.com/docs/test-project/current/simple-code/",
 		}
 
-		analysis, err := AnalyzePage(entry, urlMapping, mappings)
+		analysis, err := AnalyzePage(entry, urlMapping, mappings, AnalyzeOptions{})
 		if err != nil {
 			t.Fatalf("AnalyzePage failed: %v", err)
 		}
@@ -1111,7 +3790,7 @@ func TestAnalyzePage(t *testing.T) {
 			URL:  "https://www.mongodb.com/docs/test-project/current/with-tabs/",
 		}
 
-		analysis, err := AnalyzePage(entry, urlMapping, mappings)
+		analysis, err := AnalyzePage(entry, urlMapping, mappings, AnalyzeOptions{})
 		if err != nil {
 			t.Fatalf("AnalyzePage failed: %v", err)
 		}
@@ -1136,13 +3815,61 @@ func TestAnalyzePage(t *testing.T) {
 		}
 	})
 
+	t.Run("applies content directory overrides", func(t *testing.T) {
+		entry := PageEntry{URL: "https://www.mongodb.com/docs/test-project/current/simple-code/"}
+
+		analysis, err := AnalyzePage(entry, urlMapping, mappings, AnalyzeOptions{
+			ContentDirOverrides: map[string]string{"test-project": "Python"},
+		})
+		if err != nil {
+			t.Fatalf("AnalyzePage failed: %v", err)
+		}
+
+		products := make(map[string]string)
+		for _, ex := range analysis.CodeExamples {
+			products[ex.Language] = ex.Product
+		}
+		// Driver languages take the override; non-driver languages keep their own product
+		if products["javascript"] != "Python" {
+			t.Errorf("Expected javascript example attributed to Python, got %q", products["javascript"])
+		}
+		if products["json"] != "JSON" {
+			t.Errorf("Expected json example to stay JSON, got %q", products["json"])
+		}
+	})
+
+	t.Run("explains file with tabs", func(t *testing.T) {
+		entry := PageEntry{URL: "https://www.mongodb.com/docs/test-project/current/with-tabs/"}
+
+		analysis, err := AnalyzePage(entry, urlMapping, mappings, AnalyzeOptions{})
+		if err != nil {
+			t.Fatalf("AnalyzePage failed: %v", err)
+		}
+
+		var buf bytes.Buffer
+		OutputExplain(&buf, analysis)
+		output := buf.String()
+
+		for _, want := range []string{
+			"[1] code-block",
+			"Product:        Python (origin: tab)",
+			"Context:        driver tab :tabid: python",
+			"Tested:         no - inline code is not tested",
+			"Testable:       yes - Python has test infrastructure",
+		} {
+			if !strings.Contains(output, want) {
+				t.Errorf("Expected explain output to contain %q, got:\n%s", want, output)
+			}
+		}
+	})
+
 	t.Run("returns error for nonexistent URL", func(t *testing.T) {
 		entry := PageEntry{
 			Rank: 99,
 			URL:  "https://www.mongodb.com/docs/nonexistent-project/current/page/",
 		}
 
-		_, err := AnalyzePage(entry, urlMapping, mappings)
+		_, err := AnalyzePage(entry, urlMapping, mappings, AnalyzeOptions{})
 		if err == nil {
 			t.Error("Expected error for nonexistent URL")
 		}
@@ -1154,7 +3881,7 @@ func TestAnalyzePage(t *testing.T) {
 			URL:  "https://www.mongodb.com/docs/test-project/current/with-selected-content/",
 		}
 
-		analysis, err := AnalyzePage(entry, urlMapping, mappings)
+		analysis, err := AnalyzePage(entry, urlMapping, mappings, AnalyzeOptions{})
 		if err != nil {
 			t.Fatalf("AnalyzePage failed: %v", err)
 		}
@@ -1172,3 +3899,368 @@ func TestAnalyzePage(t *testing.T) {
 	})
 }
 
+
+// TestChangedFilesSince tests filtering pages to those changed since a git ref or date.
+func TestChangedFilesSince(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	monorepo := t.TempDir()
+	sourceDir := filepath.Join(monorepo, "content", "atlas", "source")
+	if err := os.MkdirAll(sourceDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", monorepo,
+			"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	writePage := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(sourceDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	writePage("unchanged.txt", "Unchanged\n")
+	writePage("edited.txt", "Original\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "initial")
+	git("tag", "base")
+	writePage("edited.txt", "Edited\n")
+	git("commit", "-q", "-am", "edit")
+
+	changed, err := ChangedFilesSince(monorepo, "base")
+	if err != nil {
+		t.Fatalf("ChangedFilesSince failed: %v", err)
+	}
+	if len(changed) != 1 {
+		t.Fatalf("Expected 1 changed file since base, got %d: %v", len(changed), changed)
+	}
+
+	urlMapping := &config.URLMapping{
+		URLSlugToProject:    map[string]string{"atlas": "atlas"},
+		ProjectToContentDir: map[string]string{"atlas": "atlas"},
+		MonorepoPath:        monorepo,
+	}
+	entries := []PageEntry{
+		{Rank: 1, URL: "www.mongodb.com/docs/atlas/unchanged/"},
+		{Rank: 2, URL: "www.mongodb.com/docs/atlas/edited/"},
+		{Rank: 3, URL: "www.mongodb.com/docs/unknown-project/page/"},
+	}
+
+	filtered := filterChangedEntries(entries, changed, urlMapping)
+	if len(filtered) != 2 {
+		t.Fatalf("Expected 2 entries (edited + unresolvable), got %d: %v", len(filtered), filtered)
+	}
+	if filtered[0].Rank != 2 || filtered[1].Rank != 3 {
+		t.Errorf("Expected ranks 2 and 3, got %d and %d", filtered[0].Rank, filtered[1].Rank)
+	}
+
+	// A date before every commit includes both pages
+	changed, err = ChangedFilesSince(monorepo, "2000-01-01")
+	if err != nil {
+		t.Fatalf("ChangedFilesSince with date failed: %v", err)
+	}
+	if len(changed) != 2 {
+		t.Errorf("Expected 2 changed files since 2000-01-01, got %d: %v", len(changed), changed)
+	}
+
+	if _, err := ChangedFilesSince(t.TempDir(), "base"); err == nil {
+		t.Error("Expected error outside a git repository")
+	}
+}
+
+// TestChangedFilesInMonorepos tests that --since checks every monorepo's history, so
+// changed pages from an additional monorepo are kept.
+func TestChangedFilesInMonorepos(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	// commitPage creates a monorepo with one page in content/<contentDir>, tags it, and
+	// commits an edit to it
+	commitPage := func(contentDir string) string {
+		t.Helper()
+		monorepo := t.TempDir()
+		sourceDir := filepath.Join(monorepo, "content", contentDir, "source")
+		if err := os.MkdirAll(sourceDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		git := func(args ...string) {
+			t.Helper()
+			cmd := exec.Command("git", append([]string{"-C", monorepo,
+				"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("git %v failed: %v\n%s", args, err, out)
+			}
+		}
+		page := filepath.Join(sourceDir, "edited.txt")
+		if err := os.WriteFile(page, []byte("Original\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git("init", "-q")
+		git("add", ".")
+		git("commit", "-q", "-m", "initial")
+		git("tag", "base")
+		if err := os.WriteFile(page, []byte("Edited\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git("commit", "-q", "-am", "edit")
+		return monorepo
+	}
+	mainRepo := commitPage("atlas")
+	driverRepo := commitPage("rust")
+
+	changed, err := changedFilesInMonorepos([]string{mainRepo, driverRepo}, "base")
+	if err != nil {
+		t.Fatalf("changedFilesInMonorepos failed: %v", err)
+	}
+	if len(changed) != 2 {
+		t.Fatalf("Expected 1 changed file in each monorepo, got %d: %v", len(changed), changed)
+	}
+
+	urlMapping := &config.URLMapping{
+		URLSlugToProject:    map[string]string{"atlas": "atlas", "drivers/rust": "rust"},
+		ProjectToContentDir: map[string]string{"atlas": "atlas", "rust": "rust"},
+		ProjectToMonorepo:   map[string]string{"rust": driverRepo},
+		MonorepoPath:        mainRepo,
+	}
+	entries := []PageEntry{
+		{Rank: 1, URL: "www.mongodb.com/docs/atlas/edited/"},
+		{Rank: 2, URL: "www.mongodb.com/docs/drivers/rust/edited/"},
+	}
+	if filtered := filterChangedEntries(entries, changed, urlMapping); len(filtered) != 2 {
+		t.Errorf("Expected the changed page in each monorepo, got %v", filtered)
+	}
+
+	if _, err := changedFilesInMonorepos([]string{mainRepo, t.TempDir()}, "base"); err == nil {
+		t.Error("Expected error when a monorepo isn't a git repository")
+	}
+}
+
+// TestDetectContentType tests classifying pages as tutorial, reference, landing, or other.
+func TestDetectContentType(t *testing.T) {
+	sourceDir := filepath.Join(t.TempDir(), "content", "atlas", "source")
+	writePage := func(relPath, content string) string {
+		t.Helper()
+		path := filepath.Join(sourceDir, relPath)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	testCases := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{"product landing template", writePage("overview.txt", ":template: product-landing\n:noprevnext:\n\nTitle\n"), ContentTypeLanding},
+		{"template wins over path", writePage("reference/changes.txt", ":template: landing\n"), ContentTypeLanding},
+		{"changelog template", writePage("changelog.txt", ":template: changelog\n"), ContentTypeReference},
+		{"composable tutorial", writePage("connect.txt", ".. composable-tutorial::\n   :options: language\n"), ContentTypeTutorial},
+		{"root index", writePage("index.txt", "Title\n"), ContentTypeLanding},
+		{"nested index is not a landing page", writePage("tutorial/index.txt", "Title\n"), ContentTypeTutorial},
+		{"reference segment", writePage("reference/command/find.txt", "Title\n"), ContentTypeReference},
+		{"deepest segment wins", writePage("reference/tutorials/setup.txt", "Title\n"), ContentTypeTutorial},
+		{"quick start page", writePage("quick-start.txt", "Title\n"), ContentTypeTutorial},
+		{"unrecognized template uses path", writePage("api/search.txt", ":template: document\n"), ContentTypeReference},
+		{"other", writePage("security/auth.txt", "Title\n"), ContentTypeOther},
+		{"missing file uses path", filepath.Join(sourceDir, "tutorials", "missing.txt"), ContentTypeTutorial},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := DetectContentType(tc.path); got != tc.expected {
+				t.Errorf("DetectContentType(%s) = %q, want %q", tc.path, got, tc.expected)
+			}
+		})
+	}
+
+	if err := validateContentType("guide"); err == nil {
+		t.Error("Expected an error for an unknown content type")
+	}
+
+	urlMapping := &config.URLMapping{
+		URLSlugToProject:    map[string]string{"atlas": "atlas"},
+		ProjectToContentDir: map[string]string{"atlas": "atlas"},
+		MonorepoPath:        filepath.Dir(filepath.Dir(filepath.Dir(sourceDir))),
+	}
+	entries := []PageEntry{
+		{Rank: 1, URL: "www.mongodb.com/docs/atlas/security/auth/"},
+		{Rank: 2, URL: "www.mongodb.com/docs/atlas/quick-start/"},
+		{Rank: 3, URL: "www.mongodb.com/docs/unknown-project/page/"},
+	}
+	filtered := filterContentTypeEntries(entries, ContentTypeTutorial, urlMapping)
+	if len(filtered) != 2 || filtered[0].Rank != 2 || filtered[1].Rank != 3 {
+		t.Errorf("Expected the tutorial and the unresolvable page, got %v", filtered)
+	}
+}
+
+// TestRunExplainsOnlyPage tests that a text report of a single page is followed by its
+// --explain trace, and a report of several pages isn't.
+func TestRunExplainsOnlyPage(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	useLocalRstspec(t)
+
+	dir := filepath.Join(t.TempDir(), "content", "golang", "current", "source")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	page := ".. code-block:: go\n\n   fmt.Println(\"hello\")\n"
+	if err := os.WriteFile(filepath.Join(dir, "index.txt"), []byte(page), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func() string {
+		t.Helper()
+		outputFile := filepath.Join(t.TempDir(), "report.txt")
+		opts := reportOptions{dir: dir, outputFormat: "text", outputFile: outputFile, quiet: true}
+		if err := runTestableCode(nil, dir, opts); err != nil {
+			t.Fatalf("runTestableCode failed: %v", err)
+		}
+		data, err := os.ReadFile(outputFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	if output := run(); !strings.Contains(output, "TESTABLE CODE EXPLAIN") || !strings.Contains(output, "[1] code-block") {
+		t.Errorf("Expected the report of one page to explain it, got:\n%s", output)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "other.txt"), []byte(page), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if output := run(); strings.Contains(output, "TESTABLE CODE EXPLAIN") {
+		t.Errorf("Expected no explain trace for several pages, got:\n%s", output)
+	}
+}
+
+// TestDirEntries tests finding pages in a directory for --dir and analyzing them without URLs.
+func TestDirEntries(t *testing.T) {
+	projectDir := filepath.Join("..", "..", "..", "testdata", "testable-code-test", "content", "test-project")
+	entries, err := DirEntries(projectDir)
+	if err != nil {
+		t.Fatalf("DirEntries failed: %v", err)
+	}
+	if len(entries) == 0 || entries[0].Rank != 1 || entries[0].URL != "source/simple-code.rst" {
+		t.Fatalf("Expected source/simple-code.rst ranked first, got %+v", entries)
+	}
+	for i, entry := range entries {
+		if entry.Rank != i+1 || entry.ContentDir != "test-project" || !filepath.IsAbs(entry.SourcePath) {
+			t.Errorf("Unexpected entry: %+v", entry)
+		}
+		if strings.Contains(entry.URL, "includes/") || strings.Contains(entry.URL, "code-examples/") || strings.HasSuffix(entry.URL, ".md") {
+			t.Errorf("Expected includes, code examples, and non-RST files to be skipped, got %s", entry.URL)
+		}
+	}
+
+	// Pages found with --dir are analyzed without resolving their URL
+	analysis, err := AnalyzePage(entries[0], &config.URLMapping{}, &ProductMappings{}, AnalyzeOptions{})
+	if err != nil {
+		t.Fatalf("AnalyzePage failed: %v", err)
+	}
+	if analysis.SourcePath != entries[0].SourcePath || analysis.ContentDir != "test-project" || len(analysis.CodeExamples) == 0 {
+		t.Errorf("Unexpected analysis: %+v", analysis)
+	}
+
+	if _, err := DirEntries(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("Expected an error for a missing directory")
+	}
+}
+
+// TestInferContentDir tests inferring a --dir page's content directory from its path.
+func TestInferContentDir(t *testing.T) {
+	standalone := filepath.Join(t.TempDir(), "docs-golang")
+	if err := os.MkdirAll(filepath.Join(standalone, "source", "fundamentals"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{"monorepo", filepath.Join("/repo", "content", "golang", "current", "source", "index.txt"), "golang"},
+		{"standalone repo", filepath.Join(standalone, "source", "fundamentals", "crud.txt"), "docs-golang"},
+		{"no source directory", filepath.Join(t.TempDir(), "page.txt"), ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := inferContentDir(tc.path); got != tc.expected {
+				t.Errorf("inferContentDir(%s) = %q, want %q", tc.path, got, tc.expected)
+			}
+		})
+	}
+}
+
+// TestFilterPagePathEntries tests --page-path-filter matching source paths relative to the monorepo.
+func TestFilterPagePathEntries(t *testing.T) {
+	monorepo := filepath.Join("/home", "writer", "docs-mongodb-internal")
+	urlMapping := &config.URLMapping{
+		URLSlugToProject:    map[string]string{"drivers/go": "golang"},
+		ProjectToContentDir: map[string]string{"golang": "golang"},
+		MonorepoPath:        monorepo,
+	}
+	entries := []PageEntry{
+		{Rank: 1, URL: "www.mongodb.com/docs/drivers/go/current/fundamentals/crud/"},
+		{Rank: 2, URL: "www.mongodb.com/docs/drivers/go/current/quick-start/"},
+		{Rank: 3, URL: "www.mongodb.com/docs/unknown-project/page/"},
+		{Rank: 4, URL: "www.mongodb.com/docs/drivers/go/current/fundamentals/"},
+	}
+
+	testCases := []struct {
+		name     string
+		pattern  string
+		expected []int
+	}{
+		{"directory", "/source/fundamentals/", []int{1, 3}},
+		{"anchored at the monorepo root", "^content/golang/source/quick-start", []int{2, 3}},
+		{"absolute path doesn't match", "^/home/", []int{3}},
+		{"everything", ".", []int{1, 2, 3, 4}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filtered := filterPagePathEntries(entries, regexp.MustCompile(tc.pattern), urlMapping, []string{monorepo})
+			var ranks []int
+			for _, entry := range filtered {
+				ranks = append(ranks, entry.Rank)
+			}
+			if !reflect.DeepEqual(ranks, tc.expected) {
+				t.Errorf("Ranks = %v, want %v", ranks, tc.expected)
+			}
+		})
+	}
+}
+
+// TestAnalyzeOptions tests that the report, --explain, and --expand-includes share the
+// analysis flags.
+func TestAnalyzeOptions(t *testing.T) {
+	opts := reportOptions{
+		maxIncludeDepth:      2,
+		useComposableDefault: true,
+		includeSource:        true,
+		maxSourceBytes:       100,
+	}
+	analyzeOpts := opts.analyzeOptions()
+	if analyzeOpts.MaxIncludeDepth != 2 || !analyzeOpts.UseComposableDefault || !analyzeOpts.IncludeSource || analyzeOpts.MaxSourceBytes != 100 {
+		t.Errorf("Expected the analysis flags to be copied, got %+v", analyzeOpts)
+	}
+	if analyzeOpts.Warnings == nil {
+		t.Error("Expected a WarningLog")
+	}
+	if analyzeOpts.Cache != nil {
+		t.Error("Expected no analysis cache")
+	}
+}
@@ -0,0 +1,91 @@
+package testablecode
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// slowestPagesShown is the number of slowest pages listed by --timings.
+const slowestPagesShown = 10
+
+// PageTiming is how long one page took to analyze.
+type PageTiming struct {
+	URL      string
+	Duration time.Duration
+}
+
+// RunTimings records how long each phase of a run took, for --timings, so a slow run
+// can be traced to setup (the URL mapping or rstspec.toml) or to particular pages.
+type RunTimings struct {
+	CSVParsing      time.Duration
+	URLMapping      time.Duration
+	ProductMappings time.Duration // Loading rstspec.toml
+	Pages           []PageTiming  // Analysis time of each page, in the order analyzed
+}
+
+// AddPage records how long a page took to analyze.
+func (t *RunTimings) AddPage(url string, d time.Duration) {
+	t.Pages = append(t.Pages, PageTiming{URL: url, Duration: d})
+}
+
+// PageAnalysis returns the total time spent analyzing pages.
+func (t *RunTimings) PageAnalysis() time.Duration {
+	var total time.Duration
+	for _, page := range t.Pages {
+		total += page.Duration
+	}
+	return total
+}
+
+// SlowestPages returns up to n pages, slowest first.
+func (t *RunTimings) SlowestPages(n int) []PageTiming {
+	pages := append([]PageTiming(nil), t.Pages...)
+	sort.SliceStable(pages, func(i, j int) bool {
+		return pages[i].Duration > pages[j].Duration
+	})
+	if len(pages) > n {
+		pages = pages[:n]
+	}
+	return pages
+}
+
+// PrintTimings prints the time spent in each phase of the run, the minimum, median, and
+// maximum time to analyze a page, and the slowest pages.
+func PrintTimings(w io.Writer, t *RunTimings) {
+	fmt.Fprintln(w, "\nTimings:")
+	fmt.Fprintf(w, "  %-18s %10s\n", "CSV parsing", roundDuration(t.CSVParsing))
+	fmt.Fprintf(w, "  %-18s %10s\n", "URL mapping", roundDuration(t.URLMapping))
+	fmt.Fprintf(w, "  %-18s %10s\n", "rstspec.toml", roundDuration(t.ProductMappings))
+	fmt.Fprintf(w, "  %-18s %10s  (%d page(s))\n", "Page analysis", roundDuration(t.PageAnalysis()), len(t.Pages))
+	if len(t.Pages) == 0 {
+		return
+	}
+
+	durations := make([]time.Duration, len(t.Pages))
+	for i, page := range t.Pages {
+		durations[i] = page.Duration
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	median := durations[len(durations)/2]
+	if len(durations)%2 == 0 {
+		median = (durations[len(durations)/2-1] + median) / 2
+	}
+	fmt.Fprintf(w, "  Per page: min %s, median %s, max %s\n",
+		roundDuration(durations[0]), roundDuration(median), roundDuration(durations[len(durations)-1]))
+
+	fmt.Fprintln(w, "Slowest pages:")
+	for _, page := range t.SlowestPages(slowestPagesShown) {
+		fmt.Fprintf(w, "  %10s  %s\n", roundDuration(page.Duration), page.URL)
+	}
+}
+
+// roundDuration rounds a duration for display: to the millisecond, or to the
+// microsecond if it's shorter than a millisecond.
+func roundDuration(d time.Duration) time.Duration {
+	if d < time.Millisecond {
+		return d.Round(time.Microsecond)
+	}
+	return d.Round(time.Millisecond)
+}
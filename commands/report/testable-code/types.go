@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/grove-platform/audit-cli/internal/config"
 	"github.com/grove-platform/audit-cli/internal/rst"
 	"github.com/grove-platform/audit-cli/internal/snooty"
 )
@@ -13,6 +14,10 @@ import (
 type PageEntry struct {
 	Rank int
 	URL  string
+	// SourcePath and ContentDir are set for pages found with --dir, which are analyzed
+	// from their source file instead of resolving URL.
+	SourcePath string
+	ContentDir string
 }
 
 // CodeExample represents a single code example found in a page.
@@ -35,10 +40,21 @@ type CodeExample struct {
 	// (javascript, shell) but lacks proper context to determine definitively.
 	// These are grey-area examples that may need manual review.
 	IsMaybeTestable bool
+	// Origin records which mechanism determined Product: tab, composable-language,
+	// composable-interface, content-dir, language, or unknown
+	Origin string
+	// ProductReason describes the specific context that determined Product (e.g., "driver tab :tabid: python")
+	ProductReason string
+	// Provenance records which configuration supplied the mapping that determined Product
+	Provenance ProductProvenance
 	// FilePath is the path to the included file (for literalinclude or io-code-block)
 	FilePath string
 	// SourceFile is the RST file containing this code example
 	SourceFile string
+	// Body is the example's code: the inline content of a code-block, code, fenced, or
+	// io-code-block example, or with AnalyzeOptions.MaxSourceBytes the contents of the
+	// file it includes. Only set with AnalyzeOptions.IncludeSource.
+	Body string `json:",omitempty"`
 }
 
 // PageAnalysis represents the analysis results for a single page.
@@ -47,8 +63,127 @@ type PageAnalysis struct {
 	URL          string
 	SourcePath   string
 	ContentDir   string
+	Version      string // Version directory the URL resolved to (e.g., current, v8.0); empty if non-versioned
 	Error        string // Non-empty if page could not be analyzed
 	CodeExamples []CodeExample
+	// RequestedVersion is the driver version in the URL when --driver-version-fallback
+	// resolved the page to a different version directory (Version).
+	RequestedVersion string
+	// RedirectedFrom and RedirectedTo are the retired and current URL slugs when the URL
+	// was resolved through a slug redirect (see config.DefaultSlugRedirects).
+	RedirectedFrom string
+	RedirectedTo   string
+	// Monorepo is the additional monorepo (--monorepo-path) the page's project was found
+	// in; empty for the main monorepo.
+	Monorepo string
+	// IncludeDepth is the deepest include level followed (the page itself is level 0).
+	IncludeDepth int
+	// IncludeCycles lists include chains that looped back to a file already being processed.
+	IncludeCycles []IncludeCycle
+	// DepthLimitedIncludes lists includes not followed because of AnalyzeOptions.MaxIncludeDepth.
+	DepthLimitedIncludes []string
+	// MissingTestedFiles lists /tested/ references that do not exist on disk (see AnalyzeOptions.VerifyTested).
+	MissingTestedFiles []string
+	// BadIncludeTargets lists literalinclude and io-code-block files that resolve outside
+	// the monorepo or don't exist (see AnalyzeOptions.StrictPaths).
+	BadIncludeTargets []BadIncludeTarget
+	// OversizedFiles lists files not scanned because of AnalyzeOptions.MaxFileSize.
+	OversizedFiles []OversizedFile
+	// IncludeParseErrors lists included files that could not be parsed. Their code
+	// examples are missing, but the rest of the page is still analyzed.
+	IncludeParseErrors []IncludeParseError
+	// FragmentErrors lists partial includes whose :start-after: or :end-before: text was
+	// not found in the included file. The whole file was counted instead.
+	FragmentErrors []string
+	// UnresolvedIncludes lists includes whose path couldn't be resolved, as "path: error".
+	// Their code examples are missing, but the rest of the page is still analyzed.
+	UnresolvedIncludes []string
+	// FilesScanned is the number of files (the page and its includes) that were scanned.
+	FilesScanned int
+	// BytesScanned is the total size of the files that were scanned.
+	BytesScanned int64
+}
+
+// AnalyzeOptions controls how AnalyzePage traverses a page's includes.
+type AnalyzeOptions struct {
+	// MaxIncludeDepth limits how many levels of includes are followed (0 = unlimited).
+	MaxIncludeDepth int
+	// VerifyTested checks that each /tested/ reference exists on disk. Missing files are
+	// recorded on the PageAnalysis and the example is not counted as tested.
+	VerifyTested bool
+	// StrictPaths checks that each literalinclude and io-code-block file resolves inside
+	// the monorepo and exists. Problems are recorded on the PageAnalysis.
+	StrictPaths bool
+	// ContentDirOverrides maps content directories to products and is consulted before
+	// the built-in mapping in projectinfo.GetProductFromContentDir (e.g., "cloud-docs" →
+	// "Atlas"). Set from content_dir_overrides in .audit-cli.yaml and --content-dir-override.
+	ContentDirOverrides map[string]string
+	// MaxFileSize skips any source or include file larger than this many bytes (0 = unlimited).
+	// Skipped files are recorded on the PageAnalysis.
+	MaxFileSize int64
+	// JavaScriptAsNodeJS attributes javascript/js examples to Node.js when they are in a
+	// Node.js driver context (a Node.js driver tab or language composable, or the node
+	// content directory), ahead of any other context. Off by default, because javascript
+	// is also used for browser and other non-driver code.
+	// Set from --javascript-as-nodejs-in-context.
+	JavaScriptAsNodeJS bool
+	// UseComposableDefault gives a code example with no language the default option of
+	// the language composable its composable tutorial offers (see
+	// ProductMappings.ComposableDefaults), instead of "undefined". Off by default,
+	// because it changes attribution.
+	// Set from --use-composable-default.
+	UseComposableDefault bool
+	// ContextAware gives a code example outside any tab or selected-content block only
+	// the file-wide composable-tutorial context, instead of the first tab in the file.
+	ContextAware bool
+	// RenderedOnly skips code examples that aren't rendered when the page loads: those with
+	// :visible: false and those inside a collapsed .. collapsible:: block.
+	RenderedOnly bool
+	// IncludeSource keeps each code example's Body (see CodeExample.Body).
+	IncludeSource bool
+	// MaxSourceBytes, with IncludeSource, also embeds the contents of each included file
+	// up to this many bytes as the example's Body (0 = inline bodies only).
+	MaxSourceBytes int64
+	// Cache reuses the collected code examples of unchanged pages (nil = no caching).
+	Cache *AnalysisCache
+	// Warnings de-duplicates the page warnings printed across pages, so a problem in a
+	// shared include is printed once (nil = print every warning).
+	Warnings *WarningLog
+}
+
+// BadIncludeTarget records a literalinclude or io-code-block file that failed the
+// AnalyzeOptions.StrictPaths check.
+type BadIncludeTarget struct {
+	// Type is the directive type: literalinclude or io-code-block
+	Type string
+	// Path is the file path as written in the directive
+	Path string
+	// Outside is true if the path resolves outside the monorepo; otherwise the file doesn't exist
+	Outside bool
+}
+
+// OversizedFile records a file that was not scanned because it exceeded AnalyzeOptions.MaxFileSize.
+type OversizedFile struct {
+	Path string
+	Size int64
+}
+
+// IncludeParseError records an included file whose directives could not be parsed.
+type IncludeParseError struct {
+	Path  string
+	Error string
+}
+
+// FailureGroup counts the pages that failed to analyze for the same reason.
+type FailureGroup struct {
+	Reason string
+	Count  int
+}
+
+// IncludeCycle records an include chain that looped back on itself.
+// Chain starts at the page's source file and ends with the repeated file.
+type IncludeCycle struct {
+	Chain []string
 }
 
 // ProductStats holds statistics for a single product/language.
@@ -60,6 +195,72 @@ type ProductStats struct {
 	TestedCount        int
 	TestableCount      int
 	MaybeTestableCount int
+	// PartiallyTested is true when some, but not all, testable examples are tested
+	// (0 < TestedCount < TestableCount). These are the cheapest products to finish
+	// converting to tested examples.
+	PartiallyTested bool
+	// ByOrigin counts examples by the mechanism that determined the product
+	// (see the Origin* constants). Useful for validating context inheritance.
+	ByOrigin map[string]int
+}
+
+// Error categories classify why a page could not be analyzed (PageReport.ErrorCategory).
+// Unlike the error text, they are stable, so downstream tooling can switch on them.
+const (
+	// ErrorCategoryInvalidURL means the URL has no /docs/ path (config.ErrInvalidURL).
+	ErrorCategoryInvalidURL = "invalid-url"
+	// ErrorCategorySlugNotResolved means no project matches the URL (config.ErrSlugNotResolved).
+	ErrorCategorySlugNotResolved = "slug-not-resolved"
+	// ErrorCategoryNoContentDir means the project isn't in the monorepo (config.ErrNoContentDir).
+	ErrorCategoryNoContentDir = "no-content-dir"
+	// ErrorCategorySourceNotFound means the page's source file doesn't exist (config.ErrSourceNotFound).
+	ErrorCategorySourceNotFound = "source-not-found"
+	// ErrorCategoryOther is any other error, such as a source file that can't be parsed.
+	ErrorCategoryOther = "other"
+)
+
+// Product origins record how determineProduct attributed a code example to a product.
+const (
+	// OriginTab means the product came from a driver tab's :tabid:.
+	OriginTab = "tab"
+	// OriginComposableLanguage means the product came from a language composable or
+	// a selected-content :selections: value.
+	OriginComposableLanguage = "composable-language"
+	// OriginComposableInterface means the product came from an interface composable.
+	OriginComposableInterface = "composable-interface"
+	// OriginContentDir means the product came from the page's content directory.
+	OriginContentDir = "content-dir"
+	// OriginLanguage means the product came from the example's own language.
+	OriginLanguage = "language"
+	// OriginUnknown means no product could be determined.
+	OriginUnknown = "unknown"
+)
+
+// Product provenances record where the mapping that determined a product was defined.
+const (
+	// ProvenanceRstspec means the tab ID or composable mapping came from rstspec.toml.
+	ProvenanceRstspec = "rstspec.toml"
+	// ProvenanceSnootyTOML means the composable mapping came from the project's snooty.toml.
+	ProvenanceSnootyTOML = "snooty.toml"
+	// ProvenanceConfig means the content directory mapping came from content_dir_overrides
+	// in .audit-cli.yaml or --content-dir-override.
+	ProvenanceConfig = "config"
+	// ProvenanceBuiltIn means the product came from audit-cli's own rules (the built-in
+	// content directory and language mappings, and the MongoDB Shell and Node.js handling).
+	ProvenanceBuiltIn = "built-in"
+)
+
+// ProductProvenance records where the mapping that determined a code example's product
+// was defined. Used by --provenance to check that project composables override
+// rstspec.toml as intended.
+type ProductProvenance struct {
+	// Source is one of the Provenance* constants.
+	Source string
+	// File is the snooty.toml that defined the composable (ProvenanceSnootyTOML only).
+	File string
+	// Overrides is the product rstspec.toml maps the same composable ID to, if the
+	// project's snooty.toml maps it to a different product.
+	Overrides string
 }
 
 // PageReport holds the complete analysis for a page with aggregated stats.
@@ -68,6 +269,7 @@ type PageReport struct {
 	URL                string
 	SourcePath         string
 	ContentDir         string
+	Version            string
 	Error              string
 	TotalExamples      int
 	TotalInput         int
@@ -76,6 +278,31 @@ type PageReport struct {
 	TotalTestable      int
 	TotalMaybeTestable int
 	ByProduct          map[string]*ProductStats
+	IncludeDepth       int
+	Warnings           []string // Non-fatal problems found while analyzing the page
+	// SharedExamples is the number of examples from shared includes that --own-content-only
+	// excluded from the totals.
+	SharedExamples int `json:",omitempty" toml:",omitempty"`
+	// Monorepo is the additional monorepo the page was found in; empty for the main monorepo.
+	Monorepo string `json:",omitempty" toml:",omitempty"`
+	// ErrorCategory classifies Error with one of the ErrorCategory* constants, so tooling
+	// can switch on why a page failed without parsing the error text.
+	ErrorCategory string `json:",omitempty" toml:",omitempty"`
+	// Examples lists each code example with its code, for --include-source (JSON only).
+	Examples []ExampleSource `json:",omitempty" toml:"-"`
+}
+
+// ExampleSource is one code example on a page with its code, for --include-source.
+// Bodies make the report much larger, so they're only included on request.
+type ExampleSource struct {
+	Type       string
+	Language   string
+	Product    string
+	IsInput    bool   `json:",omitempty"`
+	IsOutput   bool   `json:",omitempty"`
+	FilePath   string `json:",omitempty"`
+	SourceFile string
+	Body       string
 }
 
 // TestableProducts lists the products that have test infrastructure.
@@ -116,6 +343,10 @@ var TestableProducts = map[string]bool{
 	"mongosh":       true,
 }
 
+// DefaultBaselineProducts are the products added by --baseline-products default: the
+// testable products, so dashboards get the same columns for every page.
+var DefaultBaselineProducts = []string{"Python", "Node.js", "Go", "Java (Sync)", "C#", "MongoDB Shell"}
+
 // MaybeTestableProducts lists products that COULD be testable but lack proper context.
 //
 // These are "grey area" examples where the language (javascript, shell) could represent
@@ -144,6 +375,30 @@ var TestableDrivers = map[string]bool{
 	// Note: mongodb-shell has test infrastructure but is not a driver (use --filter mongosh)
 }
 
+// ApplyTestableOverrides merges the testable_products and testable_drivers settings
+// from the config file over the built-in TestableProducts and TestableDrivers.
+//
+// An entry set to true adds a product or driver (e.g., when test infrastructure is added
+// for Rust); an entry set to false removes a built-in one. Products are matched by the
+// exact name used in reports, so add both the display name and the internal ID
+// (e.g., "Rust" and "rust"). When the config has no overrides, the built-in sets are
+// unchanged.
+func ApplyTestableOverrides(cfg *config.Config) {
+	applyOverrides(TestableProducts, cfg.TestableProducts)
+	applyOverrides(TestableDrivers, cfg.TestableDrivers)
+}
+
+// applyOverrides sets or removes each override key in set.
+func applyOverrides(set map[string]bool, overrides map[string]bool) {
+	for name, enabled := range overrides {
+		if enabled {
+			set[name] = true
+		} else {
+			delete(set, name)
+		}
+	}
+}
+
 // ProductMappings holds the mappings from rstspec.toml for resolving
 // tab IDs and composable options to human-readable product names.
 //
@@ -172,6 +427,20 @@ type ProductMappings struct {
 	// Example: "mongosh" → "MongoDB Shell", "compass" → "Compass"
 	// Loaded from [[composables]] where id="interface" in rstspec.toml.
 	ComposableInterfaceToProduct map[string]string
+
+	// ProjectSnootyPath is the snooty.toml whose composables MergeProjectComposables
+	// merged in. Empty for the rstspec.toml mappings.
+	ProjectSnootyPath string
+
+	// ProjectComposableLanguages and ProjectComposableInterfaces hold the composable IDs
+	// defined in ProjectSnootyPath, each mapped to the product rstspec.toml gives the
+	// same ID (empty if rstspec.toml doesn't define it).
+	ProjectComposableLanguages  map[string]string
+	ProjectComposableInterfaces map[string]string
+
+	// ComposableDefaults maps the composable IDs defined in ProjectSnootyPath to their
+	// default option. Example: "language" → "python"
+	ComposableDefaults map[string]string
 }
 
 // LoadProductMappings fetches rstspec.toml and builds the product mappings.
@@ -184,7 +453,7 @@ type ProductMappings struct {
 //
 // If the network is unavailable, it falls back to an expired cache if available.
 func LoadProductMappings() (*ProductMappings, error) {
-	rstspec, err := rst.FetchRstspec()
+	rstspec, err := rst.GetRstspec()
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch rstspec.toml: %w", err)
 	}
@@ -198,6 +467,33 @@ func LoadProductMappings() (*ProductMappings, error) {
 	return mappings, nil
 }
 
+// productMappingsMemo holds the process-wide ProductMappings memoized by GetProductMappings.
+var productMappingsMemo struct {
+	sync.Mutex
+	mappings *ProductMappings
+}
+
+// GetProductMappings returns the product mappings, building them at most once per process.
+//
+// The first call loads the mappings via LoadProductMappings; every later call returns
+// the same *ProductMappings without refetching or re-parsing rstspec.toml. Errors are
+// not memoized, so the call after a failure retries. Callers must treat the returned
+// mappings as read-only. Use MergeProjectComposables to obtain a per-project copy with
+// snooty.toml overrides.
+func GetProductMappings() (*ProductMappings, error) {
+	productMappingsMemo.Lock()
+	defer productMappingsMemo.Unlock()
+	if productMappingsMemo.mappings != nil {
+		return productMappingsMemo.mappings, nil
+	}
+	mappings, err := LoadProductMappings()
+	if err != nil {
+		return nil, err
+	}
+	productMappingsMemo.mappings = mappings
+	return mappings, nil
+}
+
 // snootyCache caches parsed snooty.toml files by their path to avoid re-parsing.
 var snootyCache = struct {
 	sync.RWMutex
@@ -234,12 +530,12 @@ func MergeProjectComposables(baseMappings *ProductMappings, sourcePath string) *
 	cachedConfig, found := snootyCache.configs[snootyPath]
 	snootyCache.RUnlock()
 
-	var config *snooty.Config
+	var projectConfig *snooty.Config
 	if found {
-		config = cachedConfig
+		projectConfig = cachedConfig
 	} else {
 		// Parse the snooty.toml
-		config, err = snooty.ParseFile(snootyPath)
+		projectConfig, err = snooty.ParseFile(snootyPath)
 		if err != nil {
 			// Failed to parse, return base mappings
 			return baseMappings
@@ -247,12 +543,12 @@ func MergeProjectComposables(baseMappings *ProductMappings, sourcePath string) *
 
 		// Cache the parsed config
 		snootyCache.Lock()
-		snootyCache.configs[snootyPath] = config
+		snootyCache.configs[snootyPath] = projectConfig
 		snootyCache.Unlock()
 	}
 
 	// If no composables defined, return base mappings
-	if len(config.Composables) == 0 {
+	if len(projectConfig.Composables) == 0 {
 		return baseMappings
 	}
 
@@ -261,6 +557,9 @@ func MergeProjectComposables(baseMappings *ProductMappings, sourcePath string) *
 		DriversTabIDToProduct:        make(map[string]string),
 		ComposableLanguageToProduct:  make(map[string]string),
 		ComposableInterfaceToProduct: make(map[string]string),
+		ProjectSnootyPath:            snootyPath,
+		ProjectComposableLanguages:   make(map[string]string),
+		ProjectComposableInterfaces:  make(map[string]string),
 	}
 
 	// Copy base mappings
@@ -275,15 +574,24 @@ func MergeProjectComposables(baseMappings *ProductMappings, sourcePath string) *
 	}
 
 	// Merge project-specific composables (project takes precedence)
-	projectLanguage := snooty.BuildComposableIDToTitleMap(config.Composables, "language")
+	projectLanguage := snooty.BuildComposableIDToTitleMap(projectConfig.Composables, "language")
 	for k, v := range projectLanguage {
+		merged.ProjectComposableLanguages[k] = merged.ComposableLanguageToProduct[k]
 		merged.ComposableLanguageToProduct[k] = v
 	}
 
-	projectInterface := snooty.BuildComposableIDToTitleMap(config.Composables, "interface")
+	projectInterface := snooty.BuildComposableIDToTitleMap(projectConfig.Composables, "interface")
 	for k, v := range projectInterface {
+		merged.ProjectComposableInterfaces[k] = merged.ComposableInterfaceToProduct[k]
 		merged.ComposableInterfaceToProduct[k] = v
 	}
 
+	merged.ComposableDefaults = make(map[string]string)
+	for _, composable := range projectConfig.Composables {
+		if composable.Default != "" {
+			merged.ComposableDefaults[composable.ID] = composable.Default
+		}
+	}
+
 	return merged
 }
@@ -0,0 +1,87 @@
+package testablecode
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// UnknownContentDir counts the pages and code examples in a content directory with no
+// product mapping.
+type UnknownContentDir struct {
+	ContentDir string
+	Pages      int
+	Examples   int // Examples whose product fell back to their language
+}
+
+// UnknownContentDirs accumulates, across pages, the content directories that had no
+// product mapping, keyed by content directory. These are usually new projects whose
+// examples would otherwise silently fall back to their language as the product.
+type UnknownContentDirs map[string]*UnknownContentDir
+
+// Add counts the examples on a page whose product fell back to their language because
+// the page's content directory isn't mapped. Pages without such examples aren't counted.
+func (u UnknownContentDirs) Add(analysis *PageAnalysis) {
+	if analysis.ContentDir == "" {
+		return
+	}
+	examples := 0
+	for _, ex := range analysis.CodeExamples {
+		if ex.Origin == OriginUnknown || strings.HasPrefix(ex.ProductReason, languageFallbackReason) {
+			examples++
+		}
+	}
+	if examples == 0 {
+		return
+	}
+
+	dir, ok := u[analysis.ContentDir]
+	if !ok {
+		dir = &UnknownContentDir{ContentDir: analysis.ContentDir}
+		u[analysis.ContentDir] = dir
+	}
+	dir.Pages++
+	dir.Examples += examples
+}
+
+// Sorted returns the content directories, most examples first.
+func (u UnknownContentDirs) Sorted() []UnknownContentDir {
+	dirs := make([]UnknownContentDir, 0, len(u))
+	for _, dir := range u {
+		dirs = append(dirs, *dir)
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		if dirs[i].Examples != dirs[j].Examples {
+			return dirs[i].Examples > dirs[j].Examples
+		}
+		return dirs[i].ContentDir < dirs[j].ContentDir
+	})
+	return dirs
+}
+
+// Err returns an error listing the content directories with no product mapping, most
+// examples first, for --fail-on-unknown-content-dir. Returns nil if there are none.
+func (u UnknownContentDirs) Err() error {
+	if len(u) == 0 {
+		return nil
+	}
+	dirs := make([]string, 0, len(u))
+	for _, dir := range u.Sorted() {
+		dirs = append(dirs, dir.ContentDir)
+	}
+	return fmt.Errorf("found %d content director(ies) with no product mapping: %s", len(dirs), strings.Join(dirs, ", "))
+}
+
+// PrintUnknownContentDirs prints the content directories with no product mapping and how
+// to map them. Nothing is printed if every content directory was mapped.
+func PrintUnknownContentDirs(w io.Writer, u UnknownContentDirs) {
+	if len(u) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%d content director(ies) with no product mapping (products fell back to the example language):\n", len(u))
+	for _, dir := range u.Sorted() {
+		fmt.Fprintf(w, "  %5d  %s (%d page(s))\n", dir.Examples, dir.ContentDir, dir.Pages)
+	}
+	fmt.Fprintln(w, "Map them in internal/projectinfo, or with content_dir_overrides or --content-dir-override <dir>=<product>.")
+}
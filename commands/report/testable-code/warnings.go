@@ -0,0 +1,72 @@
+package testablecode
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// WarningLog prints each distinct warning once and counts the repeats, so a problem in
+// a file shared by many pages doesn't bury the rest of the output.
+//
+// A nil *WarningLog prints every warning.
+type WarningLog struct {
+	counts map[string]int
+	order  []string // Distinct messages in the order first seen
+}
+
+// NewWarningLog creates an empty WarningLog.
+func NewWarningLog() *WarningLog {
+	return &WarningLog{counts: make(map[string]int)}
+}
+
+// Warn prints "<indent>Warning: <message>" to w the first time the message is seen, and
+// only counts it after that.
+func (l *WarningLog) Warn(w io.Writer, indent, message string) {
+	if l != nil {
+		l.counts[message]++
+		if l.counts[message] > 1 {
+			return
+		}
+		l.order = append(l.order, message)
+	}
+	fmt.Fprintf(w, "%sWarning: %s\n", indent, message)
+}
+
+// RepeatedWarning counts the times a warning was seen.
+type RepeatedWarning struct {
+	Message string
+	Count   int
+}
+
+// Repeated returns the warnings seen more than once and how many times each was seen,
+// most frequent first.
+func (l *WarningLog) Repeated() []RepeatedWarning {
+	if l == nil {
+		return nil
+	}
+	var repeated []RepeatedWarning
+	for _, message := range l.order {
+		if count := l.counts[message]; count > 1 {
+			repeated = append(repeated, RepeatedWarning{Message: message, Count: count})
+		}
+	}
+	sort.SliceStable(repeated, func(i, j int) bool {
+		return repeated[i].Count > repeated[j].Count
+	})
+	return repeated
+}
+
+// PrintRepeatedWarnings prints each warning that was suppressed after its first
+// occurrence, with the number of times it was seen (e.g., "(x200)"). Nothing is printed
+// if no warning repeated.
+func PrintRepeatedWarnings(w io.Writer, l *WarningLog) {
+	repeated := l.Repeated()
+	if len(repeated) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%d warning(s) repeated (printed once above):\n", len(repeated))
+	for _, warning := range repeated {
+		fmt.Fprintf(w, "  %7s  %s\n", fmt.Sprintf("(x%d)", warning.Count), warning.Message)
+	}
+}
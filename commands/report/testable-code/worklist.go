@@ -0,0 +1,88 @@
+package testablecode
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WorklistEntry is a page with testable examples that aren't tested yet.
+type WorklistEntry struct {
+	Rank int
+	URL  string
+	// Untested is the number of testable examples that aren't tested (TotalTestable - TotalTested)
+	Untested int
+	// Product is the product with the most untested testable examples on the page
+	Product string
+}
+
+// BuildWorklist returns the pages with testable but untested examples, most untested
+// first, for --format worklist. Ties go to the better (lower) analytics rank. Pages that
+// failed or have nothing left to test are left out. If top is positive, only the first
+// top pages are returned.
+func BuildWorklist(reports []PageReport, top int) []WorklistEntry {
+	var entries []WorklistEntry
+	for _, report := range reports {
+		if report.Error != "" {
+			continue
+		}
+		untested := report.TotalTestable - report.TotalTested
+		if untested <= 0 {
+			continue
+		}
+		entries = append(entries, WorklistEntry{
+			Rank:     report.Rank,
+			URL:      report.URL,
+			Untested: untested,
+			Product:  dominantUntestedProduct(report),
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Untested != entries[j].Untested {
+			return entries[i].Untested > entries[j].Untested
+		}
+		return entries[i].Rank < entries[j].Rank
+	})
+	if top > 0 && top < len(entries) {
+		entries = entries[:top]
+	}
+	return entries
+}
+
+// dominantUntestedProduct returns the product with the most untested testable examples,
+// breaking ties by product name.
+func dominantUntestedProduct(report PageReport) string {
+	best, bestUntested := "", 0
+	for product, stats := range report.ByProduct {
+		untested := stats.TestableCount - stats.TestedCount
+		if untested > bestUntested || (untested == bestUntested && untested > 0 && product < best) {
+			best, bestUntested = product, untested
+		}
+	}
+	return best
+}
+
+// OutputWorklist prints the worklist as a table: position, analytics rank, untested
+// testable examples, dominant product, and URL.
+func OutputWorklist(w io.Writer, entries []WorklistEntry) error {
+	fmt.Fprintln(w, strings.Repeat("=", 100))
+	fmt.Fprintln(w, "TESTABLE BUT UNTESTED WORKLIST")
+	fmt.Fprintln(w, strings.Repeat("=", 100))
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No pages with testable but untested examples.")
+		return nil
+	}
+
+	fmt.Fprintf(w, "%-4s %-6s %-9s %-20s %s\n", "#", "Rank", "Untested", "Product", "URL")
+	fmt.Fprintln(w, strings.Repeat("-", 100))
+	total := 0
+	for i, entry := range entries {
+		fmt.Fprintf(w, "%-4d %-6d %-9d %-20s %s\n", i+1, entry.Rank, entry.Untested, entry.Product, entry.URL)
+		total += entry.Untested
+	}
+	fmt.Fprintln(w, strings.Repeat("-", 100))
+	fmt.Fprintf(w, "%d page(s), %d testable example(s) to test\n", len(entries), total)
+	return nil
+}
@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	"github.com/grove-platform/audit-cli/internal/config"
+	"github.com/grove-platform/audit-cli/internal/projectinfo"
 	"github.com/grove-platform/audit-cli/internal/rst"
 	"github.com/spf13/cobra"
 )
@@ -201,9 +202,10 @@ func collectFiles(dirPath string, recursive bool) ([]string, error) {
 			if err != nil {
 				return err
 			}
-			if !info.IsDir() {
-				files = append(files, path)
+			if info.IsDir() {
+				return projectinfo.SkipExcludedDir(dirPath, path, info.Name())
 			}
+			files = append(files, path)
 			return nil
 		})
 		if err != nil {
@@ -5,6 +5,11 @@
 //   - Environment variables (AUDIT_CLI_MONOREPO_PATH)
 //   - Command-line arguments (highest priority)
 //
+// The config file can define named profiles (for example, one for the public docs
+// monorepo and one for an internal fork). A profile is selected with the --profile flag
+// or the AUDIT_CLI_PROFILE environment variable; its values win over the file's
+// top-level fields.
+//
 // The monorepo path is resolved in the following order (highest to lowest priority):
 //  1. Command-line argument (if provided)
 //  2. Environment variable AUDIT_CLI_MONOREPO_PATH
@@ -16,6 +21,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -23,44 +30,204 @@ import (
 // Config represents the audit-cli configuration.
 type Config struct {
 	MonorepoPath string `yaml:"monorepo_path"`
+
+	// MonorepoPaths are more monorepos for report testable-code to look up projects in,
+	// in order, after the main one (e.g., a driver whose docs have their own repository).
+	// If monorepo_path isn't set, the first entry is the main monorepo.
+	MonorepoPaths []string `yaml:"monorepo_paths,omitempty"`
+
+	// TestableProducts overrides the built-in set of products with test infrastructure
+	// used by report testable-code. true adds a product, false removes a built-in one.
+	TestableProducts map[string]bool `yaml:"testable_products,omitempty"`
+
+	// TestableDrivers overrides the built-in set of driver project names with test
+	// infrastructure used by report testable-code. true adds a driver, false removes one.
+	TestableDrivers map[string]bool `yaml:"testable_drivers,omitempty"`
+
+	// ContentDirOverrides maps content directory names to products for report testable-code.
+	// Entries are consulted before the built-in content directory mapping in internal/projectinfo.
+	ContentDirOverrides map[string]string `yaml:"content_dir_overrides,omitempty"`
+
+	// SlugRedirects maps retired URL slugs to their current slugs for report testable-code,
+	// in addition to the built-in config.DefaultSlugRedirects.
+	SlugRedirects map[string]string `yaml:"slug_redirects,omitempty"`
+
+	// Profiles are named sets of settings selected with --profile or AUDIT_CLI_PROFILE
+	// (see SetProfile). A selected profile's fields win over the top-level ones. Profiles
+	// can't be nested.
+	Profiles map[string]*Config `yaml:"profiles,omitempty"`
+}
+
+// ConfigFileName is the name of the config file (see CreateSampleConfig and LoadConfig).
+const ConfigFileName = ".audit-cli.yaml"
+
+// EnvVarMonorepoPath is the environment variable name for monorepo path.
+const EnvVarMonorepoPath = "AUDIT_CLI_MONOREPO_PATH"
+
+// EnvVarProfile is the environment variable name for the config file profile.
+const EnvVarProfile = "AUDIT_CLI_PROFILE"
+
+// profileOverride is the profile selected with --profile (see SetProfile).
+var profileOverride string
+
+// SetProfile selects a config file profile, overriding AUDIT_CLI_PROFILE.
+// Pass an empty string to use the environment variable, or no profile if it's unset.
+func SetProfile(name string) {
+	profileOverride = name
 }
 
-// configFileName is the name of the config file.
-const configFileName = ".audit-cli.yaml"
+// EnvVarCacheDir is the environment variable name for the cache directory.
+const EnvVarCacheDir = "AUDIT_CLI_CACHE_DIR"
+
+// cacheDirOverride is the cache directory set with --cache-dir (see SetCacheDir).
+var cacheDirOverride string
+
+// SetCacheDir overrides the directory cache files are written to, overriding
+// AUDIT_CLI_CACHE_DIR. Pass an empty string to use the environment variable, or
+// ~/.audit-cli if it's unset.
+func SetCacheDir(dir string) {
+	cacheDirOverride = dir
+}
 
-// envVarMonorepoPath is the environment variable name for monorepo path.
-const envVarMonorepoPath = "AUDIT_CLI_MONOREPO_PATH"
+// localMode is set with --local (see SetLocal).
+var localMode bool
+
+// SetLocal turns local-only mode on or off. In local mode nothing is fetched over the
+// network and no cache files are written: the URL mapping comes from the cache (even if
+// it has expired) or the static fallback, rstspec.toml from --rstspec-file, the cache, or
+// the bundled snapshot, and anything else that needs the network fails with ErrLocal.
+func SetLocal(local bool) {
+	localMode = local
+}
+
+// IsLocal reports whether local-only mode is on (see SetLocal).
+func IsLocal() bool {
+	return localMode
+}
+
+// GetCacheDir returns the directory for cache files: the URL mapping, rstspec.toml, and
+// analysis caches. The --cache-dir flag takes precedence over AUDIT_CLI_CACHE_DIR; the
+// default is CacheDir in the home directory.
+//
+// Returns:
+//   - string: The cache directory (it may not exist yet)
+//   - error: Error if no override is set and the home directory can't be found
+func GetCacheDir() (string, error) {
+	if cacheDirOverride != "" {
+		return cacheDirOverride, nil
+	}
+	if envDir := os.Getenv(EnvVarCacheDir); envDir != "" {
+		return envDir, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, CacheDir), nil
+}
+
+// selectedProfile returns the selected profile name, or "" if none is selected.
+// The --profile flag takes precedence over the environment variable.
+func selectedProfile() string {
+	if profileOverride != "" {
+		return profileOverride
+	}
+	return os.Getenv(EnvVarProfile)
+}
 
 // LoadConfig loads configuration from file and environment variables.
 // Returns a Config struct with values populated from available sources.
+//
+// If a profile is selected (see SetProfile), its values are applied over the file's
+// top-level fields. Selecting a profile the config file doesn't define is an error.
 func LoadConfig() (*Config, error) {
 	config := &Config{}
 
 	// Try to load from config file
+	profile := selectedProfile()
 	if err := loadFromFile(config); err != nil {
 		// Config file is optional, so we don't return error if it doesn't exist
 		// Only return error if file exists but can't be parsed
 		if !os.IsNotExist(err) {
 			return nil, fmt.Errorf("failed to load config file: %w", err)
 		}
+		if profile != "" {
+			return nil, fmt.Errorf("profile %q selected but no %s config file was found", profile, ConfigFileName)
+		}
+	}
+
+	if err := config.applyProfile(profile); err != nil {
+		return nil, err
 	}
 
 	// Override with environment variable if set
-	if envPath := os.Getenv(envVarMonorepoPath); envPath != "" {
+	if envPath := os.Getenv(EnvVarMonorepoPath); envPath != "" {
 		config.MonorepoPath = envPath
 	}
 
 	return config, nil
 }
 
+// applyProfile applies the named profile's values over the top-level fields: set
+// strings and lists replace the top-level value, and map entries are merged with the
+// profile's entries winning. An empty name applies no profile.
+func (c *Config) applyProfile(name string) error {
+	if name == "" {
+		return nil
+	}
+	profile, ok := c.Profiles[name]
+	if !ok || profile == nil {
+		names := make([]string, 0, len(c.Profiles))
+		for n := range c.Profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return fmt.Errorf("profile %q not found: %s defines no profiles", name, ConfigFileName)
+		}
+		return fmt.Errorf("profile %q not found in %s (available: %s)", name, ConfigFileName, strings.Join(names, ", "))
+	}
+
+	if profile.MonorepoPath != "" {
+		c.MonorepoPath = profile.MonorepoPath
+	}
+	if len(profile.MonorepoPaths) > 0 {
+		c.MonorepoPaths = profile.MonorepoPaths
+		// A profile that lists only monorepo_paths names its own main monorepo
+		if profile.MonorepoPath == "" {
+			c.MonorepoPath = ""
+		}
+	}
+	c.TestableProducts = mergeMap(c.TestableProducts, profile.TestableProducts)
+	c.TestableDrivers = mergeMap(c.TestableDrivers, profile.TestableDrivers)
+	c.ContentDirOverrides = mergeMap(c.ContentDirOverrides, profile.ContentDirOverrides)
+	c.SlugRedirects = mergeMap(c.SlugRedirects, profile.SlugRedirects)
+	return nil
+}
+
+// mergeMap returns base with the entries of override added, override winning.
+func mergeMap[V any](base, override map[string]V) map[string]V {
+	if len(override) == 0 {
+		return base
+	}
+	merged := make(map[string]V, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+	return merged
+}
+
 // loadFromFile loads configuration from a YAML file.
 // Searches in the following order:
 //  1. .audit-cli.yaml in current directory
 //  2. .audit-cli.yaml in home directory
 func loadFromFile(config *Config) error {
 	// Try current directory first
-	if _, err := os.Stat(configFileName); err == nil {
-		return parseConfigFile(configFileName, config)
+	if _, err := os.Stat(ConfigFileName); err == nil {
+		return parseConfigFile(ConfigFileName, config)
 	}
 
 	// Try home directory
@@ -69,7 +236,7 @@ func loadFromFile(config *Config) error {
 		return fmt.Errorf("failed to get home directory: %w", err)
 	}
 
-	homeConfigPath := filepath.Join(homeDir, configFileName)
+	homeConfigPath := filepath.Join(homeDir, ConfigFileName)
 	if _, err := os.Stat(homeConfigPath); err == nil {
 		return parseConfigFile(homeConfigPath, config)
 	}
@@ -117,6 +284,9 @@ func GetMonorepoPath(cmdLineArg string) (string, error) {
 	if config.MonorepoPath != "" {
 		return config.MonorepoPath, nil
 	}
+	if len(config.MonorepoPaths) > 0 {
+		return config.MonorepoPaths[0], nil
+	}
 
 	// No path configured
 	return "", fmt.Errorf("no monorepo path configured\n\n" +
@@ -130,6 +300,28 @@ func GetMonorepoPath(cmdLineArg string) (string, error) {
 		"  - Home directory: ~/.audit-cli.yaml")
 }
 
+// AdditionalMonorepoPaths returns the monorepos to look up projects in after
+// monorepoPath, in order, without monorepoPath itself or duplicates.
+//
+// Parameters:
+//   - monorepoPath: The main monorepo (see GetMonorepoPath)
+//   - paths: monorepo_paths from the config file, then any from the command line
+//
+// Returns:
+//   - []string: The additional monorepos, for GetURLMapping
+func AdditionalMonorepoPaths(monorepoPath string, paths []string) []string {
+	seen := map[string]bool{filepath.Clean(monorepoPath): true}
+	var additional []string
+	for _, path := range paths {
+		if path == "" || seen[filepath.Clean(path)] {
+			continue
+		}
+		seen[filepath.Clean(path)] = true
+		additional = append(additional, path)
+	}
+	return additional
+}
+
 // CreateSampleConfig creates a sample config file in the current directory.
 func CreateSampleConfig(monorepoPath string) error {
 	config := &Config{
@@ -141,7 +333,7 @@ func CreateSampleConfig(monorepoPath string) error {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	if err := os.WriteFile(configFileName, data, 0644); err != nil {
+	if err := os.WriteFile(ConfigFileName, data, 0644); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
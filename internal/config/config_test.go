@@ -4,14 +4,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
 // TestGetMonorepoPath_CommandLineArg tests that command-line argument has highest priority.
 func TestGetMonorepoPath_CommandLineArg(t *testing.T) {
 	// Set environment variable
-	os.Setenv(envVarMonorepoPath, "/env/path")
-	defer os.Unsetenv(envVarMonorepoPath)
+	os.Setenv(EnvVarMonorepoPath, "/env/path")
+	defer os.Unsetenv(EnvVarMonorepoPath)
 
 	// Command-line argument should override environment
 	path, err := GetMonorepoPath("/cmd/path")
@@ -27,8 +28,8 @@ func TestGetMonorepoPath_CommandLineArg(t *testing.T) {
 // TestGetMonorepoPath_EnvironmentVariable tests environment variable fallback.
 func TestGetMonorepoPath_EnvironmentVariable(t *testing.T) {
 	// Set environment variable
-	os.Setenv(envVarMonorepoPath, "/env/path")
-	defer os.Unsetenv(envVarMonorepoPath)
+	os.Setenv(EnvVarMonorepoPath, "/env/path")
+	defer os.Unsetenv(EnvVarMonorepoPath)
 
 	// No command-line argument, should use environment
 	path, err := GetMonorepoPath("")
@@ -54,14 +55,14 @@ func TestGetMonorepoPath_ConfigFile(t *testing.T) {
 	}
 
 	// Create config file
-	configPath := filepath.Join(tempDir, configFileName)
+	configPath := filepath.Join(tempDir, ConfigFileName)
 	configContent := "monorepo_path: /config/path\n"
 	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
 		t.Fatalf("Failed to write config file: %v", err)
 	}
 
 	// Ensure environment variable is not set
-	os.Unsetenv(envVarMonorepoPath)
+	os.Unsetenv(EnvVarMonorepoPath)
 
 	// No command-line argument or environment, should use config file
 	path, err := GetMonorepoPath("")
@@ -74,6 +75,41 @@ func TestGetMonorepoPath_ConfigFile(t *testing.T) {
 	}
 }
 
+// TestGetMonorepoPath_MonorepoPaths tests that the first of monorepo_paths is the main
+// monorepo when monorepo_path isn't set, and that the rest are additional monorepos.
+func TestGetMonorepoPath_MonorepoPaths(t *testing.T) {
+	tempDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	configContent := "monorepo_paths:\n  - /docs-monorepo\n  - /rust-driver\n"
+	if err := os.WriteFile(filepath.Join(tempDir, ConfigFileName), []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	os.Unsetenv(EnvVarMonorepoPath)
+
+	path, err := GetMonorepoPath("")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if path != "/docs-monorepo" {
+		t.Errorf("Expected '/docs-monorepo', got '%s'", path)
+	}
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	additional := AdditionalMonorepoPaths(path, append(config.MonorepoPaths, "/kotlin-driver", "/rust-driver/"))
+	expected := []string{"/rust-driver", "/kotlin-driver"}
+	if fmt.Sprint(additional) != fmt.Sprint(expected) {
+		t.Errorf("Expected %v, got %v", expected, additional)
+	}
+}
+
 // TestGetMonorepoPath_NoConfig tests error when no configuration is provided.
 func TestGetMonorepoPath_NoConfig(t *testing.T) {
 	// Create temporary directory for test
@@ -87,7 +123,7 @@ func TestGetMonorepoPath_NoConfig(t *testing.T) {
 	}
 
 	// Ensure environment variable is not set
-	os.Unsetenv(envVarMonorepoPath)
+	os.Unsetenv(EnvVarMonorepoPath)
 
 	// No configuration should return error
 	_, err := GetMonorepoPath("")
@@ -114,7 +150,7 @@ func TestCreateSampleConfig(t *testing.T) {
 	}
 
 	// Verify file was created
-	configPath := filepath.Join(tempDir, configFileName)
+	configPath := filepath.Join(tempDir, ConfigFileName)
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		t.Error("Config file was not created")
 	}
@@ -130,6 +166,193 @@ func TestCreateSampleConfig(t *testing.T) {
 	}
 }
 
+// TestLoadConfig_TestableOverrides tests parsing testable product and driver overrides.
+func TestLoadConfig_TestableOverrides(t *testing.T) {
+	// Create temporary directory for test
+	tempDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+
+	// Change to temp directory
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	configPath := filepath.Join(tempDir, ConfigFileName)
+	configContent := `monorepo_path: /config/path
+testable_products:
+  Rust: true
+  rust: true
+  Java: false
+testable_drivers:
+  rust: true
+content_dir_overrides:
+  cloud-docs: Atlas
+slug_redirects:
+  atlas/data-lake: atlas/data-federation
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if config.ContentDirOverrides["cloud-docs"] != "Atlas" {
+		t.Errorf("Expected cloud-docs override to be Atlas, got %v", config.ContentDirOverrides)
+	}
+	if !config.TestableProducts["Rust"] || !config.TestableProducts["rust"] {
+		t.Errorf("Expected Rust products to be enabled, got %v", config.TestableProducts)
+	}
+	if enabled, ok := config.TestableProducts["Java"]; !ok || enabled {
+		t.Errorf("Expected Java to be explicitly disabled, got %v", config.TestableProducts)
+	}
+	if !config.TestableDrivers["rust"] {
+		t.Errorf("Expected rust driver to be enabled, got %v", config.TestableDrivers)
+	}
+	if config.SlugRedirects["atlas/data-lake"] != "atlas/data-federation" {
+		t.Errorf("Expected atlas/data-lake redirect, got %v", config.SlugRedirects)
+	}
+}
+
+// TestLoadConfig_Profiles tests selecting a config file profile with --profile or
+// AUDIT_CLI_PROFILE, and that a selected profile's values win over the top-level ones.
+func TestLoadConfig_Profiles(t *testing.T) {
+	tempDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	configContent := `monorepo_path: /public/docs
+content_dir_overrides:
+  cloud-docs: Atlas
+  c-driver: C
+profiles:
+  public: {}
+  internal:
+    monorepo_path: /internal/docs
+    content_dir_overrides:
+      cloud-docs: Atlas (Internal)
+  drivers:
+    monorepo_paths:
+      - /drivers/docs
+      - /rust-driver
+`
+	if err := os.WriteFile(filepath.Join(tempDir, ConfigFileName), []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	os.Unsetenv(EnvVarMonorepoPath)
+	defer os.Unsetenv(EnvVarProfile)
+	defer SetProfile("")
+
+	tests := []struct {
+		name     string
+		flag     string
+		env      string
+		wantPath string
+		wantC    string // content_dir_overrides["cloud-docs"]
+	}{
+		{"no profile uses top-level fields", "", "", "/public/docs", "Atlas"},
+		{"empty profile keeps top-level fields", "public", "", "/public/docs", "Atlas"},
+		{"environment variable selects profile", "", "internal", "/internal/docs", "Atlas (Internal)"},
+		{"flag wins over environment variable", "public", "internal", "/public/docs", "Atlas"},
+		{"monorepo_paths names the main monorepo", "drivers", "", "/drivers/docs", "Atlas"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetProfile(tt.flag)
+			os.Setenv(EnvVarProfile, tt.env)
+
+			path, err := GetMonorepoPath("")
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if path != tt.wantPath {
+				t.Errorf("Expected '%s', got '%s'", tt.wantPath, path)
+			}
+
+			config, err := LoadConfig()
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if config.ContentDirOverrides["cloud-docs"] != tt.wantC {
+				t.Errorf("Expected cloud-docs override %q, got %v", tt.wantC, config.ContentDirOverrides)
+			}
+			// Top-level map entries the profile doesn't set are kept
+			if config.ContentDirOverrides["c-driver"] != "C" {
+				t.Errorf("Expected c-driver override to be kept, got %v", config.ContentDirOverrides)
+			}
+		})
+	}
+
+	// The command-line argument and AUDIT_CLI_MONOREPO_PATH still win over a profile
+	SetProfile("internal")
+	os.Setenv(EnvVarMonorepoPath, "/env/path")
+	defer os.Unsetenv(EnvVarMonorepoPath)
+	if path, _ := GetMonorepoPath("/cmd/path"); path != "/cmd/path" {
+		t.Errorf("Expected '/cmd/path', got '%s'", path)
+	}
+	if path, _ := GetMonorepoPath(""); path != "/env/path" {
+		t.Errorf("Expected '/env/path', got '%s'", path)
+	}
+	os.Unsetenv(EnvVarMonorepoPath)
+
+	// An unknown profile is an error that lists the defined ones
+	SetProfile("staging")
+	_, err := LoadConfig()
+	if err == nil || !strings.Contains(err.Error(), "drivers, internal, public") {
+		t.Errorf("Expected unknown profile error listing profiles, got: %v", err)
+	}
+}
+
+// TestGetCacheDir tests that --cache-dir wins over AUDIT_CLI_CACHE_DIR, which wins over
+// ~/.audit-cli, and that the URL mapping cache is written to the chosen directory.
+func TestGetCacheDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(EnvVarCacheDir, "")
+	defer SetCacheDir("")
+
+	dir, err := GetCacheDir()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if dir != filepath.Join(home, CacheDir) {
+		t.Errorf("Expected '%s', got '%s'", filepath.Join(home, CacheDir), dir)
+	}
+
+	envDir := t.TempDir()
+	t.Setenv(EnvVarCacheDir, envDir)
+	if dir, _ := GetCacheDir(); dir != envDir {
+		t.Errorf("Expected '%s', got '%s'", envDir, dir)
+	}
+
+	flagDir := filepath.Join(t.TempDir(), "cache")
+	SetCacheDir(flagDir)
+	if dir, _ := GetCacheDir(); dir != flagDir {
+		t.Errorf("Expected '%s', got '%s'", flagDir, dir)
+	}
+
+	// The cache directory is created on first save
+	if err := saveCache(&URLMappingCache{Mapping: map[string]string{"atlas": "cloud-docs"}}); err != nil {
+		t.Fatalf("saveCache failed: %v", err)
+	}
+	path, _, err := GetURLMappingCacheInfo()
+	if err != nil {
+		t.Fatalf("GetURLMappingCacheInfo failed: %v", err)
+	}
+	if path != filepath.Join(flagDir, CacheFileName) {
+		t.Errorf("Expected cache in '%s', got '%s'", flagDir, path)
+	}
+	if _, err := os.Stat(filepath.Join(home, CacheDir)); !os.IsNotExist(err) {
+		t.Errorf("Expected nothing written to the home directory, got: %v", err)
+	}
+}
+
 // TestLoadConfig_InvalidYAML tests handling of invalid YAML.
 func TestLoadConfig_InvalidYAML(t *testing.T) {
 	// Create temporary directory for test
@@ -143,7 +366,7 @@ func TestLoadConfig_InvalidYAML(t *testing.T) {
 	}
 
 	// Create invalid config file
-	configPath := filepath.Join(tempDir, configFileName)
+	configPath := filepath.Join(tempDir, ConfigFileName)
 	invalidContent := "monorepo_path: [invalid: yaml\n"
 	if err := os.WriteFile(configPath, []byte(invalidContent), 0644); err != nil {
 		t.Fatalf("Failed to write config file: %v", err)
@@ -0,0 +1,38 @@
+package config
+
+import "net/http"
+
+// HTTPValidators are the cache validators from an HTTP response, stored with a cached
+// response so it can be revalidated instead of fetched again.
+//
+// Once a cache expires, the next request sends them as If-None-Match and
+// If-Modified-Since. If the server answers 304 Not Modified, the cached body is still
+// current and is reused, so a refresh costs a request but not a download.
+type HTTPValidators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// ValidatorsFromResponse returns the ETag and Last-Modified headers of a response.
+func ValidatorsFromResponse(resp *http.Response) HTTPValidators {
+	return HTTPValidators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+}
+
+// IsZero reports whether there are no validators, so a request can't be conditional.
+func (v HTTPValidators) IsZero() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// SetConditionalHeaders adds If-None-Match and If-Modified-Since to req for the
+// validators that are set.
+func (v HTTPValidators) SetConditionalHeaders(req *http.Request) {
+	if v.ETag != "" {
+		req.Header.Set("If-None-Match", v.ETag)
+	}
+	if v.LastModified != "" {
+		req.Header.Set("If-Modified-Since", v.LastModified)
+	}
+}
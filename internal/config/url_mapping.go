@@ -5,36 +5,61 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/BurntSushi/toml"
+	"github.com/grove-platform/audit-cli/internal/projectinfo"
 )
 
+// Errors returned when a URL can't be resolved to a source file. They are wrapped with
+// the URL, project, or path, so check for them with errors.Is.
+var (
+	// ErrInvalidURL means the URL has no /docs/ path to resolve.
+	ErrInvalidURL = errors.New("invalid URL format")
+	// ErrSlugNotResolved means no project's URL slug matches the URL.
+	ErrSlugNotResolved = errors.New("could not resolve URL slug")
+	// ErrNoContentDir means the URL's project has no content directory in the monorepo.
+	ErrNoContentDir = errors.New("no content directory found for project")
+	// ErrSourceNotFound means the URL resolved to a source file that doesn't exist.
+	ErrSourceNotFound = errors.New("source file not found")
+)
+
+// ErrLocal is returned, wrapped with what was skipped, when local mode (see SetLocal)
+// prevents a network request.
+var ErrLocal = errors.New("network access is disabled with --local")
+
 // SnootyDataAPIURL is the endpoint for fetching project metadata.
 const SnootyDataAPIURL = "https://snooty-data-api.mongodb.com/prod/projects"
 
 // CacheTTL is the time-to-live for the cached URL mapping (24 hours).
 const CacheTTL = 24 * time.Hour
 
-// CacheDir is the directory for storing cache files.
+// CacheDir is the directory for storing cache files, in the home directory (see GetCacheDir).
 const CacheDir = ".audit-cli"
 
 // CacheFileName is the name of the URL mapping cache file.
 const CacheFileName = "url-mapping-cache.json"
 
 // URLMappingCache represents the cached URL mapping data.
+//
+// The embedded HTTPValidators are from the Snooty Data API response the mapping was
+// built from, so an expired cache can be revalidated (see fetchFromAPI).
 type URLMappingCache struct {
 	Timestamp   time.Time           `json:"timestamp"`
 	Mapping     map[string]string   `json:"mapping"`      // URL slug -> snooty project name
 	Branches    map[string][]string `json:"branches"`     // project name -> list of version slugs
 	DriverSlugs []string            `json:"driver_slugs"` // URL slugs for driver documentation
+	HTTPValidators
 }
 
 // SnootyAPIResponse represents the response from the Snooty Data API.
@@ -64,9 +89,29 @@ type SnootyToml struct {
 	Name string `toml:"name"`
 }
 
+// Driver version fallbacks control how ResolveURL handles a driver URL, such as
+// drivers/node/v5.2/, whose version directory doesn't exist in the monorepo (for example,
+// in a partial checkout).
+const (
+	// DriverVersionFallbackNone resolves the page without a version directory (the default).
+	DriverVersionFallbackNone = "none"
+	// DriverVersionFallbackNearest uses the nearest numbered version directory (or current).
+	DriverVersionFallbackNearest = "nearest"
+	// DriverVersionFallbackCurrent uses the current version directory.
+	DriverVersionFallbackCurrent = "current"
+	// DriverVersionFallbackError fails to resolve the page.
+	DriverVersionFallbackError = "error"
+)
+
+// DriverVersionFallbacks lists the valid DriverVersionFallback values.
+var DriverVersionFallbacks = []string{
+	DriverVersionFallbackNone, DriverVersionFallbackNearest, DriverVersionFallbackCurrent, DriverVersionFallbackError,
+}
+
 // URLMapping provides URL-to-source-file resolution.
 type URLMapping struct {
-	// URLSlugToProject maps URL slugs to snooty project names
+	// URLSlugToProject maps URL slugs to snooty project names. Treat it as read-only
+	// once the mapping is built, since lookups use an index of it.
 	URLSlugToProject map[string]string
 	// ProjectToContentDir maps snooty project names to content directories
 	ProjectToContentDir map[string]string
@@ -76,19 +121,50 @@ type URLMapping struct {
 	DriverSlugs []string
 	// MonorepoPath is the path to the docs monorepo
 	MonorepoPath string
+	// ProjectToMonorepo maps snooty project names to the monorepo whose content directory
+	// has the project. Projects not listed are in MonorepoPath.
+	ProjectToMonorepo map[string]string
+	// DriverVersionFallback controls how a driver URL whose version directory doesn't
+	// exist is resolved: one of the DriverVersionFallback* constants (empty = none).
+	DriverVersionFallback string
+	// UsedStaticFallback is true if the Snooty Data API and cache were both unavailable and
+	// the built-in static mapping was used. It may be missing recently added projects.
+	UsedStaticFallback bool
+	// SlugRedirects maps old URL slugs to their current slugs (lowercase, no surrounding
+	// slashes). Starts as DefaultSlugRedirects; add entries with AddSlugRedirects.
+	SlugRedirects map[string]string
+
+	// lowerSlugToProject indexes URLSlugToProject by lowercased slug. It's built with the
+	// mapping (see indexSlugs); nil for a URLMapping built as a literal.
+	lowerSlugToProject map[string]string
 }
 
 // getCachePath returns the path to the cache file.
 func getCachePath() (string, error) {
-	homeDir, err := os.UserHomeDir()
+	cacheDir, err := GetCacheDir()
 	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
+		return "", err
 	}
-	return filepath.Join(homeDir, CacheDir, CacheFileName), nil
+	return filepath.Join(cacheDir, CacheFileName), nil
 }
 
 // loadCache loads the URL mapping from the cache file.
 func loadCache() (*URLMappingCache, error) {
+	cache, err := readCache()
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if cache is expired
+	if time.Since(cache.Timestamp) > CacheTTL {
+		return nil, fmt.Errorf("cache expired")
+	}
+
+	return cache, nil
+}
+
+// readCache reads the URL mapping cache file, even if it has expired.
+func readCache() (*URLMappingCache, error) {
 	cachePath, err := getCachePath()
 	if err != nil {
 		return nil, err
@@ -103,13 +179,34 @@ func loadCache() (*URLMappingCache, error) {
 	if err := json.Unmarshal(data, &cache); err != nil {
 		return nil, fmt.Errorf("failed to parse cache: %w", err)
 	}
+	return &cache, nil
+}
 
-	// Check if cache is expired
-	if time.Since(cache.Timestamp) > CacheTTL {
-		return nil, fmt.Errorf("cache expired")
+// GetURLMappingCacheInfo returns the path and timestamp of the on-disk URL mapping cache.
+//
+// The timestamp is when the mapping was last fetched from the Snooty Data API. The cache
+// is refetched once it is older than CacheTTL.
+//
+// Returns:
+//   - string: Path to the cache file
+//   - time.Time: When the cached mapping was fetched
+//   - error: Error if the cache file does not exist or cannot be parsed
+func GetURLMappingCacheInfo() (string, time.Time, error) {
+	cachePath, err := getCachePath()
+	if err != nil {
+		return "", time.Time{}, err
 	}
 
-	return &cache, nil
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		return cachePath, time.Time{}, err
+	}
+
+	var cache URLMappingCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return cachePath, time.Time{}, fmt.Errorf("failed to parse cache: %w", err)
+	}
+	return cachePath, cache.Timestamp, nil
 }
 
 // saveCache saves the URL mapping to the cache file.
@@ -149,14 +246,30 @@ func isActive(active any) bool {
 	}
 }
 
-// fetchFromAPI fetches URL mapping from the Snooty Data API.
-func fetchFromAPI() (*URLMappingCache, error) {
-	resp, err := http.Get(SnootyDataAPIURL)
+// fetchFromAPI fetches URL mapping from the Snooty Data API at url.
+//
+// If stale (an expired cache, or nil) has HTTP validators, the request is conditional:
+// on 304 Not Modified, stale is returned with a new timestamp instead of downloading and
+// rebuilding the mapping. Without validators, the mapping is always fetched in full.
+func fetchFromAPI(url string, stale *URLMappingCache) (*URLMappingCache, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch from API: %w", err)
+	}
+	if stale != nil {
+		stale.SetConditionalHeaders(req)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch from API: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && stale != nil && !stale.IsZero() {
+		stale.Timestamp = time.Now()
+		return stale, nil
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
 	}
@@ -172,10 +285,11 @@ func fetchFromAPI() (*URLMappingCache, error) {
 	}
 
 	cache := &URLMappingCache{
-		Timestamp:   time.Now(),
-		Mapping:     make(map[string]string),
-		Branches:    make(map[string][]string),
-		DriverSlugs: []string{},
+		Timestamp:      time.Now(),
+		Mapping:        make(map[string]string),
+		Branches:       make(map[string][]string),
+		DriverSlugs:    []string{},
+		HTTPValidators: ValidatorsFromResponse(resp),
 	}
 
 	// Regex to extract URL slug from fullUrl
@@ -235,7 +349,7 @@ func fetchFromAPI() (*URLMappingCache, error) {
 		cache.DriverSlugs = append(cache.DriverSlugs, slug)
 	}
 	// Sort for deterministic output
-	sortStrings(cache.DriverSlugs)
+	sortDriverSlugs(cache)
 
 	return cache, nil
 }
@@ -278,21 +392,24 @@ func isDriverSlug(slug, displayName string) bool {
 	return standaloneDriverSlugs[slug]
 }
 
-// sortStrings sorts a slice of strings in place - used to display the list of filters in alphabetical order.
-func sortStrings(s []string) {
-	for i := 0; i < len(s); i++ {
-		for j := i + 1; j < len(s); j++ {
-			if s[i] > s[j] {
-				s[i], s[j] = s[j], s[i]
-			}
-		}
-	}
+// sortDriverSlugs sorts and de-duplicates DriverSlugs in place, so the list is in the same
+// order whether it came from the API, the cache file, or the static fallback.
+func sortDriverSlugs(cache *URLMappingCache) {
+	sort.Strings(cache.DriverSlugs)
+	cache.DriverSlugs = slices.Compact(cache.DriverSlugs)
 }
 
+// versionSlugRegex matches numbered versions like v8.0 and 1.13, optionally with a
+// pre-release suffix (v8.0-rc0, v2.0-beta1), and date-based versions like 2024-01.
+var versionSlugRegex = regexp.MustCompile(`^(v?\d+(\.\d+)*(-(rc|beta|alpha)\d*)?|\d{4}-\d{2})$`)
+
 // isVersionSlug checks if a string looks like a version slug.
+//
+// Branches whose last URL segment is a version slug are recorded in ProjectBranches, so
+// a slug that isn't recognized here is treated as part of the page path instead.
 func isVersionSlug(s string) bool {
 	versionPatterns := []string{
-		"current", "upcoming", "stable", "master", "latest",
+		"current", "upcoming", "stable", "master", "latest", "beta", "alpha",
 		"manual", // MongoDB Manual uses "manual" as the current version directory
 	}
 	for _, p := range versionPatterns {
@@ -300,34 +417,101 @@ func isVersionSlug(s string) bool {
 			return true
 		}
 	}
-	// Check for version patterns like v8.0, v1.13, etc.
-	matched, _ := regexp.MatchString(`^v?\d+(\.\d+)*$`, s)
-	return matched
+	return versionSlugRegex.MatchString(s)
+}
+
+// ProjectNameCollision records a snooty project name declared by more than one content directory.
+type ProjectNameCollision struct {
+	Name       string // Snooty project name from the name field
+	KeptDir    string // Content directory that owns the name (the first one found)
+	IgnoredDir string // Content directory whose declaration was ignored
+	// KeptRoot and IgnoredRoot are the monorepos of KeptDir and IgnoredDir when the
+	// collision involves an additional monorepo; both are empty within the main monorepo.
+	KeptRoot    string
+	IgnoredRoot string
+}
+
+// KeptPath returns the content directory that owns the name, for warnings: content/<dir>
+// in the main monorepo, or the full path in an additional monorepo.
+func (c ProjectNameCollision) KeptPath() string {
+	return collisionPath(c.KeptRoot, c.KeptDir)
+}
+
+// IgnoredPath returns the content directory whose declaration was ignored, like KeptPath.
+func (c ProjectNameCollision) IgnoredPath() string {
+	return collisionPath(c.IgnoredRoot, c.IgnoredDir)
+}
+
+// collisionPath returns content/<dir>, qualified by root if it's set.
+func collisionPath(root, dir string) string {
+	if root == "" {
+		return "content/" + dir
+	}
+	return filepath.Join(root, "content", dir)
 }
 
 // scanSnootyTomlFiles scans the monorepo for snooty.toml files and builds
 // a mapping from snooty project name to content directory.
-func scanSnootyTomlFiles(monorepoPath string) (map[string]string, error) {
+//
+// Content directories are scanned in alphabetical order. If two different content
+// directories declare the same project name, the first one is kept and the collision
+// is returned so callers can warn about it. Versions of the same project sharing a
+// name are expected and are not collisions.
+func scanSnootyTomlFiles(monorepoPath string) (map[string]string, []ProjectNameCollision, error) {
 	projectToDir := make(map[string]string)
+	var collisions []ProjectNameCollision
 	contentDir := filepath.Join(monorepoPath, "content")
 
 	entries, err := os.ReadDir(contentDir)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read content directory: %w", err)
+		return nil, nil, fmt.Errorf("failed to read content directory: %w", err)
+	}
+
+	// addProject records name -> dirName unless another content directory already owns it
+	addProject := func(name, dirName string) {
+		owner, exists := projectToDir[name]
+		if !exists {
+			projectToDir[name] = dirName
+			return
+		}
+		if owner == dirName {
+			return
+		}
+		for _, c := range collisions {
+			if c.Name == name && c.IgnoredDir == dirName {
+				return
+			}
+		}
+		collisions = append(collisions, ProjectNameCollision{
+			Name:       name,
+			KeptDir:    owner,
+			IgnoredDir: dirName,
+		})
 	}
 
+	// Resolved project directories already scanned, so two symlinks to the same
+	// directory (or a symlink to another content directory) are only scanned once
+	scanned := make(map[string]bool)
+
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		// Symlinked project directories are only followed with --follow-symlinks
+		if !projectinfo.EntryIsDir(contentDir, entry) {
 			continue
 		}
 
 		dirName := entry.Name()
 		dirPath := filepath.Join(contentDir, dirName)
+		if resolved, err := filepath.EvalSymlinks(dirPath); err == nil {
+			if scanned[resolved] {
+				continue
+			}
+			scanned[resolved] = true
+		}
 
 		// Check for snooty.toml directly in the project directory
 		snootyPath := filepath.Join(dirPath, "snooty.toml")
 		if name, err := parseSnootyName(snootyPath); err == nil {
-			projectToDir[name] = dirName
+			addProject(name, dirName)
 		}
 
 		// Check for versioned subdirectories
@@ -337,7 +521,7 @@ func scanSnootyTomlFiles(monorepoPath string) (map[string]string, error) {
 		}
 
 		for _, subEntry := range subEntries {
-			if !subEntry.IsDir() {
+			if !projectinfo.EntryIsDir(dirPath, subEntry) {
 				continue
 			}
 			subDirName := subEntry.Name()
@@ -346,14 +530,59 @@ func scanSnootyTomlFiles(monorepoPath string) (map[string]string, error) {
 				// For versioned projects, store just the base directory name
 				// The version will be added from the URL during resolution
 				// Only set if not already set (prefer non-versioned snooty.toml)
-				if _, exists := projectToDir[name]; !exists {
-					projectToDir[name] = dirName
+				addProject(name, dirName)
+			}
+		}
+	}
+
+	return projectToDir, collisions, nil
+}
+
+// scanMonorepos scans each monorepo's content directory for snooty.toml files, in order,
+// and builds a mapping from snooty project name to content directory and monorepo.
+//
+// Monorepos after the first add the projects that the earlier ones don't have (e.g., a
+// driver hosted in its own repository). When two monorepos have the same project, the
+// first match wins and the later one is returned as a collision. Content directories
+// with the same name in different monorepos are fine as long as their projects differ.
+func scanMonorepos(monorepoPaths []string) (map[string]string, map[string]string, []ProjectNameCollision, error) {
+	projectToDir := make(map[string]string)
+	projectToMonorepo := make(map[string]string)
+	var collisions []ProjectNameCollision
+
+	for i, root := range monorepoPaths {
+		rootProjects, rootCollisions, err := scanSnootyTomlFiles(root)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%s: %w", root, err)
+		}
+		for _, c := range rootCollisions {
+			if i > 0 {
+				c.KeptRoot, c.IgnoredRoot = root, root
+			}
+			collisions = append(collisions, c)
+		}
+
+		names := make([]string, 0, len(rootProjects))
+		for name := range rootProjects {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			dir := rootProjects[name]
+			if owner, exists := projectToDir[name]; exists {
+				c := ProjectNameCollision{Name: name, KeptDir: owner, IgnoredDir: dir, IgnoredRoot: root}
+				if ownerRoot := projectToMonorepo[name]; ownerRoot != monorepoPaths[0] {
+					c.KeptRoot = ownerRoot
 				}
+				collisions = append(collisions, c)
+				continue
 			}
+			projectToDir[name] = dir
+			projectToMonorepo[name] = root
 		}
 	}
 
-	return projectToDir, nil
+	return projectToDir, projectToMonorepo, collisions, nil
 }
 
 // parseSnootyName extracts the name field from a snooty.toml file.
@@ -375,45 +604,78 @@ func parseSnootyName(path string) (string, error) {
 	return snootyToml.Name, nil
 }
 
-// GetURLMapping returns a URLMapping instance for resolving URLs to source files.
-// It uses cached data if available and not expired, otherwise fetches from the API.
-// Falls back to static mapping if API is unavailable.
-func GetURLMapping(monorepoPath string) (*URLMapping, error) {
-	var cache *URLMappingCache
-	var err error
-
+// loadURLMappingCache returns the cached URL mapping if it hasn't expired. Otherwise it
+// fetches (or revalidates) the mapping from the API and caches it, falling back to the
+// static mapping if the API is unavailable.
+//
+// In local mode (see SetLocal) the API isn't called: an expired cache is used if there
+// is one, and the static mapping otherwise.
+//
+// Returns the mapping and whether it is the static fallback.
+func loadURLMappingCache() (*URLMappingCache, bool) {
 	// Try to load from cache first
-	cache, err = loadCache()
-	if err != nil {
-		// Cache miss or expired, try to fetch from API
-		cache, err = fetchFromAPI()
-		if err != nil {
-			// API failed, use static fallback
-			fmt.Fprintf(os.Stderr, "Warning: Could not fetch URL mapping from API (%v), using static fallback\n", err)
-			cache = getStaticFallback()
-		} else {
-			// Save to cache for next time
-			if saveErr := saveCache(cache); saveErr != nil {
-				fmt.Fprintf(os.Stderr, "Warning: Could not save URL mapping cache: %v\n", saveErr)
-			}
+	cache, err := loadCache()
+	if err == nil {
+		return cache, false
+	}
+
+	// Cache miss or expired, try to fetch (or revalidate) from API
+	stale, _ := readCache()
+	if IsLocal() {
+		if stale != nil {
+			return stale, false
 		}
+		fmt.Fprintln(os.Stderr, "Warning: No URL mapping cache and --local is set, using static fallback")
+		return getStaticFallback(), true
 	}
+	cache, err = fetchFromAPI(SnootyDataAPIURL, stale)
+	if err != nil {
+		// API failed, use static fallback
+		fmt.Fprintf(os.Stderr, "Warning: Could not fetch URL mapping from API (%v), using static fallback\n", err)
+		return getStaticFallback(), true
+	}
+
+	// Save to cache for next time
+	if saveErr := saveCache(cache); saveErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not save URL mapping cache: %v\n", saveErr)
+	}
+	return cache, false
+}
+
+// GetURLMapping returns a URLMapping instance for resolving URLs to source files.
+// It uses cached data if available and not expired, otherwise fetches from the API.
+// Falls back to static mapping if API is unavailable.
+//
+// Projects are looked up in monorepoPath, then in each of additionalMonorepoPaths in
+// order (see scanMonorepos); the first monorepo with a project wins.
+func GetURLMapping(monorepoPath string, additionalMonorepoPaths ...string) (*URLMapping, error) {
+	cache, usedStaticFallback := loadURLMappingCache()
 
 	// Merge special cases that aren't in the API data
 	mergeSpecialCases(cache)
+	sortDriverSlugs(cache)
 
 	// Scan snooty.toml files to build project -> content dir mapping
-	projectToDir, err := scanSnootyTomlFiles(monorepoPath)
+	monorepoPaths := append([]string{monorepoPath}, additionalMonorepoPaths...)
+	projectToDir, projectToMonorepo, collisions, err := scanMonorepos(monorepoPaths)
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan snooty.toml files: %w", err)
 	}
+	for _, c := range collisions {
+		fmt.Fprintf(os.Stderr, "Warning: snooty project name %q is declared by both %s and %s; using %s\n",
+			c.Name, c.KeptPath(), c.IgnoredPath(), c.KeptPath())
+	}
 
 	return &URLMapping{
 		URLSlugToProject:    cache.Mapping,
 		ProjectToContentDir: projectToDir,
+		ProjectToMonorepo:   projectToMonorepo,
 		ProjectBranches:     cache.Branches,
 		DriverSlugs:         cache.DriverSlugs,
 		MonorepoPath:        monorepoPath,
+		UsedStaticFallback:  usedStaticFallback,
+		SlugRedirects:       newSlugRedirects(),
+		lowerSlugToProject:  indexSlugs(cache.Mapping),
 	}, nil
 }
 
@@ -421,28 +683,11 @@ func GetURLMapping(monorepoPath string) (*URLMapping, error) {
 // This is useful for operations that only need API data (like listing drivers) and don't need
 // to resolve local file paths.
 func GetURLMappingWithoutMonorepo() (*URLMapping, error) {
-	var cache *URLMappingCache
-	var err error
-
-	// Try to load from cache first
-	cache, err = loadCache()
-	if err != nil {
-		// Cache miss or expired, try to fetch from API
-		cache, err = fetchFromAPI()
-		if err != nil {
-			// API failed, use static fallback
-			fmt.Fprintf(os.Stderr, "Warning: Could not fetch URL mapping from API (%v), using static fallback\n", err)
-			cache = getStaticFallback()
-		} else {
-			// Save to cache for next time
-			if saveErr := saveCache(cache); saveErr != nil {
-				fmt.Fprintf(os.Stderr, "Warning: Could not save URL mapping cache: %v\n", saveErr)
-			}
-		}
-	}
+	cache, usedStaticFallback := loadURLMappingCache()
 
 	// Merge special cases that aren't in the API data
 	mergeSpecialCases(cache)
+	sortDriverSlugs(cache)
 
 	return &URLMapping{
 		URLSlugToProject:    cache.Mapping,
@@ -450,6 +695,9 @@ func GetURLMappingWithoutMonorepo() (*URLMapping, error) {
 		ProjectBranches:     cache.Branches,
 		DriverSlugs:         cache.DriverSlugs,
 		MonorepoPath:        "",
+		UsedStaticFallback:  usedStaticFallback,
+		SlugRedirects:       newSlugRedirects(),
+		lowerSlugToProject:  indexSlugs(cache.Mapping),
 	}, nil
 }
 
@@ -474,6 +722,61 @@ var specialPagePaths = map[string]string{
 	"get-started": "get-started", // /docs/get-started/ -> get-started.txt (not index.txt)
 }
 
+// DefaultSlugRedirects maps URL slugs that docs redirects have retired to their current
+// slugs. Analytics for old pages still report the old URLs, so Resolve rewrites them
+// rather than failing. Add entries for other redirects with slug_redirects in
+// .audit-cli.yaml.
+var DefaultSlugRedirects = map[string]string{
+	"realm":                         "atlas/device-sdks",
+	"drivers/c":                     "languages/c/c-driver",
+	"drivers/cxx":                   "languages/cpp/cpp-driver",
+	"drivers/java/reactive-streams": "languages/java/reactive-streams-driver",
+	"drivers/kotlin/sync":           "languages/kotlin/kotlin-sync-driver",
+	"drivers/pymongo":               "languages/python/pymongo-driver",
+	"drivers/pymongo-arrow":         "languages/python/pymongo-arrow-driver",
+	"drivers/scala":                 "languages/scala/scala-driver",
+}
+
+// newSlugRedirects returns a copy of DefaultSlugRedirects for a new URLMapping.
+func newSlugRedirects() map[string]string {
+	redirects := make(map[string]string, len(DefaultSlugRedirects))
+	for from, to := range DefaultSlugRedirects {
+		redirects[from] = to
+	}
+	return redirects
+}
+
+// AddSlugRedirects adds old-to-current slug redirects (e.g., from the slug_redirects config
+// entry), replacing any built-in redirect for the same old slug. Slugs are matched
+// case-insensitively and surrounding slashes are ignored.
+func (m *URLMapping) AddSlugRedirects(redirects map[string]string) {
+	if m.SlugRedirects == nil {
+		m.SlugRedirects = make(map[string]string, len(redirects))
+	}
+	for from, to := range redirects {
+		m.SlugRedirects[strings.ToLower(strings.Trim(from, "/"))] = strings.ToLower(strings.Trim(to, "/"))
+	}
+}
+
+// lookupRedirect finds the longest old slug in SlugRedirects that prefixes the lowercased
+// URL path segments. An old slug that is still a known slug is not redirected.
+//
+// Returns the old slug, its current slug, and the number of segments the old slug spans.
+func (m *URLMapping) lookupRedirect(lowerParts []string) (string, string, int, bool) {
+	for i := len(lowerParts); i > 0; i-- {
+		candidate := strings.Join(lowerParts[:i], "/")
+		to, ok := m.SlugRedirects[candidate]
+		if !ok {
+			continue
+		}
+		if _, live := m.lookupSlug(candidate); live {
+			return "", "", 0, false
+		}
+		return candidate, to, i, true
+	}
+	return "", "", 0, false
+}
+
 // specialSlugToProject maps special URL slugs to their project names.
 // These are cases not covered by the API data.
 var specialSlugToProject = map[string]string{
@@ -563,46 +866,94 @@ func getStaticFallback() *URLMappingCache {
 }
 
 // ResolveURL resolves a documentation URL to a source file path.
-// Returns the absolute path to the source file and the content directory.
+// Returns the absolute path to the source file, the content directory, and the version
+// directory the page resolved to (e.g., "current", "v8.0", "manual"). The version is
+// empty for non-versioned projects.
 //
 // URL format: www.mongodb.com/docs/{slug}/{version?}/{page-path}
 // Examples:
 //   - www.mongodb.com/docs/atlas/some-page/ -> content/atlas/source/some-page.txt
 //   - www.mongodb.com/docs/v8.0/tutorial/install/ -> content/manual/v8.0/source/tutorial/install.txt
 //   - www.mongodb.com/docs/drivers/go/current/usage/ -> content/golang/current/source/usage.txt
-func (m *URLMapping) ResolveURL(url string) (sourcePath string, contentDir string, err error) {
+func (m *URLMapping) ResolveURL(url string) (sourcePath string, contentDir string, version string, err error) {
+	resolution, err := m.Resolve(url)
+	if err != nil {
+		return "", "", "", err
+	}
+	return resolution.SourcePath, resolution.ContentDir, resolution.Version, nil
+}
+
+// URLResolution is the result of resolving a documentation URL (see Resolve).
+type URLResolution struct {
+	// SourcePath is the absolute path to the page's source file.
+	SourcePath string
+	// ContentDir is the content directory of the page's project.
+	ContentDir string
+	// Version is the version directory the page resolved to; empty for non-versioned projects.
+	Version string
+	// RequestedVersion is the version in the URL when DriverVersionFallback resolved the
+	// page to a different version directory; empty otherwise.
+	RequestedVersion string
+	// RedirectedFrom and RedirectedTo are the old and current slugs when the URL's slug
+	// was rewritten with SlugRedirects; empty otherwise.
+	RedirectedFrom string
+	RedirectedTo   string
+	// Monorepo is the additional monorepo the page's project was found in (see
+	// ProjectToMonorepo); empty for the main monorepo.
+	Monorepo string
+}
+
+// Resolve is ResolveURL with details about how the URL was resolved, including any
+// slug redirect or driver version fallback.
+func (m *URLMapping) Resolve(url string) (URLResolution, error) {
 	// Parse the URL to extract the path after /docs/
 	urlPath := extractDocsPath(url)
 	if urlPath == "" {
-		return "", "", fmt.Errorf("invalid URL format: %s", url)
+		return URLResolution{}, fmt.Errorf("%w: %s", ErrInvalidURL, url)
 	}
 
 	parts := strings.Split(urlPath, "/")
 	if len(parts) == 0 {
-		return "", "", fmt.Errorf("empty URL path")
+		return URLResolution{}, fmt.Errorf("empty URL path")
+	}
+
+	// Slugs and versions are matched case-insensitively (analytics URLs sometimes
+	// capitalize segments, e.g. /Drivers/Go/). The page path keeps its original
+	// casing because file systems may be case-sensitive.
+	lowerParts := strings.Split(strings.ToLower(urlPath), "/")
+
+	// Rewrite a retired slug (e.g., realm) to its current slug (atlas/device-sdks) first,
+	// since a shorter live slug (e.g., drivers for drivers/pymongo) would otherwise match
+	var redirectedFrom, redirectedTo string
+	if from, to, n, ok := m.lookupRedirect(lowerParts); ok {
+		redirectedFrom, redirectedTo = from, to
+		toParts := strings.Split(to, "/")
+		parts = append(toParts, parts[n:]...)
+		lowerParts = append(slices.Clone(toParts), lowerParts[n:]...)
+		urlPath = strings.Join(parts, "/")
+		url = "www.mongodb.com/docs/" + urlPath
 	}
 
 	// Try to find the longest matching slug
 	var projectName string
 	var pagePath string
-	var version string
+	var urlVersion string
 
-	for i := len(parts); i > 0; i-- {
-		candidateSlug := strings.Join(parts[:i], "/")
-		if proj, ok := m.URLSlugToProject[candidateSlug]; ok {
+	for i := len(lowerParts); i > 0; i-- {
+		candidateSlug := strings.Join(lowerParts[:i], "/")
+		if proj, ok := m.lookupSlug(candidateSlug); ok {
 			projectName = proj
 			remaining := parts[i:]
 
 			// Check if the matched slug ends with a version
 			// e.g., "drivers/go/current" matched, extract "current" as version
-			slugParts := strings.Split(candidateSlug, "/")
-			lastSlugPart := slugParts[len(slugParts)-1]
+			lastSlugPart := lowerParts[i-1]
 			if isVersionSlug(lastSlugPart) {
-				version = lastSlugPart
+				urlVersion = lastSlugPart
 				pagePath = strings.Join(remaining, "/")
-			} else if len(remaining) > 0 && isVersionSlug(remaining[0]) {
+			} else if len(remaining) > 0 && isVersionSlug(lowerParts[i]) {
 				// Check if first remaining part is a version
-				version = remaining[0]
+				urlVersion = lowerParts[i]
 				pagePath = strings.Join(remaining[1:], "/")
 			} else {
 				pagePath = strings.Join(remaining, "/")
@@ -614,20 +965,20 @@ func (m *URLMapping) ResolveURL(url string) (sourcePath string, contentDir strin
 	// Special handling for MongoDB Manual (docs project)
 	// URLs like /docs/manual/... or /docs/v8.0/... map to the "docs" project
 	if projectName == "" {
-		if len(parts) > 0 && (parts[0] == "manual" || isVersionSlug(parts[0])) {
+		if len(lowerParts) > 0 && (lowerParts[0] == "manual" || isVersionSlug(lowerParts[0])) {
 			projectName = "docs"
-			version = parts[0]
+			urlVersion = lowerParts[0]
 			pagePath = strings.Join(parts[1:], "/")
 		}
 	}
 
 	// Check for special slug mappings not in the API data
 	if projectName == "" {
-		if len(parts) > 0 {
-			if proj, ok := specialSlugToProject[parts[0]]; ok {
+		if len(lowerParts) > 0 {
+			if proj, ok := specialSlugToProject[lowerParts[0]]; ok {
 				projectName = proj
 				// For special slugs, the slug itself may be the page path
-				if specialPath, ok := specialPagePaths[parts[0]]; ok {
+				if specialPath, ok := specialPagePaths[lowerParts[0]]; ok {
 					pagePath = specialPath
 				} else {
 					pagePath = strings.Join(parts[1:], "/")
@@ -637,26 +988,39 @@ func (m *URLMapping) ResolveURL(url string) (sourcePath string, contentDir strin
 	}
 
 	if projectName == "" {
-		return "", "", fmt.Errorf("could not resolve URL slug: %s", urlPath)
+		return URLResolution{}, fmt.Errorf("%w: %s", ErrSlugNotResolved, urlPath)
 	}
 
 	// Get content directory for this project
 	contentDir, ok := m.ProjectToContentDir[projectName]
 	if !ok {
-		return "", "", fmt.Errorf("no content directory found for project: %s", projectName)
+		return URLResolution{}, fmt.Errorf("%w: %s", ErrNoContentDir, projectName)
 	}
 
 	// Build the source file path
 	// For versioned projects, the content dir already includes the version
 	// For non-versioned projects with a version in URL, we need to add it
-	sourceDir := filepath.Join(m.MonorepoPath, "content", contentDir)
+	monorepoPath := m.monorepoFor(projectName)
+	sourceDir := filepath.Join(monorepoPath, "content", contentDir)
 
 	// Check if this is a versioned project by looking for version subdirectories
 	// If the content directory has version subdirectories and URL has a version, use it
-	if version != "" {
-		versionedPath := filepath.Join(m.MonorepoPath, "content", contentDir, version)
+	var version, requestedVersion string
+	if urlVersion != "" {
+		versionedPath := filepath.Join(monorepoPath, "content", contentDir, urlVersion)
 		if _, err := os.Stat(versionedPath); err == nil {
 			sourceDir = versionedPath
+			version = urlVersion
+		} else if m.IsDriverURL(url) {
+			fallback, err := m.fallbackVersion(monorepoPath, contentDir, urlVersion)
+			if err != nil {
+				return URLResolution{}, err
+			}
+			if fallback != "" {
+				sourceDir = filepath.Join(monorepoPath, "content", contentDir, fallback)
+				version = fallback
+				requestedVersion = urlVersion
+			}
 		}
 	}
 
@@ -664,9 +1028,216 @@ func (m *URLMapping) ResolveURL(url string) (sourcePath string, contentDir strin
 	if pagePath == "" {
 		pagePath = "index"
 	}
-	sourcePath = filepath.Join(sourceDir, "source", pagePath+".txt")
+	var additionalMonorepo string
+	if monorepoPath != m.MonorepoPath {
+		additionalMonorepo = monorepoPath
+	}
+	return URLResolution{
+		SourcePath:       pageSourcePath(filepath.Join(sourceDir, "source", pagePath)),
+		ContentDir:       contentDir,
+		Version:          version,
+		RequestedVersion: requestedVersion,
+		RedirectedFrom:   redirectedFrom,
+		RedirectedTo:     redirectedTo,
+		Monorepo:         additionalMonorepo,
+	}, nil
+}
+
+// pageSourcePath returns the source file for a page path without an extension. Pages
+// are .txt files; a page migrated to Markdown is used if there is no .txt file for it.
+func pageSourcePath(base string) string {
+	if _, err := os.Stat(base + ".txt"); err != nil {
+		for _, ext := range []string{".md", ".mdx"} {
+			if _, err := os.Stat(base + ext); err == nil {
+				return base + ext
+			}
+		}
+	}
+	return base + ".txt"
+}
+
+// monorepoFor returns the monorepo whose content directory has a project.
+func (m *URLMapping) monorepoFor(project string) string {
+	if monorepoPath, ok := m.ProjectToMonorepo[project]; ok {
+		return monorepoPath
+	}
+	return m.MonorepoPath
+}
+
+// fallbackVersion picks the version directory to use for a driver URL whose version
+// directory doesn't exist, according to DriverVersionFallback.
+//
+// Returns an empty string to keep the default behavior (resolving without a version
+// directory), which nearest and current also use when the project has no version
+// directories at all. In error mode, a project without version directories is an error.
+func (m *URLMapping) fallbackVersion(monorepoPath, contentDir, requested string) (string, error) {
+	if m.DriverVersionFallback == "" || m.DriverVersionFallback == DriverVersionFallbackNone {
+		return "", nil
+	}
+
+	available := listVersionDirs(filepath.Join(monorepoPath, "content", contentDir))
+	if len(available) == 0 {
+		if m.DriverVersionFallback == DriverVersionFallbackError {
+			return "", fmt.Errorf("driver version %s not found: content/%s has no version directories", requested, contentDir)
+		}
+		return "", nil
+	}
+
+	switch m.DriverVersionFallback {
+	case DriverVersionFallbackNearest:
+		if nearest := nearestVersion(requested, available); nearest != "" {
+			return nearest, nil
+		}
+		// A named version such as "stable" has no numeric neighbor; use current
+		fallthrough
+	case DriverVersionFallbackCurrent:
+		for _, v := range available {
+			if v == "current" {
+				return v, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("driver version %s not found in content/%s (available: %s)",
+		requested, contentDir, strings.Join(available, ", "))
+}
 
-	return sourcePath, contentDir, nil
+// listVersionDirs returns the sorted names of the version directories in a content
+// directory: subdirectories with a version name and a source directory. Symlinked
+// subdirectories count when symlinks are followed (see projectinfo.EntryIsDir).
+func listVersionDirs(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var versions []string
+	for _, entry := range entries {
+		if !projectinfo.EntryIsDir(dir, entry) || !isVersionSlug(strings.ToLower(entry.Name())) {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(dir, entry.Name(), "source")); err == nil && info.IsDir() {
+			versions = append(versions, entry.Name())
+		}
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// nearestVersion returns the numbered version in available closest to requested, or an
+// empty string if requested or every available version is a named version (e.g.,
+// "current"). Versions are compared component by component, so v5.2 is nearer to v5.0
+// than to v6.2. Ties go to the newer version.
+func nearestVersion(requested string, available []string) string {
+	target, ok := projectinfo.VersionNumbers(requested)
+	if !ok {
+		return ""
+	}
+
+	var best string
+	var bestDistance []int
+	var bestNumbers []int
+	for _, v := range available {
+		numbers, ok := projectinfo.VersionNumbers(v)
+		if !ok {
+			continue
+		}
+		distance := versionDistance(target, numbers)
+		cmp := projectinfo.CompareVersionNumbers(distance, bestDistance)
+		if best == "" || cmp < 0 || (cmp == 0 && projectinfo.CompareVersionNumbers(numbers, bestNumbers) > 0) {
+			best, bestDistance, bestNumbers = v, distance, numbers
+		}
+	}
+	return best
+}
+
+// versionDistance returns the absolute difference of each version component, treating
+// missing components as 0.
+func versionDistance(a, b []int) []int {
+	size := max(len(a), len(b))
+	distance := make([]int, size)
+	for i := range distance {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		distance[i] = max(x-y, y-x)
+	}
+	return distance
+}
+
+// lookupSlug finds the project for a lowercased URL slug.
+//
+// URLSlugToProject keys come from the Snooty Data API and are matched without regard
+// to case. A mapping built without the lowercased index (a literal URLMapping) is
+// scanned instead, so lookups never write to the mapping and are safe to run
+// concurrently.
+func (m *URLMapping) lookupSlug(lowerSlug string) (string, bool) {
+	if m.lowerSlugToProject != nil {
+		project, ok := m.lowerSlugToProject[lowerSlug]
+		return project, ok
+	}
+	if project, ok := m.URLSlugToProject[lowerSlug]; ok {
+		return project, true
+	}
+	for slug, project := range m.URLSlugToProject {
+		if strings.ToLower(slug) == lowerSlug {
+			return project, true
+		}
+	}
+	return "", false
+}
+
+// indexSlugs indexes URL slugs by their lowercased form for lookupSlug.
+func indexSlugs(slugToProject map[string]string) map[string]string {
+	index := make(map[string]string, len(slugToProject))
+	for slug, project := range slugToProject {
+		index[strings.ToLower(slug)] = project
+	}
+	return index
+}
+
+// NormalizeURL canonicalizes a page URL so that variants of the same page compare equal.
+//
+// Analytics exports mix http:// and https://, hosts with and without www., and paths
+// with and without a trailing slash. The canonical form uses https://, a lowercase host
+// with www. added to a bare domain (mongodb.com -> www.mongodb.com), and a path ending in
+// a slash. A #fragment is dropped because it names a section of the same page; a query
+// string is kept. The path's case is unchanged.
+//
+// Examples:
+//   - www.mongodb.com/docs/atlas/triggers -> https://www.mongodb.com/docs/atlas/triggers/
+//   - http://MongoDB.com/docs/atlas/#setup -> https://www.mongodb.com/docs/atlas/
+func NormalizeURL(rawURL string) string {
+	url := strings.TrimSpace(rawURL)
+	if url == "" {
+		return url
+	}
+	lowerURL := strings.ToLower(url)
+	for _, scheme := range []string{"https://", "http://"} {
+		if strings.HasPrefix(lowerURL, scheme) {
+			url = url[len(scheme):]
+			break
+		}
+	}
+
+	host, path, _ := strings.Cut(url, "/")
+	host = strings.ToLower(host)
+	if strings.Count(host, ".") == 1 {
+		host = "www." + host
+	}
+
+	path, _, _ = strings.Cut(path, "#")
+	path, query, _ := strings.Cut(path, "?")
+	if path != "" && !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+	normalized := "https://" + host + "/" + path
+	if query != "" {
+		normalized += "?" + query
+	}
+	return normalized
 }
 
 // extractDocsPath extracts the path after /docs/ from a URL.
@@ -676,11 +1247,12 @@ func extractDocsPath(url string) string {
 	url = strings.TrimPrefix(url, "http://")
 	url = strings.TrimPrefix(url, "www.")
 
-	// Find /docs/ in the path
-	idx := strings.Index(url, "/docs/")
+	// Find /docs/ in the path (case-insensitive, e.g. /Docs/)
+	lowerURL := strings.ToLower(url)
+	idx := strings.Index(lowerURL, "/docs/")
 	if idx == -1 {
 		// Try without leading slash
-		idx = strings.Index(url, "docs/")
+		idx = strings.Index(lowerURL, "docs/")
 		if idx == -1 {
 			return ""
 		}
@@ -689,8 +1261,8 @@ func extractDocsPath(url string) string {
 		url = url[idx+6:]
 	}
 
-	// Remove trailing slash
-	url = strings.TrimSuffix(url, "/")
+	// Remove trailing slashes
+	url = strings.TrimRight(url, "/")
 
 	return url
 }
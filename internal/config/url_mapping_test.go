@@ -1,9 +1,49 @@
 package config
 
 import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/grove-platform/audit-cli/internal/projectinfo"
 )
 
+// TestLoadURLMappingCacheLocal tests that --local uses an expired cache or the static
+// fallback instead of calling the API, and doesn't write the cache.
+func TestLoadURLMappingCacheLocal(t *testing.T) {
+	SetCacheDir(t.TempDir())
+	defer SetCacheDir("")
+	SetLocal(true)
+	defer SetLocal(false)
+
+	cache, usedStaticFallback := loadURLMappingCache()
+	if !usedStaticFallback || len(cache.Mapping) == 0 {
+		t.Errorf("Expected the static fallback without a cache, got %d mappings (fallback %v)", len(cache.Mapping), usedStaticFallback)
+	}
+	if _, err := readCache(); err == nil {
+		t.Error("Expected no cache file to be written with --local")
+	}
+
+	expired := &URLMappingCache{
+		Timestamp: time.Now().Add(-2 * CacheTTL),
+		Mapping:   map[string]string{"drivers/go": "golang"},
+	}
+	if err := saveCache(expired); err != nil {
+		t.Fatalf("saveCache failed: %v", err)
+	}
+	cache, usedStaticFallback = loadURLMappingCache()
+	if usedStaticFallback || cache.Mapping["drivers/go"] != "golang" || len(cache.Mapping) != 1 {
+		t.Errorf("Expected the expired cache, got %v (fallback %v)", cache.Mapping, usedStaticFallback)
+	}
+}
+
 // TestIsActive tests the isActive helper function.
 func TestIsActive(t *testing.T) {
 	testCases := []struct {
@@ -92,6 +132,8 @@ func TestIsVersionSlug(t *testing.T) {
 		{"master", "master", true},
 		{"latest", "latest", true},
 		{"manual", "manual", true},
+		{"beta", "beta", true},
+		{"alpha", "alpha", true},
 
 		// Numeric versions
 		{"v8.0", "v8.0", true},
@@ -102,6 +144,13 @@ func TestIsVersionSlug(t *testing.T) {
 		{"1.0.0 semver", "1.0.0", true},
 		{"v1.0.0 semver with v", "v1.0.0", true},
 
+		// Pre-release and date-based versions
+		{"release candidate", "v8.0-rc0", true},
+		{"release candidate without number", "v8.0-rc", true},
+		{"beta release", "v2.0-beta1", true},
+		{"alpha release without v", "2.0-alpha", true},
+		{"year and month", "2024-01", true},
+
 		// Non-versions
 		{"project name", "pymongo", false},
 		{"drivers prefix", "drivers", false},
@@ -109,6 +158,10 @@ func TestIsVersionSlug(t *testing.T) {
 		{"empty string", "", false},
 		{"partial version", "v", false},
 		{"invalid version", "vX.Y", false},
+		{"unknown pre-release", "v8.0-preview", false},
+		{"full date", "2024-01-15", false},
+		{"short year", "24-01", false},
+		{"named beta page", "beta-features", false},
 	}
 
 	for _, tc := range testCases {
@@ -142,6 +195,8 @@ func TestExtractDocsPath(t *testing.T) {
 		// Edge cases
 		{"trailing slash removed", "https://mongodb.com/docs/atlas/", "atlas"},
 		{"no trailing slash", "https://mongodb.com/docs/atlas", "atlas"},
+		{"repeated trailing slashes removed", "https://mongodb.com/docs/atlas/search//", "atlas/search"},
+		{"mixed-case docs segment", "https://www.mongodb.com/Docs/Atlas/Search/", "Atlas/Search"},
 		{"deep path", "https://mongodb.com/docs/drivers/node/current/fundamentals/crud/", "drivers/node/current/fundamentals/crud"},
 
 		// Invalid URLs
@@ -160,6 +215,30 @@ func TestExtractDocsPath(t *testing.T) {
 	}
 }
 
+// TestNormalizeURL tests canonicalizing variants of the same page URL.
+func TestNormalizeURL(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"www.mongodb.com/docs/atlas/triggers/", "https://www.mongodb.com/docs/atlas/triggers/"},
+		{"https://www.mongodb.com/docs/atlas/triggers", "https://www.mongodb.com/docs/atlas/triggers/"},
+		{"http://mongodb.com/docs/atlas/triggers/", "https://www.mongodb.com/docs/atlas/triggers/"},
+		{"HTTPS://WWW.MongoDB.com/docs/Atlas/", "https://www.mongodb.com/docs/Atlas/"},
+		{"  www.mongodb.com/docs/atlas/#setup  ", "https://www.mongodb.com/docs/atlas/"},
+		{"www.mongodb.com/docs/atlas/search?tab=go#step-2", "https://www.mongodb.com/docs/atlas/search/?tab=go"},
+		{"mongodb.com", "https://www.mongodb.com/"},
+		{"learn.mongodb.com/courses", "https://learn.mongodb.com/courses/"},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		if got := NormalizeURL(tc.input); got != tc.expected {
+			t.Errorf("NormalizeURL(%q) = %q, want %q", tc.input, got, tc.expected)
+		}
+	}
+}
+
 // createTestURLMapping creates a URLMapping for testing with sample driver data.
 func createTestURLMapping() *URLMapping {
 	return &URLMapping{
@@ -337,40 +416,615 @@ func TestGetDriverSlugs(t *testing.T) {
 	}
 }
 
-// TestSortStrings tests the sortStrings helper function.
-func TestSortStrings(t *testing.T) {
+// TestSortDriverSlugs tests that equivalent driver slug lists produce the same order.
+func TestSortDriverSlugs(t *testing.T) {
+	api := &URLMappingCache{DriverSlugs: []string{"ruby-driver", "drivers/node", "drivers/go", "drivers/node"}}
+	cached := &URLMappingCache{DriverSlugs: []string{"drivers/go", "drivers/node", "ruby-driver"}}
+	sortDriverSlugs(api)
+	sortDriverSlugs(cached)
+
+	expected := []string{"drivers/go", "drivers/node", "ruby-driver"}
+	for _, cache := range []*URLMappingCache{api, cached} {
+		m := &URLMapping{DriverSlugs: cache.DriverSlugs}
+		if got := m.GetDriverSlugs(); !slices.Equal(got, expected) {
+			t.Errorf("GetDriverSlugs() = %v, want %v", got, expected)
+		}
+	}
+
+	fallback := getStaticFallback()
+	want := slices.Clone(fallback.DriverSlugs)
+	sortDriverSlugs(fallback)
+	if !slices.Equal(fallback.DriverSlugs, want) {
+		t.Errorf("Expected the static fallback driver slugs to already be sorted, got %v", want)
+	}
+}
+
+// writeSnootyToml writes a snooty.toml with the given project name under contentDir/relDir.
+func writeSnootyToml(t *testing.T, contentDir, relDir, name string) {
+	t.Helper()
+	dir := filepath.Join(contentDir, relDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create %s: %v", dir, err)
+	}
+	content := "name = \"" + name + "\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "snooty.toml"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write snooty.toml in %s: %v", dir, err)
+	}
+}
+
+// TestScanSnootyTomlFiles tests building the project name to content directory mapping.
+func TestScanSnootyTomlFiles(t *testing.T) {
+	monorepoPath := t.TempDir()
+	contentDir := filepath.Join(monorepoPath, "content")
+	writeSnootyToml(t, contentDir, "atlas", "cloud-docs")
+	writeSnootyToml(t, contentDir, filepath.Join("manual", "manual"), "docs")
+	writeSnootyToml(t, contentDir, filepath.Join("manual", "v8.0"), "docs")
+
+	projectToDir, collisions, err := scanSnootyTomlFiles(monorepoPath)
+	if err != nil {
+		t.Fatalf("scanSnootyTomlFiles failed: %v", err)
+	}
+
+	if projectToDir["cloud-docs"] != "atlas" {
+		t.Errorf("Expected cloud-docs -> atlas, got %q", projectToDir["cloud-docs"])
+	}
+	if projectToDir["docs"] != "manual" {
+		t.Errorf("Expected docs -> manual, got %q", projectToDir["docs"])
+	}
+	// Versions of the same project share a name and are not collisions
+	if len(collisions) != 0 {
+		t.Errorf("Expected no collisions, got %+v", collisions)
+	}
+}
+
+// TestScanSnootyTomlFilesCollision tests that a duplicate project name keeps the first directory.
+func TestScanSnootyTomlFilesCollision(t *testing.T) {
+	monorepoPath := t.TempDir()
+	contentDir := filepath.Join(monorepoPath, "content")
+	writeSnootyToml(t, contentDir, "atlas", "cloud-docs")
+	writeSnootyToml(t, contentDir, filepath.Join("atlas-legacy", "current"), "cloud-docs")
+	writeSnootyToml(t, contentDir, filepath.Join("atlas-legacy", "v1.0"), "cloud-docs")
+
+	projectToDir, collisions, err := scanSnootyTomlFiles(monorepoPath)
+	if err != nil {
+		t.Fatalf("scanSnootyTomlFiles failed: %v", err)
+	}
+
+	if projectToDir["cloud-docs"] != "atlas" {
+		t.Errorf("Expected first directory to be kept (atlas), got %q", projectToDir["cloud-docs"])
+	}
+
+	// Both versioned snooty.toml files collide with atlas, but are reported once
+	if len(collisions) != 1 {
+		t.Fatalf("Expected 1 collision, got %d: %+v", len(collisions), collisions)
+	}
+	expected := ProjectNameCollision{Name: "cloud-docs", KeptDir: "atlas", IgnoredDir: "atlas-legacy"}
+	if collisions[0] != expected {
+		t.Errorf("Expected collision %+v, got %+v", expected, collisions[0])
+	}
+}
+
+// TestScanMonorepos tests looking up projects across monorepos, first match wins.
+func TestScanMonorepos(t *testing.T) {
+	mainRepo := t.TempDir()
+	writeSnootyToml(t, filepath.Join(mainRepo, "content"), "atlas", "cloud-docs")
+	writeSnootyToml(t, filepath.Join(mainRepo, "content"), filepath.Join("golang", "current"), "golang")
+	driverRepo := t.TempDir()
+	writeSnootyToml(t, filepath.Join(driverRepo, "content"), filepath.Join("rust", "current"), "rust")
+	// Same project as the main monorepo, and a different project in a same-named directory
+	writeSnootyToml(t, filepath.Join(driverRepo, "content"), "golang-fork", "golang")
+	writeSnootyToml(t, filepath.Join(driverRepo, "content"), "atlas", "atlas-cli")
+
+	projectToDir, projectToMonorepo, collisions, err := scanMonorepos([]string{mainRepo, driverRepo})
+	if err != nil {
+		t.Fatalf("scanMonorepos failed: %v", err)
+	}
+
+	expected := map[string][2]string{
+		"cloud-docs": {"atlas", mainRepo},
+		"golang":     {"golang", mainRepo},
+		"rust":       {"rust", driverRepo},
+		"atlas-cli":  {"atlas", driverRepo},
+	}
+	for project, want := range expected {
+		if projectToDir[project] != want[0] || projectToMonorepo[project] != want[1] {
+			t.Errorf("%s: expected %s in %s, got %s in %s",
+				project, want[0], want[1], projectToDir[project], projectToMonorepo[project])
+		}
+	}
+
+	if len(collisions) != 1 {
+		t.Fatalf("Expected 1 collision, got %d: %+v", len(collisions), collisions)
+	}
+	c := collisions[0]
+	if c.Name != "golang" || c.KeptPath() != "content/golang" || c.IgnoredPath() != filepath.Join(driverRepo, "content", "golang-fork") {
+		t.Errorf("Unexpected collision: %+v (%s, %s)", c, c.KeptPath(), c.IgnoredPath())
+	}
+
+	if _, _, _, err := scanMonorepos([]string{mainRepo, filepath.Join(driverRepo, "missing")}); err == nil {
+		t.Error("Expected an error for a monorepo with no content directory")
+	}
+}
+
+// TestResolveURLAdditionalMonorepo tests resolving a page of a project in another monorepo.
+func TestResolveURLAdditionalMonorepo(t *testing.T) {
+	mainRepo := t.TempDir()
+	driverRepo := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(driverRepo, "content", "rust", "current", "source"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	mapping := &URLMapping{
+		URLSlugToProject:    map[string]string{"drivers/rust": "rust", "atlas": "cloud-docs"},
+		ProjectToContentDir: map[string]string{"rust": "rust", "cloud-docs": "atlas"},
+		ProjectToMonorepo:   map[string]string{"rust": driverRepo},
+		MonorepoPath:        mainRepo,
+	}
+
+	sourcePath, contentDir, version, err := mapping.ResolveURL("www.mongodb.com/docs/drivers/rust/current/quick-start/")
+	if err != nil {
+		t.Fatalf("ResolveURL failed: %v", err)
+	}
+	expected := filepath.Join(driverRepo, "content", "rust", "current", "source", "quick-start.txt")
+	if sourcePath != expected || contentDir != "rust" || version != "current" {
+		t.Errorf("Expected %s (rust, current), got %s (%s, %s)", expected, sourcePath, contentDir, version)
+	}
+	if resolution, err := mapping.Resolve("www.mongodb.com/docs/drivers/rust/current/quick-start/"); err != nil || resolution.Monorepo != driverRepo {
+		t.Errorf("Expected the resolution to name monorepo %s, got %q (%v)", driverRepo, resolution.Monorepo, err)
+	}
+
+	sourcePath, _, _, err = mapping.ResolveURL("www.mongodb.com/docs/atlas/triggers/")
+	if err != nil {
+		t.Fatalf("ResolveURL failed: %v", err)
+	}
+	if want := filepath.Join(mainRepo, "content", "atlas", "source", "triggers.txt"); sourcePath != want {
+		t.Errorf("Expected %s, got %s", want, sourcePath)
+	}
+	if resolution, err := mapping.Resolve("www.mongodb.com/docs/atlas/triggers/"); err != nil || resolution.Monorepo != "" {
+		t.Errorf("Expected no monorepo for the main monorepo, got %q (%v)", resolution.Monorepo, err)
+	}
+}
+
+// TestResolveURLSentinelErrors tests that resolution failures wrap the sentinel errors,
+// so callers can classify them with errors.Is.
+func TestResolveURLSentinelErrors(t *testing.T) {
+	mapping := &URLMapping{
+		URLSlugToProject:    map[string]string{"atlas": "cloud-docs", "compass": "compass"},
+		ProjectToContentDir: map[string]string{"cloud-docs": "atlas"},
+		MonorepoPath:        t.TempDir(),
+	}
+
 	testCases := []struct {
-		name     string
-		input    []string
-		expected []string
+		url      string
+		expected error
 	}{
-		{"already sorted", []string{"a", "b", "c"}, []string{"a", "b", "c"}},
-		{"reverse order", []string{"c", "b", "a"}, []string{"a", "b", "c"}},
-		{"mixed order", []string{"banana", "apple", "cherry"}, []string{"apple", "banana", "cherry"}},
-		{"empty slice", []string{}, []string{}},
-		{"single element", []string{"only"}, []string{"only"}},
-		{"duplicates", []string{"b", "a", "b", "a"}, []string{"a", "a", "b", "b"}},
+		{"www.mongodb.com/blog/post", ErrInvalidURL},
+		{"www.mongodb.com/docs/no-such-project/page/", ErrSlugNotResolved},
+		{"www.mongodb.com/docs/compass/page/", ErrNoContentDir},
+	}
+	for _, tc := range testCases {
+		_, _, _, err := mapping.ResolveURL(tc.url)
+		if !errors.Is(err, tc.expected) {
+			t.Errorf("ResolveURL(%q) error = %v, expected it to wrap %v", tc.url, err, tc.expected)
+		}
+	}
+}
+
+// TestResolveURLMarkdownPage tests resolving a page that has a Markdown source file.
+func TestResolveURLMarkdownPage(t *testing.T) {
+	monorepoPath := t.TempDir()
+	sourceDir := filepath.Join(monorepoPath, "content", "atlas", "source")
+	if err := os.MkdirAll(sourceDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"triggers.md", "migrated.mdx", "both.txt", "both.md"} {
+		if err := os.WriteFile(filepath.Join(sourceDir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mapping := &URLMapping{
+		URLSlugToProject:    map[string]string{"atlas": "cloud-docs"},
+		ProjectToContentDir: map[string]string{"cloud-docs": "atlas"},
+		MonorepoPath:        monorepoPath,
+	}
+	for page, want := range map[string]string{
+		"triggers": "triggers.md",
+		"migrated": "migrated.mdx",
+		"both":     "both.txt",
+		"missing":  "missing.txt",
+	} {
+		sourcePath, _, _, err := mapping.ResolveURL("www.mongodb.com/docs/atlas/" + page + "/")
+		if err != nil {
+			t.Fatalf("ResolveURL failed: %v", err)
+		}
+		if sourcePath != filepath.Join(sourceDir, want) {
+			t.Errorf("Expected %s, got %s", filepath.Join(sourceDir, want), sourcePath)
+		}
+	}
+}
+
+// TestScanSnootyTomlFilesFollowSymlinks tests scanning symlinked project directories.
+func TestScanSnootyTomlFilesFollowSymlinks(t *testing.T) {
+	defer projectinfo.SetFollowSymlinks(false)
+
+	monorepoPath := t.TempDir()
+	contentDir := filepath.Join(monorepoPath, "content")
+	writeSnootyToml(t, contentDir, "atlas", "cloud-docs")
+	external := t.TempDir()
+	writeSnootyToml(t, external, filepath.Join("golang", "current"), "golang")
+	if err := os.Symlink(filepath.Join(external, "golang"), filepath.Join(contentDir, "golang")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	// A second link to a directory that was already scanned is ignored, not a collision
+	if err := os.Symlink(filepath.Join(contentDir, "atlas"), filepath.Join(contentDir, "zz-atlas-link")); err != nil {
+		t.Fatal(err)
+	}
+
+	projectToDir, _, err := scanSnootyTomlFiles(monorepoPath)
+	if err != nil {
+		t.Fatalf("scanSnootyTomlFiles failed: %v", err)
+	}
+	if _, ok := projectToDir["golang"]; ok {
+		t.Errorf("Expected symlinked project to be skipped by default, got %v", projectToDir)
+	}
+
+	projectinfo.SetFollowSymlinks(true)
+	projectToDir, collisions, err := scanSnootyTomlFiles(monorepoPath)
+	if err != nil {
+		t.Fatalf("scanSnootyTomlFiles failed: %v", err)
+	}
+	if projectToDir["golang"] != "golang" || projectToDir["cloud-docs"] != "atlas" {
+		t.Errorf("Expected golang -> golang and cloud-docs -> atlas, got %v", projectToDir)
+	}
+	if len(collisions) != 0 {
+		t.Errorf("Expected no collisions, got %+v", collisions)
+	}
+}
+
+// TestListVersionDirsFollowSymlinks tests listing symlinked version directories.
+func TestListVersionDirsFollowSymlinks(t *testing.T) {
+	defer projectinfo.SetFollowSymlinks(false)
+
+	projectDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(projectDir, "v1.0", "source"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	external := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(external, "source"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(external, filepath.Join(projectDir, "v2.0")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	if versions := listVersionDirs(projectDir); !slices.Equal(versions, []string{"v1.0"}) {
+		t.Errorf("Expected the symlinked version to be skipped by default, got %v", versions)
+	}
+	projectinfo.SetFollowSymlinks(true)
+	if versions := listVersionDirs(projectDir); !slices.Equal(versions, []string{"v1.0", "v2.0"}) {
+		t.Errorf("Expected v1.0 and the symlinked v2.0, got %v", versions)
+	}
+}
+
+// TestResolveURLSlugRedirects tests resolving URLs whose slug was retired by a docs redirect.
+func TestResolveURLSlugRedirects(t *testing.T) {
+	mapping := &URLMapping{
+		URLSlugToProject: map[string]string{
+			"atlas/device-sdks":               "realm",
+			"atlas":                           "cloud-docs",
+			"drivers":                         "drivers",
+			"drivers/c":                       "c-legacy",
+			"languages/python/pymongo-driver": "pymongo",
+		},
+		ProjectToContentDir: map[string]string{
+			"realm":      "realm",
+			"cloud-docs": "atlas",
+			"drivers":    "drivers",
+			"c-legacy":   "c-legacy",
+			"pymongo":    "pymongo",
+		},
+		MonorepoPath:  "/repo",
+		SlugRedirects: newSlugRedirects(),
+	}
+	mapping.AddSlugRedirects(map[string]string{"/Atlas/Data-Lake/": "atlas/data-federation"})
+
+	testCases := []struct {
+		name         string
+		url          string
+		expectedPath string
+		expectedFrom string
+		expectedTo   string
+	}{
+		{"realm to atlas/device-sdks", "www.mongodb.com/docs/realm/sdk/swift/",
+			"/repo/content/realm/source/sdk/swift.txt", "realm", "atlas/device-sdks"},
+		{"old driver slug under a live parent slug", "www.mongodb.com/docs/drivers/pymongo/current/tutorial/",
+			"/repo/content/pymongo/source/tutorial.txt", "drivers/pymongo", "languages/python/pymongo-driver"},
+		{"redirect from config", "www.mongodb.com/docs/atlas/data-lake/overview/",
+			"/repo/content/atlas/source/data-federation/overview.txt", "atlas/data-lake", "atlas/data-federation"},
+		{"a live slug is not redirected", "www.mongodb.com/docs/drivers/c/tutorial/",
+			"/repo/content/c-legacy/source/tutorial.txt", "", ""},
+		{"current slug is unchanged", "www.mongodb.com/docs/atlas/device-sdks/sdk/swift/",
+			"/repo/content/realm/source/sdk/swift.txt", "", ""},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			// Make a copy to avoid modifying the test case
-			input := make([]string, len(tc.input))
-			copy(input, tc.input)
+			resolution, err := mapping.Resolve(tc.url)
+			if err != nil {
+				t.Fatalf("Resolve(%q) failed: %v", tc.url, err)
+			}
+			if resolution.SourcePath != tc.expectedPath {
+				t.Errorf("Resolve(%q) path = %q, expected %q", tc.url, resolution.SourcePath, tc.expectedPath)
+			}
+			if resolution.RedirectedFrom != tc.expectedFrom || resolution.RedirectedTo != tc.expectedTo {
+				t.Errorf("Resolve(%q) redirect = %q -> %q, expected %q -> %q",
+					tc.url, resolution.RedirectedFrom, resolution.RedirectedTo, tc.expectedFrom, tc.expectedTo)
+			}
+		})
+	}
+}
 
-			sortStrings(input)
+// TestLookupSlug tests that an indexed mapping and a literal mapping find slugs
+// case-insensitively, and that concurrent lookups don't write to the mapping.
+func TestLookupSlug(t *testing.T) {
+	slugs := map[string]string{"drivers/Go": "golang", "manual": "docs"}
+	for _, mapping := range []*URLMapping{
+		{URLSlugToProject: slugs, lowerSlugToProject: indexSlugs(slugs)},
+		{URLSlugToProject: slugs},
+	} {
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if project, ok := mapping.lookupSlug("drivers/go"); !ok || project != "golang" {
+					t.Errorf("Expected drivers/go to resolve to golang, got %q, %v", project, ok)
+				}
+				if _, ok := mapping.lookupSlug("drivers/rust"); ok {
+					t.Error("Expected drivers/rust not to resolve")
+				}
+			}()
+		}
+		wg.Wait()
+	}
+}
 
-			if len(input) != len(tc.expected) {
-				t.Errorf("Length mismatch: got %d, expected %d", len(input), len(tc.expected))
-				return
+// TestResolveURLCaseInsensitive tests that slug and version matching ignores case
+// while the page path keeps its original casing.
+func TestResolveURLCaseInsensitive(t *testing.T) {
+	monorepo := t.TempDir()
+	for _, dir := range []string{"golang/current", "manual/manual", "manual/v8.0"} {
+		if err := os.MkdirAll(filepath.Join(monorepo, "content", dir, "source"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mapping := &URLMapping{
+		URLSlugToProject: map[string]string{
+			"drivers/go": "golang",
+			"manual":     "docs",
+		},
+		ProjectToContentDir: map[string]string{
+			"golang": "golang",
+			"docs":   "manual",
+		},
+		MonorepoPath: monorepo,
+	}
+
+	testCases := []struct {
+		name            string
+		url             string
+		expectedDir     string
+		expectedVersion string
+		expected        string
+	}{
+		{
+			name:            "lowercase driver URL",
+			url:             "www.mongodb.com/docs/drivers/go/current/usage-examples/",
+			expectedDir:     "golang",
+			expectedVersion: "current",
+			expected:        filepath.Join("golang", "current", "source", "usage-examples.txt"),
+		},
+		{
+			name:            "mixed-case driver URL",
+			url:             "https://www.mongodb.com/docs/Drivers/Go/Current/Usage-Examples/",
+			expectedDir:     "golang",
+			expectedVersion: "current",
+			expected:        filepath.Join("golang", "current", "source", "Usage-Examples.txt"),
+		},
+		{
+			name:            "mixed-case manual URL",
+			url:             "www.mongodb.com/docs/Manual/Tutorial/Install/",
+			expectedDir:     "manual",
+			expectedVersion: "manual",
+			expected:        filepath.Join("manual", "manual", "source", "Tutorial", "Install.txt"),
+		},
+		{
+			name:            "mixed-case manual version URL",
+			url:             "www.mongodb.com/docs/V8.0/reference/",
+			expectedDir:     "manual",
+			expectedVersion: "v8.0",
+			expected:        filepath.Join("manual", "v8.0", "source", "reference.txt"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sourcePath, contentDir, version, err := mapping.ResolveURL(tc.url)
+			if err != nil {
+				t.Fatalf("ResolveURL(%q) failed: %v", tc.url, err)
+			}
+			if contentDir != tc.expectedDir {
+				t.Errorf("ResolveURL(%q) content dir = %q, expected %q", tc.url, contentDir, tc.expectedDir)
+			}
+			if version != tc.expectedVersion {
+				t.Errorf("ResolveURL(%q) version = %q, expected %q", tc.url, version, tc.expectedVersion)
+			}
+			expected := filepath.Join(monorepo, "content", tc.expected)
+			if sourcePath != expected {
+				t.Errorf("ResolveURL(%q) = %q, expected %q", tc.url, sourcePath, expected)
 			}
+		})
+	}
+}
+
+// TestResolveURLVersion tests the version returned for versioned and non-versioned projects.
+func TestResolveURLVersion(t *testing.T) {
+	monorepo := t.TempDir()
+	for _, dir := range []string{"atlas", "golang/current", "golang/v1.12", "golang/v2.0-rc0"} {
+		if err := os.MkdirAll(filepath.Join(monorepo, "content", dir, "source"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mapping := &URLMapping{
+		URLSlugToProject: map[string]string{
+			"atlas":      "cloud-docs",
+			"drivers/go": "golang",
+		},
+		ProjectToContentDir: map[string]string{
+			"cloud-docs": "atlas",
+			"golang":     "golang",
+		},
+		MonorepoPath: monorepo,
+	}
+
+	testCases := []struct {
+		url      string
+		expected string
+	}{
+		{"www.mongodb.com/docs/atlas/triggers/", ""},
+		{"www.mongodb.com/docs/drivers/go/current/usage-examples/", "current"},
+		{"www.mongodb.com/docs/drivers/go/v1.12/usage-examples/", "v1.12"},
+		{"www.mongodb.com/docs/drivers/go/v2.0-rc0/usage-examples/", "v2.0-rc0"},
+		// No v2.0 directory, so the page is not resolved to that version
+		{"www.mongodb.com/docs/drivers/go/v2.0/usage-examples/", ""},
+	}
+
+	for _, tc := range testCases {
+		_, _, version, err := mapping.ResolveURL(tc.url)
+		if err != nil {
+			t.Fatalf("ResolveURL(%q) failed: %v", tc.url, err)
+		}
+		if version != tc.expected {
+			t.Errorf("ResolveURL(%q) version = %q, expected %q", tc.url, version, tc.expected)
+		}
+	}
+}
+
+// TestResolveURLDriverVersionFallback tests resolving driver URLs whose version directory is missing.
+func TestResolveURLDriverVersionFallback(t *testing.T) {
+	monorepo := t.TempDir()
+	for _, dir := range []string{"node/current", "node/v5.0", "node/v6.2", "node/v6.8", "golang", "atlas"} {
+		if err := os.MkdirAll(filepath.Join(monorepo, "content", dir, "source"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
 
-			for i, v := range input {
-				if v != tc.expected[i] {
-					t.Errorf("At index %d: got %q, expected %q", i, v, tc.expected[i])
+	newMapping := func(fallback string) *URLMapping {
+		return &URLMapping{
+			URLSlugToProject:      map[string]string{"drivers/node": "node", "drivers/go": "golang", "atlas": "cloud-docs"},
+			ProjectToContentDir:   map[string]string{"node": "node", "golang": "golang", "cloud-docs": "atlas"},
+			DriverSlugs:           []string{"drivers/node", "drivers/go"},
+			MonorepoPath:          monorepo,
+			DriverVersionFallback: fallback,
+		}
+	}
+
+	testCases := []struct {
+		name              string
+		fallback          string
+		url               string
+		expectedVersion   string
+		expectedRequested string
+		expectedError     string
+	}{
+		{"default keeps today's behavior", "", "www.mongodb.com/docs/drivers/node/v5.2/usage/", "", "", ""},
+		{"none keeps today's behavior", DriverVersionFallbackNone, "www.mongodb.com/docs/drivers/node/v5.2/usage/", "", "", ""},
+		{"existing version is unchanged", DriverVersionFallbackNearest, "www.mongodb.com/docs/drivers/node/v6.2/usage/", "v6.2", "", ""},
+		{"nearest same major", DriverVersionFallbackNearest, "www.mongodb.com/docs/drivers/node/v5.2/usage/", "v5.0", "v5.2", ""},
+		{"nearest minor", DriverVersionFallbackNearest, "www.mongodb.com/docs/drivers/node/v6.6/usage/", "v6.8", "v6.6", ""},
+		{"nearest tie prefers newer", DriverVersionFallbackNearest, "www.mongodb.com/docs/drivers/node/v6.5/usage/", "v6.8", "v6.5", ""},
+		{"nearest named version uses current", DriverVersionFallbackNearest, "www.mongodb.com/docs/drivers/node/upcoming/usage/", "current", "upcoming", ""},
+		{"current", DriverVersionFallbackCurrent, "www.mongodb.com/docs/drivers/node/v5.2/usage/", "current", "v5.2", ""},
+		{"error", DriverVersionFallbackError, "www.mongodb.com/docs/drivers/node/v5.2/usage/", "", "", "available: current, v5.0, v6.2, v6.8"},
+		{"nearest without version directories", DriverVersionFallbackNearest, "www.mongodb.com/docs/drivers/go/v1.2/usage/", "", "", ""},
+		{"error without version directories", DriverVersionFallbackError, "www.mongodb.com/docs/drivers/go/v1.2/usage/", "", "", "content/golang has no version directories"},
+		{"non-driver URL is unchanged", DriverVersionFallbackError, "www.mongodb.com/docs/atlas/v1.0/triggers/", "", "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resolution, err := newMapping(tc.fallback).Resolve(tc.url)
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve(%q) failed: %v", tc.url, err)
+			}
+			if resolution.Version != tc.expectedVersion || resolution.RequestedVersion != tc.expectedRequested {
+				t.Errorf("Resolve(%q) version = %q (requested %q), expected %q (requested %q)",
+					tc.url, resolution.Version, resolution.RequestedVersion, tc.expectedVersion, tc.expectedRequested)
+			}
+			if tc.expectedVersion != "" {
+				expected := filepath.Join(monorepo, "content", "node", tc.expectedVersion, "source", "usage.txt")
+				if resolution.SourcePath != expected {
+					t.Errorf("Resolve(%q) path = %q, expected %q", tc.url, resolution.SourcePath, expected)
 				}
 			}
 		})
 	}
 }
 
+// TestFetchFromAPIConditional tests revalidating an expired URL mapping cache with Last-Modified.
+func TestFetchFromAPIConditional(t *testing.T) {
+	const lastModified = "Wed, 01 Jan 2025 00:00:00 GMT"
+	const body = `{"data": [{"project": "golang", "branches": [{"active": true, "fullUrl": "https://www.mongodb.com/docs/drivers/go/current/", "label": "current"}]}]}`
+	var requests, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-Modified-Since") == lastModified {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", lastModified)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	cache, err := fetchFromAPI(server.URL, nil)
+	if err != nil {
+		t.Fatalf("fetchFromAPI failed: %v", err)
+	}
+	if cache.LastModified != lastModified || cache.Mapping["drivers/go"] != "golang" {
+		t.Fatalf("Expected the mapping and its Last-Modified, got %+v", cache)
+	}
+
+	// An expired cache with validators is revalidated: a 304 keeps the mapping with a new timestamp
+	stale := &URLMappingCache{
+		Timestamp:      time.Now().Add(-2 * CacheTTL),
+		Mapping:        map[string]string{"cached": "cached"},
+		HTTPValidators: cache.HTTPValidators,
+	}
+	revalidated, err := fetchFromAPI(server.URL, stale)
+	if err != nil {
+		t.Fatalf("fetchFromAPI with a stale cache failed: %v", err)
+	}
+	if notModified != 1 || revalidated.Mapping["cached"] != "cached" {
+		t.Errorf("Expected the cached mapping after a 304, got %+v", revalidated.Mapping)
+	}
+	if time.Since(revalidated.Timestamp) > time.Minute {
+		t.Errorf("Expected a revalidated cache to get a new timestamp, got %v", revalidated.Timestamp)
+	}
+
+	// A cache without validators is fetched in full
+	full, err := fetchFromAPI(server.URL, &URLMappingCache{Mapping: map[string]string{"cached": "cached"}})
+	if err != nil || full.Mapping["drivers/go"] != "golang" || requests != 3 {
+		t.Errorf("Expected a full fetch without validators, got %+v, %v", full, err)
+	}
+}
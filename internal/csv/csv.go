@@ -0,0 +1,98 @@
+// Package csv provides CSV quoting and writing shared by the commands with CSV output.
+//
+// This package provides:
+//   - Escape, which quotes a field for CSV when needed
+//   - Writer, a minimal row writer that escapes every field with Escape
+//
+// WHY NOT encoding/csv:
+// Several outputs build rows with fmt.Fprintf and only need fields quoted, and the
+// standard library writer has no hook for guarding fields against spreadsheet formula
+// injection. Keeping the quoting rules here gives every CSV output the same edge-case
+// handling (embedded quotes, CR and LF, formula prefixes).
+package csv
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// formulaPrefix is prepended to a field that a spreadsheet would otherwise evaluate as a
+// formula, so it is shown as text.
+const formulaPrefix = "'"
+
+// formulaStarts are the leading characters that make Excel, Google Sheets, and LibreOffice
+// evaluate a field as a formula. Tab and CR are included because some spreadsheets strip
+// them before checking the next character.
+const formulaStarts = "=+-@\t\r"
+
+// Escape returns s as a CSV field.
+//
+// A field that contains a comma, a double quote, a CR, or an LF is wrapped in double
+// quotes, with internal double quotes doubled; embedded CRLF line breaks are kept inside
+// the quotes.
+//
+// A field starting with "=", "+", "-", "@", a tab, or a CR is prefixed with a single
+// quote so spreadsheets treat it as text instead of a formula: a URL or path like
+// =HYPERLINK(...) in an analytics export would otherwise run when the CSV is opened.
+func Escape(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	if strings.IndexByte(formulaStarts, s[0]) >= 0 {
+		s = formulaPrefix + s
+	}
+
+	if !strings.ContainsAny(s, ",\"\r\n") {
+		return s
+	}
+
+	// Escape quotes by doubling them and wrap in quotes
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// Writer writes CSV rows, escaping every field with Escape. Rows end with "\n".
+//
+// Writes are buffered: call Flush when done, then check Error. This matches the
+// encoding/csv Writer API so callers can switch between them.
+type Writer struct {
+	w   *bufio.Writer
+	err error
+}
+
+// NewWriter returns a Writer that writes to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// Write writes one row. After the first error, Write does nothing and returns it.
+func (w *Writer) Write(record []string) error {
+	if w.err != nil {
+		return w.err
+	}
+	for i, field := range record {
+		if i > 0 {
+			if w.err = w.w.WriteByte(','); w.err != nil {
+				return w.err
+			}
+		}
+		if _, w.err = w.w.WriteString(Escape(field)); w.err != nil {
+			return w.err
+		}
+	}
+	w.err = w.w.WriteByte('\n')
+	return w.err
+}
+
+// Flush writes any buffered rows to the underlying writer.
+func (w *Writer) Flush() {
+	if w.err == nil {
+		w.err = w.w.Flush()
+	}
+}
+
+// Error returns the first error from Write or Flush, if any.
+func (w *Writer) Error() error {
+	return w.err
+}
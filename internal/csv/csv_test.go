@@ -0,0 +1,86 @@
+package csv
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty", "", ""},
+		{"plain", "simple", "simple"},
+		{"spaces", "normal text", "normal text"},
+		{"comma", "with,comma", `"with,comma"`},
+		{"quote", `with"quote`, `"with""quote"`},
+		{"newline", "with\nnewline", "\"with\nnewline\""},
+		{"CRLF", "line one\r\nline two", "\"line one\r\nline two\""},
+		{"bare CR", "with\rreturn", "\"with\rreturn\""},
+		{"formula", "=HYPERLINK(\"http://evil\")", `"'=HYPERLINK(""http://evil"")"`},
+		{"equals", "=1+2", "'=1+2"},
+		{"plus", "+1+2", "'+1+2"},
+		{"minus", "-2+3+cmd|' /C calc'!A0", "'-2+3+cmd|' /C calc'!A0"},
+		{"at", "@SUM(A1:A9)", "'@SUM(A1:A9)"},
+		{"tab", "\t=1+2", "'\t=1+2"},
+		{"CR", "\r=1+2", "\"'\r=1+2\""},
+		{"formula characters inside", "a=b+c-d@e", "a=b+c-d@e"},
+		{"URL", "www.mongodb.com/docs/atlas/", "www.mongodb.com/docs/atlas/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Escape(tt.input); got != tt.want {
+				t.Errorf("Escape(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	rows := [][]string{
+		{"URL", "Count"},
+		{"www.mongodb.com/docs/a,b/", "3"},
+		{"=cmd|'/c calc'!A1", ""},
+	}
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
+			t.Fatalf("Write(%q) failed: %v", row, err)
+		}
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected rows to be buffered until Flush, got %q", buf.String())
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	want := "URL,Count\n\"www.mongodb.com/docs/a,b/\",3\n'=cmd|'/c calc'!A1,\n"
+	if buf.String() != want {
+		t.Errorf("Writer output = %q, want %q", buf.String(), want)
+	}
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestWriterError(t *testing.T) {
+	w := NewWriter(failingWriter{})
+	if err := w.Write([]string{"a", "b"}); err != nil {
+		t.Fatalf("Expected the row to be buffered, got %v", err)
+	}
+	w.Flush()
+	if err := w.Error(); err == nil || err.Error() != "disk full" {
+		t.Errorf("Error() = %v, want disk full", err)
+	}
+	if err := w.Write([]string{"c"}); err == nil {
+		t.Error("Expected Write after an error to return the error")
+	}
+}
@@ -21,7 +21,10 @@ const (
 	CSharp     = "csharp"
 	Console    = "console"
 	CSS        = "css"
+	Dockerfile = "dockerfile"
 	Go         = "go"
+	GraphQL    = "graphql"
+	HCL        = "hcl"
 	HTML       = "html"
 	Java       = "java"
 	JavaScript = "javascript"
@@ -30,6 +33,7 @@ const (
 	PHP        = "php"
 	PowerShell = "powershell"
 	PS5        = "ps5"
+	Protobuf   = "protobuf"
 	Python     = "python"
 	Ruby       = "ruby"
 	Rust       = "rust"
@@ -42,6 +46,7 @@ const (
 	Undefined  = "undefined"
 	XML        = "xml"
 	YAML       = "yaml"
+	Zsh        = "zsh"
 )
 
 // File extension constants define the file extensions for each language.
@@ -53,7 +58,10 @@ const (
 	CSharpExtension     = ".cs"
 	ConsoleExtension    = ".sh"
 	CSSExtension        = ".css"
+	DockerfileExtension = ".dockerfile"
 	GoExtension         = ".go"
+	GraphQLExtension    = ".graphql"
+	HCLExtension        = ".tf"
 	HTMLExtension       = ".html"
 	JavaExtension       = ".java"
 	JavaScriptExtension = ".js"
@@ -62,6 +70,7 @@ const (
 	PHPExtension        = ".php"
 	PowerShellExtension = ".ps1"
 	PS5Extension        = ".ps1"
+	ProtobufExtension   = ".proto"
 	PythonExtension     = ".py"
 	RubyExtension       = ".rb"
 	RustExtension       = ".rs"
@@ -74,6 +83,7 @@ const (
 	UndefinedExtension  = ".txt"
 	XMLExtension        = ".xml"
 	YAMLExtension       = ".yaml"
+	ZshExtension        = ".sh"
 )
 
 // GetExtensionFromLanguage returns the appropriate file extension for a given language.
@@ -91,49 +101,59 @@ func GetExtensionFromLanguage(language string) string {
 	lang := strings.ToLower(strings.TrimSpace(language))
 
 	langExtensionMap := map[string]string{
-		Bash:       BashExtension,
-		C:          CExtension,
-		CPP:        CPPExtension,
-		CSharp:     CSharpExtension,
-		Console:    ConsoleExtension,
-		CSS:        CSSExtension,
-		Go:         GoExtension,
-		HTML:       HTMLExtension,
-		Java:       JavaExtension,
-		JavaScript: JavaScriptExtension,
-		JSON:       JSONExtension,
-		Kotlin:     KotlinExtension,
-		PHP:        PHPExtension,
-		PowerShell: PowerShellExtension,
-		PS5:        PS5Extension,
-		Python:     PythonExtension,
-		Ruby:       RubyExtension,
-		Rust:       RustExtension,
-		Scala:      ScalaExtension,
-		Shell:      ShellExtension,
-		SQL:        SQLExtension,
-		Swift:      SwiftExtension,
-		Text:       TextExtension,
-		TypeScript: TypeScriptExtension,
-		Undefined:  UndefinedExtension,
-		XML:        XMLExtension,
-		YAML:       YAMLExtension,
-		"c++":      CPPExtension,
-		"c#":       CSharpExtension,
-		"cs":       CSharpExtension,
-		"golang":   GoExtension,
-		"js":       JavaScriptExtension,
-		"kt":       KotlinExtension,
-		"py":       PythonExtension,
-		"rb":       RubyExtension,
-		"rs":       RustExtension,
-		"sh":       ShellExtension,
-		"ts":       TypeScriptExtension,
-		"txt":      TextExtension,
-		"ps1":      PowerShellExtension,
-		"yml":      YAMLExtension,
-		"":         UndefinedExtension,
-		"none":     UndefinedExtension,
+		Bash:        BashExtension,
+		C:           CExtension,
+		CPP:         CPPExtension,
+		CSharp:      CSharpExtension,
+		Console:     ConsoleExtension,
+		CSS:         CSSExtension,
+		Dockerfile:  DockerfileExtension,
+		Go:          GoExtension,
+		GraphQL:     GraphQLExtension,
+		HCL:         HCLExtension,
+		HTML:        HTMLExtension,
+		Java:        JavaExtension,
+		JavaScript:  JavaScriptExtension,
+		JSON:        JSONExtension,
+		Kotlin:      KotlinExtension,
+		PHP:         PHPExtension,
+		PowerShell:  PowerShellExtension,
+		PS5:         PS5Extension,
+		Protobuf:    ProtobufExtension,
+		Python:      PythonExtension,
+		Ruby:        RubyExtension,
+		Rust:        RustExtension,
+		Scala:       ScalaExtension,
+		Shell:       ShellExtension,
+		SQL:         SQLExtension,
+		Swift:       SwiftExtension,
+		Text:        TextExtension,
+		TypeScript:  TypeScriptExtension,
+		Undefined:   UndefinedExtension,
+		XML:         XMLExtension,
+		YAML:        YAMLExtension,
+		Zsh:         ZshExtension,
+		"c++":       CPPExtension,
+		"c#":        CSharpExtension,
+		"cs":        CSharpExtension,
+		"golang":    GoExtension,
+		"js":        JavaScriptExtension,
+		"kt":        KotlinExtension,
+		"py":        PythonExtension,
+		"rb":        RubyExtension,
+		"rs":        RustExtension,
+		"sh":        ShellExtension,
+		"ts":        TypeScriptExtension,
+		"txt":       TextExtension,
+		"ps1":       PowerShellExtension,
+		"yml":       YAMLExtension,
+		"docker":    DockerfileExtension,
+		"gql":       GraphQLExtension,
+		"proto":     ProtobufExtension,
+		"terraform": HCLExtension,
+		"tf":        HCLExtension,
+		"":          UndefinedExtension,
+		"none":      UndefinedExtension,
 	}
 
 	if extension, exists := langExtensionMap[lang]; exists {
@@ -143,6 +163,35 @@ func GetExtensionFromLanguage(language string) string {
 	return UndefinedExtension
 }
 
+// strictShellExtensions gives each shell dialect its own extension. It overrides the
+// shared .sh extension in GetExtensionFromLanguageStrict.
+var strictShellExtensions = map[string]string{
+	Bash:    ".bash",
+	Zsh:     ".zsh",
+	Console: TextExtension,
+}
+
+// GetExtensionFromLanguageStrict returns the file extension for a language, keeping
+// shell dialects distinct.
+//
+// GetExtensionFromLanguage maps bash, sh, shell, zsh, and console to ".sh", so files
+// extracted from different dialects can't be told apart. This variant maps bash to
+// ".bash" and zsh to ".zsh", and treats console (commands mixed with their output) as
+// ".txt" since it can't be run as a script. sh and shell stay ".sh", and PowerShell
+// stays ".ps1". Every other language gets the same extension as GetExtensionFromLanguage.
+//
+// Parameters:
+//   - language: The language identifier (case-insensitive)
+//
+// Returns:
+//   - string: The file extension including the leading dot (e.g., ".bash", ".py")
+func GetExtensionFromLanguageStrict(language string) string {
+	if extension, exists := strictShellExtensions[Normalize(language)]; exists {
+		return extension
+	}
+	return GetExtensionFromLanguage(language)
+}
+
 // GetLanguageFromExtension infers the language from a file extension.
 //
 // This function maps file extensions to their corresponding language names.
@@ -156,35 +205,46 @@ func GetExtensionFromLanguage(language string) string {
 func GetLanguageFromExtension(filePath string) string {
 	ext := strings.ToLower(filepath.Ext(filePath))
 	extensionMap := map[string]string{
-		".py":    Python,
-		".js":    JavaScript,
-		".ts":    TypeScript,
-		".go":    Go,
-		".java":  Java,
-		".cs":    CSharp,
-		".cpp":   CPP,
-		".c":     C,
-		".rb":    Ruby,
-		".rs":    Rust,
-		".swift": Swift,
-		".kt":    Kotlin,
-		".scala": Scala,
-		".sh":    Shell,
-		".bash":  Shell,
-		".ps1":   PowerShell,
-		".json":  JSON,
-		".yaml":  YAML,
-		".yml":   YAML,
-		".xml":   XML,
-		".html":  HTML,
-		".css":   CSS,
-		".sql":   SQL,
-		".txt":   Text,
-		".php":   PHP,
+		".py":         Python,
+		".js":         JavaScript,
+		".ts":         TypeScript,
+		".go":         Go,
+		".java":       Java,
+		".cs":         CSharp,
+		".cpp":        CPP,
+		".c":          C,
+		".rb":         Ruby,
+		".rs":         Rust,
+		".swift":      Swift,
+		".kt":         Kotlin,
+		".scala":      Scala,
+		".sh":         Shell,
+		".bash":       Shell,
+		".zsh":        Shell,
+		".ps1":        PowerShell,
+		".json":       JSON,
+		".yaml":       YAML,
+		".yml":        YAML,
+		".xml":        XML,
+		".html":       HTML,
+		".css":        CSS,
+		".sql":        SQL,
+		".txt":        Text,
+		".php":        PHP,
+		".tf":         HCL,
+		".hcl":        HCL,
+		".proto":      Protobuf,
+		".graphql":    GraphQL,
+		".gql":        GraphQL,
+		".dockerfile": Dockerfile,
 	}
 	if lang, ok := extensionMap[ext]; ok {
 		return lang
 	}
+	// Dockerfiles are usually named by convention rather than extension
+	if base := strings.ToLower(filepath.Base(filePath)); base == "dockerfile" || strings.HasPrefix(base, "dockerfile.") {
+		return Dockerfile
+	}
 	return ""
 }
 
@@ -245,48 +305,58 @@ func Normalize(language string) string {
 	lang := strings.ToLower(strings.TrimSpace(language))
 
 	normalizeMap := map[string]string{
-		Bash:       Bash,
-		C:          C,
-		CPP:        CPP,
-		CSharp:     CSharp,
-		Console:    Console,
-		CSS:        CSS,
-		Go:         Go,
-		HTML:       HTML,
-		Java:       Java,
-		JavaScript: JavaScript,
-		JSON:       JSON,
-		Kotlin:     Kotlin,
-		PHP:        PHP,
-		PowerShell: PowerShell,
-		PS5:        PS5,
-		Python:     Python,
-		Ruby:       Ruby,
-		Rust:       Rust,
-		Scala:      Scala,
-		Shell:      Shell,
-		SQL:        SQL,
-		Swift:      Swift,
-		Text:       Text,
-		TypeScript: TypeScript,
-		XML:        XML,
-		YAML:       YAML,
-		"c++":      CPP,
-		"c#":       CSharp,
-		"cs":       CSharp,
-		"golang":   Go,
-		"js":       JavaScript,
-		"kt":       Kotlin,
-		"py":       Python,
-		"rb":       Ruby,
-		"rs":       Rust,
-		"sh":       Shell,
-		"ts":       TypeScript,
-		"txt":      Text,
-		"ps1":      PowerShell,
-		"yml":      YAML,
-		"":         Undefined,
-		"none":     Undefined,
+		Bash:        Bash,
+		C:           C,
+		CPP:         CPP,
+		CSharp:      CSharp,
+		Console:     Console,
+		CSS:         CSS,
+		Dockerfile:  Dockerfile,
+		Go:          Go,
+		GraphQL:     GraphQL,
+		HCL:         HCL,
+		HTML:        HTML,
+		Java:        Java,
+		JavaScript:  JavaScript,
+		JSON:        JSON,
+		Kotlin:      Kotlin,
+		PHP:         PHP,
+		PowerShell:  PowerShell,
+		PS5:         PS5,
+		Protobuf:    Protobuf,
+		Python:      Python,
+		Ruby:        Ruby,
+		Rust:        Rust,
+		Scala:       Scala,
+		Shell:       Shell,
+		SQL:         SQL,
+		Swift:       Swift,
+		Text:        Text,
+		TypeScript:  TypeScript,
+		XML:         XML,
+		YAML:        YAML,
+		Zsh:         Zsh,
+		"c++":       CPP,
+		"c#":        CSharp,
+		"cs":        CSharp,
+		"golang":    Go,
+		"js":        JavaScript,
+		"kt":        Kotlin,
+		"py":        Python,
+		"rb":        Ruby,
+		"rs":        Rust,
+		"sh":        Shell,
+		"ts":        TypeScript,
+		"txt":       Text,
+		"ps1":       PowerShell,
+		"yml":       YAML,
+		"docker":    Dockerfile,
+		"gql":       GraphQL,
+		"proto":     Protobuf,
+		"terraform": HCL,
+		"tf":        HCL,
+		"":          Undefined,
+		"none":      Undefined,
 	}
 
 	if normalized, exists := normalizeMap[lang]; exists {
@@ -328,6 +398,7 @@ var LanguageToProduct = map[string]string{
 	"sh":         "Shell",
 	"shell":      "Shell",
 	"console":    "Shell",
+	"zsh":        "Shell",
 	"powershell": "PowerShell",
 	"ps1":        "PowerShell",
 	"json":       "JSON",
@@ -340,6 +411,15 @@ var LanguageToProduct = map[string]string{
 	"ini":        "INI",
 	"toml":       "TOML",
 	"properties": "Properties",
+	"hcl":        "HCL",
+	"terraform":  "HCL",
+	"tf":         "HCL",
+	"dockerfile": "Dockerfile",
+	"docker":     "Dockerfile",
+	"protobuf":   "Protobuf",
+	"proto":      "Protobuf",
+	"graphql":    "GraphQL",
+	"gql":        "GraphQL",
 	"text":       "Text",
 	"txt":        "Text",
 	"none":       "Text",
@@ -372,7 +452,8 @@ func GetProductFromLanguage(lang string) string {
 // WHY THIS EXISTS:
 // Driver documentation often includes code examples that are NOT driver code:
 //   - Shell commands to install packages (bash, sh)
-//   - Configuration files (json, yaml, xml, ini, toml)
+//   - Configuration files (json, yaml, xml, ini, toml, hcl, dockerfile)
+//   - Schema and interface definitions (protobuf, graphql)
 //   - SQL queries for comparison
 //   - HTTP requests showing API calls
 //
@@ -388,6 +469,7 @@ func GetProductFromLanguage(lang string) string {
 var NonDriverLanguages = map[string]bool{
 	"bash":       true,
 	"sh":         true,
+	"zsh":        true,
 	"console":    true,
 	"text":       true,
 	"json":       true,
@@ -399,6 +481,15 @@ var NonDriverLanguages = map[string]bool{
 	"sql":        true,
 	"none":       true,
 	"http":       true,
+	"hcl":        true,
+	"terraform":  true,
+	"tf":         true,
+	"dockerfile": true,
+	"docker":     true,
+	"protobuf":   true,
+	"proto":      true,
+	"graphql":    true,
+	"gql":        true,
 }
 
 // IsNonDriverLanguage checks if a language should NOT inherit context from
@@ -445,4 +536,3 @@ var MongoShellLanguages = map[string]bool{
 func IsMongoShellLanguage(language string) bool {
 	return MongoShellLanguages[strings.ToLower(strings.TrimSpace(language))]
 }
-
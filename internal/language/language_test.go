@@ -38,6 +38,17 @@ func TestGetExtensionFromLanguage(t *testing.T) {
 		{"txt alias", "txt", ".txt"},
 		{"empty string", "", ".txt"},
 		{"none", "none", ".txt"},
+		{"hcl", "hcl", ".tf"},
+		{"terraform alias", "terraform", ".tf"},
+		{"tf alias", "tf", ".tf"},
+		{"dockerfile", "dockerfile", ".dockerfile"},
+		{"docker alias", "docker", ".dockerfile"},
+		{"protobuf", "protobuf", ".proto"},
+		{"proto alias", "proto", ".proto"},
+		{"graphql", "graphql", ".graphql"},
+		{"gql alias", "gql", ".graphql"},
+		{"zsh", "zsh", ".sh"},
+		{"console", "console", ".sh"},
 		{"unknown language", "unknownlang", ".txt"},
 		{"whitespace", "  python  ", ".py"},
 	}
@@ -52,6 +63,34 @@ func TestGetExtensionFromLanguage(t *testing.T) {
 	}
 }
 
+func TestGetExtensionFromLanguageStrict(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		want     string
+	}{
+		{"bash", "bash", ".bash"},
+		{"Bash uppercase", "Bash", ".bash"},
+		{"zsh", "zsh", ".zsh"},
+		{"sh", "sh", ".sh"},
+		{"shell", "shell", ".sh"},
+		{"console", "console", ".txt"},
+		{"powershell", "powershell", ".ps1"},
+		{"ps1 alias", "ps1", ".ps1"},
+		{"python unchanged", "python", ".py"},
+		{"unknown language", "unknownlang", ".txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GetExtensionFromLanguageStrict(tt.language)
+			if got != tt.want {
+				t.Errorf("GetExtensionFromLanguageStrict(%q) = %q, want %q", tt.language, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetLanguageFromExtension(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -79,6 +118,14 @@ func TestGetLanguageFromExtension(t *testing.T) {
 		{"sql file", "query.sql", SQL},
 		{"text file", "readme.txt", Text},
 		{"php file", "index.php", PHP},
+		{"terraform file", "main.tf", HCL},
+		{"hcl file", "config.hcl", HCL},
+		{"proto file", "service.proto", Protobuf},
+		{"graphql file", "schema.graphql", GraphQL},
+		{"gql file", "query.gql", GraphQL},
+		{"dockerfile extension", "app.dockerfile", Dockerfile},
+		{"Dockerfile by name", "/path/to/Dockerfile", Dockerfile},
+		{"Dockerfile with suffix", "Dockerfile.dev", Dockerfile},
 		{"full path", "/path/to/file.py", Python},
 		{"unknown extension", "file.xyz", ""},
 		{"no extension", "Makefile", ""},
@@ -118,6 +165,15 @@ func TestNormalize(t *testing.T) {
 		{"sh shorthand", "sh", Shell},
 		{"yaml", "yaml", YAML},
 		{"yml alias", "yml", YAML},
+		{"hcl", "hcl", HCL},
+		{"terraform alias", "terraform", HCL},
+		{"tf alias", "tf", HCL},
+		{"dockerfile", "Dockerfile", Dockerfile},
+		{"docker alias", "docker", Dockerfile},
+		{"protobuf", "protobuf", Protobuf},
+		{"proto alias", "proto", Protobuf},
+		{"graphql", "GraphQL", GraphQL},
+		{"gql alias", "gql", GraphQL},
 		{"empty string", "", Undefined},
 		{"none", "none", Undefined},
 		{"unknown language", "unknownlang", "unknownlang"},
@@ -159,6 +215,15 @@ func TestGetProductFromLanguage(t *testing.T) {
 		{"json", "json", "JSON"},
 		{"yaml", "yaml", "YAML"},
 		{"yml alias", "yml", "YAML"},
+		{"hcl", "hcl", "HCL"},
+		{"terraform alias", "terraform", "HCL"},
+		{"tf alias", "tf", "HCL"},
+		{"dockerfile", "dockerfile", "Dockerfile"},
+		{"docker alias", "docker", "Dockerfile"},
+		{"protobuf", "protobuf", "Protobuf"},
+		{"proto alias", "proto", "Protobuf"},
+		{"graphql", "graphql", "GraphQL"},
+		{"gql alias", "gql", "GraphQL"},
 		{"unknown returns original", "unknownlang", "unknownlang"},
 		{"whitespace trimmed", "  python  ", "Python"},
 	}
@@ -192,6 +257,15 @@ func TestIsNonDriverLanguage(t *testing.T) {
 		{"sql is non-driver", "sql", true},
 		{"none is non-driver", "none", true},
 		{"http is non-driver", "http", true},
+		{"hcl is non-driver", "hcl", true},
+		{"terraform is non-driver", "terraform", true},
+		{"tf is non-driver", "tf", true},
+		{"dockerfile is non-driver", "dockerfile", true},
+		{"docker is non-driver", "docker", true},
+		{"protobuf is non-driver", "protobuf", true},
+		{"proto is non-driver", "proto", true},
+		{"graphql is non-driver", "graphql", true},
+		{"gql is non-driver", "gql", true},
 		{"python is driver", "python", false},
 		{"javascript is driver", "javascript", false},
 		{"go is driver", "go", false},
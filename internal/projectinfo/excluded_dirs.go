@@ -0,0 +1,52 @@
+package projectinfo
+
+import (
+	"path/filepath"
+)
+
+// DefaultExcludedDirs lists directory names that are never part of the published docs.
+// Directory walks always skip these, in addition to any set with SetExcludedDirs.
+var DefaultExcludedDirs = []string{".git", "node_modules", "build"}
+
+// excludedDirs holds the extra directory names set with the --exclude-dir flag.
+var excludedDirs = map[string]bool{}
+
+// SetExcludedDirs sets the extra directory names to skip during directory walks.
+//
+// This replaces any names set by an earlier call. DefaultExcludedDirs are always skipped.
+//
+// Parameters:
+//   - dirs: Directory names (not paths) to skip, e.g. "snapshots"
+func SetExcludedDirs(dirs []string) {
+	excludedDirs = make(map[string]bool, len(dirs))
+	for _, dir := range dirs {
+		if dir != "" {
+			excludedDirs[filepath.Base(filepath.Clean(dir))] = true
+		}
+	}
+}
+
+// IsExcludedDir reports whether a directory with this name should be skipped during walks.
+func IsExcludedDir(name string) bool {
+	for _, dir := range DefaultExcludedDirs {
+		if name == dir {
+			return true
+		}
+	}
+	return excludedDirs[name]
+}
+
+// SkipExcludedDir returns filepath.SkipDir if a directory reached during a walk of root
+// is excluded, and nil otherwise. The walk root itself is never skipped.
+//
+// Use it at the top of a filepath.Walk callback:
+//
+//	if info.IsDir() {
+//	    return projectinfo.SkipExcludedDir(root, path, info.Name())
+//	}
+func SkipExcludedDir(root, path, name string) error {
+	if filepath.Clean(path) != filepath.Clean(root) && IsExcludedDir(name) {
+		return filepath.SkipDir
+	}
+	return nil
+}
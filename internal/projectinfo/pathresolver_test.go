@@ -1,7 +1,9 @@
 package projectinfo
 
 import (
+	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 )
 
@@ -263,6 +265,30 @@ func TestIsVersionDirectory(t *testing.T) {
 	}
 }
 
+// TestCompareVersions tests ordering version directories by their version numbers.
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v9.0", "v10.0", -1},
+		{"v10.0", "v9.0", 1},
+		{"v8.0", "v8.0", 0},
+		{"v8", "v8.0", -1}, // Equal numbers fall back to the name
+		{"v7.3", "v7.10", -1},
+		{"V9.0", "v10.0", -1},
+		{"current", "v1.0", -1},
+		{"v1.0", "upcoming", 1},
+		{"current", "upcoming", -1},
+	}
+
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
 func TestIsCurrentVersion(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -306,6 +332,52 @@ func TestIsCurrentVersion(t *testing.T) {
 	}
 }
 
+func TestIsNonCurrentVersionDir(t *testing.T) {
+	contentDir := filepath.Join("monorepo", "content")
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"older version", filepath.Join(contentDir, "manual", "v7.0"), true},
+		{"upcoming version", filepath.Join(contentDir, "manual", "upcoming"), true},
+		{"current version", filepath.Join(contentDir, "manual", "current"), false},
+		{"source directory", filepath.Join(contentDir, "golang", "source"), false},
+		{"project directory", filepath.Join(contentDir, "manual"), false},
+		{"nested under version", filepath.Join(contentDir, "manual", "v7.0", "source"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsNonCurrentVersionDir(contentDir, tt.path); got != tt.want {
+				t.Errorf("IsNonCurrentVersionDir(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindContentDirectory(t *testing.T) {
+	root := t.TempDir()
+	contentDir := filepath.Join(root, "content")
+	if err := os.Mkdir(contentDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dir := range []string{root, contentDir} {
+		got, err := FindContentDirectory(dir)
+		if err != nil {
+			t.Fatalf("FindContentDirectory(%q) returned error: %v", dir, err)
+		}
+		if got != contentDir {
+			t.Errorf("FindContentDirectory(%q) = %q, want %q", dir, got, contentDir)
+		}
+	}
+
+	if _, err := FindContentDirectory(t.TempDir()); err == nil {
+		t.Error("Expected an error for a directory without a content directory")
+	}
+}
+
 func TestDiscoverAllVersions(t *testing.T) {
 	// Get absolute path to test data product directory
 	testFile := "../../testdata/compare/product/v8.0/source/includes/example.rst"
@@ -359,3 +431,89 @@ func TestDiscoverAllVersionsNonExistentDir(t *testing.T) {
 	}
 }
 
+// TestIsExcludedDir tests skipping the default excluded directories and those set with SetExcludedDirs.
+func TestIsExcludedDir(t *testing.T) {
+	defer SetExcludedDirs(nil)
+
+	SetExcludedDirs([]string{"snapshots", "archive/"})
+
+	tests := []struct {
+		name     string
+		expected bool
+	}{
+		{".git", true},
+		{"node_modules", true},
+		{"build", true},
+		{"snapshots", true},
+		{"archive", true},
+		{"source", false},
+		{"includes", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsExcludedDir(tt.name); got != tt.expected {
+			t.Errorf("IsExcludedDir(%q) = %v, want %v", tt.name, got, tt.expected)
+		}
+	}
+
+	// The walk root is never skipped, even if its name is excluded
+	if err := SkipExcludedDir("/tmp/build", "/tmp/build", "build"); err != nil {
+		t.Errorf("SkipExcludedDir on the walk root = %v, want nil", err)
+	}
+}
+
+// TestWalkFollowSymlinks tests walking into symlinked directories with cycle protection.
+func TestWalkFollowSymlinks(t *testing.T) {
+	defer SetFollowSymlinks(false)
+
+	root := t.TempDir()
+	external := t.TempDir()
+	for _, path := range []string{
+		filepath.Join(root, "content", "manual", "source", "index.txt"),
+		filepath.Join(external, "source", "index.txt"),
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	content := filepath.Join(root, "content")
+	// A driver repo linked into content/, a second link to it, and a link back to an ancestor
+	for name, target := range map[string]string{"golang": external, "golang-alias": external, "loop": content} {
+		if err := os.Symlink(target, filepath.Join(content, name)); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	walk := func() []string {
+		var files []string
+		err := Walk(content, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && info.Mode()&os.ModeSymlink == 0 {
+				rel, _ := filepath.Rel(content, path)
+				files = append(files, rel)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Walk failed: %v", err)
+		}
+		slices.Sort(files)
+		return files
+	}
+
+	if files := walk(); !slices.Equal(files, []string{"manual/source/index.txt"}) {
+		t.Errorf("Expected symlinks to be skipped by default, got %v", files)
+	}
+
+	SetFollowSymlinks(true)
+	// golang and golang-alias resolve to the same directory, which is walked once
+	expected := []string{"golang/source/index.txt", "manual/source/index.txt"}
+	if files := walk(); !slices.Equal(files, expected) {
+		t.Errorf("Expected %v, got %v", expected, files)
+	}
+}
@@ -6,6 +6,19 @@ import (
 	"path/filepath"
 )
 
+// FindContentDirectory finds the content directory from the given path.
+// It checks if the path is already a content directory, or if it contains one.
+func FindContentDirectory(dirPath string) (string, error) {
+	if filepath.Base(dirPath) == "content" {
+		return dirPath, nil
+	}
+	contentDir := filepath.Join(dirPath, "content")
+	if _, err := os.Stat(contentDir); err == nil {
+		return contentDir, nil
+	}
+	return "", fmt.Errorf("content directory not found in: %s\n\nPlease provide the path to the monorepo root or content directory", dirPath)
+}
+
 // FindSourceDirectory walks up the directory tree to find the "source" directory.
 //
 // MongoDB documentation is typically organized with a "source" directory at the root.
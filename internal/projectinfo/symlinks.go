@@ -0,0 +1,105 @@
+package projectinfo
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// followSymlinks is set with the --follow-symlinks flag.
+var followSymlinks bool
+
+// SetFollowSymlinks sets whether Walk and EntryIsDir follow symlinked directories.
+//
+// Off by default: filepath.Walk doesn't follow symlinks, so a driver repo symlinked
+// into content/ is skipped.
+func SetFollowSymlinks(follow bool) {
+	followSymlinks = follow
+}
+
+// FollowSymlinks reports whether symlinked directories are followed.
+func FollowSymlinks() bool {
+	return followSymlinks
+}
+
+// EntryIsDir reports whether a directory entry read from parent is a directory. When
+// symlinks are followed, a symlink to a directory also counts.
+func EntryIsDir(parent string, entry os.DirEntry) bool {
+	if entry.IsDir() {
+		return true
+	}
+	if !followSymlinks || entry.Type()&os.ModeSymlink == 0 {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(parent, entry.Name()))
+	return err == nil && info.IsDir()
+}
+
+// Walk is filepath.Walk, but when symlinks are followed (see SetFollowSymlinks) it also
+// descends into symlinked directories.
+//
+// Paths passed to fn are under root as written, not the symlink targets. Each directory
+// is visited once by its resolved path, so a symlink back to an ancestor, or two
+// symlinks to the same directory, don't cause a cycle or duplicate results.
+func Walk(root string, fn filepath.WalkFunc) error {
+	if !followSymlinks {
+		return filepath.Walk(root, fn)
+	}
+
+	resolved, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		// Let filepath.Walk report the error to fn
+		return filepath.Walk(root, fn)
+	}
+	return walkFollowingSymlinks(root, resolved, fn, make(map[string]bool))
+}
+
+// walkFollowingSymlinks walks the resolved directory actualRoot, reporting paths under
+// logicalRoot, and recurses into symlinked directories.
+func walkFollowingSymlinks(logicalRoot, actualRoot string, fn filepath.WalkFunc, visited map[string]bool) error {
+	return filepath.Walk(actualRoot, func(path string, info os.FileInfo, err error) error {
+		logical := logicalRoot
+		if rel, relErr := filepath.Rel(actualRoot, path); relErr == nil && rel != "." {
+			logical = filepath.Join(logicalRoot, rel)
+		}
+		if err != nil {
+			return fn(logical, info, err)
+		}
+		if path == actualRoot {
+			// Report the root under the name it was reached by (the symlink's name)
+			info = namedFileInfo{info, filepath.Base(logicalRoot)}
+		}
+
+		if info.IsDir() {
+			if visited[path] {
+				return filepath.SkipDir
+			}
+			visited[path] = true
+			return fn(logical, info, nil)
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			target, evalErr := filepath.EvalSymlinks(path)
+			if evalErr == nil {
+				if targetInfo, statErr := os.Stat(target); statErr == nil && targetInfo.IsDir() {
+					if visited[target] {
+						return nil
+					}
+					return walkFollowingSymlinks(logical, target, fn, visited)
+				}
+			}
+		}
+
+		return fn(logical, info, nil)
+	})
+}
+
+// namedFileInfo is an os.FileInfo reported under a different name.
+type namedFileInfo struct {
+	os.FileInfo
+	name string
+}
+
+// Name returns the overridden name.
+func (i namedFileInfo) Name() string {
+	return i.name
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -213,6 +214,77 @@ func IsCurrentVersion(versionName string) bool {
 	return versionName == "current" || versionName == "manual"
 }
 
+// IsNonCurrentVersionDir reports whether path is a version directory of a versioned
+// project (content/project/version) other than the current version.
+func IsNonCurrentVersionDir(contentDir, path string) bool {
+	relPath, err := filepath.Rel(contentDir, path)
+	if err != nil {
+		return false
+	}
+	parts := strings.Split(relPath, string(filepath.Separator))
+	if len(parts) != 2 || parts[1] == "source" {
+		return false
+	}
+	return IsVersionDirectory(parts[1]) && !IsCurrentVersion(parts[1])
+}
+
+// CompareVersions compares two version directory names, returning -1, 0, or 1.
+//
+// Numbered versions compare by their numbers, so "v9.0" sorts before "v10.0". Named
+// versions (e.g., "current", "upcoming") sort before numbered ones, by name.
+func CompareVersions(a, b string) int {
+	aNumbers, aNumbered := VersionNumbers(a)
+	bNumbers, bNumbered := VersionNumbers(b)
+	switch {
+	case !aNumbered && !bNumbered:
+		return strings.Compare(a, b)
+	case !aNumbered:
+		return -1
+	case !bNumbered:
+		return 1
+	}
+	if cmp := CompareVersionNumbers(aNumbers, bNumbers); cmp != 0 {
+		return cmp
+	}
+	return strings.Compare(a, b)
+}
+
+// VersionNumbers parses a version like "v8.0" (or "V8.0") into its numbers ([8, 0]).
+// It reports false for a named version such as "current" or "upcoming".
+func VersionNumbers(version string) ([]int, bool) {
+	parts := strings.Split(strings.TrimPrefix(strings.ToLower(version), "v"), ".")
+	numbers := make([]int, 0, len(parts))
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, false
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers, true
+}
+
+// CompareVersionNumbers compares two parsed versions (see VersionNumbers) component by
+// component, treating missing trailing components as 0, so v8 and v8.0 are equal.
+func CompareVersionNumbers(a, b []int) int {
+	for i := 0; i < max(len(a), len(b)); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
 // DiscoverAllVersions finds all version directories within a product directory.
 //
 // This function scans the product directory to find all subdirectories that:
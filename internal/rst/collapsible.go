@@ -0,0 +1,80 @@
+package rst
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// FindCollapsedBlocks returns the line ranges of the collapsible blocks in a file that
+// are collapsed when the page loads.
+//
+// A .. collapsible:: directive is collapsed unless it has :expanded: true. Each range
+// starts at the directive and ends at the last line of its content, which is the line
+// before the next non-blank line indented at or below the directive. Nested collapsed
+// blocks are returned too.
+//
+// Parameters:
+//   - filePath: Path to the RST file to parse
+//
+// Returns:
+//   - []LineRange: The collapsed blocks, in the order they end
+//   - error: Any error encountered while reading the file
+func FindCollapsedBlocks(filePath string) ([]LineRange, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	type openBlock struct {
+		start    int
+		indent   int
+		expanded bool
+	}
+	var open []openBlock
+	var collapsed []LineRange
+	lastContentLine := 0
+
+	closeBlock := func(block openBlock, last int) {
+		if !block.expanded {
+			collapsed = append(collapsed, LineRange{First: block.start, Last: last})
+		}
+	}
+
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		trimmedLine := strings.TrimSpace(line)
+		if trimmedLine == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		// A non-blank line at or below a block's indentation ends the block
+		for len(open) > 0 && indent <= open[len(open)-1].indent {
+			closeBlock(open[len(open)-1], lastContentLine)
+			open = open[:len(open)-1]
+		}
+		lastContentLine = lineNum
+
+		if CollapsibleDirectiveRegex.MatchString(trimmedLine) {
+			open = append(open, openBlock{start: lineNum, indent: indent})
+			continue
+		}
+		if len(open) > 0 {
+			if matches := ExpandedOptionRegex.FindStringSubmatch(line); len(matches) > 1 {
+				open[len(open)-1].expanded = strings.TrimSpace(matches[1]) == "true"
+			}
+		}
+	}
+
+	// Blocks still open extend to the end of the file
+	for i := len(open) - 1; i >= 0; i-- {
+		closeBlock(open[i], lastContentLine)
+	}
+
+	return collapsed, scanner.Err()
+}
@@ -0,0 +1,50 @@
+package rst
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindCollapsedBlocks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "page.rst")
+	content := `Title
+=====
+
+.. collapsible::
+   :heading: Collapsed
+
+   .. code-block:: python
+
+      print("hidden")
+
+.. collapsible::
+   :heading: Expanded
+   :expanded: true
+
+   .. code-block:: python
+
+      print("shown")
+
+   .. collapsible::
+      :heading: Nested
+
+      Nested content.
+
+Text after.
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write page: %v", err)
+	}
+
+	got, err := FindCollapsedBlocks(path)
+	if err != nil {
+		t.Fatalf("FindCollapsedBlocks failed: %v", err)
+	}
+	// The expanded block is not returned, but the collapsed block nested in it is
+	want := []LineRange{{First: 4, Last: 9}, {First: 19, Last: 22}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindCollapsedBlocks() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,66 @@
+package rst
+
+import (
+	"regexp"
+	"strings"
+)
+
+// explicitMarkupNotCommentRegex matches explicit markup that is not a comment: a
+// directive (.. name::), a hyperlink target (.. _label:), a substitution definition
+// (.. |name| replace::), or a footnote or citation (.. [1]).
+var explicitMarkupNotCommentRegex = regexp.MustCompile(`^\.\.\s+([\w:.+-]+::|_|\||\[)`)
+
+// IsCommentStart reports whether a trimmed line starts an RST comment: ".." alone, or
+// ".." followed by text that isn't a directive, target, substitution, or footnote.
+func IsCommentStart(trimmedLine string) bool {
+	if trimmedLine == ".." {
+		return true
+	}
+	return strings.HasPrefix(trimmedLine, ".. ") && !explicitMarkupNotCommentRegex.MatchString(trimmedLine)
+}
+
+// CommentTracker tells which lines of a file are inside RST comment blocks, so line-based
+// parsers can skip commented-out directives.
+//
+// A comment is ".." (optionally followed by text) plus every following line indented
+// more than it. Blank lines don't end a comment, except that an empty ".." followed by
+// a blank line is an empty comment and doesn't absorb the next indented block.
+//
+// Feed every line of the file to InComment, in order.
+type CommentTracker struct {
+	active bool
+	indent int
+	// empty is true while the comment is a bare ".." with no text yet
+	empty bool
+}
+
+// InComment reports whether line is part of a comment block (including the ".." line
+// that starts it), and updates the tracker.
+func (c *CommentTracker) InComment(line string) bool {
+	trimmedLine := strings.TrimSpace(line)
+	indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+	if c.active {
+		switch {
+		case trimmedLine == "":
+			if c.empty {
+				// An empty comment ends at the first blank line
+				c.active = false
+			}
+			return c.active
+		case indent > c.indent:
+			c.empty = false
+			return true
+		default:
+			c.active = false
+		}
+	}
+
+	if IsCommentStart(trimmedLine) {
+		c.active = true
+		c.indent = indent
+		c.empty = trimmedLine == ".."
+		return true
+	}
+	return false
+}
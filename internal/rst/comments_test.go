@@ -0,0 +1,98 @@
+package rst
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIsCommentStart(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{"..", true},
+		{".. TODO: fix this example", true},
+		{".. code-block:: python", false},
+		{".. _my-label:", false},
+		{".. |version| replace:: 8.0", false},
+		{".. [1] A footnote", false},
+		{".. sharedinclude:: dbx/example.rst", false},
+		{"..code-block:: python", false},
+		{"Not a comment", false},
+	}
+	for _, tt := range tests {
+		if got := IsCommentStart(tt.line); got != tt.want {
+			t.Errorf("IsCommentStart(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestCommentTracker(t *testing.T) {
+	lines := []string{
+		"Text",                      // 0
+		"",                          // 1
+		"..",                        // 2: comment
+		"   .. code-block:: python", // 3: comment
+		"",                          // 4: comment
+		"      print(1)",            // 5: comment
+		"",                          // 6: comment
+		"More text",                 // 7
+		"",                          // 8
+		"   .. note:: indented",     // 9
+		"",                          // 10
+		"   .. comment in a block",  // 11: comment
+		"      .. include:: /a.rst", // 12: comment
+		"   .. code-block:: python", // 13
+		"",                          // 14
+		"..",                        // 15: empty comment
+		"",                          // 16
+		"   .. code-block:: python", // 17: not absorbed by the empty comment
+	}
+	want := []int{2, 3, 4, 5, 6, 11, 12, 15}
+
+	var tracker CommentTracker
+	var got []int
+	for i, line := range lines {
+		if tracker.InComment(line) {
+			got = append(got, i)
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("lines in comments = %v, want %v", got, want)
+	}
+}
+
+func TestParseDirectivesSkipsComments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "page.rst")
+	content := strings.Join([]string{
+		".. code-block:: python",
+		"",
+		"   print(1)",
+		"",
+		"Text.",
+		"",
+		"..",
+		"   .. code-block:: python",
+		"",
+		"      print(2)",
+		"",
+		"Text.",
+		"",
+		".. literalinclude:: /code-examples/kept.py",
+		"",
+	}, "\n")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	directives, err := ParseDirectives(path)
+	if err != nil {
+		t.Fatalf("ParseDirectives failed: %v", err)
+	}
+	if len(directives) != 2 || directives[0].Type != CodeBlock || directives[1].Type != LiteralInclude {
+		t.Errorf("Expected the code-block and literalinclude outside the comment, got %+v", directives)
+	}
+}
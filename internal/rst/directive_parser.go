@@ -24,8 +24,11 @@ import (
 type DirectiveType string
 
 const (
-	// CodeBlock represents inline code blocks (.. code-block:: and .. code::)
+	// CodeBlock represents Snooty inline code blocks (.. code-block::)
 	CodeBlock DirectiveType = "code-block"
+	// Code represents standard RST inline code blocks (.. code::). It is functionally
+	// equivalent to CodeBlock, but kept distinct so reports can tell them apart.
+	Code DirectiveType = "code"
 	// LiteralInclude represents external file references (.. literalinclude::)
 	LiteralInclude DirectiveType = "literalinclude"
 	// IoCodeBlock represents input/output examples (.. io-code-block::)
@@ -41,7 +44,7 @@ const (
 // Contains all information needed to extract content from the directive,
 // including the directive type, arguments, options, and content.
 type Directive struct {
-	Type     DirectiveType     // Type of directive (code-block, literalinclude, io-code-block)
+	Type     DirectiveType     // Type of directive (code-block, code, literalinclude, io-code-block)
 	Argument string            // Main argument (e.g., language for code-block, filepath for literalinclude)
 	Options  map[string]string // Directive options (e.g., :language:, :start-after:, etc.)
 	Content  string            // Content of the directive (for code-block and inline io-code-block)
@@ -65,15 +68,15 @@ type SubDirective struct {
 // ResolveLanguage determines the language for a code example directive.
 //
 // The resolution strategy depends on the directive type:
-//   - CodeBlock: Argument is the language (e.g., .. code-block:: python)
+//   - CodeBlock, Code: Argument is the language (e.g., .. code-block:: python)
 //   - LiteralInclude: Argument is a filepath, infer language from extension
 //   - IoCodeBlock: Use :language: option only (sub-directives handle their own)
 //
 // Returns the normalized language name, or "undefined" if not determinable.
 func (d Directive) ResolveLanguage() string {
 	switch d.Type {
-	case CodeBlock:
-		// For code-block, the argument IS the language
+	case CodeBlock, Code:
+		// For code-block and code, the argument IS the language
 		return language.Resolve(d.Argument, d.Options["language"], "")
 	case LiteralInclude:
 		// For literalinclude, the argument is a filepath
@@ -122,6 +125,35 @@ func (s SubDirective) ResolveLanguage(parentOptions map[string]string) string {
 	return language.Undefined
 }
 
+// ExampleLanguage is one code example in a directive and its resolved language.
+type ExampleLanguage struct {
+	Part     string // "input" or "output" for the halves of an io-code-block, empty otherwise
+	Language string // Resolved language, or "undefined"
+}
+
+// ExampleLanguages returns each code example in the directive with its resolved language.
+//
+// Code-block, code, and literalinclude directives are one example. An io-code-block is
+// an example for its input and one for its output, each resolved with
+// SubDirective.ResolveLanguage. Directives that are not code examples (e.g., include)
+// return nil.
+func (d Directive) ExampleLanguages() []ExampleLanguage {
+	switch d.Type {
+	case CodeBlock, Code, LiteralInclude:
+		return []ExampleLanguage{{Language: d.ResolveLanguage()}}
+	case IoCodeBlock:
+		var examples []ExampleLanguage
+		if d.InputDirective != nil {
+			examples = append(examples, ExampleLanguage{Part: "input", Language: d.InputDirective.ResolveLanguage(d.Options)})
+		}
+		if d.OutputDirective != nil {
+			examples = append(examples, ExampleLanguage{Part: "output", Language: d.OutputDirective.ResolveLanguage(d.Options)})
+		}
+		return examples
+	}
+	return nil
+}
+
 // Regular expressions for directive parsing
 //
 // Note: literalIncludeRegex is imported from directive_regex.go (LiteralIncludeDirectiveRegex)
@@ -180,12 +212,18 @@ func ParseDirectives(filePath string) ([]Directive, error) {
 	var directives []Directive
 	scanner := bufio.NewScanner(file)
 	lineNum := 0
+	var comments CommentTracker
 
 	for scanner.Scan() {
 		lineNum++
 		line := scanner.Text()
 		trimmedLine := strings.TrimSpace(line)
 
+		// Commented-out directives are not code examples
+		if comments.InComment(line) {
+			continue
+		}
+
 		// Check for literalinclude directive
 		if matches := literalIncludeRegex.FindStringSubmatch(trimmedLine); len(matches) > 1 {
 			directive := Directive{
@@ -220,7 +258,7 @@ func ParseDirectives(filePath string) ([]Directive, error) {
 		// Check for code directive (shorter alias for code-block in standard RST)
 		if matches := codeDirectiveRegex.FindStringSubmatch(trimmedLine); len(matches) > 1 {
 			directive := Directive{
-				Type:     CodeBlock, // Treat as code-block since they're functionally equivalent
+				Type:     Code,
 				Argument: strings.TrimSpace(matches[1]),
 				Options:  make(map[string]string),
 				LineNum:  lineNum,
@@ -450,7 +488,9 @@ func dedentContent(content string) string {
 	return strings.Join(dedentedLines, "\n")
 }
 
-// parseIoCodeBlock parses an io-code-block directive with its nested input/output directives
+// parseIoCodeBlock parses an io-code-block directive with its nested input/output directives.
+// Each sub-directive is stored by its name (InputDirective or OutputDirective), so they
+// can appear in either order.
 func parseIoCodeBlock(scanner *bufio.Scanner, directive *Directive, lineNum *int) {
 	// First, parse any options for the io-code-block itself
 	// This might return the first input/output directive line
@@ -1,6 +1,9 @@
 package rst
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/grove-platform/audit-cli/internal/language"
@@ -111,6 +114,15 @@ func TestDirective_ResolveLanguage(t *testing.T) {
 			},
 			want: "typescript",
 		},
+		{
+			name: "code with language argument",
+			directive: Directive{
+				Type:     Code,
+				Argument: "python",
+				Options:  map[string]string{},
+			},
+			want: "python",
+		},
 	}
 
 	for _, tt := range tests {
@@ -180,6 +192,16 @@ func TestSubDirective_ResolveLanguage(t *testing.T) {
 			parentOptions: map[string]string{"language": "go"},
 			want:          "python",
 		},
+		{
+			name: "output console language takes priority over parent python",
+			subDir: SubDirective{
+				Argument: "",
+				Options:  map[string]string{"language": "console"},
+				Content:  "['admin', 'config', 'local']",
+			},
+			parentOptions: map[string]string{"language": "python"},
+			want:          "console",
+		},
 		{
 			name: "sub-directive with no language returns undefined",
 			subDir: SubDirective{
@@ -222,3 +244,178 @@ func TestSubDirective_ResolveLanguage(t *testing.T) {
 	}
 }
 
+
+func TestIncludeReference_IncludedLines(t *testing.T) {
+	dir := t.TempDir()
+	shared := filepath.Join(dir, "shared.rst")
+	content := "Intro\n.. start-a\nA1\nA2\n.. end-a\n.. start-b\nB1\n.. end-b\n"
+	if err := os.WriteFile(shared, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write shared file: %v", err)
+	}
+	page := filepath.Join(dir, "page.rst")
+	pageContent := "Title\n\n.. include:: shared.rst\n   :start-after: start-a\n   :end-before: end-a\n\n.. include:: shared.rst\n   :start-after: start-b\n\n.. include:: shared.rst\n"
+	if err := os.WriteFile(page, []byte(pageContent), 0644); err != nil {
+		t.Fatalf("failed to write page: %v", err)
+	}
+
+	refs, err := FindIncludeReferences(page)
+	if err != nil {
+		t.Fatalf("FindIncludeReferences failed: %v", err)
+	}
+	if len(refs) != 3 {
+		t.Fatalf("Expected 3 includes, got %d", len(refs))
+	}
+
+	tests := []struct {
+		name       string
+		ref        IncludeReference
+		lineNum    int
+		startAfter string
+		endBefore  string
+		expected   LineRange
+	}{
+		{"start-after and end-before", refs[0], 3, "start-a", "end-a", LineRange{First: 3, Last: 4}},
+		{"start-after only", refs[1], 7, "start-b", "", LineRange{First: 7}},
+		{"whole file", refs[2], 10, "", "", LineRange{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.ref.LineNum != tt.lineNum || tt.ref.StartAfter != tt.startAfter || tt.ref.EndBefore != tt.endBefore {
+				t.Errorf("Expected line %d, start-after %q, end-before %q; got %d, %q, %q",
+					tt.lineNum, tt.startAfter, tt.endBefore, tt.ref.LineNum, tt.ref.StartAfter, tt.ref.EndBefore)
+			}
+			// The temp directory isn't a docs source tree, so the path isn't resolved
+			tt.ref.ResolvedPath = shared
+			got, err := tt.ref.IncludedLines()
+			if err != nil {
+				t.Fatalf("IncludedLines failed: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("IncludedLines() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+
+	missing := IncludeReference{ResolvedPath: shared, StartAfter: "start-c"}
+	if _, err := missing.IncludedLines(); err == nil {
+		t.Error("Expected an error for a missing start-after tag")
+	}
+	empty := IncludeReference{ResolvedPath: shared, EndBefore: "Intro"}
+	if lines, err := empty.IncludedLines(); err != nil || lines.Contains(1) || lines.Contains(2) {
+		t.Errorf("Expected an empty range for an end-before tag on line 1, got %+v (%v)", lines, err)
+	}
+}
+
+func TestParseDirectivesCode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "page.rst")
+	content := ".. code:: python\n\n   print('code')\n\nSame thing:\n\n.. code-block:: python\n\n   print('code-block')\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write page: %v", err)
+	}
+
+	directives, err := ParseDirectives(path)
+	if err != nil {
+		t.Fatalf("ParseDirectives failed: %v", err)
+	}
+	if len(directives) != 2 {
+		t.Fatalf("Expected 2 directives, got %d", len(directives))
+	}
+	if directives[0].Type != Code || directives[0].Argument != "python" || directives[0].Content != "print('code')" {
+		t.Errorf("Expected a python code directive, got %+v", directives[0])
+	}
+	if directives[1].Type != CodeBlock {
+		t.Errorf("Expected a code-block directive, got %s", directives[1].Type)
+	}
+}
+
+func TestParseDirectivesIoCodeBlockOutputFirst(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "page.rst")
+	content := `.. io-code-block::
+   :copyable: true
+
+   .. output::
+      :language: json
+
+      { "_id": 1 }
+
+   .. input:: /code-examples/tested/python/find.py
+      :language: python
+
+After the block.
+
+.. code-block:: python
+
+   print("next")
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write page: %v", err)
+	}
+
+	directives, err := ParseDirectives(path)
+	if err != nil {
+		t.Fatalf("ParseDirectives failed: %v", err)
+	}
+	if len(directives) != 2 {
+		t.Fatalf("Expected the io-code-block and the code-block, got %+v", directives)
+	}
+
+	io := directives[0]
+	if io.Type != IoCodeBlock {
+		t.Fatalf("Expected an io-code-block, got %s", io.Type)
+	}
+	if io.InputDirective == nil || io.OutputDirective == nil {
+		t.Fatalf("Expected both input and output, got input %+v, output %+v", io.InputDirective, io.OutputDirective)
+	}
+	if io.InputDirective.Argument != "/code-examples/tested/python/find.py" || io.InputDirective.Options["language"] != "python" {
+		t.Errorf("Expected the python file as input, got %+v", io.InputDirective)
+	}
+	if io.OutputDirective.Argument != "" || io.OutputDirective.Options["language"] != "json" || io.OutputDirective.Content != `{ "_id": 1 }` {
+		t.Errorf("Expected the inline json as output, got %+v", io.OutputDirective)
+	}
+	if directives[1].Type != CodeBlock || directives[1].LineNum != 14 {
+		t.Errorf("Expected the code-block at line 14 after the io-code-block, got %+v", directives[1])
+	}
+}
+
+// TestDirective_ExampleLanguages tests listing the code examples in a directive.
+func TestDirective_ExampleLanguages(t *testing.T) {
+	tests := []struct {
+		name      string
+		directive Directive
+		want      []ExampleLanguage
+	}{
+		{
+			name:      "code-block",
+			directive: Directive{Type: CodeBlock, Argument: "py"},
+			want:      []ExampleLanguage{{Language: "python"}},
+		},
+		{
+			name:      "literalinclude without language",
+			directive: Directive{Type: LiteralInclude, Argument: "/includes/example"},
+			want:      []ExampleLanguage{{Language: "undefined"}},
+		},
+		{
+			name: "io-code-block",
+			directive: Directive{
+				Type:            IoCodeBlock,
+				Options:         map[string]string{"language": "javascript"},
+				InputDirective:  &SubDirective{Argument: "/code-examples/find.py"},
+				OutputDirective: &SubDirective{},
+			},
+			want: []ExampleLanguage{{Part: "input", Language: "python"}, {Part: "output", Language: "javascript"}},
+		},
+		{
+			name:      "include",
+			directive: Directive{Type: "include", Argument: "/includes/steps.rst"},
+			want:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.directive.ExampleLanguages(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExampleLanguages() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -71,6 +71,10 @@ var ComposableTutorialDirectiveRegex = regexp.MustCompile(`^\.\.\s+composable-tu
 // Example: .. selected-content::
 var SelectedContentDirectiveRegex = regexp.MustCompile(`^\.\.\s+selected-content::`)
 
+// CollapsibleDirectiveRegex matches .. collapsible:: directives in RST files.
+// Example: .. collapsible::
+var CollapsibleDirectiveRegex = regexp.MustCompile(`^\.\.\s+collapsible::`)
+
 // RST Directive Option Regular Expressions
 //
 // These patterns match directive options (lines starting with :option-name:).
@@ -88,6 +92,10 @@ var OptionsOptionRegex = regexp.MustCompile(`^\s+:options:\s*(.*)$`)
 // Example: :selections: python
 var SelectionsOptionRegex = regexp.MustCompile(`^\s+:selections:\s*(.*)$`)
 
+// ExpandedOptionRegex matches :expanded: options in RST files (used in collapsible).
+// Example: :expanded: true
+var ExpandedOptionRegex = regexp.MustCompile(`^\s+:expanded:\s*(.*)$`)
+
 // LanguageOptionRegex matches :language: options in RST files.
 // Example: :language: python
 var LanguageOptionRegex = regexp.MustCompile(`^\s+:language:\s*(.*)$`)
@@ -1,9 +1,12 @@
 package rst
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/grove-platform/audit-cli/internal/projectinfo"
 )
 
 // TraverseDirectory traverses a directory and returns all file paths.
@@ -26,9 +29,10 @@ func TraverseDirectory(rootPath string, recursive bool) ([]string, error) {
 			if err != nil {
 				return err
 			}
-			if !info.IsDir() {
-				files = append(files, path)
+			if info.IsDir() {
+				return projectinfo.SkipExcludedDir(rootPath, path, info.Name())
 			}
+			files = append(files, path)
 			return nil
 		})
 		if err != nil {
@@ -70,3 +74,51 @@ func ShouldProcessFile(filePath string) bool {
 	return false
 }
 
+// WalkContentDirectives parses the directives of every .txt and .rst file in a content
+// directory and calls fn with each file's path and directives.
+//
+// code-examples directories (tested code, not RST) and excluded directories (see
+// projectinfo.IsExcludedDir) are skipped. Files that can't be parsed are skipped with a
+// warning. Includes are not followed: each file is parsed once, where it lives.
+//
+// Parameters:
+//   - contentDir: The content directory to walk
+//   - currentOnly: If true, skip non-current version directories of versioned projects
+//   - fn: Called for each parsed file
+//
+// Returns:
+//   - int: The number of files parsed
+//   - error: Any error encountered during the walk
+func WalkContentDirectives(contentDir string, currentOnly bool, fn func(path string, directives []Directive)) (int, error) {
+	filesScanned := 0
+	err := filepath.Walk(contentDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			// Tested code example files are not RST
+			if info.Name() == "code-examples" {
+				return filepath.SkipDir
+			}
+			if currentOnly && projectinfo.IsNonCurrentVersionDir(contentDir, path) {
+				return filepath.SkipDir
+			}
+			return projectinfo.SkipExcludedDir(contentDir, path, info.Name())
+		}
+
+		ext := filepath.Ext(path)
+		if ext != ".txt" && ext != ".rst" {
+			return nil
+		}
+
+		directives, err := ParseDirectives(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to parse %s: %v\n", path, err)
+			return nil
+		}
+		filesScanned++
+		fn(path, directives)
+		return nil
+	})
+	return filesScanned, err
+}
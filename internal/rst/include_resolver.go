@@ -14,6 +14,8 @@ import (
 //
 // This function scans the file for .. include:: directives and resolves each path
 // using MongoDB-specific conventions (steps files, extracts, template variables, etc.).
+// Includes that cannot be resolved are skipped with a warning; use FindIncludeReferences
+// to inspect them.
 //
 // Parameters:
 //   - filePath: Path to the RST file to scan
@@ -22,17 +24,182 @@ import (
 //   - []string: List of resolved absolute paths to included files
 //   - error: Any error encountered during scanning
 func FindIncludeDirectives(filePath string) ([]string, error) {
+	refs, err := FindResolvedIncludes(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var includePaths []string
+	for _, ref := range refs {
+		includePaths = append(includePaths, ref.ResolvedPath)
+	}
+
+	return includePaths, nil
+}
+
+// FindResolvedIncludes finds all include directives in a file whose paths can be resolved.
+//
+// Like FindIncludeDirectives, includes that cannot be resolved are skipped with a warning,
+// but each result keeps the directive's line number and :start-after:/:end-before: options,
+// for callers that need to honor partial includes.
+//
+// Parameters:
+//   - filePath: Path to the RST file to scan
+//
+// Returns:
+//   - []IncludeReference: Resolved include directives, in file order
+//   - error: Any error encountered during scanning
+func FindResolvedIncludes(filePath string) ([]IncludeReference, error) {
+	refs, err := FindIncludeReferences(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var resolved []IncludeReference
+	for _, ref := range refs {
+		if ref.Err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to resolve include path %s: %v\n", ref.Path, ref.Err)
+			continue
+		}
+		resolved = append(resolved, ref)
+	}
+
+	return resolved, nil
+}
+
+// IncludeReference is a single .. include:: directive found in a file.
+type IncludeReference struct {
+	Path         string // The include path as written in the directive
+	ResolvedPath string // Absolute path to the included file (empty if unresolved)
+	Err          error  // Non-nil if the path could not be resolved
+	LineNum      int    // Line number of the directive (1-based)
+	// StartAfter and EndBefore are the directive's :start-after: and :end-before: options.
+	// When set, only the part of the included file between the lines containing them is
+	// included (see IncludedLines).
+	StartAfter string
+	EndBefore  string
+}
+
+// LineRange is an inclusive range of 1-based line numbers in a file.
+// Last is 0 when the range extends to the end of the file; the zero value is the whole file.
+type LineRange struct {
+	First int
+	Last  int
+}
+
+// Contains reports whether lineNum is in the range.
+func (r LineRange) Contains(lineNum int) bool {
+	return lineNum >= r.First && (r.Last == 0 || lineNum <= r.Last)
+}
+
+// IsPartial reports whether the include selects only part of the file.
+func (r IncludeReference) IsPartial() bool {
+	return r.StartAfter != "" || r.EndBefore != ""
+}
+
+// IncludedLines returns the lines of the included file selected by :start-after: and
+// :end-before:, matching how the docs build treats these options: the fragment starts on
+// the line after the first line containing the start-after text, and ends on the line
+// before the first following line containing the end-before text.
+//
+// Returns the zero LineRange (the whole file) if the include is not partial.
+//
+// Returns:
+//   - LineRange: The selected lines
+//   - error: Error if the file can't be read or a tag is not found
+func (r IncludeReference) IncludedLines() (LineRange, error) {
+	if !r.IsPartial() {
+		return LineRange{}, nil
+	}
+
+	content, err := os.ReadFile(r.ResolvedPath)
+	if err != nil {
+		return LineRange{}, err
+	}
+	lines := strings.Split(string(content), "\n")
+
+	lineRange := LineRange{First: 1}
+	if r.StartAfter != "" {
+		lineRange.First = 0
+		for i, line := range lines {
+			if strings.Contains(line, r.StartAfter) {
+				lineRange.First = i + 2
+				break
+			}
+		}
+		if lineRange.First == 0 {
+			return LineRange{}, fmt.Errorf("start-after tag '%s' not found in %s", r.StartAfter, r.ResolvedPath)
+		}
+	}
+	if r.EndBefore != "" {
+		found := false
+		for i := lineRange.First - 1; i < len(lines); i++ {
+			if strings.Contains(lines[i], r.EndBefore) {
+				// Line i+1 has the tag. A tag on the fragment's first line selects nothing;
+				// Last can't be 0 for that (0 means end of file), so use First 2, Last 1.
+				lineRange.Last = i
+				if i == 0 {
+					lineRange = LineRange{First: 2, Last: 1}
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return LineRange{}, fmt.Errorf("end-before tag '%s' not found in %s", r.EndBefore, r.ResolvedPath)
+		}
+	}
+
+	return lineRange, nil
+}
+
+// FindIncludeReferences finds all include directives in a file, including those that
+// cannot be resolved.
+//
+// Parameters:
+//   - filePath: Path to the RST file to scan
+//
+// Returns:
+//   - []IncludeReference: One entry per include directive, in file order
+//   - error: Any error encountered reading the file
+func FindIncludeReferences(filePath string) ([]IncludeReference, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
-	var includePaths []string
+	var refs []IncludeReference
 	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	// inOptions is true while reading the option lines directly after an include directive
+	inOptions := false
+	var comments CommentTracker
 
 	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+		lineNum++
+		rawLine := scanner.Text()
+		line := strings.TrimSpace(rawLine)
+
+		// Commented-out includes are not followed
+		if comments.InComment(rawLine) {
+			inOptions = false
+			continue
+		}
+
+		if inOptions {
+			if matches := optionRegex.FindStringSubmatch(rawLine); len(matches) > 2 {
+				ref := &refs[len(refs)-1]
+				switch matches[1] {
+				case "start-after":
+					ref.StartAfter = strings.TrimSpace(matches[2])
+				case "end-before":
+					ref.EndBefore = strings.TrimSpace(matches[2])
+				}
+				continue
+			}
+			inOptions = false
+		}
 
 		// Check if this line is an include directive
 		matches := IncludeDirectiveRegex.FindStringSubmatch(line)
@@ -41,12 +208,13 @@ func FindIncludeDirectives(filePath string) ([]string, error) {
 
 			// Resolve the include path relative to the source directory
 			resolvedPath, err := ResolveIncludePath(filePath, includePath)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to resolve include path %s: %v\n", includePath, err)
-				continue
-			}
-
-			includePaths = append(includePaths, resolvedPath)
+			refs = append(refs, IncludeReference{
+				Path:         includePath,
+				ResolvedPath: resolvedPath,
+				Err:          err,
+				LineNum:      lineNum,
+			})
+			inOptions = true
 		}
 	}
 
@@ -54,7 +222,7 @@ func FindIncludeDirectives(filePath string) ([]string, error) {
 		return nil, err
 	}
 
-	return includePaths, nil
+	return refs, nil
 }
 
 // FindToctreeEntries finds all toctree entries in a file and resolves their paths.
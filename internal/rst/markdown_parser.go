@@ -0,0 +1,135 @@
+package rst
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grove-platform/audit-cli/internal/language"
+)
+
+// MarkdownCodeBlock represents a fenced code block in a Markdown source file.
+const MarkdownCodeBlock DirectiveType = "fenced-code"
+
+// IsMarkdownFile reports whether a source file is Markdown (.md or .mdx).
+func IsMarkdownFile(filePath string) bool {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	return ext == ".md" || ext == ".mdx"
+}
+
+// ParseMarkdownFile parses the fenced code blocks in a Markdown file.
+//
+// A fence is a line of three or more backticks or tildes, indented by up to three
+// spaces. The first word of the info string after the opening fence is the language,
+// so ```python title="app.py" is a python block. A block ends at a closing fence of
+// the same character that is at least as long, or at the end of the file. Fences
+// inside a longer fence (for example, ``` inside ````) are part of its content.
+//
+// Example Markdown format:
+//
+//	```python
+//	print("hello")
+//	```
+//
+// Parameters:
+//   - filePath: Path to the Markdown file
+//
+// Returns:
+//   - []Directive: One MarkdownCodeBlock per fence, with the normalized language as the
+//     Argument, or an empty Argument if the fence has no info string
+//   - error: Any error encountered while reading the file
+func ParseMarkdownFile(filePath string) ([]Directive, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var directives []Directive
+	var current *Directive
+	var fence string
+	var content []string
+
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+
+		if current == nil {
+			marker, info, ok := parseFence(line)
+			if !ok {
+				continue
+			}
+			current = &Directive{
+				Type:     MarkdownCodeBlock,
+				Argument: fenceLanguage(info),
+				Options:  make(map[string]string),
+				LineNum:  lineNum,
+			}
+			fence = marker
+			content = nil
+			continue
+		}
+
+		if marker, info, ok := parseFence(line); ok && info == "" &&
+			marker[0] == fence[0] && len(marker) >= len(fence) {
+			current.Content = strings.Join(content, "\n")
+			directives = append(directives, *current)
+			current = nil
+			continue
+		}
+		content = append(content, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// An unclosed fence runs to the end of the file
+	if current != nil {
+		current.Content = strings.Join(content, "\n")
+		directives = append(directives, *current)
+	}
+	return directives, nil
+}
+
+// parseFence returns the fence marker and trimmed info string if a line is a code fence.
+func parseFence(line string) (marker, info string, ok bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 || len(trimmed) < 3 {
+		return "", "", false
+	}
+	char := trimmed[0]
+	if char != '`' && char != '~' {
+		return "", "", false
+	}
+	n := 0
+	for n < len(trimmed) && trimmed[n] == char {
+		n++
+	}
+	if n < 3 {
+		return "", "", false
+	}
+	info = strings.TrimSpace(trimmed[n:])
+	// A backtick fence's info string can't contain backticks (that's inline code)
+	if char == '`' && strings.Contains(info, "`") {
+		return "", "", false
+	}
+	return trimmed[:n], info, true
+}
+
+// fenceLanguage returns the normalized language from a fence info string. The
+// language is the first word, with the braces and leading dot of {.python} removed.
+func fenceLanguage(info string) string {
+	fields := strings.Fields(info)
+	if len(fields) == 0 {
+		return ""
+	}
+	lang := strings.Trim(fields[0], "{}")
+	lang = strings.TrimPrefix(lang, ".")
+	if lang == "" {
+		return ""
+	}
+	return language.Normalize(lang)
+}
@@ -0,0 +1,69 @@
+package rst
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseMarkdownFile(t *testing.T) {
+	filePath := filepath.Join("..", "..", "testdata", "testable-code-test", "content", "test-project", "source", "markdown-page.md")
+
+	directives, err := ParseMarkdownFile(filePath)
+	if err != nil {
+		t.Fatalf("ParseMarkdownFile failed: %v", err)
+	}
+
+	expected := []struct {
+		language string
+		lineNum  int
+	}{
+		{"python", 5},
+		{"javascript", 12},
+		{"markdown", 18},
+		{"", 26},
+	}
+	if len(directives) != len(expected) {
+		t.Fatalf("Expected %d fenced blocks, got %d: %+v", len(expected), len(directives), directives)
+	}
+	for i, want := range expected {
+		d := directives[i]
+		if d.Type != MarkdownCodeBlock {
+			t.Errorf("Block %d: expected type %s, got %s", i, MarkdownCodeBlock, d.Type)
+		}
+		if d.Argument != want.language || d.LineNum != want.lineNum {
+			t.Errorf("Block %d: expected %q at line %d, got %q at line %d", i, want.language, want.lineNum, d.Argument, d.LineNum)
+		}
+	}
+	if directives[2].Content != "```python\nprint(\"not counted\")\n```" {
+		t.Errorf("Expected the nested fence as content, got %q", directives[2].Content)
+	}
+}
+
+func TestParseMarkdownFileUnclosedFence(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "page.mdx")
+	if err := os.WriteFile(filePath, []byte("```{.go}\nfmt.Println()\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	directives, err := ParseMarkdownFile(filePath)
+	if err != nil {
+		t.Fatalf("ParseMarkdownFile failed: %v", err)
+	}
+	if len(directives) != 1 || directives[0].Argument != "go" || directives[0].Content != "fmt.Println()" {
+		t.Errorf("Expected one go block running to the end of the file, got %+v", directives)
+	}
+}
+
+func TestIsMarkdownFile(t *testing.T) {
+	for path, want := range map[string]bool{
+		"page.md":  true,
+		"page.MDX": true,
+		"page.txt": false,
+		"page.rst": false,
+	} {
+		if got := IsMarkdownFile(path); got != want {
+			t.Errorf("IsMarkdownFile(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
@@ -2,15 +2,18 @@
 package rst
 
 import (
+	_ "embed"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/BurntSushi/toml"
+	"github.com/grove-platform/audit-cli/internal/config"
 )
 
 // RstspecURL is the URL to the canonical rstspec.toml file in the snooty-parser repository.
@@ -19,12 +22,27 @@ const RstspecURL = "https://raw.githubusercontent.com/mongodb/snooty-parser/refs
 // RstspecCacheTTL is the time-to-live for the cached rstspec.toml (24 hours).
 const RstspecCacheTTL = 24 * time.Hour
 
-// RstspecCacheDir is the directory for storing cache files.
+// RstspecCacheDir is the directory for storing cache files, in the home directory. It's the
+// same as config.CacheDir; the cache directory can be overridden (see config.GetCacheDir).
 const RstspecCacheDir = ".audit-cli"
 
 // RstspecCacheFileName is the name of the rstspec cache file.
 const RstspecCacheFileName = "rstspec-cache.json"
 
+// RstspecURLEnvVar names the environment variable that overrides RstspecURL
+// (e.g., to test against a snooty-parser branch). --rstspec-url takes precedence.
+const RstspecURLEnvVar = "AUDIT_CLI_RSTSPEC_URL"
+
+// RstspecFileEnvVar names the environment variable that loads rstspec.toml from a local
+// file instead of fetching it. --rstspec-file takes precedence.
+const RstspecFileEnvVar = "AUDIT_CLI_RSTSPEC_FILE"
+
+// rstspecSnapshot is the bundled copy of rstspec.toml that local mode falls back to
+// (see FetchRstspec).
+//
+//go:embed rstspec_snapshot.toml
+var rstspecSnapshot []byte
+
 // RstspecComposable represents a composable definition from rstspec.toml.
 type RstspecComposable struct {
 	ID           string                       `toml:"id"`
@@ -114,23 +132,27 @@ func (c *RstspecConfig) BuildTabIDToTitleMap(tabsetID string) map[string]string
 }
 
 // RstspecCache represents the cached rstspec.toml data.
+//
+// The embedded HTTPValidators are from the response rstspec.toml was fetched with, so an
+// expired cache can be revalidated (see fetchRstspecConditional).
 type RstspecCache struct {
 	Timestamp   time.Time         `json:"timestamp"`
 	Composables []RstspecComposable `json:"composables"`
 	Tabs        map[string][]RstspecTabOption `json:"tabs"`
+	config.HTTPValidators
 }
 
 // getRstspecCachePath returns the path to the rstspec cache file.
 func getRstspecCachePath() (string, error) {
-	homeDir, err := os.UserHomeDir()
+	cacheDir, err := config.GetCacheDir()
 	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
+		return "", err
 	}
-	return filepath.Join(homeDir, RstspecCacheDir, RstspecCacheFileName), nil
+	return filepath.Join(cacheDir, RstspecCacheFileName), nil
 }
 
-// loadRstspecCache loads the rstspec from the cache file.
-func loadRstspecCache() (*RstspecConfig, error) {
+// readRstspecCache reads the rstspec cache file, even if it has expired.
+func readRstspecCache() (*RstspecCache, error) {
 	cachePath, err := getRstspecCachePath()
 	if err != nil {
 		return nil, err
@@ -145,20 +167,47 @@ func loadRstspecCache() (*RstspecConfig, error) {
 	if err := json.Unmarshal(data, &cache); err != nil {
 		return nil, fmt.Errorf("failed to parse rstspec cache: %w", err)
 	}
+	return &cache, nil
+}
 
-	// Check if cache is expired
-	if time.Since(cache.Timestamp) > RstspecCacheTTL {
-		return nil, fmt.Errorf("rstspec cache expired")
+// toConfig returns the cached rstspec.toml.
+func (c *RstspecCache) toConfig() *RstspecConfig {
+	return &RstspecConfig{
+		Composables: c.Composables,
+		Tabs:        c.Tabs,
 	}
+}
 
-	return &RstspecConfig{
-		Composables: cache.Composables,
-		Tabs:        cache.Tabs,
-	}, nil
+// GetRstspecCacheInfo returns the path and timestamp of the on-disk rstspec.toml cache.
+//
+// The timestamp is when rstspec.toml was last fetched. Use it to tell whether product
+// mappings come from a fresh fetch or from a cache that may be up to RstspecCacheTTL old
+// (or older, if the last fetch failed and the expired cache was used).
+//
+// Returns:
+//   - string: Path to the cache file
+//   - time.Time: When the cached rstspec.toml was fetched
+//   - error: Error if the cache file does not exist or cannot be parsed
+func GetRstspecCacheInfo() (string, time.Time, error) {
+	cachePath, err := getRstspecCachePath()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		return cachePath, time.Time{}, err
+	}
+
+	var cache RstspecCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return cachePath, time.Time{}, fmt.Errorf("failed to parse rstspec cache: %w", err)
+	}
+	return cachePath, cache.Timestamp, nil
 }
 
-// saveRstspecCache saves the rstspec to the cache file.
-func saveRstspecCache(config *RstspecConfig) error {
+// saveRstspecCache saves the rstspec and the validators it was fetched with to the cache file.
+func saveRstspecCache(spec *RstspecConfig, validators config.HTTPValidators) error {
 	cachePath, err := getRstspecCachePath()
 	if err != nil {
 		return err
@@ -171,9 +220,10 @@ func saveRstspecCache(config *RstspecConfig) error {
 	}
 
 	cache := RstspecCache{
-		Timestamp:   time.Now(),
-		Composables: config.Composables,
-		Tabs:        config.Tabs,
+		Timestamp:      time.Now(),
+		Composables:    spec.Composables,
+		Tabs:           spec.Tabs,
+		HTTPValidators: validators,
 	}
 
 	data, err := json.MarshalIndent(cache, "", "  ")
@@ -188,29 +238,111 @@ func saveRstspecCache(config *RstspecConfig) error {
 	return nil
 }
 
-// fetchRstspecFromURL fetches and parses rstspec.toml from the remote URL.
-func fetchRstspecFromURL() (*RstspecConfig, error) {
-	resp, err := http.Get(RstspecURL)
+// rstspecURLOverride and rstspecFileOverride are set with --rstspec-url and --rstspec-file.
+var (
+	rstspecURLOverride  string
+	rstspecFileOverride string
+)
+
+// SetRstspecSource overrides where rstspec.toml is loaded from: a URL to fetch, or a local
+// file to read without any network access. Pass empty strings to use the environment
+// variables (RstspecURLEnvVar, RstspecFileEnvVar), or the canonical URL if they're unset.
+func SetRstspecSource(url, file string) {
+	rstspecURLOverride = url
+	rstspecFileOverride = file
+}
+
+// RstspecSource returns the overriding URL or file, if any (see SetRstspecSource).
+// Flags take precedence over environment variables; only one of the two may be set.
+func RstspecSource() (url, file string, err error) {
+	url, file = rstspecURLOverride, rstspecFileOverride
+	if url == "" && file == "" {
+		url, file = os.Getenv(RstspecURLEnvVar), os.Getenv(RstspecFileEnvVar)
+	}
+	if url != "" && file != "" {
+		return "", "", fmt.Errorf("set only one of --rstspec-url (%s) and --rstspec-file (%s)", RstspecURLEnvVar, RstspecFileEnvVar)
+	}
+	return url, file, nil
+}
+
+// fetchRstspecFromURL fetches and parses rstspec.toml from a URL.
+func fetchRstspecFromURL(url string) (*RstspecConfig, error) {
+	spec, _, err := fetchRstspecConditional(url, nil)
+	return spec, err
+}
+
+// fetchRstspecConditional fetches and parses rstspec.toml from a URL, and returns the
+// response's validators for the cache.
+//
+// If stale (an expired cache, or nil) has HTTP validators, the request is conditional:
+// on 304 Not Modified, stale's contents and validators are returned without a download.
+func fetchRstspecConditional(url string, stale *RstspecCache) (*RstspecConfig, config.HTTPValidators, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, config.HTTPValidators{}, fmt.Errorf("failed to fetch rstspec.toml: %w", err)
+	}
+	if stale != nil {
+		stale.SetConditionalHeaders(req)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch rstspec.toml: %w", err)
+		return nil, config.HTTPValidators{}, fmt.Errorf("failed to fetch rstspec.toml: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && stale != nil && !stale.IsZero() {
+		return stale.toConfig(), stale.HTTPValidators, nil
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch rstspec.toml: HTTP %d", resp.StatusCode)
+		return nil, config.HTTPValidators{}, fmt.Errorf("failed to fetch rstspec.toml: HTTP %d", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, config.HTTPValidators{}, fmt.Errorf("failed to read rstspec.toml: %w", err)
+	}
+
+	spec, err := parseRstspec(body, url)
+	if err != nil {
+		return nil, config.HTTPValidators{}, err
+	}
+	return spec, config.ValidatorsFromResponse(resp), nil
+}
+
+// LoadRstspecFile reads and parses rstspec.toml from a local file.
+func LoadRstspecFile(path string) (*RstspecConfig, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read rstspec.toml: %w", err)
 	}
+	return parseRstspec(data, path)
+}
 
-	var config RstspecConfig
-	if err := toml.Unmarshal(body, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse rstspec.toml: %w", err)
+// parseRstspec parses rstspec.toml and checks that it has the structure product mappings
+// depend on: at least one composable, each with an ID. A file that parses as TOML but
+// isn't an rstspec.toml (e.g., an HTML error page or the wrong file) fails here instead
+// of silently producing empty mappings.
+//
+// Parameters:
+//   - data: The file contents
+//   - source: URL or path, for error messages
+func parseRstspec(data []byte, source string) (*RstspecConfig, error) {
+	var spec RstspecConfig
+	if err := toml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse rstspec.toml from %s: %w", source, err)
 	}
 
-	return &config, nil
+	if len(spec.Composables) == 0 {
+		return nil, fmt.Errorf("invalid rstspec.toml from %s: no [[composables]] defined", source)
+	}
+	for i, composable := range spec.Composables {
+		if composable.ID == "" {
+			return nil, fmt.Errorf("invalid rstspec.toml from %s: composable %d has no id", source, i+1)
+		}
+	}
+
+	return &spec, nil
 }
 
 // FetchRstspec fetches and parses the canonical rstspec.toml file.
@@ -218,9 +350,18 @@ func fetchRstspecFromURL() (*RstspecConfig, error) {
 // This function uses a local cache (stored in ~/.audit-cli/rstspec-cache.json)
 // to avoid repeated network requests. The cache has a 24-hour TTL.
 // If the cache is missing or expired, it fetches from the snooty-parser repository.
+// An expired cache is revalidated with the ETag and Last-Modified it was fetched with,
+// so an unchanged rstspec.toml isn't downloaded again.
 // If the network request fails and a cached version exists (even if expired),
 // it falls back to the cached version for offline support.
 //
+// If the source is overridden (see SetRstspecSource), the override is loaded directly:
+// the cache is neither read nor written, so it always holds the canonical rstspec.toml.
+//
+// In local mode (see config.SetLocal) nothing is fetched: the cache is used even if it
+// has expired, and without one the bundled snapshot is used. A --rstspec-url override
+// fails with config.ErrLocal instead of waiting on the network.
+//
 // Returns:
 //   - *RstspecConfig: The parsed rstspec configuration
 //   - error: Any error encountered during fetch or parse
@@ -233,37 +374,74 @@ func fetchRstspecFromURL() (*RstspecConfig, error) {
 //	}
 //	fmt.Printf("Found %d composables\n", len(config.Composables))
 func FetchRstspec() (*RstspecConfig, error) {
+	url, file, err := RstspecSource()
+	if err != nil {
+		return nil, err
+	}
+	if file != "" {
+		return LoadRstspecFile(file)
+	}
+	if url != "" {
+		if config.IsLocal() {
+			return nil, fmt.Errorf("%w: can't fetch rstspec.toml from %s; use --rstspec-file", config.ErrLocal, url)
+		}
+		return fetchRstspecFromURL(url)
+	}
+
 	// Try to load from cache first
-	config, err := loadRstspecCache()
-	if err == nil {
-		return config, nil
+	cache, err := readRstspecCache()
+	if err == nil && (time.Since(cache.Timestamp) <= RstspecCacheTTL || config.IsLocal()) {
+		return cache.toConfig(), nil
+	}
+	if err != nil {
+		cache = nil
+	}
+	if config.IsLocal() {
+		fmt.Fprintln(os.Stderr, "Warning: No cached rstspec.toml, using the bundled snapshot (--local)")
+		return parseRstspec(rstspecSnapshot, "the bundled snapshot")
 	}
 
-	// Cache miss or expired, try to fetch from URL
-	config, fetchErr := fetchRstspecFromURL()
+	// Cache miss or expired, try to fetch (or revalidate) from URL
+	spec, validators, fetchErr := fetchRstspecConditional(RstspecURL, cache)
 	if fetchErr != nil {
 		// Network failed - try to use expired cache as fallback for offline support
-		cachePath, pathErr := getRstspecCachePath()
-		if pathErr == nil {
-			if data, readErr := os.ReadFile(cachePath); readErr == nil {
-				var cache RstspecCache
-				if jsonErr := json.Unmarshal(data, &cache); jsonErr == nil {
-					// Return expired cache with a warning
-					fmt.Fprintf(os.Stderr, "Warning: Could not fetch rstspec.toml (%v), using expired cache\n", fetchErr)
-					return &RstspecConfig{
-						Composables: cache.Composables,
-						Tabs:        cache.Tabs,
-					}, nil
-				}
-			}
+		if cache != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Could not fetch rstspec.toml (%v), using expired cache\n", fetchErr)
+			return cache.toConfig(), nil
 		}
 		return nil, fetchErr
 	}
 
-	// Save to cache for next time
-	if saveErr := saveRstspecCache(config); saveErr != nil {
+	// Save to cache for next time (a revalidated cache gets a new timestamp)
+	if saveErr := saveRstspecCache(spec, validators); saveErr != nil {
 		fmt.Fprintf(os.Stderr, "Warning: Could not save rstspec cache: %v\n", saveErr)
 	}
 
-	return config, nil
+	return spec, nil
+}
+
+// rstspecMemo holds the process-wide rstspec.toml memoized by GetRstspec.
+var rstspecMemo struct {
+	sync.Mutex
+	config *RstspecConfig
+}
+
+// GetRstspec returns the parsed rstspec.toml, fetching it at most once per process.
+//
+// FetchRstspec reads (and may re-parse) the on-disk cache on every call. Commands that
+// need rstspec data repeatedly should call GetRstspec instead so the file is fetched
+// and parsed only once. Only a successful result is reused; after an error, the next
+// call tries FetchRstspec again.
+func GetRstspec() (*RstspecConfig, error) {
+	rstspecMemo.Lock()
+	defer rstspecMemo.Unlock()
+	if rstspecMemo.config != nil {
+		return rstspecMemo.config, nil
+	}
+	spec, err := FetchRstspec()
+	if err != nil {
+		return nil, err
+	}
+	rstspecMemo.config = spec
+	return spec, nil
 }
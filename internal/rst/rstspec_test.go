@@ -1,7 +1,17 @@
 package rst
 
 import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/grove-platform/audit-cli/internal/config"
 )
 
 // TestFetchRstspec tests fetching and parsing the canonical rstspec.toml file.
@@ -154,3 +164,248 @@ func TestRstspecLanguageComposable(t *testing.T) {
 	t.Logf("Language composable has %d options", len(languageComp.Options))
 }
 
+
+// TestGetRstspecCacheInfo tests reading the timestamp of the on-disk rstspec cache.
+func TestGetRstspecCacheInfo(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if _, _, err := GetRstspecCacheInfo(); err == nil {
+		t.Error("Expected error when no cache exists")
+	}
+
+	before := time.Now()
+	if err := saveRstspecCache(&RstspecConfig{}, config.HTTPValidators{}); err != nil {
+		t.Fatalf("saveRstspecCache failed: %v", err)
+	}
+
+	path, timestamp, err := GetRstspecCacheInfo()
+	if err != nil {
+		t.Fatalf("GetRstspecCacheInfo failed: %v", err)
+	}
+	if path == "" {
+		t.Error("Expected cache path")
+	}
+	if timestamp.Before(before.Add(-time.Second)) || timestamp.After(time.Now()) {
+		t.Errorf("Expected timestamp near %v, got %v", before, timestamp)
+	}
+}
+
+// TestFetchRstspecLocal tests that --local uses the rstspec.toml cache even if it has
+// expired, falls back to the bundled snapshot without one, and fails fast with
+// config.ErrLocal for --rstspec-url.
+func TestFetchRstspecLocal(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(RstspecURLEnvVar, "")
+	t.Setenv(RstspecFileEnvVar, "")
+	defer SetRstspecSource("", "")
+	config.SetLocal(true)
+	defer config.SetLocal(false)
+
+	bundled, err := FetchRstspec()
+	if err != nil {
+		t.Fatalf("FetchRstspec without a cache failed: %v", err)
+	}
+	if title, ok := bundled.GetComposableOptionTitle("language", "nodejs"); !ok || title != "Node.js" {
+		t.Errorf("Expected the bundled language composable, got %q", title)
+	}
+	if title, ok := bundled.GetTabOptionTitle("drivers", "java-sync"); !ok || title != "Java (Sync)" {
+		t.Errorf("Expected the bundled drivers tabs, got %q", title)
+	}
+	if _, _, err := GetRstspecCacheInfo(); err == nil {
+		t.Error("Expected the bundled snapshot not to be cached")
+	}
+
+	SetRstspecSource("http://127.0.0.1:1/rstspec.toml", "")
+	if _, err := FetchRstspec(); !errors.Is(err, config.ErrLocal) {
+		t.Errorf("Expected config.ErrLocal with --rstspec-url, got %v", err)
+	}
+	SetRstspecSource("", "")
+
+	spec := &RstspecConfig{Composables: []RstspecComposable{{ID: "language", Title: "Language"}}}
+	if err := saveRstspecCache(spec, config.HTTPValidators{}); err != nil {
+		t.Fatalf("saveRstspecCache failed: %v", err)
+	}
+	path, _, err := GetRstspecCacheInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache, err := readRstspecCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Timestamp = time.Now().Add(-2 * RstspecCacheTTL)
+	data, err := json.Marshal(cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := FetchRstspec()
+	if err != nil {
+		t.Fatalf("FetchRstspec with an expired cache failed: %v", err)
+	}
+	if len(loaded.Composables) != 1 || loaded.Composables[0].ID != "language" {
+		t.Errorf("Expected the cached composables, got %+v", loaded.Composables)
+	}
+}
+
+// TestRstspecSourceOverride tests loading rstspec.toml from --rstspec-file and --rstspec-url.
+func TestRstspecSourceOverride(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(RstspecURLEnvVar, "")
+	t.Setenv(RstspecFileEnvVar, "")
+	defer SetRstspecSource("", "")
+
+	const valid = `
+[[composables]]
+id = "language"
+title = "Language"
+default = "python"
+options = [{id = "python", title = "Python"}, {id = "kotlin-coroutine", title = "Kotlin (Coroutine)"}]
+`
+	dir := t.TempDir()
+	validPath := filepath.Join(dir, "rstspec.toml")
+	invalidPath := filepath.Join(dir, "not-rstspec.toml")
+	if err := os.WriteFile(validPath, []byte(valid), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(invalidPath, []byte("[tabs]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	SetRstspecSource("", validPath)
+	config, err := FetchRstspec()
+	if err != nil {
+		t.Fatalf("FetchRstspec with --rstspec-file failed: %v", err)
+	}
+	if title, ok := config.GetComposableOptionTitle("language", "kotlin-coroutine"); !ok || title != "Kotlin (Coroutine)" {
+		t.Errorf("Expected Kotlin (Coroutine) from the local file, got %q", title)
+	}
+	if _, _, err := GetRstspecCacheInfo(); err == nil {
+		t.Error("Expected an overridden rstspec.toml not to be cached")
+	}
+
+	SetRstspecSource("", invalidPath)
+	if _, err := FetchRstspec(); err == nil || !strings.Contains(err.Error(), "no [[composables]]") {
+		t.Errorf("Expected a validation error for a file without composables, got %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(valid))
+	}))
+	defer server.Close()
+	SetRstspecSource(server.URL, "")
+	if config, err := FetchRstspec(); err != nil || len(config.Composables) != 1 {
+		t.Errorf("FetchRstspec with --rstspec-url: got %v, %v", config, err)
+	}
+
+	// Environment variables apply when no flag is set
+	SetRstspecSource("", "")
+	t.Setenv(RstspecFileEnvVar, validPath)
+	if _, err := FetchRstspec(); err != nil {
+		t.Errorf("FetchRstspec with %s failed: %v", RstspecFileEnvVar, err)
+	}
+	t.Setenv(RstspecURLEnvVar, server.URL)
+	if _, err := FetchRstspec(); err == nil {
+		t.Error("Expected an error when both environment variables are set")
+	}
+
+	// A flag overrides both environment variables
+	SetRstspecSource("", validPath)
+	if _, err := FetchRstspec(); err != nil {
+		t.Errorf("Expected --rstspec-file to take precedence over the environment, got %v", err)
+	}
+}
+
+// TestGetRstspecRetriesAfterError tests that GetRstspec memoizes a loaded rstspec.toml but not an error.
+func TestGetRstspecRetriesAfterError(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	defer SetRstspecSource("", "")
+	rstspecMemo.config = nil
+	defer func() { rstspecMemo.config = nil }()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rstspec.toml")
+	SetRstspecSource("", path)
+	if _, err := GetRstspec(); err == nil {
+		t.Fatal("Expected an error for a missing rstspec.toml")
+	}
+
+	const valid = `
+[[composables]]
+id = "language"
+title = "Language"
+default = "python"
+options = [{id = "python", title = "Python"}]
+`
+	if err := os.WriteFile(path, []byte(valid), 0644); err != nil {
+		t.Fatal(err)
+	}
+	first, err := GetRstspec()
+	if err != nil {
+		t.Fatalf("Expected GetRstspec to retry after an error, got %v", err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	second, err := GetRstspec()
+	if err != nil || second != first {
+		t.Errorf("Expected the loaded rstspec.toml to be reused, got %v, %v", second, err)
+	}
+}
+
+// TestFetchRstspecConditional tests revalidating an expired rstspec cache with its ETag.
+func TestFetchRstspecConditional(t *testing.T) {
+	const valid = `
+[[composables]]
+id = "language"
+title = "Language"
+default = "python"
+options = [{id = "python", title = "Python"}]
+`
+	var notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(valid))
+	}))
+	defer server.Close()
+
+	// Without a cache, the file is fetched and its validators returned
+	spec, validators, err := fetchRstspecConditional(server.URL, nil)
+	if err != nil || len(spec.Composables) != 1 {
+		t.Fatalf("fetchRstspecConditional failed: %v, %v", spec, err)
+	}
+	if validators.ETag != `"v1"` {
+		t.Errorf("Expected ETag \"v1\", got %q", validators.ETag)
+	}
+
+	// With the cached ETag, a 304 returns the cached contents
+	stale := &RstspecCache{
+		Composables:    []RstspecComposable{{ID: "cached"}},
+		HTTPValidators: validators,
+	}
+	spec, validators, err = fetchRstspecConditional(server.URL, stale)
+	if err != nil {
+		t.Fatalf("fetchRstspecConditional with a stale cache failed: %v", err)
+	}
+	if notModified != 1 || len(spec.Composables) != 1 || spec.Composables[0].ID != "cached" {
+		t.Errorf("Expected the cached composables after a 304, got %v (%d not modified)", spec.Composables, notModified)
+	}
+	if validators.ETag != `"v1"` {
+		t.Errorf("Expected the cached ETag to be kept, got %q", validators.ETag)
+	}
+
+	// A cache without validators is fetched in full
+	spec, _, err = fetchRstspecConditional(server.URL, &RstspecCache{Composables: []RstspecComposable{{ID: "cached"}}})
+	if err != nil || spec.Composables[0].ID != "language" {
+		t.Errorf("Expected a full fetch without validators, got %v, %v", spec, err)
+	}
+}
@@ -10,17 +10,25 @@
 //   - analyze: Analyze RST file structures and relationships
 //   - compare: Compare files across different versions
 //   - count: Count documentation content (code examples, pages)
+//   - doctor: Check the audit-cli setup
+//   - init: Create a .audit-cli.yaml config file
 package main
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/grove-platform/audit-cli/commands/analyze"
 	"github.com/grove-platform/audit-cli/commands/compare"
 	"github.com/grove-platform/audit-cli/commands/count"
+	"github.com/grove-platform/audit-cli/commands/doctor"
 	"github.com/grove-platform/audit-cli/commands/extract"
+	initcmd "github.com/grove-platform/audit-cli/commands/init"
 	"github.com/grove-platform/audit-cli/commands/report"
 	"github.com/grove-platform/audit-cli/commands/search"
+	"github.com/grove-platform/audit-cli/internal/config"
+	"github.com/grove-platform/audit-cli/internal/projectinfo"
+	"github.com/grove-platform/audit-cli/internal/rst"
 	"github.com/spf13/cobra"
 )
 
@@ -42,9 +50,48 @@ documentation monorepo, including:
   - Comparing files across documentation versions
   - Counting documentation content for reporting and metrics
 
+Run "audit-cli init /path/to/docs-monorepo" to create a config file, and
+"audit-cli doctor" to check your setup.
+
 Designed for maintenance tasks, scoping work, and reporting to stakeholders.`,
 	}
 
+	// Directory names skipped by every directory walk, in addition to .git, node_modules, and build
+	var excludeDirs []string
+	rootCmd.PersistentFlags().StringArrayVar(&excludeDirs, "exclude-dir", nil,
+		"Directory name to skip when walking directories (repeatable; .git, node_modules, and build are always skipped)")
+	// Symlinked directories (e.g., driver repos linked into content/) are skipped unless set
+	var followSymlinks bool
+	rootCmd.PersistentFlags().BoolVar(&followSymlinks, "follow-symlinks", false,
+		"Descend into symlinked directories when scanning content/ for projects and in analyze usage")
+	// Load rstspec.toml from somewhere other than snooty-parser's main branch
+	var rstspecURL, rstspecFile string
+	rootCmd.PersistentFlags().StringVar(&rstspecURL, "rstspec-url", "",
+		"Fetch rstspec.toml from this URL instead of snooty-parser's main branch (env: "+rst.RstspecURLEnvVar+")")
+	rootCmd.PersistentFlags().StringVar(&rstspecFile, "rstspec-file", "",
+		"Load rstspec.toml from this local file, without network access (env: "+rst.RstspecFileEnvVar+")")
+	rootCmd.MarkFlagsMutuallyExclusive("rstspec-url", "rstspec-file")
+	// Write cache files somewhere other than ~/.audit-cli (e.g., in ephemeral CI containers)
+	var cacheDir string
+	rootCmd.PersistentFlags().StringVar(&cacheDir, "cache-dir", "",
+		"Directory for cache files instead of ~/.audit-cli (env: "+config.EnvVarCacheDir+")")
+	// Iterate against a local monorepo without waiting on the network or touching the caches
+	var local bool
+	rootCmd.PersistentFlags().BoolVar(&local, "local", false,
+		"Never use the network or write cache files: use cached data (even if expired), the static URL mapping, and --rstspec-file or the bundled rstspec.toml")
+	// Select a named profile from .audit-cli.yaml
+	var profile string
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "",
+		"Use this named profile from the .audit-cli.yaml config file (env: "+config.EnvVarProfile+")")
+	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		projectinfo.SetExcludedDirs(excludeDirs)
+		projectinfo.SetFollowSymlinks(followSymlinks)
+		rst.SetRstspecSource(rstspecURL, rstspecFile)
+		config.SetProfile(profile)
+		config.SetCacheDir(cacheDir)
+		config.SetLocal(local)
+	}
+
 	// Customize version output format
 	rootCmd.SetVersionTemplate(fmt.Sprintf("audit-cli version %s\n", version))
 
@@ -55,9 +102,12 @@ Designed for maintenance tasks, scoping work, and reporting to stakeholders.`,
 	rootCmd.AddCommand(compare.NewCompareCommand())
 	rootCmd.AddCommand(count.NewCountCommand())
 	rootCmd.AddCommand(report.NewReportCommand())
+	rootCmd.AddCommand(doctor.NewDoctorCommand())
+	rootCmd.AddCommand(initcmd.NewInitCommand())
 
 	err := rootCmd.Execute()
 	if err != nil {
-		return
+		// Cobra has already printed the error; exit non-zero so scripts and CI can detect it
+		os.Exit(1)
 	}
 }